@@ -1,14 +1,29 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"fmt"
 	"io"
+	"io/ioutil"
 	"log"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
 
+	"github.com/jedruniu/spotify-cli/pkg/cache"
+	"github.com/jedruniu/spotify-cli/pkg/config"
+	"github.com/jedruniu/spotify-cli/pkg/daemon"
+	"github.com/jedruniu/spotify-cli/pkg/dirs"
+	"github.com/jedruniu/spotify-cli/pkg/hooks"
+	"github.com/jedruniu/spotify-cli/pkg/lyrics"
 	"github.com/jedruniu/spotify-cli/pkg/player"
+	"github.com/jedruniu/spotify-cli/pkg/scrobble"
 	"github.com/jedruniu/spotify-cli/pkg/web"
 
 	"time"
@@ -24,57 +39,431 @@ type albumsList struct {
 }
 
 var debugMode bool
+var kioskMode bool
+var exportPath string
+var importPath string
+var headlessMode bool
+var profile string
 
-func checkMode() {
+// quietMode discards output of commands, so that only errors are printed, i.e. from cron jobs.
+var quietMode bool
+
+// verbosity is 1 with -v, when requests to Spotify Web API are logged, and 2 with -vv, when
+// their headers are logged as well.
+var verbosity int
+
+// output is where commands print, it discards everything in quiet mode.
+var output io.Writer = os.Stdout
+
+// passphrase gives passphrase of the token file, it is nil unless enabled in the config.
+var passphrase func() (string, error)
+
+// configFile is the configuration file given with -config, the default one is used when it is empty.
+var configFile string
+
+// checkMode parses flags, those overriding settings of the config file change it.
+func checkMode(args []string, cfg *config.Config) {
 	debugModeFlag := flag.Bool("debug", false, "When set to true, app is populated with faked data and is not connecting with Spotify Web API.")
-	flag.Parse()
+	kioskModeFlag := flag.Bool("kiosk", false, "When set to true, app only allows to search and queue songs, leaving it requires Ctrl+Q and PIN from the config.")
+	exportFlag := flag.String("export", "", "When set, saved albums, liked tracks and playlists are exported to the given .json or .csv file and app quits without starting the player.")
+	importFlag := flag.String("import", "", "When set, playlist named after the given file is created from Spotify URIs or \"Artist - Title\" lines of the file and app quits without starting the player.")
+	profileFlag := flag.String("profile", config.DefaultProfile, "Name of the profile, each of them is logged in to its own account and has its own cache.")
+	// taken out of the arguments before the config file is loaded, listed here for -help
+	flag.String("config", configFile, "Path of the configuration file, i.e. to run with another account or theme, used instead of config.toml in the config directory.")
+	quietFlag := flag.Bool("q", false, "When set to true, commands print nothing but errors.")
+	verboseFlag := flag.Bool("v", false, "When set to true, requests sent to Spotify Web API are logged to the standard error.")
+	veryVerboseFlag := flag.Bool("vv", false, "When set to true, requests sent to Spotify Web API are logged to the standard error along with their headers.")
+	headlessFlag := flag.Bool("headless", false, "When set to true, login URL is printed instead of being opened in the browser, and the URL you were redirected to after logging in is read from the terminal.")
+	flag.StringVar(&cfg.Spotify.ClientID, "client-id", cfg.Spotify.ClientID, "Client ID of the application registered in Spotify dashboard, overrides the config file.")
+	flag.StringVar(&cfg.Spotify.RedirectHost, "redirect-host", cfg.Spotify.Host(), "Host of the redirect URI of the application registered in Spotify dashboard, overrides the config file.")
+	flag.IntVar(&cfg.Spotify.RedirectPort, "redirect-port", cfg.Spotify.Port(), "Port of the redirect URI of the application registered in Spotify dashboard, overrides the config file.")
+	flag.StringVar(&cfg.Spotify.DefaultDevice, "device", cfg.Spotify.DefaultDevice, "Name of the device playback is transferred to at startup, overrides the config file.")
+	flag.StringVar(&cfg.Spotify.Market, "market", cfg.Spotify.Market, "Country code, i.e. PL, search and browse results are given for, overrides the config file.")
+	flag.StringVar(&cfg.Spotify.APIURL, "api-url", cfg.Spotify.APIURL, "Base URL of Spotify Web API, i.e. of a local mock server, overrides the config file.")
+	// flags can be given with environment variables as well, those given on the command line take precedence
+	flag.VisitAll(func(f *flag.Flag) {
+		if value, ok := os.LookupEnv(config.EnvName(f.Name)); ok {
+			if err := f.Value.Set(value); err != nil {
+				log.Fatalf("Quiting, could not use %s environment variable: %v", config.EnvName(f.Name), err)
+			}
+		}
+	})
+	flag.CommandLine.Parse(args)
+	// aliases are expanded after flags, i.e. in `-profile work np`, flags they stand for are parsed as well
+	if _, ok := cfg.Aliases[flag.Arg(0)]; ok {
+		expanded, err := cfg.Aliases.Expand(flag.Args())
+		if err != nil {
+			log.Fatalf("Quiting, could not expand command line aliases: %v", err)
+		}
+		flag.CommandLine.Parse(expanded)
+	}
 	debugMode = *debugModeFlag
+	kioskMode = *kioskModeFlag
+	exportPath = *exportFlag
+	importPath = *importFlag
+	headlessMode = *headlessFlag
+	profile = *profileFlag
+	quietMode = *quietFlag
+	switch {
+	case *veryVerboseFlag:
+		verbosity = 2
+	case *verboseFlag:
+		verbosity = 1
+	}
+	if quietMode {
+		output = ioutil.Discard
+	}
 }
 
-func NewSpotifyAuthenticator() spotify.Authenticator {
-	envKeys := []string{"SPOTIFY_CLIENT_ID", "SPOTIFY_SECRET"}
-	envVars := map[string]string{}
-	for _, key := range envKeys {
-		v := os.Getenv(key)
-		if v == "" {
-			log.Fatalf("Quiting, there is no %s environment variable.", key)
+// configFlag takes -config flag out of the arguments, or SPOTIFY_CLI_CONFIG environment variable
+// when there is no flag. It is needed before the other flags are parsed, as the config file gives
+// aliases of the arguments and defaults of the flags.
+func configFlag(args []string) ([]string, error) {
+	path, _ := os.LookupEnv(config.EnvName("config"))
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		name := strings.TrimLeft(args[i], "-")
+		switch {
+		case args[i] == "--":
+			rest = append(rest, args[i:]...)
+			i = len(args)
+		case !strings.HasPrefix(args[i], "-"):
+			rest = append(rest, args[i])
+		case strings.HasPrefix(name, "config="):
+			path = strings.TrimPrefix(name, "config=")
+		case name == "config":
+			if i+1 == len(args) {
+				return nil, fmt.Errorf("config flag needs path of the configuration file")
+			}
+			i++
+			path = args[i]
+		default:
+			rest = append(rest, args[i])
 		}
-		envVars[key] = v
 	}
+	if path == "" {
+		return rest, nil
+	}
+	// absolute, so that the same file is used once the application is started again with another profile
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not locate config file %s: %v", path, err)
+	}
+	configFile = abs
+	return rest, nil
+}
 
-	redirectURI := url.URL{Scheme: "http", Host: "localhost:8888", Path: "/spotify-cli"}
+func configPath() string {
+	if configFile != "" {
+		return configFile
+	}
+	path, err := config.DefaultPath()
+	if err != nil {
+		log.Fatalf("Quiting, could not locate config file: %v", err)
+	}
+	return path
+}
 
-	auth := spotify.NewAuthenticator(
-		redirectURI.String(),
-		spotify.ScopeUserReadPrivate,
-		spotify.ScopeUserReadCurrentlyPlaying,
-		spotify.ScopeUserReadPlaybackState,
-		spotify.ScopeUserModifyPlaybackState,
-		spotify.ScopeUserLibraryRead,
-		// Used for Web Playback SDK
-		"streaming",
-		spotify.ScopeUserReadEmail,
-	)
-	auth.SetAuthInfo(envVars["SPOTIFY_CLIENT_ID"], envVars["SPOTIFY_SECRET"])
-	return auth
+// stateDir is where tokens and logs are kept.
+func stateDir() string {
+	dir, err := dirs.State()
+	if err != nil {
+		log.Fatalf("Quiting, could not locate state directory: %v", err)
+	}
+	return dir
+}
+
+// statePath is where pinned albums, playlist folders, the layout and granted permissions of
+// the profile are kept.
+func statePath() string {
+	return config.StatePath(stateDir(), profile)
+}
+
+// socketPath is where the daemon of the profile listens.
+func socketPath() string {
+	dir, err := dirs.Runtime()
+	if err != nil {
+		log.Fatalf("Quiting, could not locate runtime directory: %v", err)
+	}
+	return config.SocketPath(dir, profile)
+}
+
+// tokenStore keeps the token of the profile between runs in the keyring, or encrypted in
+// the state directory when there is no keyring.
+func tokenStore() web.TokenStore {
+	dir := stateDir()
+	return web.KeyringTokenStore{
+		Service:  "spotify-cli",
+		Account:  profile,
+		Fallback: web.EncryptedFileTokenStore{Path: config.EncryptedTokenPath(dir, profile), AskPassphrase: passphrase},
+		Legacy:   config.TokenPath(dir, profile),
+	}
+}
+
+func loadConfig() *config.Config {
+	cfg, err := config.Load(configPath())
+	if err != nil {
+		log.Fatalf("Quiting, could not load config: %v", err)
+	}
+	return cfg
+}
+
+func baseCacheDir() string {
+	dir, err := cache.DefaultDir()
+	if err != nil {
+		log.Fatalf("Quiting, could not locate cache directory: %v", err)
+	}
+	return dir
+}
+
+func cacheDir() string {
+	return config.ProfileCacheDir(baseCacheDir(), profile)
+}
+
+// callbackURL is where Spotify redirects the user after logging in, to the
+// port of the address callback server listens on.
+func callbackURL(host, addr string) *url.URL {
+	_, port, _ := net.SplitHostPort(addr)
+	return &url.URL{Scheme: "http", Host: net.JoinHostPort(host, port), Path: "/spotify-cli"}
+}
+
+// callbackServer listens on the redirect port, or on the first fallback port which is not in use.
+func callbackServer(app config.Spotify) *web.HTTPServer {
+	server := &web.HTTPServer{Addr: fmt.Sprintf(":%d", app.Port())}
+	for _, port := range app.FallbackPorts {
+		server.Fallbacks = append(server.Fallbacks, fmt.Sprintf(":%d", port))
+	}
+	return server
+}
+
+// spotifyScopes are permissions the user is asked to grant when logging in, they are needed
+// to browse the library and control playback. Permissions to change the library, follows
+// and playlists are asked for once a feature needs them, see player.ScopeTransport.
+var spotifyScopes = []string{
+	spotify.ScopeUserReadPrivate,
+	spotify.ScopeUserReadCurrentlyPlaying,
+	spotify.ScopeUserReadPlaybackState,
+	spotify.ScopeUserModifyPlaybackState,
+	spotify.ScopeUserLibraryRead,
+	spotify.ScopeUserFollowRead,
+	spotify.ScopePlaylistReadPrivate,
+	spotify.ScopeUserTopRead,
+	spotify.ScopeUserReadRecentlyPlayed,
+	// Used for resuming podcast episodes
+	"user-read-playback-position",
+	// Used for Web Playback SDK
+	"streaming",
+	spotify.ScopeUserReadEmail,
+}
+
+// requestedScopes returns permissions needed for features enabled in the config, along with
+// the configured ones and the ones user granted later.
+func requestedScopes(cfg *config.Config, granted []string) []string {
+	scopes := append([]string{}, spotifyScopes...)
+	if cfg.Inbox.Playlist != "" {
+		// song requests are read from a collaborative playlist
+		scopes = append(scopes, spotify.ScopePlaylistReadCollaborative)
+	}
+	for _, scope := range append(cfg.Auth.Scopes, granted...) {
+		if !contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// credentialsFromEnv fills client ID and secret of the application from environment
+// variables, they take precedence over the config file.
+func credentialsFromEnv(app *config.Spotify) {
+	if id := os.Getenv("SPOTIFY_CLIENT_ID"); id != "" {
+		app.ClientID = id
+	}
+	if secret := os.Getenv("SPOTIFY_SECRET"); secret != "" {
+		app.ClientSecret = secret
+	}
+}
+
+func NewSpotifyAuthenticator(auth config.Auth, app config.Spotify, redirectURL string, scopes []string) *web.Authenticator {
+	pkce, _ := auth.PKCE() // validated when config was loaded
+	if app.ClientID == "" {
+		log.Fatalf("Quiting, there is no client ID in the config file nor SPOTIFY_CLIENT_ID environment variable.")
+	}
+	// client secret is not needed with PKCE
+	if !pkce && app.ClientSecret == "" {
+		log.Fatalf("Quiting, there is no client secret in the config file nor SPOTIFY_SECRET environment variable.")
+	}
+
+	if pkce {
+		authenticator, err := web.NewPKCEAuthenticator(redirectURL, app.ClientID, scopes...)
+		if err != nil {
+			log.Fatalf("Quiting, could not create authenticator: %v", err)
+		}
+		return authenticator
+	}
+	return web.NewAuthenticator(redirectURL, app.ClientID, app.ClientSecret, scopes...)
 }
 
 func main() {
 	log.SetFlags(log.Llongfile)
-	f, _ := os.Create("log.txt")
+	if err := dirs.Migrate(); err != nil {
+		log.Printf("Could not move files to XDG base directories with %s", err)
+	}
+	if err := os.MkdirAll(stateDir(), 0700); err != nil {
+		log.Printf("Could not create state directory with %s", err)
+	}
+	f, _ := os.Create(filepath.Join(stateDir(), "log.txt"))
 	defer f.Close()
-	log.SetOutput(io.MultiWriter(f, os.Stdout))
+	// standard output is left to commands, so that it can be piped
+	log.SetOutput(io.MultiWriter(f, os.Stderr))
+
+	args, err := configFlag(os.Args[1:])
+	if err != nil {
+		log.Fatalf("Quiting, %v", err)
+	}
+	cfg := loadConfig()
+	credentialsFromEnv(&cfg.Spotify)
+	if err := cfg.ApplyEnv(os.LookupEnv); err != nil {
+		log.Fatalf("Quiting, %v", err)
+	}
+	checkMode(args, cfg)
+	// run before the config is validated, so that invalid settings can be changed with it
+	if flag.Arg(0) == "config" {
+		if err := configCommand(flag.Args()[1:], os.Stdout); err != nil {
+			log.Fatalf("Quiting, %v", err)
+		}
+		return
+	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Quiting, %v", err)
+	}
+	if quietMode && verbosity > 0 {
+		log.Printf("Quiting, -q cannot be given with -v or -vv")
+		os.Exit(player.ExitUsage)
+	}
+	if err := config.ValidateProfile(profile); err != nil {
+		log.Fatalf("Quiting, %v", err)
+	}
+	if cfg.Auth.Passphrase {
+		passphrase = web.AskPassphrase(os.Stdin, os.Stdout)
+		// i.e. given by a password manager, so that it does not have to be typed
+		if value, ok := os.LookupEnv(config.EnvName("passphrase")); ok {
+			passphrase = func() (string, error) { return value, nil }
+		}
+	}
+	if flag.Arg(0) == "logout" {
+		if err := logout(); err != nil {
+			log.Fatalf("Quiting, could not log out: %v", err)
+		}
+		fmt.Fprintf(output, "Logged out of profile %s, its token and cached data were removed\n", profile)
+		return
+	}
+	// the player is started when there is no command
+	var commandArgs []string
+	if flag.NArg() > 0 {
+		commandArgs, err = player.ReadPickedURI(flag.Arg(0), flag.Args()[1:], os.Stdin)
+		if err != nil {
+			log.Printf("Quiting, could not run %s command: %v", flag.Arg(0), err)
+			os.Exit(player.ExitCode(err))
+		}
+	}
+	// commands are run by the daemon when it runs, as it is logged in already
+	if name := flag.Arg(0); player.IsCommand(name) {
+		if conn, err := daemon.Dial(socketPath()); err == nil {
+			err := conn.Run(name, commandArgs, output)
+			conn.Close()
+			if err != nil {
+				log.Printf("Quiting, could not run %s command: %v", name, err)
+				os.Exit(player.ExitCode(err))
+			}
+			return
+		}
+	}
+	if flag.Arg(0) == "batch" {
+		if conn, err := daemon.Dial(socketPath()); err == nil {
+			conn.Close()
+			runBatch(cfg.Aliases, func(name string, args []string) error {
+				conn, err := daemon.Dial(socketPath())
+				if err != nil {
+					return err
+				}
+				defer conn.Close()
+				return conn.Run(name, args, output)
+			})
+			return
+		}
+	}
+	// the player is started only when asked for, commands never touch the terminal
+	if !playerMode() && !commandMode() {
+		if flag.Arg(0) == "ui" {
+			log.Printf("Quiting, ui command takes no arguments, got %v", flag.Args()[1:])
+		} else {
+			log.Printf("Quiting, unknown command %s, run ui to start the player", flag.Arg(0))
+		}
+		os.Exit(player.ExitUsage)
+	}
 
-	checkMode()
+	session, err := login(cfg)
+	if err != nil {
+		// commands, exports and imports are run without the player, i.e. from scripts
+		if commandMode() {
+			log.Printf("Quiting, %v", err)
+			os.Exit(player.ExitAuth)
+		}
+		showFailure(err, cfg)
+	}
+	player.UseLibraryCache(session.library)
+	player.UseLyrics(session.lyrics)
 
-	var client player.SpotifyClient
-	var spotifyAuthenticator = NewSpotifyAuthenticator()
+	// requests in flight, i.e. fetching all pages of a large library, are cancelled when quitting
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	authHandler := &web.AuthHandler{
-		Client:        make(chan *spotify.Client),
-		State:         uuid.New().String(),
-		Authenticator: spotifyAuthenticator,
+	if commandMode() {
+		runCommand(ctx, cfg, session, commandArgs)
+		return
 	}
+	runPlayer(ctx, cancel, cfg, session)
+}
+
+// playerMode tells whether the player is started, it is when no command or ui is given.
+func playerMode() bool {
+	return flag.NArg() == 0 || (flag.NArg() == 1 && flag.Arg(0) == "ui")
+}
+
+// commandMode tells whether a command, export or import is run instead of the player.
+func commandMode() bool {
+	name := flag.Arg(0)
+	return player.IsCommand(name) || name == "daemon" || name == "batch" || exportPath != "" || importPath != ""
+}
+
+// session is what commands and the player use once logged in.
+type session struct {
+	client    player.SpotifyClient
+	library   *player.LibraryCache
+	lyrics    lyrics.Provider
+	proxy     *url.URL
+	scopes    *player.ScopeTransport
+	rateLimit *player.RateLimitTransport
+	offline   *player.OfflineTransport
+	// webPlayer tells about the web player, which is served along with the auth callback
+	webPlayer *web.WebsocketHandler
+}
+
+// login waits for the user to log in, or uses the token kept before, and creates the client.
+func login(cfg *config.Config) (*session, error) {
+	// validated along with the rest of the config
+	proxy, _ := cfg.ProxyURL()
+
+	var client player.SpotifyClient
 
 	webSocketHandler := &web.WebsocketHandler{
 		PlayerShutdown:    make(chan bool),
@@ -82,82 +471,1212 @@ func main() {
 		PlayerStateChange: make(chan *web.WebPlaybackState),
 	}
 
+	h := http.NewServeMux()
+	h.Handle("/ws", webSocketHandler)
+	h.HandleFunc("/player", web.PlayerHandleFunc)
+
+	server := callbackServer(cfg.Spotify)
+	addr, err := server.Listen()
+	if err != nil {
+		log.Fatalf("Quiting, could not listen for auth callback: %v", err)
+	}
+	callback := callbackURL(cfg.Spotify.Host(), addr)
+	flow := &web.Flow{
+		Server:       server,
+		CallbackPath: callback.Path,
+		State:        uuid.New().String(),
+	}
+	if debugMode {
+		flow.Authenticator = web.DebugAuthenticator{RedirectURL: callback.String()}
+		flow.OpenBrowser = web.DebugBrowser
+	} else {
+		// permissions granted from the application are kept in the state file of the profile
+		state, err := config.LoadState(statePath(), cfg)
+		if err != nil {
+			log.Printf("Could not load granted permissions with %s", err)
+			state = &config.State{}
+		}
+		authenticator := NewSpotifyAuthenticator(cfg.Auth, cfg.Spotify, callback.String(), requestedScopes(cfg, state.Scopes))
+		if proxy != nil {
+			authenticator.SetProxy(proxy)
+		}
+		flow.Authenticator = authenticator
+		flow.OpenBrowser = web.OpenBrowser
+		flow.Tokens = tokenStore()
+	}
+	if headlessMode {
+		flow.OpenBrowser = web.PrintURL(os.Stdout)
+		flow.Pasted = os.Stdin
+		flow.Output = os.Stdout
+	}
+
+	// wait for authentication to complete
+	httpClient, err := flow.Authenticate(h)
+	if err != nil {
+		return nil, fmt.Errorf("could not authenticate: %v", err)
+	}
+	scopes := &player.ScopeTransport{}
+	rateLimit := &player.RateLimitTransport{}
+	// library is browsed from responses kept while online, when Spotify cannot be reached
+	offline := &player.OfflineTransport{Store: cache.NewStore(cacheDir())}
+	// cache directory of the profile marks it as used, so that it can be switched to
+	if err := os.MkdirAll(cacheDir(), 0700); err != nil {
+		log.Printf("Could not create cache directory with %s", err)
+	}
+
 	if debugMode {
 		client = player.NewDebugClient()
 		go func() {
 			webSocketHandler.PlayerDeviceID <- "debug"
 		}()
 	} else {
-		var err error
+		refresh := web.TokenRefresh(httpClient)
+		rateLimit.Base = httpClient.Transport
+		if verbosity > 0 {
+			// innermost, so that each request sent again when rate limited is logged
+			rateLimit.Base = &player.RequestLogTransport{
+				Base:    httpClient.Transport,
+				Logger:  log.New(os.Stderr, "", log.LstdFlags),
+				Headers: verbosity > 1,
+			}
+		}
+		scopes.Base = rateLimit
+		offline.Base = scopes
+		httpClient.Transport = offline
+		// token revoked during a long session is refreshed instead of failing until restart
+		api := player.NewClient(httpClient)
+		if cfg.Spotify.APIURL != "" {
+			api.SetBaseURL(cfg.Spotify.APIURL)
+		}
+		api.SetMarket(cfg.Spotify.Market, cfg.Spotify.Locale)
+		client = player.NewRefreshingClient(api, refresh)
+	}
+	return &session{
+		client:    client,
+		library:   player.NewLibraryCache(cache.NewStore(cacheDir())),
+		lyrics:    newLyricsProvider(cfg, proxy),
+		proxy:     proxy,
+		scopes:    scopes,
+		rateLimit: rateLimit,
+		offline:   offline,
+		webPlayer: webSocketHandler,
+	}, nil
+}
 
-		h := http.NewServeMux()
-		h.Handle("/ws", webSocketHandler)
-		h.Handle("/spotify-cli", authHandler)
-		h.HandleFunc("/player", web.PlayerHandleFunc)
+// runCommand runs the command, export or import given on the command line, quitting with
+// the exit code of the error.
+func runCommand(ctx context.Context, cfg *config.Config, session *session, commandArgs []string) {
+	client, library := session.client, session.library
+	if exportPath != "" {
+		export, err := player.ExportLibraryToFile(ctx, client, library, exportPath)
+		if err != nil {
+			log.Fatalf("Quiting, could not export library: %v", err)
+		}
+		fmt.Fprintf(output, "Exported %s to %s\n", export.Summary(), exportPath)
+		return
+	}
+	if importPath != "" {
+		imported, err := player.ImportPlaylistFromFile(ctx, client, importPath, "")
+		if err != nil {
+			log.Fatalf("Quiting, could not import playlist: %v", err)
+		}
+		fmt.Fprintf(output, "Imported %d tracks to %s\n", imported.Added, imported.Playlist.Name)
+		for _, line := range imported.Unmatched {
+			fmt.Fprintf(output, "Not found, %s\n", line)
+		}
+		return
+	}
+	if flag.Arg(0) == "daemon" {
+		if err := runDaemon(ctx, client, cfg, flag.Args()[1:]); err != nil {
+			log.Fatalf("Quiting, %v", err)
+		}
+		return
+	}
+	if flag.Arg(0) == "batch" {
+		runBatch(cfg.Aliases, func(name string, args []string) error {
+			return player.RunCommand(ctx, client, name, args, output)
+		})
+		return
+	}
+	if name := flag.Arg(0); player.IsCommand(name) {
+		if err := player.RunCommand(ctx, client, name, commandArgs, output); err != nil {
+			log.Printf("Quiting, could not run %s command: %v", name, err)
+			os.Exit(player.ExitCode(err))
+		}
+	}
+}
 
-		go func() {
-			log.Fatal(http.ListenAndServe(":8888", h))
-		}()
+// runPlayer starts the player, it returns once the user quits.
+func runPlayer(ctx context.Context, cancel context.CancelFunc, cfg *config.Config, session *session) {
+	client, library, proxy := session.client, session.library, session.proxy
+	scopes, rateLimit, offline := session.scopes, session.rateLimit, session.offline
+	webSocketHandler, lyricsProvider := session.webPlayer, session.lyrics
 
-		err = player.StartRemoteAuthentication(spotifyAuthenticator, authHandler.State)
+	if _, err := client.CurrentUser(ctx); player.Unreachable(err) {
+		log.Printf("Could not reach Spotify, starting offline in read-only mode with %s", err)
+		offline.GoOffline()
+	}
+
+	// the web player only tells about itself, what is played on other devices is polled
+	if cfg.Hooks.TrackChange != "" && !offline.Offline() {
+		polling := player.NewPollingClient(client)
+		runHooks(ctx, cfg.Hooks, polling)
+		go polling.Poll(ctx, cfg.Hooks.Interval())
+	}
+
+	// wait for device to be ready, there is none offline
+	var webPlayerID spotify.ID
+	if !offline.Offline() {
+		webPlayerID = <-webSocketHandler.PlayerDeviceID
+	}
+
+	confirmation := player.NewConfirmation()
+	sidebar, err := player.NewSideBar(ctx, client, confirmation, library)
+	if err != nil {
+		cancel()
+		showFailure(fmt.Errorf("could not fetch albums: %v", err), cfg)
+	}
+	state, err := config.LoadState(statePath(), cfg)
+	if err != nil {
+		log.Printf("Could not load pinned albums and playlist folders with %s", err)
+		state = &config.State{}
+	}
+	sidebar.AlbumList.SetPinned(state.PinnedAlbums)
+	// pins are only kept locally, so they are saved to the state file right away
+	sidebar.AlbumList.OnPinned(func(ids []string) {
+		err := config.UpdateState(statePath(), cfg, func(file *config.State) error {
+			file.PinnedAlbums = ids
+			return nil
+		})
 		if err != nil {
-			log.Printf("could not get client, shutting down, err: %v", err)
+			log.Printf("could not save pinned albums, err: %v", err)
 		}
+	})
+	search := player.NewSearch(ctx, client, sidebar.AlbumList, confirmation)
+	playerStates := webSocketHandler.PlayerStateChange
+	if cfg.ListenBrainz.Token != "" {
+		listenBrainz := scrobble.NewListenBrainz(cfg.ListenBrainz.URL, cfg.ListenBrainz.Token, cache.NewStore(cacheDir()))
+		if proxy != nil {
+			listenBrainz.SetProxy(proxy)
+		}
+		playerStates = scrobbleStates(scrobble.NewScrobbler(listenBrainz), playerStates)
+	}
+	related := player.NewRelatedArtists(ctx, client)
+	queue := player.NewQueueList(ctx, client)
+	sidebarPane, relatedPane, queuePane := player.NewPane(sidebar.Box), player.NewPane(related.Box), player.NewPane(queue.Box)
+	refreshPanes := func() {
+		if err := related.Refresh(); err != nil {
+			log.Printf("Could not refresh related artists with %s", err)
+		}
+		// the queue is only asked for while it is shown
+		if queuePane.Hidden() {
+			return
+		}
+		if err := queue.Refresh(); err != nil {
+			log.Printf("Could not refresh queue with %s", err)
+		}
+	}
+	playerStates = refreshOnTrackChange(refreshPanes, playerStates)
+	playback := player.NewPlayback(ctx, client, playerStates, webPlayerID, cfg.Spotify.DefaultDevice)
+	if interval := cfg.Refresh.DevicesInterval(); interval > 0 && !offline.Offline() {
+		go func() {
+			for range time.Tick(interval) {
+				if err := playback.Devices.Refresh(); err != nil {
+					log.Printf("Could not refresh devices with %s", err)
+				}
+			}
+		}()
 	}
 
-	// wait for authentication to complete
-	client = <-authHandler.Client
+	if kioskMode {
+		runKiosk(ctx, cancel, client, cfg, playback.NowPlaying, webSocketHandler.PlayerShutdown)
+		return
+	}
 
-	// wait for device to be ready
-	webPlayerID := <-webSocketHandler.PlayerDeviceID
+	palette := player.NewCommandPalette(ctx, client, cfg.Aliases)
 
-	sidebar, _ := player.NewSideBar(client)
-	search := player.NewSearch(client)
-	playback := player.NewPlayback(client, webSocketHandler.PlayerStateChange, webPlayerID)
+	mainArea := player.NewMainArea()
+	playlistPicker := player.NewPlaylistPicker(ctx, client)
+	addToPlaylist := func(trackIDs []spotify.ID) {
+		if err := playlistPicker.Pick(trackIDs); err != nil {
+			log.Printf("could not pick playlist, err: %v", err)
+			return
+		}
+		mainArea.Show("add-to-playlist")
+	}
+	recommender, err := player.NewRecommender(ctx, cfg.Recommendations.Provider, client)
+	if err != nil {
+		log.Printf("could not create configured recommender, falling back to %s, err: %v", player.DefaultRecommender, err)
+		recommender, _ = player.NewRecommender(ctx, player.DefaultRecommender, client)
+	}
+	location, _ := cfg.Location() // validated when config was loaded
+	// home is added first, so that it is the landing view
+	home := player.NewHome(ctx, client, recommender, cache.NewStore(cacheDir()), location)
+	if err := home.Refresh(); err != nil {
+		log.Printf("could not refresh home suggestions, err: %v", err)
+	}
+	mainArea.Add("home", player.View{Widget: home.Box, Focusables: home.Focusables})
+	home.OnAddToPlaylist(addToPlaylist)
+	mainArea.Add("search", player.View{Widget: search.Box, Focusables: search.Focusables})
+	search.OnAddToPlaylist(addToPlaylist)
+	followedArtists, err := player.NewFollowedArtists(ctx, client)
+	if err != nil {
+		log.Printf("could not create followed artists view, err: %v", err)
+	} else {
+		mainArea.Add("artists", player.View{Widget: followedArtists.Box, Focusables: followedArtists.Focusables})
+		followedArtists.OnAddToPlaylist(addToPlaylist)
+	}
+	top, err := player.NewTop(ctx, client)
+	if err != nil {
+		log.Printf("could not create top view, err: %v", err)
+	} else {
+		mainArea.Add("top", player.View{Widget: top.Box, Focusables: top.Focusables})
+		top.OnAddToPlaylist(addToPlaylist)
+	}
+	charts, err := player.NewCharts(ctx, client, cache.NewStore(cacheDir()), location, cfg.Charts)
+	if err != nil {
+		log.Printf("could not create charts view, err: %v", err)
+	} else {
+		mainArea.Add("charts", player.View{Widget: charts.Box, Focusables: charts.Focusables})
+		charts.OnAddToPlaylist(addToPlaylist)
+		palette.Register("chart", func(args []string) error {
+			if len(args) == 0 {
+				return fmt.Errorf("chart command takes chart name, i.e. global")
+			}
+			if err := mainArea.Show("charts"); err != nil {
+				return err
+			}
+			return charts.ShowChart(strings.Join(args, " "))
+		})
+	}
+	shows, err := player.NewShows(ctx, client)
+	if err != nil {
+		log.Printf("could not create shows view, err: %v", err)
+	} else {
+		mainArea.Add("shows", player.View{Widget: shows.Box, Focusables: shows.Focusables})
+	}
+	recommendations := player.NewRecommendations(ctx, client, recommender)
+	mainArea.Add("recommendations", player.View{Widget: recommendations.Box, Focusables: recommendations.Focusables})
+	recommendations.OnAddToPlaylist(addToPlaylist)
+	palette.Register("recommend", func(args []string) error {
+		if len(args) != 0 {
+			return fmt.Errorf("recommend command does not take arguments, got %v", args)
+		}
+		if err := recommendations.Refresh(); err != nil {
+			return err
+		}
+		return mainArea.Show("recommendations")
+	})
+	credits := player.NewCredits(ctx, client)
+	mainArea.Add("credits", player.View{Widget: credits.Box, Focusables: credits.Focusables})
+	palette.Register("credits", func(args []string) error {
+		if len(args) != 0 {
+			return fmt.Errorf("credits command does not take arguments, got %v", args)
+		}
+		if err := credits.ShowPlaying(); err != nil {
+			return err
+		}
+		return mainArea.Show("credits")
+	})
+	lyricsView := player.NewLyrics(ctx, client, lyricsProvider)
+	mainArea.Add("lyrics", player.View{Widget: lyricsView.Box, Focusables: lyricsView.Focusables})
+	showLyrics := func() error {
+		if err := lyricsView.ShowPlaying(); err != nil {
+			return err
+		}
+		return mainArea.Show("lyrics")
+	}
+	palette.Register("lyrics", func(args []string) error {
+		if len(args) != 0 {
+			return fmt.Errorf("lyrics command does not take arguments, got %v", args)
+		}
+		return showLyrics()
+	})
+	audiobooks, err := player.NewAudiobooks(ctx, client)
+	if err != nil {
+		log.Printf("could not create audiobooks view, err: %v", err)
+	} else {
+		mainArea.Add("audiobooks", player.View{Widget: audiobooks.Box, Focusables: audiobooks.Focusables})
+	}
+	quiz, err := player.NewQuiz(ctx, client, playback.NowPlaying)
+	if err != nil {
+		log.Printf("could not create quiz view, err: %v", err)
+	} else {
+		mainArea.Add("quiz", player.View{Widget: quiz.Box, Focusables: quiz.Focusables})
+	}
+	playlist := player.NewPlaylist(ctx, client, confirmation)
+	mainArea.Add("playlist", player.View{Widget: playlist.Box, Focusables: playlist.Focusables})
+	playlistForm := player.NewPlaylistForm(ctx, client)
+	mainArea.Add("new-playlist", player.View{Widget: playlistForm.Box, Focusables: playlistForm.Focusables})
+	mainArea.Add("add-to-playlist", player.View{Widget: playlistPicker.Box, Focusables: playlistPicker.Focusables})
+	mainArea.Add("edit-playlist", player.View{Widget: playlistForm.Box, Focusables: playlistForm.Focusables})
+	openPlaylist := func(saved *spotify.FullPlaylist) {
+		if err := playlist.Open(saved.ID, saved.Name); err != nil {
+			log.Printf("could not open playlist, err: %v", err)
+			return
+		}
+		mainArea.Show("playlist")
+	}
+	playlistForm.OnCreated(openPlaylist)
+	playlistForm.OnEdited(openPlaylist)
+	playlist.OnEdit(func(playlistID spotify.ID) error {
+		if err := playlistForm.Edit(playlistID); err != nil {
+			return err
+		}
+		return mainArea.Show("edit-playlist")
+	})
+	playlistFolders := player.NewPlaylistFolders(ctx, client, state.PlaylistFolders)
+	if err := playlistFolders.Refresh(); err != nil {
+		log.Printf("could not refresh playlists, err: %v", err)
+	}
+	mainArea.Add("playlists", player.View{Widget: playlistFolders.Box, Focusables: playlistFolders.Focusables})
+	playlistFolders.OnOpen(func(opened spotify.SimplePlaylist) {
+		if err := playlist.Open(opened.ID, opened.Name); err != nil {
+			log.Printf("could not open playlist, err: %v", err)
+			return
+		}
+		mainArea.Show("playlist")
+	})
+	// folders are only kept locally, so they are saved to the state file right away
+	playlistFolders.OnChanged(func(folders []config.PlaylistFolder) {
+		err := config.UpdateState(statePath(), cfg, func(file *config.State) error {
+			file.PlaylistFolders = folders
+			return nil
+		})
+		if err != nil {
+			log.Printf("could not save playlist folders, err: %v", err)
+		}
+	})
+	palette.Register("playlists", func(args []string) error {
+		if len(args) != 0 {
+			return fmt.Errorf("playlists command does not take arguments, got %v", args)
+		}
+		if err := playlistFolders.Refresh(); err != nil {
+			return err
+		}
+		return mainArea.Show("playlists")
+	})
+	palette.Register("folder", func(args []string) error {
+		return playlistFolders.MoveSelected(strings.Join(args, " "))
+	})
+	palette.Register("import", func(args []string) error {
+		if len(args) == 0 {
+			return fmt.Errorf("import command takes path of the file and optional playlist name")
+		}
+		imported, err := player.ImportPlaylistFromFile(ctx, client, args[0], strings.Join(args[1:], " "))
+		if err != nil {
+			return err
+		}
+		if err := playlist.OpenImported(imported); err != nil {
+			return err
+		}
+		return mainArea.Show("playlist")
+	})
+	palette.Register("smart-playlist", func(args []string) error {
+		name, filters := "", strings.Join(args, " ")
+		if parts := strings.SplitN(filters, ":", 2); len(parts) == 2 {
+			name, filters = strings.TrimSpace(parts[0]), parts[1]
+		}
+		query, err := player.ParseSmartPlaylistQuery(filters)
+		if err != nil {
+			return err
+		}
+		smart, err := player.CreateSmartPlaylist(ctx, client, library, name, query)
+		if err != nil {
+			return err
+		}
+		log.Print(smart.Summary())
+		if err := playlist.Open(smart.Playlist.ID, smart.Playlist.Name); err != nil {
+			return err
+		}
+		return mainArea.Show("playlist")
+	})
+	palette.Register("new-playlist", func(args []string) error {
+		playlistForm.New(strings.Join(args, " "))
+		return mainArea.Show("new-playlist")
+	})
+	if cfg.Inbox.Playlist != "" {
+		inbox, err := player.NewInbox(ctx, client, spotify.ID(cfg.Inbox.Playlist))
+		if err != nil {
+			log.Printf("could not create inbox view, err: %v", err)
+		} else {
+			mainArea.Add("inbox", player.View{Widget: inbox.Box, Focusables: inbox.Focusables})
+			go inbox.Watch(cfg.Inbox.Interval())
+		}
+	}
+	artistFilter := player.NewArtistFilter(sidebar.AlbumList)
+	mainArea.Add("library-artists", player.View{Widget: artistFilter.Box, Focusables: artistFilter.Focusables})
+	palette.Register("filter-artist", func(args []string) error {
+		if len(args) != 0 {
+			return sidebar.AlbumList.FilterArtist(strings.Join(args, " "))
+		}
+		artistFilter.Refresh()
+		return mainArea.Show("library-artists")
+	})
+	recentlyAdded := player.NewRecentlyAdded(ctx, client, sidebar.AlbumList, location)
+	mainArea.Add("recent", player.View{Widget: recentlyAdded.Box, Focusables: recentlyAdded.Focusables})
+	palette.Register("recent", func(args []string) error {
+		if len(args) != 0 {
+			return fmt.Errorf("recent command does not take arguments, got %v", args)
+		}
+		recentlyAdded.Refresh()
+		return mainArea.Show("recent")
+	})
+	duplicates := player.NewDuplicates(ctx, client, sidebar.AlbumList, confirmation)
+	mainArea.Add("duplicates", player.View{Widget: duplicates.Box, Focusables: duplicates.Focusables})
+	palette.Register("duplicates", func(args []string) error {
+		if len(args) != 0 {
+			return fmt.Errorf("duplicates command does not take arguments, got %v", args)
+		}
+		if err := duplicates.Refresh(); err != nil {
+			return err
+		}
+		return mainArea.Show("duplicates")
+	})
+	liked := player.NewLikedSongs(ctx, client)
+	mainArea.Add("liked", player.View{Widget: liked.Box, Focusables: liked.Focusables})
+	liked.OnAddToPlaylist(addToPlaylist)
+	palette.Register("liked", func(args []string) error {
+		if len(args) != 0 {
+			return fmt.Errorf("liked command does not take arguments, got %v", args)
+		}
+		if err := liked.Load(); err != nil {
+			return err
+		}
+		return mainArea.Show("liked")
+	})
+	palette.Register("export", func(args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("export command takes exactly one argument - path of .json or .csv file, got %v", args)
+		}
+		export, err := player.ExportLibraryToFile(ctx, client, library, args[0])
+		if err != nil {
+			return err
+		}
+		log.Printf("Exported %s to %s", export.Summary(), args[0])
+		return nil
+	})
+	profiles := player.NewProfileSwitcher(profile)
+	mainArea.Add("profiles", player.View{Widget: profiles.Box, Focusables: profiles.Focusables})
+	palette.Register("profile", func(args []string) error {
+		if len(args) == 1 {
+			if err := config.ValidateProfile(args[0]); err != nil {
+				return err
+			}
+			profiles.Switch(args[0])
+			return nil
+		}
+		if len(args) != 0 {
+			return fmt.Errorf("profile command takes at most one argument - profile name, got %v", args)
+		}
+		names, err := config.Profiles(baseCacheDir())
+		if err != nil {
+			return err
+		}
+		profiles.Refresh(names)
+		return mainArea.Show("profiles")
+	})
+	keyHelp := player.NewKeyHelp(cfg.Keys.Bindings())
+	mainArea.Add("keys", player.View{Widget: keyHelp.Box, Focusables: keyHelp.Focusables})
+	palette.Register("keys", func(args []string) error {
+		if len(args) != 0 {
+			return fmt.Errorf("keys command takes no arguments, got %v", args)
+		}
+		return mainArea.Show("keys")
+	})
+	// the Queue tab lists the queue as well, taking the whole main area
+	queued := player.NewQueueList(ctx, client)
+	queued.Box.SetSizePolicy(tui.Expanding, tui.Expanding)
+	mainArea.Add("queue", player.View{Widget: queued.Box, Focusables: queued.Focusables})
+	history := player.NewHistory(ctx, client, location)
+	mainArea.Add("history", player.View{Widget: history.Box, Focusables: history.Focusables})
+	// views are grouped in tabs, views which are in none are shown from the palette only
+	tabs := player.NewTabs(mainArea,
+		player.Tab{Title: "Library", Views: []string{"liked", "recent", "library-artists", "duplicates", "artists"}},
+		player.Tab{Title: "Playlists", Views: []string{"playlists", "playlist", "new-playlist", "edit-playlist", "add-to-playlist", "inbox"}},
+		player.Tab{Title: "Search", Views: []string{"search"}},
+		player.Tab{Title: "Queue", Views: []string{"queue"}, Refresh: queued.Refresh},
+		player.Tab{Title: "Browse", Views: []string{"home", "top", "charts", "recommendations", "shows", "audiobooks", "credits", "lyrics", "quiz"}},
+		player.Tab{Title: "History", Views: []string{"history"}, Refresh: history.Refresh},
+	)
+	palette.Register("view", func(args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("view command takes exactly one argument - view name, got %v", args)
+		}
+		return mainArea.Show(args[0])
+	})
 
+	status := player.NewStatusBar()
+	player.UseStatusBar(status)
 	mainFrame := tui.NewVBox(
-		search.Box,
+		tabs.Box,
+		mainArea.Box,
 		tui.NewSpacer(),
+		confirmation.Box,
 		playback.Box,
+		palette.Box,
+		status.Box,
 	)
 	mainFrame.SetSizePolicy(tui.Expanding, tui.Expanding)
 
+	rightColumn := tui.NewVBox(queuePane, relatedPane)
+	rightColumn.SetSizePolicy(tui.Preferred, tui.Expanding)
 	window := tui.NewHBox(
-		sidebar.Box,
+		sidebarPane,
 		mainFrame,
+		rightColumn,
 	)
 	window.SetTitle("SPOTIFY CLI")
+	if offline.Offline() {
+		window.SetTitle("SPOTIFY CLI - OFFLINE, READ-ONLY")
+		status.Show("Spotify could not be reached, browsing the library cached before, nothing can be played or changed")
+	}
 
-	playBackButtons := []tui.Widget{playback.Playback.Previous, playback.Playback.Play, playback.Playback.Stop, playback.Playback.Next}
-	focusables := append(playBackButtons, sidebar.AlbumList.Table)
-	focusables = append(focusables, search.Focusables...)
-	focusables = append(focusables, playback.Devices.Table)
+	// panes are shown and hidden as they were left, hidden ones cannot be focused
+	panes := player.NewPanes(state.Layout, sidebarPane, playback.DevicesPane, relatedPane, queuePane)
+	shownFocusables := func() []tui.Widget {
+		focusables := playback.Playback.Buttons()
+		if !sidebarPane.Hidden() {
+			focusables = append(focusables, sidebar.AlbumList.Table)
+		}
+		if !playback.DevicesPane.Hidden() {
+			focusables = append(focusables, playback.Devices.Table.Native().(tui.Widget))
+		}
+		focusables = append(focusables, palette.Entry)
+		if !queuePane.Hidden() {
+			focusables = append(focusables, queue.Focusables...)
+		}
+		if !relatedPane.Hidden() {
+			focusables = append(focusables, related.Focusables...)
+		}
+		return focusables
+	}
+	focusables := shownFocusables()
+	refreshPanes()
 
-	tui.DefaultFocusChain.Set(focusables...)
+	focusChain := &player.FocusChain{}
+	focusChain.Set(append(focusables, mainArea.Current().Focusables...)...)
 
-	theme := tui.DefaultTheme
-	theme.SetStyle("box.focused.border", tui.Style{Fg: tui.ColorYellow, Bg: tui.ColorDefault})
-	theme.SetStyle("table.focused.border", tui.Style{Fg: tui.ColorYellow, Bg: tui.ColorDefault})
+	// keys are listed over the window, unless ? is typed into an entry
+	help := player.NewHelpOverlay(cfg.Keys.Bindings(), func() bool {
+		_, typing := focusedWidget(append(focusables, mainArea.Current().Focusables...)).(*tui.Entry)
+		return typing
+	})
+	root := panes.Wrap(window)
+	// vim keys are handled before the focused widget gets them, ui is set once it is created
+	var ui *player.Terminal
+	if cfg.Keys.Preset() == config.VimKeyPreset {
+		vim := player.NewVimKeys(func() tui.Widget {
+			return focusedWidget(append(focusables, mainArea.Current().Focusables...))
+		}, func(back bool) {
+			focusChain.Move(ui, back)
+		})
+		root = vim.Wrap(root)
+	}
+	root = confirmation.Modal(help.Wrap(root))
+	ui = newUI(root, cfg.Theme)
+	ui.SetFocusChain(focusChain)
 
-	ui, err := tui.New(window)
-	if err != nil {
-		panic(err)
+	// clicks are handled by shown widgets, but not while a question or keys are shown
+	if !cfg.DisableMouse {
+		mouse := player.NewMouse(func() []tui.Widget {
+			if confirmation.Pending() || help.Shown() {
+				return nil
+			}
+			return append(focusables, mainArea.Current().Focusables...)
+		}, func(w tui.Widget) {
+			focusChain.Focus(ui, w)
+		})
+		ui.OnMouse(mouse.Handle)
+	}
+	status.OnUpdate(ui.Update)
+
+	// requests are often sent while handling keys, the status is updated once they are handled
+	rateLimit.OnRateLimited(func(retryIn time.Duration) {
+		go ui.Update(func() {
+			status.RateLimited(retryIn)
+		})
+	})
+
+	// Liked Songs keep loading in the background once the UI is running
+	liked.OnUpdate(ui.Update)
+	if err := liked.Load(); err != nil {
+		log.Printf("could not load liked songs, err: %v", err)
+	}
+
+	// while confirmation is asked nothing else can be focused, afterwards
+	// focus goes back to the widget which asked for it
+	var confirming tui.Widget
+	confirmation.OnAsk(func() {
+		help.Hide()
+		confirming = focusedWidget(append(focusables, mainArea.Current().Focusables...))
+		focusChain.Set(confirmation.Focusables...)
+		if len(confirmation.Focusables) > 0 {
+			focusChain.Focus(ui, confirmation.Focusables[0])
+		}
+	})
+	confirmation.OnAnswer(func() {
+		focusChain.Set(append(focusables, mainArea.Current().Focusables...)...)
+		if confirming != nil {
+			focusChain.Focus(ui, confirming)
+		}
+	})
+
+	// while keys are listed only the list can be focused, afterwards focus goes back
+	var helping tui.Widget
+	help.OnShow(func() {
+		helping = focusedWidget(append(focusables, mainArea.Current().Focusables...))
+		focusChain.Set(help.Focusables...)
+		if len(help.Focusables) > 0 {
+			focusChain.Focus(ui, help.Focusables[0])
+		}
+	})
+	help.OnHide(func() {
+		focusChain.Set(append(focusables, mainArea.Current().Focusables...)...)
+		if helping != nil {
+			focusChain.Focus(ui, helping)
+		}
+	})
+
+	mainArea.OnShow(func(view player.View) {
+		tabs.Update()
+		focusChain.Set(append(focusables, view.Focusables...)...)
+		if len(view.Focusables) > 0 {
+			focusChain.Focus(ui, view.Focusables[0])
+		}
+	})
+
+	panes.OnChange(func(layout config.Layout) {
+		err := config.UpdateState(statePath(), cfg, func(file *config.State) error {
+			file.Layout = layout
+			return nil
+		})
+		if err != nil {
+			log.Printf("Could not save layout with %s", err)
+		}
+		focused := focusedWidget(append(focusables, mainArea.Current().Focusables...))
+		focusables = shownFocusables()
+		shown := append(focusables, mainArea.Current().Focusables...)
+		focusChain.Set(shown...)
+		if layout.ShowQueue {
+			if err := queue.Refresh(); err != nil {
+				status.Error(err)
+			}
+		}
+		// focus moves to the main area when the focused pane is hidden
+		for _, w := range shown {
+			if w == focused {
+				return
+			}
+		}
+		if current := mainArea.Current().Focusables; len(current) > 0 {
+			focusChain.Focus(ui, current[0])
+		}
+	})
+
+	artistFilter.OnFiltered(func() {
+		focusChain.Focus(ui, sidebar.AlbumList.Table)
+	})
+
+	// actions bound to keys of the keymap, validated when config was loaded
+	actions := map[string]func(){
+		"play-pause": func() {
+			if err := player.TogglePlayback(ctx, client); err != nil {
+				status.Error(fmt.Errorf("could not toggle playback: %v", err))
+			}
+		},
+		"next": func() {
+			if err := client.Next(ctx); err != nil {
+				status.Error(fmt.Errorf("could not play next track: %v", err))
+			}
+		},
+		"previous": func() {
+			if err := client.Previous(ctx); err != nil {
+				status.Error(fmt.Errorf("could not play previous track: %v", err))
+			}
+		},
+		"search": func() {
+			if err := mainArea.Show("search"); err != nil {
+				status.Error(fmt.Errorf("could not show search: %v", err))
+			}
+		},
+		"library": func() {
+			if sidebarPane.Hidden() {
+				panes.ToggleSidebar()
+			}
+			focusChain.Focus(ui, sidebar.AlbumList.Table)
+		},
+		"devices": func() {
+			if playback.DevicesPane.Hidden() {
+				panes.ToggleDevices()
+			}
+			focusChain.Focus(ui, playback.Devices.Table.Native().(tui.Widget))
+		},
+		"tab-library":      switchTab(tabs, 1, status),
+		"tab-playlists":    switchTab(tabs, 2, status),
+		"tab-search":       switchTab(tabs, 3, status),
+		"tab-queue":        switchTab(tabs, 4, status),
+		"tab-browse":       switchTab(tabs, 5, status),
+		"tab-history":      switchTab(tabs, 6, status),
+		"toggle-sidebar":   panes.ToggleSidebar,
+		"toggle-devices":   panes.ToggleDevices,
+		"toggle-queue":     panes.ToggleQueue,
+		"toggle-related":   panes.ToggleRelated,
+		"sidebar-wider":    panes.WidenSidebar,
+		"sidebar-narrower": panes.NarrowSidebar,
+		"palette": func() {
+			focusChain.Focus(ui, palette.Entry)
+		},
+		"lyrics": func() {
+			if err := showLyrics(); err != nil {
+				status.Error(fmt.Errorf("could not show lyrics: %v", err))
+			}
+		},
+		"help": help.Show,
+		"quit": func() {
+			shutdown(cancel, ui, webSocketHandler.PlayerShutdown)
+		},
+	}
+	for _, binding := range cfg.Keys.Bindings() {
+		name, action := binding.Name, actions[binding.Name]
+		ui.SetKeybinding(binding.Key, func() {
+			defer player.Recover(name)
+			// while confirmation is asked nothing else can be done, but quitting,
+			// while keys are listed Esc closes the list instead
+			if (confirmation.Pending() && name != "quit") || help.Shown() {
+				return
+			}
+			action()
+		})
+	}
+
+	palette.Register("logout", func(args []string) error {
+		if len(args) != 0 {
+			return fmt.Errorf("logout command takes no arguments, got %v", args)
+		}
+		confirmation.Ask(fmt.Sprintf("Log out of profile %s and remove its cached data?", profile), func() {
+			if err := logout(); err != nil {
+				log.Printf("Could not log out with %s", err)
+				return
+			}
+			shutdown(cancel, ui, webSocketHandler.PlayerShutdown)
+		})
+		return nil
+	})
+
+	// the application is started again with the other profile once it quits
+	switchTo := ""
+
+	// features which need permissions user has not granted ask to log in again with them,
+	// the token is removed and the application is started again asking for all of them
+	var missingScopes []string
+	grant := func(scopes []string) {
+		err := config.UpdateState(statePath(), cfg, func(file *config.State) error {
+			for _, scope := range scopes {
+				if !contains(file.Scopes, scope) {
+					file.Scopes = append(file.Scopes, scope)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			log.Printf("Could not save granted permissions with %s", err)
+			return
+		}
+		if err := tokenStore().Delete(); err != nil {
+			log.Printf("Could not remove token with %s", err)
+			return
+		}
+		switchTo = profile
+		shutdown(cancel, ui, webSocketHandler.PlayerShutdown)
 	}
+	scopes.OnMissing(func(missing player.MissingScopeError) {
+		// requests are often sent while handling keys, the question waits for them to be handled
+		go ui.Update(func() {
+			for _, scope := range missing.Scopes {
+				if !contains(missingScopes, scope) {
+					missingScopes = append(missingScopes, scope)
+				}
+			}
+			if confirmation.Pending() {
+				return
+			}
+			question := fmt.Sprintf("%s needs permission %s. Log in again to grant it?", missing.Feature, strings.Join(missing.Scopes, ", "))
+			confirmation.Ask(question, func() {
+				grant(missingScopes)
+			})
+		})
+	})
+	palette.Register("grant", func(args []string) error {
+		if len(args) == 0 {
+			if len(missingScopes) == 0 {
+				return fmt.Errorf("grant command takes permissions, none were missing so far, known are %s", strings.Join(player.OptionalScopes(), ", "))
+			}
+			args = missingScopes
+		}
+		for _, scope := range args {
+			if !contains(player.OptionalScopes(), scope) {
+				return fmt.Errorf("unknown permission %s, known are %s", scope, strings.Join(player.OptionalScopes(), ", "))
+			}
+		}
+		confirmation.Ask(fmt.Sprintf("Log in again to grant permission %s?", strings.Join(args, ", ")), func() {
+			grant(args)
+		})
+		return nil
+	})
+
+	profiles.OnSwitch(func(name string) {
+		switchTo = name
+		shutdown(cancel, ui, webSocketHandler.PlayerShutdown)
+	})
 
-	ui.SetKeybinding("Esc", func() {
+	runUI(ui, cfg.Refresh.UIInterval())
+	if switchTo != "" {
+		restart(switchTo, debugMode)
+	}
+}
+
+// showFailure shows why the application could not start instead of the player, and
+// restarts it or quits depending on what the user chooses. It does not return.
+func showFailure(reason error, cfg *config.Config) {
+	log.Printf("Could not start with %s", reason)
+	failure := player.NewFailure(reason)
+	focusChain := &player.FocusChain{}
+	focusChain.Set(failure.Focusables...)
+
+	ui := newUI(failure.Box, cfg.Theme)
+	ui.SetFocusChain(focusChain)
+
+	chosen := player.FailureQuit
+	failure.OnChoose(func(action string) {
+		chosen = action
 		ui.Quit()
-		webSocketHandler.PlayerShutdown <- true
+	})
+	for _, binding := range cfg.Keys.Bindings() {
+		if binding.Name == "quit" {
+			ui.SetKeybinding(binding.Key, ui.Quit)
+		}
+	}
+	runUI(ui, cfg.Refresh.UIInterval())
+
+	switch chosen {
+	case player.FailureRetry:
+		restart(profile, debugMode)
+	case player.FailureLogIn:
+		if err := tokenStore().Delete(); err != nil {
+			log.Fatalf("Quiting, could not remove token: %v", err)
+		}
+		restart(profile, debugMode)
+	case player.FailureDebug:
+		restart(profile, true)
+	}
+	os.Exit(1)
+}
+
+// logout removes the token of the profile and its cached data. Spotify does not let applications
+// revoke tokens, access of the application is revoked at https://www.spotify.com/account/apps/.
+func logout() error {
+	if err := tokenStore().Delete(); err != nil {
+		return err
+	}
+	if err := cache.NewStore(cacheDir()).Clear(); err != nil {
+		return err
+	}
+	if profile == config.DefaultProfile {
+		return nil
+	}
+	// profiles are listed after their cache directories
+	if err := os.Remove(cacheDir()); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("could not remove cache directory: %v", err)
+	}
+	return nil
+}
+
+// runDaemon keeps the session and serves commands on the socket of the profile, until it is
+// interrupted. What is played is polled, so that status is given right away.
+func runDaemon(ctx context.Context, client player.SpotifyClient, cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("daemon", flag.ContinueOnError)
+	interval := flags.Duration("interval", 2*time.Second, "How often what is played and the devices are polled.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *interval <= 0 {
+		return fmt.Errorf("interval has to be positive, got %s", *interval)
+	}
+	listener, err := daemon.Listen(socketPath())
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	interrupted := make(chan os.Signal, 1)
+	signal.Notify(interrupted, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-interrupted
+		cancel()
+	}()
+
+	polling := player.NewPollingClient(client)
+	runHooks(ctx, cfg.Hooks, polling)
+	go polling.Poll(ctx, *interval)
+	fmt.Fprintf(output, "Daemon of profile %s listens on %s\n", profile, socketPath())
+	return daemon.Serve(ctx, listener, func(ctx context.Context, name string, args []string, out io.Writer) error {
+		err := player.RunCommand(ctx, polling, name, args, out)
+		// playback may have been changed, it is requested again until polled
+		if name != "status" {
+			polling.Forget()
+		}
+		if err != nil {
+			// clients exit with the code of the error, as if they ran the command
+			return &player.CommandError{Code: player.ExitCode(err), Err: err}
+		}
+		return nil
+	})
+}
+
+// runBatch runs commands read from the standard input with run, quitting with the exit code of
+// the one which failed.
+func runBatch(aliases config.Aliases, run func(name string, args []string) error) {
+	if flag.NArg() != 1 {
+		log.Printf("Quiting, batch command reads commands from the standard input, got arguments %v", flag.Args()[1:])
+		os.Exit(player.ExitUsage)
+	}
+	if err := player.RunBatch(os.Stdin, aliases, run); err != nil {
+		log.Printf("Quiting, %v", err)
+		os.Exit(player.ExitCode(err))
+	}
+}
+
+// newLyricsProvider creates the configured provider of lyrics, commands are killed after
+// the hook timeout.
+func newLyricsProvider(cfg *config.Config, proxy *url.URL) lyrics.Provider {
+	if cfg.Lyrics.Provider == "command" {
+		return lyrics.Command{Hook: hooks.Hook{Command: cfg.Lyrics.Command, Timeout: cfg.Hooks.CommandTimeout()}}
+	}
+	lrclib := lyrics.NewLRCLIB(cfg.Lyrics.URL)
+	if proxy != nil {
+		lrclib.SetProxy(proxy)
+	}
+	return lrclib
+}
+
+// runHooks runs the hook configured for track changes each time the polling client finds
+// that another track plays, hooks do not hold polling.
+func runHooks(ctx context.Context, cfg config.Hooks, polling *player.PollingClient) {
+	if cfg.TrackChange == "" {
 		return
+	}
+	hook := hooks.Hook{Command: cfg.TrackChange, Timeout: cfg.CommandTimeout()}
+	polling.OnTrackChange(func(item *player.PlaybackItem) {
+		go func() {
+			if err := hook.Run(ctx, player.HookEnv(item)); err != nil {
+				log.Printf("Could not run track change hook with %s", err)
+			}
+		}()
 	})
+}
+
+// configCommand lists, prints or changes settings of the config file, i.e. "set theme.preset ocean".
+// Settings overridden with flags or environment variables are not taken into account.
+func configCommand(args []string, out io.Writer) error {
+	usage := fmt.Errorf("config command is one of: list, get <key>, set <key> <value>, got %v", args)
+	if len(args) == 0 {
+		return usage
+	}
+	switch {
+	case args[0] == "list" && len(args) == 1:
+		cfg, err := config.Load(configPath())
+		if err != nil {
+			return err
+		}
+		for _, setting := range cfg.List() {
+			fmt.Fprintf(out, "%s = %s\n", setting.Key, setting.Value)
+		}
+		return nil
+	case args[0] == "get" && len(args) == 2:
+		cfg, err := config.Load(configPath())
+		if err != nil {
+			return err
+		}
+		value, err := cfg.Get(args[1])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, value)
+		return nil
+	case args[0] == "set" && len(args) == 3:
+		return config.Update(configPath(), func(cfg *config.Config) error {
+			return cfg.Set(args[1], args[2])
+		})
+	default:
+		return usage
+	}
+}
+
+// restart replaces the process with the application using the given profile, modes
+// given on the command line are kept and debug mode is turned on when asked for.
+func restart(name string, debug bool) {
+	executable, err := os.Executable()
+	if err != nil {
+		log.Fatalf("Quiting, could not locate executable to restart with profile %s: %v", name, err)
+	}
+	args := []string{executable, "-profile", name}
+	if configFile != "" {
+		args = append(args, "-config", configFile)
+	}
+	if debug {
+		args = append(args, "-debug")
+	}
+	if headlessMode {
+		args = append(args, "-headless")
+	}
+	if err := syscall.Exec(executable, args, os.Environ()); err != nil {
+		log.Fatalf("Quiting, could not restart with profile %s: %v", name, err)
+	}
+}
+
+// focusedWidget returns the focused one of the given widgets, nil if none is focused.
+func focusedWidget(widgets []tui.Widget) tui.Widget {
+	for _, w := range widgets {
+		if w.IsFocused() {
+			return w
+		}
+	}
+	return nil
+}
+
+// scrobbleStates passes states of the web player to the scrobbler,
+// returned channel receives the same states afterwards.
+func scrobbleStates(scrobbler *scrobble.Scrobbler, states chan *web.WebPlaybackState) chan *web.WebPlaybackState {
+	scrobbled := make(chan *web.WebPlaybackState)
+	// submitting may take a while, it should not hold the player
+	listens := make(chan scrobble.Listen, 16)
+	go func() {
+		for listen := range listens {
+			scrobbler.Update(listen, time.Now())
+		}
+	}()
+	go func() {
+		for state := range states {
+			select {
+			case listens <- scrobble.Listen{
+				ArtistName:  state.CurrentArtistName,
+				TrackName:   state.CurrentTrackName,
+				ReleaseName: state.CurrentAlbumName,
+			}:
+			default:
+				log.Printf("Scrobbler is busy, skipping player state")
+			}
+			scrobbled <- state
+		}
+	}()
+	return scrobbled
+}
+
+// switchTab returns action switching to the tab with the given number, failures are shown in the status bar.
+func switchTab(tabs *player.Tabs, number int, status *player.StatusBar) func() {
+	return func() {
+		if err := tabs.Switch(number); err != nil {
+			status.Error(fmt.Errorf("could not switch tab: %v", err))
+		}
+	}
+}
+
+// refreshOnTrackChange calls refresh each time the web player starts playing another
+// track, returned channel receives the same states afterwards.
+func refreshOnTrackChange(refresh func(), states chan *web.WebPlaybackState) chan *web.WebPlaybackState {
+	refreshed := make(chan *web.WebPlaybackState)
+	changes := make(chan struct{}, 1)
+	go func() {
+		for range changes {
+			refresh()
+		}
+	}()
+	go func() {
+		track := ""
+		for state := range states {
+			if state.CurrentTrackName != track {
+				track = state.CurrentTrackName
+				select {
+				case changes <- struct{}{}:
+				default: // refresh is already pending
+				}
+			}
+			refreshed <- state
+		}
+	}()
+	return refreshed
+}
 
+func newUI(root tui.Widget, theme config.Theme) *player.Terminal {
+	resolved, _ := theme.Resolve() // validated when config was loaded
+	player.ApplyTheme(resolved)
+
+	// a panic while keys are handled is shown instead of quitting
+	ui, err := player.NewTerminal(player.Recovering(root))
+	if err != nil {
+		log.Fatalf("Quiting, could not create terminal interface: %v", err)
+	}
+	return ui
+}
+
+func runUI(ui tui.UI, interval time.Duration) {
 	go func() {
-		for range time.Tick(500 * time.Millisecond) {
+		for range time.Tick(interval) {
 			ui.Update(func() {})
 		}
 	}()
 
 	if err := ui.Run(); err != nil {
-		panic(err)
+		log.Fatalf("Quiting, could not run terminal interface: %v", err)
+	}
+}
+
+// shutdown quits the ui, cancelling requests in flight, and closes the web player unless it
+// never connected, i.e. in offline mode.
+func shutdown(cancel context.CancelFunc, ui tui.UI, playerShutdown chan bool) {
+	cancel()
+	ui.Quit()
+	select {
+	case playerShutdown <- true:
+	default:
 	}
+}
+
+// runKiosk runs locked-down jukebox, which does not quit on Esc
+// and does not give access to the library.
+func runKiosk(ctx context.Context, cancel context.CancelFunc, client player.SpotifyClient, cfg *config.Config, nowPlaying *player.NowPlaying, playerShutdown chan bool) {
+	kiosk := player.NewKiosk(ctx, client, cfg.Kiosk.PIN, nowPlaying)
+	window := tui.NewVBox(kiosk.Box)
+	window.SetTitle("SPOTIFY CLI - JUKEBOX")
+
+	focusChain := &player.FocusChain{}
+	focusChain.Set(kiosk.Focusables...)
+
+	ui := newUI(window, cfg.Theme)
+	ui.SetFocusChain(focusChain)
+
+	quit := func() {
+		shutdown(cancel, ui, playerShutdown)
+	}
+	kiosk.OnUnlock(func(ok bool) {
+		if ok {
+			quit()
+			return
+		}
+		focusChain.Set(kiosk.Focusables...)
+		if len(kiosk.Focusables) > 0 {
+			focusChain.Focus(ui, kiosk.Focusables[0])
+		}
+	})
+	ui.SetKeybinding("Ctrl+Q", func() {
+		if !kiosk.RequiresPIN() {
+			quit()
+			return
+		}
+		focusChain.Set(append(kiosk.Focusables, kiosk.Unlock)...)
+		focusChain.Focus(ui, kiosk.Unlock)
+	})
 
+	runUI(ui, cfg.Refresh.UIInterval())
 }
@@ -3,8 +3,9 @@ module github.com/jedruniu/spotify-cli
 go 1.12
 
 require (
+	github.com/BurntSushi/toml v0.3.1
 	github.com/gdamore/encoding v0.0.0-20151215212835-b23993cbb635 // indirect
-	github.com/gdamore/tcell v1.0.0 // indirect
+	github.com/gdamore/tcell v1.0.0
 	github.com/gobuffalo/envy v1.9.0 // indirect
 	github.com/gobuffalo/packd v1.0.0 // indirect
 	github.com/gobuffalo/packr v1.30.1
@@ -23,11 +24,10 @@ require (
 	github.com/smartystreets/goconvey v1.6.4 // indirect
 	github.com/spf13/cobra v1.0.0 // indirect
 	github.com/spf13/pflag v1.0.5 // indirect
-	github.com/stretchr/testify v1.5.1 // indirect
-	github.com/zmb3/spotify v0.0.0-20180212041948-79deba8533f6
-	golang.org/x/crypto v0.0.0-20200604202706-70a84ac30bf9 // indirect
+	github.com/zmb3/spotify v1.3.0
+	golang.org/x/crypto v0.0.0-20200604202706-70a84ac30bf9
 	golang.org/x/net v0.0.0-20200226121028-0de0cce0169b // indirect
-	golang.org/x/oauth2 v0.0.0-20190604053449-0f29369cfe45
+	golang.org/x/oauth2 v0.0.0-20200107190931-bf48bf16ab8d
 	golang.org/x/sync v0.0.0-20200317015054-43a5402ce75a // indirect
 	golang.org/x/sys v0.0.0-20200620081246-981b61492c35 // indirect
 	google.golang.org/appengine v1.6.5 // indirect
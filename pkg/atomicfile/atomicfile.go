@@ -0,0 +1,42 @@
+// Package atomicfile writes files so that they are either fully written or
+// left untouched, even when the application crashes in the middle of a write.
+package atomicfile
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// WriteFile writes data to a temporary file next to the file under given path,
+// which is renamed to that path once data is safely on disk.
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	tmp, err := ioutil.TempFile(dir, "."+filepath.Base(path)+".tmp")
+	if err != nil {
+		return fmt.Errorf("could not create temporary file: %v", err)
+	}
+	// Removing fails once the file is renamed, which is fine.
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("could not write temporary file: %v", err)
+	}
+	err = os.Chmod(tmp.Name(), perm)
+	if err != nil {
+		return fmt.Errorf("could not set permissions of temporary file: %v", err)
+	}
+	err = os.Rename(tmp.Name(), path)
+	if err != nil {
+		return fmt.Errorf("could not replace %s: %v", path, err)
+	}
+	return nil
+}
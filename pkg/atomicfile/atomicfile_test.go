@@ -0,0 +1,43 @@
+package atomicfile
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFileReplacesFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spotify-cli-atomicfile")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "file.json")
+	for _, content := range []string{"first", "second"} {
+		if err := WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("Did not expect to fail, but it did with %v", err)
+		}
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil || string(data) != "second" {
+		t.Fatalf("Expected file to be replaced, got %q, %v", data, err)
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.Mode().Perm() != 0600 {
+		t.Fatalf("Expected file to have given permissions, got %v, %v", info.Mode(), err)
+	}
+	// Nothing but the written file is left behind
+	files, _ := ioutil.ReadDir(dir)
+	if len(files) != 1 {
+		t.Fatalf("Expected temporary files to be removed, got %d files", len(files))
+	}
+}
+
+func TestWriteFileLeavesFileWhenDirectoryIsMissing(t *testing.T) {
+	path := filepath.Join(os.TempDir(), "spotify-cli-not-existing", "file.json")
+	if err := WriteFile(path, []byte("data"), 0600); err == nil {
+		t.Fatalf("Expected to fail when directory is missing, but it didn't")
+	}
+}
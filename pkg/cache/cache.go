@@ -0,0 +1,143 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/jedruniu/spotify-cli/pkg/atomicfile"
+	"github.com/jedruniu/spotify-cli/pkg/dirs"
+)
+
+// DefaultDir returns directory in which cached data is stored
+// when no other directory is given.
+func DefaultDir() (string, error) {
+	return dirs.Cache()
+}
+
+// SchemaVersion is the version of format in which entries are saved.
+// Entries saved in older versions are migrated when loaded.
+const SchemaVersion = 1
+
+// Migration upgrades data of an entry saved in the previous schema version.
+type Migration func(name string, data json.RawMessage) (json.RawMessage, error)
+
+// migrations[i] upgrades entries of version i to version i+1. Entries of
+// version 0 were saved as bare JSON, before versions were introduced.
+var migrations = []Migration{
+	func(name string, data json.RawMessage) (json.RawMessage, error) { return data, nil },
+}
+
+// versionedEntry is the format in which entries are saved.
+type versionedEntry struct {
+	Version int             `json:"version"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// Store keeps data between application runs, each entry is kept
+// as JSON file inside of the store directory. Entries are written
+// atomically, along with the schema version.
+type Store struct {
+	dir string
+}
+
+// NewStore creates store keeping data inside of the given directory.
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Load decodes entry of the given name into v. Missing entry
+// is not an error, v is left untouched in such case. Entry saved
+// in an older schema version is migrated and saved again.
+func (s *Store) Load(name string, v interface{}) error {
+	data, err := ioutil.ReadFile(s.path(name))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not read cache entry %s: %v", name, err)
+	}
+	entry := decodeEntry(data)
+	if entry.Version > SchemaVersion {
+		return fmt.Errorf("cache entry %s was saved by a newer version of the application", name)
+	}
+	migrated := entry.Version < SchemaVersion
+	for ; entry.Version < SchemaVersion; entry.Version++ {
+		entry.Data, err = migrations[entry.Version](name, entry.Data)
+		if err != nil {
+			return fmt.Errorf("could not migrate cache entry %s to version %d: %v", name, entry.Version+1, err)
+		}
+	}
+	err = json.Unmarshal(entry.Data, v)
+	if err != nil {
+		return fmt.Errorf("could not decode cache entry %s: %v", name, err)
+	}
+	if migrated {
+		return s.write(name, entry)
+	}
+	return nil
+}
+
+// decodeEntry decodes versioned entry, data which is not one is treated
+// as entry saved before versions were introduced.
+func decodeEntry(data []byte) versionedEntry {
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(data, &fields) == nil && len(fields) == 2 && fields["version"] != nil && fields["data"] != nil {
+		entry := versionedEntry{}
+		if json.Unmarshal(data, &entry) == nil {
+			return entry
+		}
+	}
+	return versionedEntry{Version: 0, Data: data}
+}
+
+// Save encodes v and stores it as entry of the given name.
+func (s *Store) Save(name string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("could not encode cache entry %s: %v", name, err)
+	}
+	return s.write(name, versionedEntry{Version: SchemaVersion, Data: data})
+}
+
+// Clear removes all entries of the store, subdirectories (i.e. caches of other profiles) are left.
+func (s *Store) Clear() error {
+	files, err := ioutil.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not list cache entries: %v", err)
+	}
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		if err := os.Remove(filepath.Join(s.dir, file.Name())); err != nil {
+			return fmt.Errorf("could not remove cache entry %s: %v", file.Name(), err)
+		}
+	}
+	return nil
+}
+
+func (s *Store) write(name string, entry versionedEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("could not encode cache entry %s: %v", name, err)
+	}
+	err = os.MkdirAll(s.dir, 0700)
+	if err != nil {
+		return fmt.Errorf("could not create cache directory: %v", err)
+	}
+	err = atomicfile.WriteFile(s.path(name), data, 0600)
+	if err != nil {
+		return fmt.Errorf("could not write cache entry %s: %v", name, err)
+	}
+	return nil
+}
+
+func (s *Store) path(name string) string {
+	return filepath.Join(s.dir, name+".json")
+}
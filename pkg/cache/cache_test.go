@@ -0,0 +1,117 @@
+package cache
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestStoreSaveAndLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spotify-cli-cache")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	store := NewStore(filepath.Join(dir, "nested"))
+	saved := map[string]int{"first": 1, "second": 2}
+	if err := store.Save("entry", saved); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+
+	loaded := map[string]int{}
+	if err := store.Load("entry", &loaded); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if !reflect.DeepEqual(saved, loaded) {
+		t.Fatalf("Expected to load %v, loaded %v", saved, loaded)
+	}
+}
+
+func TestStoreLoadMissingEntry(t *testing.T) {
+	store := NewStore(filepath.Join(os.TempDir(), "spotify-cli-not-existing"))
+	loaded := map[string]int{"untouched": 1}
+	if err := store.Load("missing", &loaded); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if loaded["untouched"] != 1 {
+		t.Fatalf("Expected value to be left untouched, got %v", loaded)
+	}
+}
+
+func TestStoreMigratesUnversionedEntry(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spotify-cli-cache")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Entry saved before schema versions were introduced
+	path := filepath.Join(dir, "entry.json")
+	if err := ioutil.WriteFile(path, []byte(`{"first": 1}`), 0600); err != nil {
+		t.Fatalf("Could not write cache entry: %v", err)
+	}
+	store := NewStore(dir)
+	loaded := map[string]int{}
+	if err := store.Load("entry", &loaded); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if loaded["first"] != 1 {
+		t.Fatalf("Expected to load unversioned entry, loaded %v", loaded)
+	}
+	data, _ := ioutil.ReadFile(path)
+	if string(data) != `{"version":1,"data":{"first":1}}` {
+		t.Fatalf("Expected migrated entry to be saved again, got %s", data)
+	}
+}
+
+func TestStoreRejectsNewerEntry(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spotify-cli-cache")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "entry.json")
+	if err := ioutil.WriteFile(path, []byte(`{"version": 1000, "data": {"first": 1}}`), 0600); err != nil {
+		t.Fatalf("Could not write cache entry: %v", err)
+	}
+	loaded := map[string]int{}
+	if err := NewStore(dir).Load("entry", &loaded); err == nil {
+		t.Fatalf("Expected to fail loading entry of newer version, but loaded %v", loaded)
+	}
+	data, _ := ioutil.ReadFile(path)
+	if string(data) != `{"version": 1000, "data": {"first": 1}}` {
+		t.Fatalf("Expected entry of newer version to be left untouched, got %s", data)
+	}
+}
+
+func TestStoreClear(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spotify-cli-cache")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	store := NewStore(dir)
+	other := NewStore(filepath.Join(dir, "profiles", "work"))
+	for _, s := range []*Store{store, other} {
+		if err := s.Save("entry", 1); err != nil {
+			t.Fatalf("Did not expect to fail, but it did with %v", err)
+		}
+	}
+	if err := store.Clear(); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if _, err := os.Stat(store.path("entry")); !os.IsNotExist(err) {
+		t.Fatalf("Expected entry to be removed, got %v", err)
+	}
+	if _, err := os.Stat(other.path("entry")); err != nil {
+		t.Fatalf("Expected entry in the subdirectory to be left, got %v", err)
+	}
+	if err := NewStore(filepath.Join(dir, "missing")).Clear(); err != nil {
+		t.Fatalf("Did not expect to fail clearing missing store, but it did with %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Aliases maps alias name to the command line it stands for.
+type Aliases map[string]string
+
+// Expand replaces first of the given arguments with the command line it is
+// aliased to. Aliases may refer to other aliases, cycles are reported as errors.
+func (a Aliases) Expand(args []string) ([]string, error) {
+	seen := map[string]bool{}
+	for len(args) > 0 {
+		commandLine, ok := a[args[0]]
+		if !ok {
+			break
+		}
+		if seen[args[0]] {
+			return nil, fmt.Errorf("alias %q refers to itself", args[0])
+		}
+		seen[args[0]] = true
+
+		expanded, err := SplitCommandLine(commandLine)
+		if err != nil {
+			return nil, fmt.Errorf("could not expand alias %q: %v", args[0], err)
+		}
+		args = append(expanded, args[1:]...)
+	}
+	return args, nil
+}
+
+// SplitCommandLine splits command line into arguments the way shell does,
+// honoring single and double quotes.
+func SplitCommandLine(commandLine string) ([]string, error) {
+	args := []string{}
+	var current strings.Builder
+	var quote rune
+	inArg := false
+	for _, r := range commandLine {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			current.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote = r
+			inArg = true
+		case unicode.IsSpace(r):
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in %q", commandLine)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitCommandLine(t *testing.T) {
+	cases := []struct {
+		commandLine  string
+		expectedArgs []string
+	}{
+		{"", []string{}},
+		{"next", []string{"next"}},
+		{"  device   kitchen ", []string{"device", "kitchen"}},
+		{`device "Kitchen speaker"`, []string{"device", "Kitchen speaker"}},
+		{`status --format '{artist} - {title}'`, []string{"status", "--format", "{artist} - {title}"}},
+		{`say ""`, []string{"say", ""}},
+	}
+	for _, c := range cases {
+		args, err := SplitCommandLine(c.commandLine)
+		if err != nil {
+			t.Fatalf("Did not expect to fail for %q, but it did with %v", c.commandLine, err)
+		}
+		if !reflect.DeepEqual(args, c.expectedArgs) {
+			t.Errorf("Expected %q to be split into %#v, got %#v", c.commandLine, c.expectedArgs, args)
+		}
+	}
+}
+
+func TestSplitCommandLineFailsOnUnterminatedQuote(t *testing.T) {
+	_, err := SplitCommandLine(`device "Kitchen speaker`)
+	if err == nil {
+		t.Fatalf("Expected to fail, but it didn't")
+	}
+}
+
+func TestAliasesExpand(t *testing.T) {
+	aliases := Aliases{
+		"np":      "status --format '{artist} - {title}'",
+		"kitchen": `device "Kitchen speaker"`,
+		"k":       "kitchen",
+	}
+	cases := []struct {
+		args         []string
+		expectedArgs []string
+	}{
+		{[]string{"np"}, []string{"status", "--format", "{artist} - {title}"}},
+		{[]string{"np", "--json"}, []string{"status", "--format", "{artist} - {title}", "--json"}},
+		{[]string{"k"}, []string{"device", "Kitchen speaker"}},
+		{[]string{"next"}, []string{"next"}},
+		{[]string{}, []string{}},
+	}
+	for _, c := range cases {
+		args, err := aliases.Expand(c.args)
+		if err != nil {
+			t.Fatalf("Did not expect to fail for %v, but it did with %v", c.args, err)
+		}
+		if !reflect.DeepEqual(args, c.expectedArgs) {
+			t.Errorf("Expected %v to be expanded into %#v, got %#v", c.args, c.expectedArgs, args)
+		}
+	}
+}
+
+func TestAliasesExpandFailsOnCycle(t *testing.T) {
+	aliases := Aliases{"a": "b", "b": "a --flag"}
+	_, err := aliases.Expand([]string{"a"})
+	if err == nil {
+		t.Fatalf("Expected to fail, but it didn't")
+	}
+}
@@ -0,0 +1,455 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jedruniu/spotify-cli/pkg/atomicfile"
+	"github.com/jedruniu/spotify-cli/pkg/dirs"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Config holds user settings read from the configuration file.
+type Config struct {
+	// Version of the configuration format, files without it are of version 0.
+	Version int `toml:"version"`
+	// Aliases maps alias name to the command it expands to,
+	// i.e. np = "status --format '{artist} - {title}'".
+	Aliases Aliases `toml:"aliases"`
+	Kiosk   Kiosk   `toml:"kiosk"`
+	Charts  Charts  `toml:"charts"`
+	// Recommendations selects provider deciding what to play next.
+	Recommendations Recommendations `toml:"recommendations"`
+	// ListenBrainz enables submission of listens when token is given.
+	ListenBrainz ListenBrainz `toml:"listenbrainz"`
+	// Inbox enables song requests through a collaborative playlist when playlist is given.
+	Inbox Inbox `toml:"inbox"`
+	// Hooks are commands run when something happens in the player, i.e. another track plays.
+	Hooks Hooks `toml:"hooks"`
+	// Lyrics selects provider of lyrics of the played track.
+	Lyrics Lyrics `toml:"lyrics"`
+	// TimeZone is an IANA time zone name, i.e. "Europe/Warsaw", in which
+	// times are displayed. Local time zone is used when it is empty.
+	TimeZone string `toml:"timezone"`
+	// PlaylistFolders and PinnedAlbums are kept in the state file of the profile once they
+	// are changed from the application, until then they are read from here.
+	PlaylistFolders []PlaylistFolder `toml:"playlist_folders"`
+	PinnedAlbums    []string         `toml:"pinned_albums"`
+	// Auth selects the flow in which user logs in to Spotify.
+	Auth Auth `toml:"auth"`
+	// Spotify holds the application registered in Spotify dashboard and the device to play on.
+	Spotify Spotify `toml:"spotify"`
+	// Proxy is URL of HTTP or SOCKS5 proxy through which logging in, Spotify API and
+	// ListenBrainz are reached, i.e. "socks5://localhost:1080". HTTPS_PROXY, HTTP_PROXY
+	// and NO_PROXY environment variables are honored when it is empty.
+	Proxy string `toml:"proxy"`
+	// Refresh holds how often the interface and devices are refreshed.
+	Refresh Refresh `toml:"refresh"`
+	// Theme holds colors of the interface.
+	Theme Theme `toml:"theme"`
+	// Keys maps actions to keys bound to them.
+	Keys Keys `toml:"keys"`
+	// DisableMouse leaves the mouse to the terminal, which selects text with it, instead of
+	// clicking and scrolling the interface.
+	DisableMouse bool `toml:"disable_mouse"`
+}
+
+// Spotify holds settings of the application registered in Spotify dashboard.
+type Spotify struct {
+	// ClientID and ClientSecret of the application, SPOTIFY_CLIENT_ID and
+	// SPOTIFY_SECRET environment variables are used when they are empty.
+	ClientID     string `toml:"client_id"`
+	ClientSecret string `toml:"client_secret"`
+	// RedirectHost and RedirectPort make up redirect URI of the application,
+	// http://<host>:<port>/spotify-cli. localhost and 8888 are used when they are empty.
+	RedirectHost string `toml:"redirect_host"`
+	RedirectPort int    `toml:"redirect_port"`
+	// FallbackPorts are tried in order when the redirect port is already in use,
+	// redirect URIs with each of them have to be added to the application.
+	FallbackPorts []int `toml:"fallback_ports"`
+	// DefaultDevice is a name of the device playback is transferred to at
+	// startup, the web player of the application is used when it is empty.
+	DefaultDevice string `toml:"default_device"`
+	// APIURL is the base URL of Spotify Web API, i.e. of a local mock server in end-to-end
+	// tests or of a corporate gateway, https://api.spotify.com/v1/ is used when it is empty.
+	APIURL string `toml:"api_url"`
+	// Market is the country code, i.e. PL, search and browse results are given for, instead
+	// of the one Spotify guesses. Locale, i.e. pl_PL, is the language of browse categories.
+	Market string `toml:"market"`
+	Locale string `toml:"locale"`
+}
+
+// Defaults used when no redirect host or port is configured.
+const (
+	DefaultRedirectHost = "localhost"
+	DefaultRedirectPort = 8888
+)
+
+// Host returns the host of redirect URI of the application.
+func (app Spotify) Host() string {
+	if app.RedirectHost == "" {
+		return DefaultRedirectHost
+	}
+	return app.RedirectHost
+}
+
+// Port returns the port of redirect URI of the application.
+func (app Spotify) Port() int {
+	if app.RedirectPort == 0 {
+		return DefaultRedirectPort
+	}
+	return app.RedirectPort
+}
+
+// Refresh holds refresh intervals, in milliseconds.
+type Refresh struct {
+	// UI is how often the interface is redrawn, 500 is used when it is 0.
+	UI int `toml:"ui"`
+	// Devices is how often list of devices is fetched again, it is not when it is 0.
+	Devices int `toml:"devices"`
+}
+
+// DefaultUIRefreshInterval is used when no interface refresh interval is configured.
+var DefaultUIRefreshInterval = 500 * time.Millisecond
+
+// UIInterval returns how often the interface should be redrawn.
+func (refresh Refresh) UIInterval() time.Duration {
+	if refresh.UI == 0 {
+		return DefaultUIRefreshInterval
+	}
+	return time.Duration(refresh.UI) * time.Millisecond
+}
+
+// DevicesInterval returns how often list of devices should be fetched, 0 means never.
+func (refresh Refresh) DevicesInterval() time.Duration {
+	return time.Duration(refresh.Devices) * time.Millisecond
+}
+
+var (
+	marketCode = regexp.MustCompile(`^[A-Z]{2}$`)
+	localeCode = regexp.MustCompile(`^[a-z]{2}_[A-Z]{2}$`)
+)
+
+// Validate checks whether settings can be used, it is called again once they are
+// overridden, i.e. with flags.
+func (cfg *Config) Validate() error {
+	if _, err := cfg.Location(); err != nil {
+		return err
+	}
+	if _, err := cfg.Auth.PKCE(); err != nil {
+		return err
+	}
+	for _, port := range append([]int{cfg.Spotify.Port()}, cfg.Spotify.FallbackPorts...) {
+		if port < 1 || port > 65535 {
+			return fmt.Errorf("redirect port %d is not between 1 and 65535", port)
+		}
+	}
+	if strings.ContainsAny(cfg.Spotify.Host(), ":/") {
+		return fmt.Errorf("redirect host %s cannot contain port nor path", cfg.Spotify.Host())
+	}
+	if cfg.Spotify.APIURL != "" {
+		api, err := url.Parse(cfg.Spotify.APIURL)
+		if err != nil || (api.Scheme != "http" && api.Scheme != "https") || api.Host == "" {
+			return fmt.Errorf("API URL %s is not an http or https URL", cfg.Spotify.APIURL)
+		}
+	}
+	if cfg.Spotify.Market != "" && !marketCode.MatchString(cfg.Spotify.Market) {
+		return fmt.Errorf("market %s is not a two letter country code, i.e. PL", cfg.Spotify.Market)
+	}
+	if cfg.Spotify.Locale != "" && !localeCode.MatchString(cfg.Spotify.Locale) {
+		return fmt.Errorf("locale %s is not a language followed by a country code, i.e. pl_PL", cfg.Spotify.Locale)
+	}
+	if _, err := cfg.ProxyURL(); err != nil {
+		return err
+	}
+	if cfg.Refresh.UI < 0 || cfg.Refresh.Devices < 0 {
+		return fmt.Errorf("refresh intervals cannot be negative, got ui %d and devices %d", cfg.Refresh.UI, cfg.Refresh.Devices)
+	}
+	if err := cfg.Lyrics.Validate(); err != nil {
+		return err
+	}
+	if err := cfg.Keys.Validate(); err != nil {
+		return err
+	}
+	_, err := cfg.Theme.Resolve()
+	return err
+}
+
+// Auth holds settings of logging in to Spotify.
+type Auth struct {
+	// Flow is either "code", authorization code flow which needs the client secret,
+	// or "pkce", authorization code flow with PKCE which needs only the client ID.
+	// "code" is used when it is empty.
+	Flow string `toml:"flow"`
+	// Scopes are permissions asked for on top of the ones needed for enabled features,
+	// the ones user grants from the application are kept in the state file instead.
+	Scopes []string `toml:"scopes"`
+	// Passphrase, when true, encrypts the token file used when there is no keyring with
+	// a passphrase, instead of identity of the machine. It is asked for on start, unless
+	// it is given with SPOTIFY_CLI_PASSPHRASE environment variable.
+	Passphrase bool `toml:"passphrase"`
+}
+
+// Authorization flows which can be configured.
+const (
+	AuthFlowCode = "code"
+	AuthFlowPKCE = "pkce"
+)
+
+// PKCE tells whether authorization code flow with PKCE is configured.
+func (auth Auth) PKCE() (bool, error) {
+	switch auth.Flow {
+	case "", AuthFlowCode:
+		return false, nil
+	case AuthFlowPKCE:
+		return true, nil
+	default:
+		return false, fmt.Errorf("unknown auth flow %s, expected %s or %s", auth.Flow, AuthFlowCode, AuthFlowPKCE)
+	}
+}
+
+// Kiosk holds settings of the kiosk mode.
+type Kiosk struct {
+	// PIN has to be given to leave the kiosk, when empty Ctrl+Q is enough.
+	PIN string `toml:"pin"`
+}
+
+// Charts holds settings of chart playlists quick access.
+type Charts struct {
+	// Country is ISO 3166-1 alpha-2 code of the country whose Top 50
+	// is available as a shortcut, i.e. "PL".
+	Country string `toml:"country"`
+	// Shortcuts maps shortcut name to ID of a chart playlist.
+	Shortcuts map[string]string `toml:"shortcuts"`
+}
+
+// PlaylistFolder groups playlists locally, Spotify Web API does not
+// expose folders created in Spotify apps.
+type PlaylistFolder struct {
+	Name string `toml:"name"`
+	// Playlists are IDs or names of the playlists in the folder.
+	Playlists []string `toml:"playlists"`
+	// Collapsed tells whether playlists of the folder are hidden.
+	Collapsed bool `toml:"collapsed"`
+}
+
+// Recommendations holds settings of track recommendations.
+type Recommendations struct {
+	// Provider is a name of recommendation provider, i.e. "spotify" or "history".
+	Provider string `toml:"provider"`
+}
+
+// ListenBrainz holds settings of ListenBrainz listen submission.
+type ListenBrainz struct {
+	// Token is the user token from ListenBrainz profile page.
+	Token string `toml:"token"`
+	// URL of ListenBrainz API, official one is used when empty.
+	URL string `toml:"url"`
+}
+
+// Inbox holds settings of the song request inbox.
+type Inbox struct {
+	// Playlist is ID of a collaborative playlist, tracks added to it by
+	// others are queued and removed from the playlist.
+	Playlist string `toml:"playlist"`
+	// PollInterval is how often, in seconds, playlist is checked for new requests.
+	PollInterval int `toml:"poll_interval"`
+}
+
+// DefaultInboxPollInterval is used when no poll interval is configured.
+var DefaultInboxPollInterval = 30 * time.Second
+
+// Interval returns how often playlist should be checked for new requests.
+func (inbox Inbox) Interval() time.Duration {
+	if inbox.PollInterval <= 0 {
+		return DefaultInboxPollInterval
+	}
+	return time.Duration(inbox.PollInterval) * time.Second
+}
+
+// Hooks holds commands run when something happens in the player.
+type Hooks struct {
+	// TrackChange is run with sh -c (cmd /C on Windows) each time another track starts
+	// playing, with the track in SPOTIFY_TITLE, SPOTIFY_ARTIST, SPOTIFY_ALBUM and SPOTIFY_URI.
+	TrackChange string `toml:"track_change"`
+	// Timeout is how long, in seconds, a hook may run before it is killed.
+	Timeout int `toml:"timeout"`
+	// PollInterval is how often, in seconds, the player checks what is played, the daemon
+	// checks it as often as it polls.
+	PollInterval int `toml:"poll_interval"`
+}
+
+// Lyrics holds settings of fetching lyrics.
+type Lyrics struct {
+	// Provider is "lrclib", which fetches lyrics from LRCLIB, or "command", which runs Command.
+	// "lrclib" is used when it is empty.
+	Provider string `toml:"provider"`
+	// URL of LRCLIB API, official one is used when empty.
+	URL string `toml:"url"`
+	// Command is run with sh -c (cmd /C on Windows) with the track in SPOTIFY_ARTIST,
+	// SPOTIFY_TITLE, SPOTIFY_ALBUM and SPOTIFY_DURATION, and prints its lyrics. It is
+	// killed after the hook timeout.
+	Command string `toml:"command"`
+}
+
+// Validate checks that the provider is known and that the command is given for it.
+func (lyrics Lyrics) Validate() error {
+	switch lyrics.Provider {
+	case "", "lrclib":
+		return nil
+	case "command":
+		if lyrics.Command == "" {
+			return fmt.Errorf("lyrics provider command needs the command to run")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown lyrics provider %s, known are lrclib and command", lyrics.Provider)
+	}
+}
+
+// Defaults used when no hook timeout or poll interval is configured.
+var (
+	DefaultHookTimeout      = 10 * time.Second
+	DefaultHookPollInterval = 5 * time.Second
+)
+
+// CommandTimeout returns how long a hook may run.
+func (hooks Hooks) CommandTimeout() time.Duration {
+	if hooks.Timeout <= 0 {
+		return DefaultHookTimeout
+	}
+	return time.Duration(hooks.Timeout) * time.Second
+}
+
+// Interval returns how often the player should check what is played.
+func (hooks Hooks) Interval() time.Duration {
+	if hooks.PollInterval <= 0 {
+		return DefaultHookPollInterval
+	}
+	return time.Duration(hooks.PollInterval) * time.Second
+}
+
+// Version is the current version of the configuration format.
+const Version = 1
+
+// migrations[i] upgrades configuration of version i to version i+1.
+var migrations = []func(*Config) error{
+	func(*Config) error { return nil },
+}
+
+// migrate upgrades configuration to the current version.
+func (cfg *Config) migrate() error {
+	if cfg.Version > Version {
+		return fmt.Errorf("config version %d is newer than supported version %d", cfg.Version, Version)
+	}
+	for ; cfg.Version < Version; cfg.Version++ {
+		if err := migrations[cfg.Version](cfg); err != nil {
+			return fmt.Errorf("could not migrate config to version %d: %v", cfg.Version+1, err)
+		}
+	}
+	return nil
+}
+
+// Location returns time zone in which times should be displayed.
+func (cfg *Config) Location() (*time.Location, error) {
+	if cfg.TimeZone == "" {
+		return time.Local, nil
+	}
+	location, err := time.LoadLocation(cfg.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("could not load time zone %s: %v", cfg.TimeZone, err)
+	}
+	return location, nil
+}
+
+// ProxyURL returns URL of the configured proxy, nil when none is configured.
+func (cfg *Config) ProxyURL() (*url.URL, error) {
+	if cfg.Proxy == "" {
+		return nil, nil
+	}
+	proxy, err := url.Parse(cfg.Proxy)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse proxy URL: %v", err)
+	}
+	switch proxy.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("unknown proxy scheme %q, expected http, https or socks5", proxy.Scheme)
+	}
+	if proxy.Host == "" {
+		return nil, fmt.Errorf("proxy URL %s has no host", cfg.Proxy)
+	}
+	return proxy, nil
+}
+
+// DefaultPath returns location of the configuration file
+// used when no other location is given.
+func DefaultPath() (string, error) {
+	dir, err := dirs.Config()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.toml"), nil
+}
+
+// Load reads configuration from the file under given path. Missing
+// file is not an error, empty configuration is returned instead.
+// Configuration of an older version is migrated to the current one.
+func Load(path string) (*Config, error) {
+	cfg := &Config{Aliases: Aliases{}}
+	_, err := toml.DecodeFile(path, cfg)
+	if os.IsNotExist(err) {
+		cfg.Version = Version
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not decode config file %s: %v", path, err)
+	}
+	if err := cfg.migrate(); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Update loads configuration from the file under given path, changes it with the function
+// and saves it back, so that settings overridden i.e. with flags are not written to the file.
+// File is left untouched when the change fails.
+func Update(path string, change func(*Config) error) error {
+	cfg, err := Load(path)
+	if err != nil {
+		return err
+	}
+	if err := change(cfg); err != nil {
+		return err
+	}
+	return Save(path, cfg)
+}
+
+// Save writes configuration to the file under given path, file is either
+// fully written or left untouched.
+func Save(path string, cfg *Config) error {
+	buf := &bytes.Buffer{}
+	err := toml.NewEncoder(buf).Encode(cfg)
+	if err != nil {
+		return fmt.Errorf("could not encode config: %v", err)
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return fmt.Errorf("could not create config directory: %v", err)
+	}
+	err = atomicfile.WriteFile(path, buf.Bytes(), 0600)
+	if err != nil {
+		return fmt.Errorf("could not write config file %s: %v", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,282 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadMissingFile(t *testing.T) {
+	cfg, err := Load(filepath.Join(os.TempDir(), "spotify-cli-missing", "config.toml"))
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if len(cfg.Aliases) != 0 || cfg.Kiosk.PIN != "" {
+		t.Fatalf("Expected empty config, got %#v", cfg)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spotify-cli")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config.toml")
+	content := `
+disable_mouse = true
+
+[aliases]
+np = "status"
+
+[kiosk]
+pin = "1234"
+`
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Could not write config file: %v", err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if cfg.Aliases["np"] != "status" {
+		t.Fatalf("Expected alias to be loaded, got %#v", cfg.Aliases)
+	}
+	if cfg.Kiosk.PIN != "1234" {
+		t.Fatalf("Expected kiosk PIN to be loaded, got %q", cfg.Kiosk.PIN)
+	}
+	if !cfg.DisableMouse {
+		t.Fatalf("Expected mouse to be disabled")
+	}
+}
+
+func TestLocation(t *testing.T) {
+	cfg := &Config{}
+	location, err := cfg.Location()
+	if err != nil || location != time.Local {
+		t.Fatalf("Expected local time zone when none is configured, got %v, %v", location, err)
+	}
+
+	cfg.TimeZone = "America/New_York"
+	location, err = cfg.Location()
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	// 2021-03-14 is the day of DST change in New York
+	winter := time.Date(2021, 3, 14, 6, 0, 0, 0, time.UTC).In(location)
+	summer := time.Date(2021, 3, 14, 8, 0, 0, 0, time.UTC).In(location)
+	if winter.Format("15:04 MST") != "01:00 EST" || summer.Format("15:04 MST") != "04:00 EDT" {
+		t.Fatalf("Expected times to honor DST, got %s and %s", winter.Format("15:04 MST"), summer.Format("15:04 MST"))
+	}
+
+	cfg.TimeZone = "Not/Existing"
+	if _, err := cfg.Location(); err == nil {
+		t.Fatalf("Expected to fail on unknown time zone, but it didn't")
+	}
+}
+
+func TestProxyURL(t *testing.T) {
+	if proxy, err := (&Config{}).ProxyURL(); err != nil || proxy != nil {
+		t.Fatalf("Expected no proxy when none is configured, got %v, %v", proxy, err)
+	}
+	proxy, err := (&Config{Proxy: "socks5://localhost:1080"}).ProxyURL()
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if proxy.Scheme != "socks5" || proxy.Host != "localhost:1080" {
+		t.Fatalf("Expected SOCKS5 proxy at localhost:1080, got %v", proxy)
+	}
+	for _, invalid := range []string{"ftp://proxy:21", "localhost:3128", "http://"} {
+		if _, err := (&Config{Proxy: invalid}).ProxyURL(); err == nil {
+			t.Fatalf("Expected to fail on proxy %s, but it didn't", invalid)
+		}
+	}
+}
+
+func TestHooksDurations(t *testing.T) {
+	if (Hooks{}).CommandTimeout() != DefaultHookTimeout || (Hooks{}).Interval() != DefaultHookPollInterval {
+		t.Fatalf("Expected default timeout and interval when none are configured")
+	}
+	hooks := Hooks{Timeout: 3, PollInterval: 2}
+	if hooks.CommandTimeout() != 3*time.Second || hooks.Interval() != 2*time.Second {
+		t.Fatalf("Expected configured timeout of 3s and interval of 2s, got %v and %v", hooks.CommandTimeout(), hooks.Interval())
+	}
+}
+
+func TestLyricsValidate(t *testing.T) {
+	for _, lyrics := range []Lyrics{{}, {Provider: "lrclib"}, {Provider: "command", Command: "lyrics.sh"}} {
+		if err := lyrics.Validate(); err != nil {
+			t.Fatalf("Did not expect to fail, but it did with %v", err)
+		}
+	}
+	for _, lyrics := range []Lyrics{{Provider: "command"}, {Provider: "genius"}} {
+		if err := lyrics.Validate(); err == nil {
+			t.Fatalf("Expected %+v to be rejected", lyrics)
+		}
+	}
+}
+
+func TestInboxInterval(t *testing.T) {
+	if interval := (Inbox{}).Interval(); interval != DefaultInboxPollInterval {
+		t.Fatalf("Expected default interval when none is configured, got %v", interval)
+	}
+	if interval := (Inbox{PollInterval: 5}).Interval(); interval != 5*time.Second {
+		t.Fatalf("Expected configured interval of 5s, got %v", interval)
+	}
+}
+
+func TestAuthPKCE(t *testing.T) {
+	cases := []struct {
+		flow     string
+		expected bool
+	}{
+		{"", false},
+		{AuthFlowCode, false},
+		{AuthFlowPKCE, true},
+	}
+	for _, c := range cases {
+		pkce, err := (Auth{Flow: c.flow}).PKCE()
+		if err != nil {
+			t.Fatalf("Did not expect to fail, but it did with %v", err)
+		}
+		if pkce != c.expected {
+			t.Fatalf("Expected PKCE to be %v for flow %q, got %v", c.expected, c.flow, pkce)
+		}
+	}
+	if _, err := (Auth{Flow: "implicit"}).PKCE(); err == nil {
+		t.Fatalf("Expected to fail with unknown flow")
+	}
+}
+
+func TestLoadSpotifySettings(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spotify-cli")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config.toml")
+	content := `
+[spotify]
+client_id = "client"
+redirect_host = "127.0.0.1"
+redirect_port = 9999
+fallback_ports = [9998]
+default_device = "Kitchen"
+
+[refresh]
+devices = 10000
+
+[theme]
+focused = "cyan"
+
+[keys]
+quit = "Ctrl+Q"
+`
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Could not write config file: %v", err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if cfg.Spotify.ClientID != "client" || cfg.Spotify.Host() != "127.0.0.1" || cfg.Spotify.Port() != 9999 || cfg.Spotify.DefaultDevice != "Kitchen" {
+		t.Fatalf("Expected Spotify settings to be loaded, got %#v", cfg.Spotify)
+	}
+	if cfg.Refresh.UIInterval() != DefaultUIRefreshInterval || cfg.Refresh.DevicesInterval() != 10*time.Second {
+		t.Fatalf("Expected default UI and configured devices refresh interval, got %v and %v", cfg.Refresh.UIInterval(), cfg.Refresh.DevicesInterval())
+	}
+	if theme, _ := cfg.Theme.Resolve(); theme.Focused != "cyan" || cfg.Keys.Key("quit") != "Ctrl+Q" || cfg.Keys.Key("palette") != "Ctrl+P" {
+		t.Fatalf("Expected theme and keys to be loaded, got %#v and %#v", cfg.Theme, cfg.Keys)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (&Config{}).Validate(); err != nil {
+		t.Fatalf("Expected defaults to be valid, but got %v", err)
+	}
+	invalid := []*Config{
+		{Spotify: Spotify{RedirectPort: 70000}},
+		{Spotify: Spotify{FallbackPorts: []int{0}}},
+		{Spotify: Spotify{RedirectHost: "localhost:8888"}},
+		{Spotify: Spotify{APIURL: "localhost:9090/v1"}},
+		{Spotify: Spotify{Market: "pl"}},
+		{Spotify: Spotify{Locale: "pl-PL"}},
+		{Refresh: Refresh{Devices: -1}},
+		{Theme: Theme{Focused: "orange"}},
+	}
+	for _, cfg := range invalid {
+		if err := cfg.Validate(); err == nil {
+			t.Fatalf("Expected config %#v to be invalid", cfg)
+		}
+	}
+}
+
+func TestSaveAndLoadMigratedConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spotify-cli")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "nested", "config.toml")
+
+	// Config written before versions were introduced
+	if err := Save(path, &Config{Kiosk: Kiosk{PIN: "1234"}}); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if cfg.Version != Version || cfg.Kiosk.PIN != "1234" {
+		t.Fatalf("Expected config to be migrated to version %d, got %#v", Version, cfg)
+	}
+
+	cfg.Version = Version + 1
+	if err := Save(path, cfg); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Fatalf("Expected to fail loading config of newer version, but it didn't")
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spotify-cli")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config.toml")
+	if err := Save(path, &Config{Kiosk: Kiosk{PIN: "1234"}}); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+
+	err = Update(path, func(cfg *Config) error {
+		cfg.PinnedAlbums = []string{"album"}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if cfg.Kiosk.PIN != "1234" || len(cfg.PinnedAlbums) != 1 {
+		t.Fatalf("Expected change to be saved along with other settings, got %#v", cfg)
+	}
+
+	err = Update(path, func(cfg *Config) error {
+		return cfg.Set("theme.focused", "orange")
+	})
+	if err == nil {
+		t.Fatalf("Expected to fail with invalid change, but it didn't")
+	}
+	if _, err := Load(path); err != nil {
+		t.Fatalf("Expected file to be left untouched, but it could not be loaded with %v", err)
+	}
+}
@@ -0,0 +1,31 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EnvPrefix is the prefix of environment variables overriding settings of the config file.
+const EnvPrefix = "SPOTIFY_CLI_"
+
+// EnvName returns name of the environment variable of the setting under the given key,
+// i.e. SPOTIFY_CLI_SPOTIFY_CLIENT_ID for spotify.client_id, or of the flag with the given name.
+func EnvName(key string) string {
+	return EnvPrefix + strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(key))
+}
+
+// ApplyEnv overrides settings with environment variables given by lookup, i.e. os.LookupEnv.
+// Strings, numbers, booleans and comma separated lists of them can be given, other settings
+// (aliases, chart shortcuts, playlist folders and keys) can only be set in the config file.
+func (cfg *Config) ApplyEnv(lookup func(string) (string, bool)) error {
+	for _, s := range cfg.settings() {
+		env, ok := lookup(EnvName(s.key))
+		if !ok {
+			continue
+		}
+		if err := setValue(s.value, env); err != nil {
+			return fmt.Errorf("could not use %s environment variable: %v", EnvName(s.key), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestApplyEnv(t *testing.T) {
+	env := map[string]string{
+		"SPOTIFY_CLI_SPOTIFY_CLIENT_ID":      "client",
+		"SPOTIFY_CLI_SPOTIFY_FALLBACK_PORTS": "8890, 8891",
+		"SPOTIFY_CLI_REFRESH_UI":             "250",
+		"SPOTIFY_CLI_TIMEZONE":               "Europe/Warsaw",
+		"SPOTIFY_CLI_PINNED_ALBUMS":          "",
+		"SPOTIFY_CLI_VERSION":                "7",
+	}
+	lookup := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+	cfg := &Config{Version: Version, PinnedAlbums: []string{"album"}, Kiosk: Kiosk{PIN: "1234"}}
+
+	if err := cfg.ApplyEnv(lookup); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if cfg.Spotify.ClientID != "client" || !reflect.DeepEqual(cfg.Spotify.FallbackPorts, []int{8890, 8891}) || cfg.Refresh.UI != 250 {
+		t.Fatalf("Expected settings to be overridden, got %#v and %#v", cfg.Spotify, cfg.Refresh)
+	}
+	if cfg.TimeZone != "Europe/Warsaw" || len(cfg.PinnedAlbums) != 0 {
+		t.Fatalf("Expected top-level settings to be overridden, got %q and %v", cfg.TimeZone, cfg.PinnedAlbums)
+	}
+	if cfg.Version != Version || cfg.Kiosk.PIN != "1234" {
+		t.Fatalf("Expected version and settings without variables to be kept, got %d and %q", cfg.Version, cfg.Kiosk.PIN)
+	}
+
+	for name, value := range map[string]string{"SPOTIFY_CLI_REFRESH_UI": "fast", "SPOTIFY_CLI_ALIASES": "np=status"} {
+		env = map[string]string{name: value}
+		if err := (&Config{}).ApplyEnv(lookup); err == nil {
+			t.Fatalf("Expected to fail with %s=%s, but it didn't", name, value)
+		}
+	}
+}
+
+func TestEnvName(t *testing.T) {
+	if name := EnvName("spotify.client_id"); name != "SPOTIFY_CLI_SPOTIFY_CLIENT_ID" {
+		t.Fatalf("Expected variable of the setting, got %s", name)
+	}
+	if name := EnvName("redirect-port"); name != "SPOTIFY_CLI_REDIRECT_PORT" {
+		t.Fatalf("Expected variable of the flag, got %s", name)
+	}
+}
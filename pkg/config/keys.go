@@ -0,0 +1,175 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Keys maps actions to keys bound to them, i.e. quit = "Ctrl+Q". Keys are named like
+// "Esc", "F1" or "Alt+N", actions which are not given are bound to their default keys.
+// The preset entry names one of KeyPresets instead of an action.
+type Keys map[string]string
+
+// keysPreset is the entry of Keys naming the preset.
+const keysPreset = "preset"
+
+// DefaultKeyPreset moves in tables with arrows and between widgets with Tab, it is used
+// when no preset is configured.
+const DefaultKeyPreset = "default"
+
+// VimKeyPreset moves in tables with j, k, g, G, Ctrl+D and Ctrl+U as well, and between
+// widgets with h and l.
+const VimKeyPreset = "vim"
+
+// KeyPresets are built-in ways of moving around the application.
+var KeyPresets = []string{DefaultKeyPreset, VimKeyPreset}
+
+// Action can be bound to a key from anywhere in the application.
+type Action struct {
+	Name        string
+	Default     string
+	Description string
+}
+
+// Actions are all actions which can be bound to keys, in the order they are listed in help.
+var Actions = []Action{
+	{"play-pause", "Alt+P", "Play or pause"},
+	{"next", "Alt+N", "Play next track"},
+	{"previous", "Alt+B", "Play previous track"},
+	{"search", "Alt+S", "Search"},
+	{"library", "Alt+L", "Focus albums in the sidebar"},
+	{"devices", "Alt+D", "Focus devices"},
+	{"lyrics", "Alt+Y", "Show lyrics of the played track"},
+	{"tab-library", "Alt+1", "Switch to the Library tab"},
+	{"tab-playlists", "Alt+2", "Switch to the Playlists tab"},
+	{"tab-search", "Alt+3", "Switch to the Search tab"},
+	{"tab-queue", "Alt+4", "Switch to the Queue tab"},
+	{"tab-browse", "Alt+5", "Switch to the Browse tab"},
+	{"tab-history", "Alt+6", "Switch to the History tab"},
+	{"toggle-sidebar", "F2", "Show or hide albums in the sidebar"},
+	{"toggle-devices", "F3", "Show or hide devices"},
+	{"toggle-queue", "F4", "Show or hide the queue"},
+	{"toggle-related", "F5", "Show or hide related artists"},
+	{"sidebar-wider", "Alt+Right", "Widen the sidebar"},
+	{"sidebar-narrower", "Alt+Left", "Narrow the sidebar"},
+	{"palette", "Ctrl+P", "Focus the command palette"},
+	{"help", "F1", "List keys"},
+	{"quit", "Esc", "Quit"},
+}
+
+// Binding is the key bound to the action.
+type Binding struct {
+	Action
+	Key string
+}
+
+// keyModifiers can be combined with a key, i.e. "Ctrl+Alt+X".
+var keyModifiers = []string{"Shift", "Alt", "Meta", "Ctrl"}
+
+// namedKeys can be bound without modifiers, other keys would be typed into
+// search and the command palette.
+var namedKeys = []string{
+	"Esc", "Enter", "Tab", "Backtab", "Backspace", "Insert", "Delete", "Home", "End", "PgUp", "PgDn",
+	"Up", "Down", "Left", "Right",
+	"F1", "F2", "F3", "F4", "F5", "F6", "F7", "F8", "F9", "F10", "F11", "F12",
+}
+
+// Key returns the key bound to the action.
+func (keys Keys) Key(action string) string {
+	if key, ok := keys[action]; ok {
+		return key
+	}
+	for _, known := range Actions {
+		if known.Name == action {
+			return known.Default
+		}
+	}
+	return ""
+}
+
+// Preset returns the configured preset, DefaultKeyPreset when none is.
+func (keys Keys) Preset() string {
+	if preset, ok := keys[keysPreset]; ok {
+		return preset
+	}
+	return DefaultKeyPreset
+}
+
+// Bindings returns keys bound to all actions, in the order of Actions.
+func (keys Keys) Bindings() []Binding {
+	bindings := []Binding{}
+	for _, action := range Actions {
+		bindings = append(bindings, Binding{Action: action, Key: keys.Key(action.Name)})
+	}
+	return bindings
+}
+
+// Validate checks whether all actions are known, their keys can be bound and each key
+// is bound to one action at most.
+func (keys Keys) Validate() error {
+	if preset := keys.Preset(); !knownKeyPreset(preset) {
+		return fmt.Errorf("unknown keys preset %s, expected one of %s", preset, strings.Join(KeyPresets, ", "))
+	}
+	for action := range keys {
+		if action != keysPreset && !knownAction(action) {
+			return fmt.Errorf("unknown action %s in keys", action)
+		}
+	}
+	bound := map[string]string{}
+	for _, binding := range keys.Bindings() {
+		if err := validateKey(binding.Key); err != nil {
+			return fmt.Errorf("could not bind %s: %v", binding.Name, err)
+		}
+		name := strings.ToLower(binding.Key)
+		if other, ok := bound[name]; ok {
+			return fmt.Errorf("key %s is bound to both %s and %s", binding.Key, other, binding.Name)
+		}
+		bound[name] = binding.Name
+	}
+	return nil
+}
+
+func knownKeyPreset(name string) bool {
+	for _, preset := range KeyPresets {
+		if preset == name {
+			return true
+		}
+	}
+	return false
+}
+
+func knownAction(name string) bool {
+	for _, action := range Actions {
+		if action.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// validateKey checks whether the key is either a named key or any key with modifiers.
+func validateKey(key string) error {
+	parts := strings.Split(key, "+")
+	name := parts[len(parts)-1]
+	for _, modifier := range parts[:len(parts)-1] {
+		if !containsFold(keyModifiers, modifier) {
+			return fmt.Errorf("unknown modifier %q in key %q, expected one of %s", modifier, key, strings.Join(keyModifiers, ", "))
+		}
+	}
+	if containsFold(namedKeys, name) {
+		return nil
+	}
+	if len(parts) == 1 || len([]rune(name)) != 1 {
+		return fmt.Errorf("key %q is neither a named key (%s) nor a single character with a modifier", key, strings.Join(namedKeys, ", "))
+	}
+	return nil
+}
+
+func containsFold(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+package config
+
+import "testing"
+
+func TestKeys(t *testing.T) {
+	keys := Keys{"quit": "Ctrl+Q", "next": "F9"}
+	if err := keys.Validate(); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if keys.Key("quit") != "Ctrl+Q" || keys.Key("palette") != "Ctrl+P" {
+		t.Fatalf("Expected configured and default keys, got %s and %s", keys.Key("quit"), keys.Key("palette"))
+	}
+	bindings := keys.Bindings()
+	if len(bindings) != len(Actions) || bindings[1].Name != "next" || bindings[1].Key != "F9" {
+		t.Fatalf("Expected bindings of all actions in order, got %v", bindings)
+	}
+
+	if keys.Preset() != DefaultKeyPreset {
+		t.Fatalf("Expected default preset, got %s", keys.Preset())
+	}
+	vim := Keys{"preset": VimKeyPreset}
+	if err := vim.Validate(); err != nil || vim.Preset() != VimKeyPreset || len(vim.Bindings()) != len(Actions) {
+		t.Fatalf("Expected vim preset with bindings of all actions, got %s, %v", vim.Preset(), err)
+	}
+
+	invalid := []Keys{
+		{"preset": "emacs"},
+		{"dance": "F2"},
+		{"quit": "q"},
+		{"quit": "Super+Q"},
+		{"quit": "Alt+Qq"},
+		{"quit": "alt+p"},
+	}
+	for _, keys := range invalid {
+		if err := keys.Validate(); err == nil {
+			t.Fatalf("Expected keys %v to be invalid", keys)
+		}
+	}
+}
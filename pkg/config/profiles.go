@@ -0,0 +1,81 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DefaultProfile is the profile used when none is chosen, its files
+// are kept where they were before profiles were introduced.
+const DefaultProfile = "default"
+
+var profileName = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// ValidateProfile checks whether the profile name can be used in file names.
+func ValidateProfile(profile string) error {
+	if !profileName.MatchString(profile) {
+		return fmt.Errorf("profile name %q can only contain letters, digits, - and _", profile)
+	}
+	return nil
+}
+
+// TokenPath returns path of the file which kept the token of the profile in plain text in
+// the given directory, before tokens were kept in the keyring.
+func TokenPath(dir, profile string) string {
+	if profile == DefaultProfile {
+		return filepath.Join(dir, "token.json")
+	}
+	return filepath.Join(dir, "token-"+profile+".json")
+}
+
+// EncryptedTokenPath returns path of the file keeping the encrypted token of the profile in
+// the given directory, used when there is no keyring.
+func EncryptedTokenPath(dir, profile string) string {
+	return strings.TrimSuffix(TokenPath(dir, profile), ".json") + ".enc"
+}
+
+// StatePath returns path of the file keeping state of the profile, i.e. pinned albums, in the given directory.
+func StatePath(dir, profile string) string {
+	if profile == DefaultProfile {
+		return filepath.Join(dir, "state.toml")
+	}
+	return filepath.Join(dir, "state-"+profile+".toml")
+}
+
+// SocketPath returns path of the socket the daemon of the profile listens on in the given directory.
+func SocketPath(dir, profile string) string {
+	if profile == DefaultProfile {
+		return filepath.Join(dir, "daemon.sock")
+	}
+	return filepath.Join(dir, "daemon-"+profile+".sock")
+}
+
+// ProfileCacheDir returns directory of cached data of the profile, inside the given cache directory.
+func ProfileCacheDir(dir, profile string) string {
+	if profile == DefaultProfile {
+		return dir
+	}
+	return filepath.Join(dir, "profiles", profile)
+}
+
+// Profiles returns names of profiles with cache directories inside the given cache directory,
+// sorted by name, i.e. profiles which were used. The default profile is always there.
+func Profiles(dir string) ([]string, error) {
+	files, err := ioutil.ReadDir(filepath.Join(dir, "profiles"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("could not list profiles: %v", err)
+	}
+	profiles := []string{DefaultProfile}
+	for _, file := range files {
+		if file.IsDir() && ValidateProfile(file.Name()) == nil {
+			profiles = append(profiles, file.Name())
+		}
+	}
+	sort.Strings(profiles)
+	return profiles, nil
+}
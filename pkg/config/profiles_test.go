@@ -0,0 +1,54 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestProfiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spotify-cli")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	profiles, err := Profiles(filepath.Join(dir, "missing"))
+	if expected := []string{DefaultProfile}; err != nil || !reflect.DeepEqual(profiles, expected) {
+		t.Fatalf("Expected only default profile in missing directory, got %v, %v", profiles, err)
+	}
+	for _, profile := range []string{"work", "family", "bad name"} {
+		if err := os.MkdirAll(ProfileCacheDir(dir, profile), 0700); err != nil {
+			t.Fatalf("Could not create cache directory: %v", err)
+		}
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "profiles", "notes.txt"), []byte(""), 0600); err != nil {
+		t.Fatalf("Could not write file: %v", err)
+	}
+	profiles, err = Profiles(dir)
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if expected := []string{DefaultProfile, "family", "work"}; !reflect.DeepEqual(profiles, expected) {
+		t.Fatalf("Expected profiles %v, got %v", expected, profiles)
+	}
+	if ProfileCacheDir(dir, DefaultProfile) != dir || ProfileCacheDir(dir, "work") != filepath.Join(dir, "profiles", "work") {
+		t.Fatalf("Expected cache of other profiles than the default one in a subdirectory")
+	}
+	if SocketPath(dir, DefaultProfile) != filepath.Join(dir, "daemon.sock") || SocketPath(dir, "work") != filepath.Join(dir, "daemon-work.sock") {
+		t.Fatalf("Expected socket of each profile to be named after it")
+	}
+	if StatePath(dir, DefaultProfile) != filepath.Join(dir, "state.toml") || StatePath(dir, "work") != filepath.Join(dir, "state-work.toml") {
+		t.Fatalf("Expected state of each profile to be named after it")
+	}
+	if EncryptedTokenPath(dir, "work") != filepath.Join(dir, "token-work.enc") {
+		t.Fatalf("Expected encrypted token next to the plain text one, got %s", EncryptedTokenPath(dir, "work"))
+	}
+	for _, invalid := range []string{"", "../work", "my work"} {
+		if err := ValidateProfile(invalid); err == nil {
+			t.Fatalf("Expected profile name %q to be rejected", invalid)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Setting is a setting of the config file under its key, i.e. spotify.client_id,
+// with its value formatted as text.
+type Setting struct {
+	Key   string
+	Value string
+}
+
+// setting is a field of the config under its key, sections are flattened.
+type setting struct {
+	key   string
+	value reflect.Value
+}
+
+// settings returns settings of the config in the order of its fields, except for the version.
+func (cfg *Config) settings() []setting {
+	return sectionSettings(reflect.ValueOf(cfg).Elem(), "")
+}
+
+func sectionSettings(section reflect.Value, prefix string) []setting {
+	settings := []setting{}
+	for i := 0; i < section.NumField(); i++ {
+		key := section.Type().Field(i).Tag.Get("toml")
+		if key == "" || key == "version" {
+			continue
+		}
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		value := section.Field(i)
+		if value.Kind() == reflect.Struct {
+			settings = append(settings, sectionSettings(value, key)...)
+			continue
+		}
+		settings = append(settings, setting{key: key, value: value})
+	}
+	return settings
+}
+
+// List returns all settings, entries of maps (i.e. keys.quit) are listed one by one.
+// Playlist folders are not listed, they are changed from the application.
+func (cfg *Config) List() []Setting {
+	list := []Setting{}
+	for _, s := range cfg.settings() {
+		switch {
+		case s.value.Kind() == reflect.Map:
+			names := []string{}
+			for _, name := range s.value.MapKeys() {
+				names = append(names, name.String())
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				list = append(list, Setting{Key: s.key + "." + name, Value: s.value.MapIndex(reflect.ValueOf(name)).String()})
+			}
+		case s.value.Kind() == reflect.Slice && s.value.Type().Elem().Kind() == reflect.Struct:
+			// playlist folders
+		default:
+			list = append(list, Setting{Key: s.key, Value: formatValue(s.value)})
+		}
+	}
+	return list
+}
+
+// Get returns value of the setting under the key formatted as text, it is empty when
+// the setting is not set.
+func (cfg *Config) Get(key string) (string, error) {
+	value, entry, err := cfg.lookup(key)
+	if err != nil {
+		return "", err
+	}
+	if value.Kind() == reflect.Map {
+		entryValue := value.MapIndex(reflect.ValueOf(entry))
+		if !entryValue.IsValid() {
+			return "", nil
+		}
+		return entryValue.String(), nil
+	}
+	return formatValue(value), nil
+}
+
+// Set parses the text according to the type of the setting under the key and sets it,
+// the config is validated afterwards. Entry of a map is removed when the text is empty.
+func (cfg *Config) Set(key, text string) error {
+	value, entry, err := cfg.lookup(key)
+	if err != nil {
+		return err
+	}
+	if value.Kind() == reflect.Map {
+		if value.IsNil() {
+			value.Set(reflect.MakeMap(value.Type()))
+		}
+		if text == "" {
+			value.SetMapIndex(reflect.ValueOf(entry), reflect.Value{})
+		} else {
+			value.SetMapIndex(reflect.ValueOf(entry), reflect.ValueOf(text).Convert(value.Type().Elem()))
+		}
+	} else if err := setValue(value, text); err != nil {
+		return fmt.Errorf("could not set %s: %v", key, err)
+	}
+	return cfg.Validate()
+}
+
+// lookup returns the field of the setting under the key, or the map and the name of its entry.
+func (cfg *Config) lookup(key string) (reflect.Value, string, error) {
+	for _, s := range cfg.settings() {
+		if s.key == key {
+			if s.value.Kind() == reflect.Map {
+				return reflect.Value{}, "", fmt.Errorf("%s holds several settings, use %s.<name>", key, key)
+			}
+			if s.value.Kind() == reflect.Slice && s.value.Type().Elem().Kind() == reflect.Struct {
+				return reflect.Value{}, "", fmt.Errorf("%s can only be changed in the config file", key)
+			}
+			return s.value, "", nil
+		}
+		if s.value.Kind() == reflect.Map && strings.HasPrefix(key, s.key+".") {
+			return s.value, strings.TrimPrefix(key, s.key+"."), nil
+		}
+	}
+	return reflect.Value{}, "", fmt.Errorf("unknown setting %s", key)
+}
+
+// formatValue formats the value like setValue parses it.
+func formatValue(value reflect.Value) string {
+	switch value.Kind() {
+	case reflect.Int:
+		return strconv.Itoa(int(value.Int()))
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool())
+	case reflect.Slice:
+		items := []string{}
+		for i := 0; i < value.Len(); i++ {
+			items = append(items, formatValue(value.Index(i)))
+		}
+		return strings.Join(items, ",")
+	default:
+		return value.String()
+	}
+}
+
+// setValue parses the text according to the type of the value and sets it.
+func setValue(value reflect.Value, text string) error {
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(text)
+	case reflect.Int:
+		n, err := strconv.Atoi(strings.TrimSpace(text))
+		if err != nil {
+			return fmt.Errorf("%q is not a number", text)
+		}
+		value.SetInt(int64(n))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(text))
+		if err != nil {
+			return fmt.Errorf("%q is neither true nor false", text)
+		}
+		value.SetBool(b)
+	case reflect.Slice:
+		parts := []string{}
+		if strings.TrimSpace(text) != "" {
+			parts = strings.Split(text, ",")
+		}
+		items := reflect.MakeSlice(value.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := setValue(items.Index(i), strings.TrimSpace(part)); err != nil {
+				return err
+			}
+		}
+		value.Set(items)
+	default:
+		return fmt.Errorf("setting of kind %s can only be set in the config file", value.Kind())
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetAndSet(t *testing.T) {
+	cfg := &Config{Version: Version, Aliases: Aliases{"np": "status"}}
+
+	for key, value := range map[string]string{
+		"spotify.default_device": "Kitchen",
+		"spotify.fallback_ports": "8890, 8891",
+		"theme.preset":           "ocean",
+		"keys.quit":              "Ctrl+Q",
+	} {
+		if err := cfg.Set(key, value); err != nil {
+			t.Fatalf("Did not expect to fail setting %s, but it did with %v", key, err)
+		}
+	}
+	if cfg.Spotify.DefaultDevice != "Kitchen" || !reflect.DeepEqual(cfg.Spotify.FallbackPorts, []int{8890, 8891}) || cfg.Keys["quit"] != "Ctrl+Q" {
+		t.Fatalf("Expected settings to be set, got %#v and %#v", cfg.Spotify, cfg.Keys)
+	}
+	if value, err := cfg.Get("spotify.fallback_ports"); err != nil || value != "8890,8891" {
+		t.Fatalf("Expected list of ports, got %q, %v", value, err)
+	}
+	if value, err := cfg.Get("aliases.np"); err != nil || value != "status" {
+		t.Fatalf("Expected alias, got %q, %v", value, err)
+	}
+	if value, err := cfg.Get("keys.palette"); err != nil || value != "" {
+		t.Fatalf("Expected key which is not set to be empty, got %q, %v", value, err)
+	}
+	if err := cfg.Set("aliases.np", ""); err != nil || len(cfg.Aliases) != 0 {
+		t.Fatalf("Expected alias to be removed, got %v, %v", cfg.Aliases, err)
+	}
+
+	invalid := map[string]string{
+		"spotify.redirect_port": "http",
+		"theme.focused":         "orange",
+		"keys.dance":            "F2",
+		"keys":                  "F2",
+		"playlist_folders":      "Archive",
+		"version":               "2",
+		"spotify.volume":        "50",
+	}
+	for key, value := range invalid {
+		if err := (&Config{}).Set(key, value); err == nil {
+			t.Fatalf("Expected to fail setting %s to %s, but it didn't", key, value)
+		}
+	}
+}
+
+func TestList(t *testing.T) {
+	cfg := &Config{Keys: Keys{"quit": "Ctrl+Q"}, PlaylistFolders: []PlaylistFolder{{Name: "Archive"}}}
+	settings := map[string]string{}
+	for _, setting := range cfg.List() {
+		settings[setting.Key] = setting.Value
+	}
+	if settings["keys.quit"] != "Ctrl+Q" {
+		t.Fatalf("Expected entries of maps to be listed, got %v", settings)
+	}
+	if value, ok := settings["spotify.client_id"]; !ok || value != "" {
+		t.Fatalf("Expected settings which are not set to be listed empty, got %v", settings)
+	}
+	if _, ok := settings["playlist_folders"]; ok {
+		t.Fatalf("Expected playlist folders not to be listed, got %v", settings)
+	}
+}
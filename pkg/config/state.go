@@ -0,0 +1,106 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jedruniu/spotify-cli/pkg/atomicfile"
+
+	"github.com/BurntSushi/toml"
+)
+
+// State holds what is changed from the application, i.e. pinned albums or the layout. It is
+// kept in a file of the profile, so that the configuration file is left as the user wrote it.
+type State struct {
+	// Version of the state file format, files without it are of version 0.
+	Version int `toml:"version"`
+	// PlaylistFolders group playlists in the playlists view.
+	PlaylistFolders []PlaylistFolder `toml:"playlist_folders"`
+	// PinnedAlbums are IDs of albums listed at the top of the sidebar.
+	PinnedAlbums []string `toml:"pinned_albums"`
+	// Layout is how panes of the window were left.
+	Layout Layout `toml:"layout"`
+	// Scopes are permissions user granted to use a feature needing them, they are asked for
+	// from then on.
+	Scopes []string `toml:"scopes"`
+}
+
+// Layout tells which panes of the window are shown and how wide the sidebar is.
+type Layout struct {
+	HideSidebar bool `toml:"hide_sidebar"`
+	HideDevices bool `toml:"hide_devices"`
+	HideRelated bool `toml:"hide_related"`
+	ShowQueue   bool `toml:"show_queue"`
+	// SidebarWidth is the percent of the window width taken by the sidebar, 0 when it
+	// takes as much as the albums need.
+	SidebarWidth int `toml:"sidebar_width"`
+}
+
+// StateVersion is the current version of the state file format.
+const StateVersion = 1
+
+// stateMigrations[i] upgrades state of version i to version i+1.
+var stateMigrations = []func(*State) error{
+	func(*State) error { return nil },
+}
+
+// migrate upgrades state to the current version.
+func (state *State) migrate() error {
+	if state.Version > StateVersion {
+		return fmt.Errorf("state version %d is newer than supported version %d", state.Version, StateVersion)
+	}
+	for ; state.Version < StateVersion; state.Version++ {
+		if err := stateMigrations[state.Version](state); err != nil {
+			return fmt.Errorf("could not migrate state to version %d: %v", state.Version+1, err)
+		}
+	}
+	return nil
+}
+
+// LoadState reads state from the file under given path. Missing file is not an error,
+// folders and pins of the configuration, where older versions kept them, are used instead.
+// State of an older version is migrated to the current one.
+func LoadState(path string, cfg *Config) (*State, error) {
+	state := &State{}
+	_, err := toml.DecodeFile(path, state)
+	if os.IsNotExist(err) {
+		return &State{Version: StateVersion, PlaylistFolders: cfg.PlaylistFolders, PinnedAlbums: cfg.PinnedAlbums}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not decode state file %s: %v", path, err)
+	}
+	if err := state.migrate(); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// UpdateState loads state from the file under given path, changes it with the function and
+// saves it back. File is left untouched when the change fails.
+func UpdateState(path string, cfg *Config, change func(*State) error) error {
+	state, err := LoadState(path, cfg)
+	if err != nil {
+		return err
+	}
+	if err := change(state); err != nil {
+		return err
+	}
+	return SaveState(path, state)
+}
+
+// SaveState writes state to the file under given path, file is either fully written or left untouched.
+func SaveState(path string, state *State) error {
+	buf := &bytes.Buffer{}
+	if err := toml.NewEncoder(buf).Encode(state); err != nil {
+		return fmt.Errorf("could not encode state: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("could not create state directory: %v", err)
+	}
+	if err := atomicfile.WriteFile(path, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("could not write state file %s: %v", path, err)
+	}
+	return nil
+}
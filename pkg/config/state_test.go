@@ -0,0 +1,107 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestState(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spotify-cli")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "state.toml")
+	cfg := &Config{
+		PinnedAlbums:    []string{"album1"},
+		PlaylistFolders: []PlaylistFolder{{Name: "Running", Playlists: []string{"playlist1"}}},
+	}
+	state, err := LoadState(path, cfg)
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if state.Version != StateVersion {
+		t.Fatalf("Expected state of version %d without the state file, got %d", StateVersion, state.Version)
+	}
+	if !reflect.DeepEqual(state.PinnedAlbums, cfg.PinnedAlbums) || !reflect.DeepEqual(state.PlaylistFolders, cfg.PlaylistFolders) {
+		t.Fatalf("Expected pins and folders of the config without the state file, got %+v", state)
+	}
+
+	err = UpdateState(path, cfg, func(state *State) error {
+		state.PinnedAlbums = []string{"album2", "album1"}
+		state.Layout = Layout{HideDevices: true, SidebarWidth: 30}
+		state.Scopes = []string{"user-library-modify"}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	state, err = LoadState(path, &Config{})
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if expected := []string{"album2", "album1"}; !reflect.DeepEqual(state.PinnedAlbums, expected) {
+		t.Fatalf("Expected pins %v, got %v", expected, state.PinnedAlbums)
+	}
+	if expected := (Layout{HideDevices: true, SidebarWidth: 30}); state.Layout != expected {
+		t.Fatalf("Expected layout %+v, got %+v", expected, state.Layout)
+	}
+	if expected := []string{"user-library-modify"}; !reflect.DeepEqual(state.Scopes, expected) {
+		t.Fatalf("Expected granted scopes %v, got %v", expected, state.Scopes)
+	}
+	if !reflect.DeepEqual(state.PlaylistFolders, cfg.PlaylistFolders) {
+		t.Fatalf("Expected folders of the config to be saved along with pins, got %+v", state.PlaylistFolders)
+	}
+}
+
+func TestSaveAndLoadMigratedState(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spotify-cli")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "state.toml")
+
+	// State written before versions were introduced
+	if err := ioutil.WriteFile(path, []byte("pinned_albums = [\"album1\"]\n"), 0600); err != nil {
+		t.Fatalf("Could not write file: %v", err)
+	}
+	state, err := LoadState(path, &Config{})
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if state.Version != StateVersion || !reflect.DeepEqual(state.PinnedAlbums, []string{"album1"}) {
+		t.Fatalf("Expected state to be migrated to version %d, got %#v", StateVersion, state)
+	}
+
+	state.Version = StateVersion + 1
+	if err := SaveState(path, state); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if _, err := LoadState(path, &Config{}); err == nil {
+		t.Fatalf("Expected to fail loading state of newer version, but it didn't")
+	}
+}
+
+func TestStateFailsOnInvalidFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spotify-cli")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "state.toml")
+	if err := ioutil.WriteFile(path, []byte("pinned_albums = "), 0600); err != nil {
+		t.Fatalf("Could not write file: %v", err)
+	}
+	if _, err := LoadState(path, &Config{}); err == nil {
+		t.Fatalf("Expected to fail, but it didn't")
+	}
+	if err := UpdateState(path, &Config{}, func(*State) error { return nil }); err == nil {
+		t.Fatalf("Expected to fail, but it didn't")
+	}
+}
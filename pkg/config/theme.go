@@ -0,0 +1,87 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Theme holds colors of the interface, they are names of terminal colors, i.e. "yellow"
+// or "default". Colors which are not given are taken from the preset.
+type Theme struct {
+	// Preset is a name of a built-in theme, "default" is used when it is empty.
+	Preset string `toml:"preset"`
+	// Border is color of borders of boxes.
+	Border string `toml:"border"`
+	// Focused is color of the border of focused box and of focused button.
+	Focused string `toml:"focused"`
+	// Selected is background color of selected rows, their colors are reversed when it is "reverse".
+	Selected string `toml:"selected"`
+	// NowPlaying is color of the description of currently played item.
+	NowPlaying string `toml:"now_playing"`
+}
+
+// Colors are names of colors which can be used in the theme.
+var Colors = []string{"default", "black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+// SelectedReverse reverses colors of selected rows instead of changing their background.
+const SelectedReverse = "reverse"
+
+// DefaultPreset is used when no preset is configured.
+const DefaultPreset = "default"
+
+// Presets are built-in themes.
+var Presets = map[string]Theme{
+	DefaultPreset: {Border: "default", Focused: "yellow", Selected: SelectedReverse, NowPlaying: "default"},
+	"ocean":       {Border: "blue", Focused: "cyan", Selected: "blue", NowPlaying: "cyan"},
+	"forest":      {Border: "green", Focused: "yellow", Selected: "green", NowPlaying: "green"},
+	"ember":       {Border: "red", Focused: "yellow", Selected: "red", NowPlaying: "yellow"},
+	"mono":        {Border: "default", Focused: "white", Selected: SelectedReverse, NowPlaying: "default"},
+}
+
+// Resolve returns colors of the theme, with those which are not given taken from the preset.
+func (theme Theme) Resolve() (Theme, error) {
+	preset := theme.Preset
+	if preset == "" {
+		preset = DefaultPreset
+	}
+	resolved, ok := Presets[preset]
+	if !ok {
+		names := []string{}
+		for name := range Presets {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return Theme{}, fmt.Errorf("unknown theme preset %s, expected one of %s", preset, strings.Join(names, ", "))
+	}
+	resolved.Preset = preset
+	colors := []struct {
+		name     string
+		color    string
+		resolved *string
+	}{
+		{"border", theme.Border, &resolved.Border},
+		{"focused", theme.Focused, &resolved.Focused},
+		{"selected", theme.Selected, &resolved.Selected},
+		{"now_playing", theme.NowPlaying, &resolved.NowPlaying},
+	}
+	for _, c := range colors {
+		if c.color == "" {
+			continue
+		}
+		if !knownColor(c.color) && !(c.name == "selected" && c.color == SelectedReverse) {
+			return Theme{}, fmt.Errorf("unknown %s color %s, expected one of %s", c.name, c.color, strings.Join(Colors, ", "))
+		}
+		*c.resolved = c.color
+	}
+	return resolved, nil
+}
+
+func knownColor(name string) bool {
+	for _, color := range Colors {
+		if color == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,28 @@
+package config
+
+import "testing"
+
+func TestThemeResolve(t *testing.T) {
+	theme, err := (Theme{}).Resolve()
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if theme.Preset != DefaultPreset || theme.Focused != "yellow" || theme.Selected != SelectedReverse {
+		t.Fatalf("Expected default preset when nothing is configured, got %#v", theme)
+	}
+
+	theme, err = (Theme{Preset: "ocean", Focused: "magenta", Selected: SelectedReverse}).Resolve()
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	expected := Theme{Preset: "ocean", Border: "blue", Focused: "magenta", Selected: SelectedReverse, NowPlaying: "cyan"}
+	if theme != expected {
+		t.Fatalf("Expected %#v, got %#v", expected, theme)
+	}
+
+	for _, invalid := range []Theme{{Preset: "neon"}, {Border: "orange"}, {NowPlaying: SelectedReverse}} {
+		if _, err := invalid.Resolve(); err == nil {
+			t.Fatalf("Expected theme %#v to be invalid", invalid)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+// Package daemon serves commands over a Unix socket, so that they are run in a process which
+// is already logged in instead of each of them logging in and starting on its own.
+//
+// A client sends a request, a JSON object in a line, and receives responses in the following
+// lines: output of the command as it is written, then the last response telling whether the
+// command failed and with which exit code. The command is cancelled once the client disconnects.
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Request asks to run the command with the arguments.
+type Request struct {
+	Command string   `json:"command"`
+	Args    []string `json:"args"`
+}
+
+// Response carries output of the command, or tells that it is done and whether it failed.
+type Response struct {
+	Output string `json:"output,omitempty"`
+	Done   bool   `json:"done,omitempty"`
+	Error  string `json:"error,omitempty"`
+	Code   int    `json:"code,omitempty"`
+}
+
+// Error tells why the command run by the daemon failed and the code the client exits with.
+// Errors returned by runners can give the code with ExitCode method, it is 1 otherwise.
+type Error struct {
+	Message string
+	Code    int
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// ExitCode returns the code the client exits with.
+func (e *Error) ExitCode() int {
+	return e.Code
+}
+
+// Runner runs the command with the arguments, writing its output to out, until the context is done.
+type Runner func(ctx context.Context, command string, args []string, out io.Writer) error
+
+// Listen listens on the socket with the given path, which only the user can connect to. Socket
+// left by a daemon which did not stop cleanly is removed, listening fails when a daemon runs.
+func Listen(path string) (net.Listener, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("could not create directory of the socket: %v", err)
+	}
+	if conn, err := net.Dial("unix", path); err == nil {
+		conn.Close()
+		return nil, fmt.Errorf("daemon already listens on %s", path)
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("could not remove stale socket: %v", err)
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("could not listen on %s: %v", path, err)
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("could not restrict access to the socket: %v", err)
+	}
+	return listener, nil
+}
+
+// Serve runs commands requested by clients connecting to the listener with the runner, each
+// client in its own goroutine, until the context is done. The listener is closed then.
+func Serve(ctx context.Context, listener net.Listener, run Runner) error {
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("could not accept connection: %v", err)
+		}
+		go serve(ctx, conn, run)
+	}
+}
+
+// serve runs command requested by the client, cancelling it once the client disconnects.
+func serve(ctx context.Context, conn net.Conn, run Runner) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	var request Request
+	line, err := reader.ReadBytes('\n')
+	if err == nil {
+		err = json.Unmarshal(line, &request)
+	}
+	out := &responseWriter{encoder: json.NewEncoder(conn)}
+	if err != nil {
+		out.send(Response{Done: true, Error: fmt.Sprintf("could not read request: %v", err)})
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		// nothing else is sent by the client, reading stops when it disconnects
+		io.Copy(ioutil.Discard, reader)
+		cancel()
+	}()
+	done := Response{Done: true}
+	if err := run(ctx, request.Command, request.Args, out); err != nil {
+		done.Error, done.Code = err.Error(), 1
+		if coded, ok := err.(interface{ ExitCode() int }); ok {
+			done.Code = coded.ExitCode()
+		}
+	}
+	// the client which disconnected is not responded to
+	if err := out.send(done); err != nil && ctx.Err() == nil {
+		log.Printf("Could not respond to %s command with %s", request.Command, err)
+	}
+}
+
+// responseWriter sends everything written to it as output of the command.
+type responseWriter struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	if err := w.send(Response{Output: string(p)}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (w *responseWriter) send(response Response) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.encoder.Encode(response)
+}
+
+// ErrNotRunning is returned by Dial when no daemon listens on the socket.
+var ErrNotRunning = errors.New("daemon is not running")
+
+// Client runs commands in the daemon.
+type Client struct {
+	conn net.Conn
+}
+
+// Dial connects to the daemon listening on the socket with the given path.
+func Dial(path string) (*Client, error) {
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return nil, ErrNotRunning
+	}
+	return &Client{conn: conn}, nil
+}
+
+// Run runs the command with the arguments in the daemon, writing its output to out. The error
+// is *Error when the command failed, or tells that the daemon could not be asked to run it.
+func (client *Client) Run(command string, args []string, out io.Writer) error {
+	if err := json.NewEncoder(client.conn).Encode(Request{Command: command, Args: args}); err != nil {
+		return fmt.Errorf("could not send request to daemon: %v", err)
+	}
+	decoder := json.NewDecoder(client.conn)
+	for {
+		var response Response
+		if err := decoder.Decode(&response); err != nil {
+			return fmt.Errorf("could not read response of daemon: %v", err)
+		}
+		if response.Output != "" {
+			if _, err := io.WriteString(out, response.Output); err != nil {
+				return err
+			}
+		}
+		if response.Done {
+			if response.Error != "" {
+				return &Error{Message: response.Error, Code: response.Code}
+			}
+			return nil
+		}
+	}
+}
+
+// Close disconnects from the daemon, which cancels the command if it still runs.
+func (client *Client) Close() error {
+	return client.conn.Close()
+}
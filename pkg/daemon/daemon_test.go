@@ -0,0 +1,101 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestServeRunsCommandsOfClients(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spotify-cli-daemon")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "daemon.sock")
+	listener, err := Listen(path)
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if _, err := Listen(path); err == nil {
+		t.Fatalf("Expected listening to fail while daemon listens")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelled := make(chan struct{})
+	served := make(chan error)
+	go func() {
+		served <- Serve(ctx, listener, func(ctx context.Context, command string, args []string, out io.Writer) error {
+			switch command {
+			case "echo":
+				fmt.Fprintln(out, strings.Join(args, " "))
+				return nil
+			case "follow":
+				fmt.Fprintln(out, "following")
+				<-ctx.Done()
+				close(cancelled)
+				return nil
+			case "pause":
+				return &Error{Message: "there is no active device", Code: 4}
+			default:
+				return fmt.Errorf("unknown command %s", command)
+			}
+		})
+	}()
+
+	run := func(command string, args ...string) (string, error) {
+		client, err := Dial(path)
+		if err != nil {
+			t.Fatalf("Did not expect to fail, but it did with %v", err)
+		}
+		defer client.Close()
+		out := &bytes.Buffer{}
+		err = client.Run(command, args, out)
+		return out.String(), err
+	}
+	if out, err := run("echo", "kid", "a"); err != nil || out != "kid a\n" {
+		t.Fatalf("Expected output of the command, got %q, %v", out, err)
+	}
+	if _, err := run("rewind"); err == nil || err.Error() != "unknown command rewind" {
+		t.Fatalf("Expected error of the command, got %v", err)
+	} else if err.(*Error).Code != 1 {
+		t.Fatalf("Expected command to fail with exit code 1, got %d", err.(*Error).Code)
+	}
+	if _, err := run("pause"); err == nil || err.(*Error).Code != 4 {
+		t.Fatalf("Expected command to fail with its exit code, got %v", err)
+	}
+
+	client, err := Dial(path)
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	following := &notifyingWriter{written: make(chan struct{})}
+	go client.Run("follow", nil, following)
+	<-following.written
+	client.Close()
+	<-cancelled
+
+	cancel()
+	if err := <-served; err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if _, err := Dial(path); err != ErrNotRunning {
+		t.Fatalf("Expected daemon not to run once stopped, got %v", err)
+	}
+}
+
+// notifyingWriter tells when something was written to it.
+type notifyingWriter struct {
+	written chan struct{}
+}
+
+func (w *notifyingWriter) Write(p []byte) (int, error) {
+	close(w.written)
+	return len(p), nil
+}
@@ -0,0 +1,195 @@
+// Package dirs locates directories in which the application keeps its files, following the
+// XDG Base Directory Specification: configuration under $XDG_CONFIG_HOME, cached data under
+// $XDG_CACHE_HOME, tokens and logs under $XDG_STATE_HOME and sockets under $XDG_RUNTIME_DIR.
+// Without the variables, their usual locations are used, or their equivalents on macOS and Windows.
+package dirs
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+const name = "spotify-cli"
+
+// getenv, goos and userHomeDir are replaced in tests, so that directories of every OS are located.
+var (
+	getenv      = os.Getenv
+	goos        = runtime.GOOS
+	userHomeDir = os.UserHomeDir
+)
+
+// Config returns directory of the configuration file.
+func Config() (string, error) {
+	return locate("XDG_CONFIG_HOME", map[string][]string{
+		"darwin":  {"Library", "Application Support", name},
+		"windows": {"%AppData%", name},
+		"":        {".config", name},
+	})
+}
+
+// Cache returns directory of cached data, which can be removed at any time.
+func Cache() (string, error) {
+	return locate("XDG_CACHE_HOME", map[string][]string{
+		"darwin":  {"Library", "Caches", name},
+		"windows": {"%LocalAppData%", name, "cache"},
+		"":        {".cache", name},
+	})
+}
+
+// State returns directory of data which should outlive restarts, but is not worth
+// backing up, i.e. tokens and logs.
+func State() (string, error) {
+	return locate("XDG_STATE_HOME", map[string][]string{
+		"darwin":  {"Library", "Application Support", name},
+		"windows": {"%LocalAppData%", name, "state"},
+		"":        {".local", "state", name},
+	})
+}
+
+// Runtime returns directory of files which are only needed while the application runs, i.e.
+// sockets. The state directory is used when $XDG_RUNTIME_DIR is not set, as the specification
+// gives no default.
+func Runtime() (string, error) {
+	if dir := getenv("XDG_RUNTIME_DIR"); filepath.IsAbs(dir) {
+		return filepath.Join(dir, name), nil
+	}
+	return State()
+}
+
+// locate returns directory under the XDG variable, or the default one of the OS, given
+// relative to the home directory or to the Windows variable it starts with. Relative
+// paths in XDG variables are ignored, as the specification requires.
+func locate(variable string, defaults map[string][]string) (string, error) {
+	if dir := getenv(variable); filepath.IsAbs(dir) {
+		return filepath.Join(dir, name), nil
+	}
+	path, ok := defaults[goos]
+	if !ok {
+		path = defaults[""]
+	}
+	if windowsVariable := path[0]; windowsVariable[0] == '%' {
+		dir := getenv(windowsVariable[1 : len(windowsVariable)-1])
+		if dir == "" {
+			return "", fmt.Errorf("could not find directory, %s is not set", windowsVariable)
+		}
+		return filepath.Join(append([]string{dir}, path[1:]...)...), nil
+	}
+	home, err := userHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("could not find home directory: %v", err)
+	}
+	return filepath.Join(append([]string{home}, path...)...), nil
+}
+
+// legacyConfig and legacyCache return directories in which files were kept before
+// the specification was followed, on every OS.
+func legacyConfig() (string, error) {
+	home, err := userHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("could not find home directory: %v", err)
+	}
+	return filepath.Join(home, ".config", name), nil
+}
+
+func legacyCache() (string, error) {
+	home, err := userHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("could not find home directory: %v", err)
+	}
+	return filepath.Join(home, ".cache", name), nil
+}
+
+// Migrate moves the configuration file, cached data and tokens from where they were kept
+// before the specification was followed. Files which are already in the new locations are
+// left untouched, files which cannot be moved are logged and left where they were.
+func Migrate() error {
+	oldConfig, err := legacyConfig()
+	if err != nil {
+		return err
+	}
+	oldCache, err := legacyCache()
+	if err != nil {
+		return err
+	}
+	config, err := Config()
+	if err != nil {
+		return err
+	}
+	cache, err := Cache()
+	if err != nil {
+		return err
+	}
+	state, err := State()
+	if err != nil {
+		return err
+	}
+	moves := map[string]string{
+		filepath.Join(oldConfig, "config.toml"): filepath.Join(config, "config.toml"),
+		oldCache:                                cache,
+	}
+	// tokens of all profiles, kept in plain text by older versions or encrypted without keyring
+	tokens, err := filepath.Glob(filepath.Join(oldConfig, "token*"))
+	if err != nil {
+		return fmt.Errorf("could not list tokens: %v", err)
+	}
+	for _, token := range tokens {
+		moves[token] = filepath.Join(state, filepath.Base(token))
+	}
+	for from, to := range moves {
+		if err := move(from, to); err != nil {
+			log.Printf("Could not move %s to %s with %s", from, to, err)
+		}
+	}
+	return nil
+}
+
+// move renames file or directory, unless there is nothing under the old path or there
+// is something under the new one already. Files are copied when they cannot be renamed,
+// i.e. to another file system.
+func move(from, to string) error {
+	if filepath.Clean(from) == filepath.Clean(to) {
+		return nil
+	}
+	info, err := os.Stat(from)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(to); err == nil || !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(to), 0700); err != nil {
+		return fmt.Errorf("could not create directory: %v", err)
+	}
+	if err := os.Rename(from, to); err == nil || info.IsDir() {
+		return err
+	}
+	if err := copyFile(from, to, info.Mode()); err != nil {
+		return err
+	}
+	return os.Remove(from)
+}
+
+func copyFile(from, to string, perm os.FileMode) error {
+	in, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(to, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(to)
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,127 @@
+package dirs
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fakeEnvironment replaces the environment, the OS and the home directory until the returned function is called.
+func fakeEnvironment(system, home string, env map[string]string) func() {
+	previousGetenv, previousGOOS, previousUserHomeDir := getenv, goos, userHomeDir
+	getenv = func(key string) string { return env[key] }
+	goos = system
+	userHomeDir = func() (string, error) { return home, nil }
+	return func() {
+		getenv, goos, userHomeDir = previousGetenv, previousGOOS, previousUserHomeDir
+	}
+}
+
+func TestDirectories(t *testing.T) {
+	for _, test := range []struct {
+		os                   string
+		env                  map[string]string
+		config, cache, state string
+	}{
+		{
+			os:     "linux",
+			config: "/home/user/.config/spotify-cli",
+			cache:  "/home/user/.cache/spotify-cli",
+			state:  "/home/user/.local/state/spotify-cli",
+		},
+		{
+			os:     "linux",
+			env:    map[string]string{"XDG_CONFIG_HOME": "/xdg/config", "XDG_CACHE_HOME": "/xdg/cache", "XDG_STATE_HOME": "relative/state"},
+			config: "/xdg/config/spotify-cli",
+			cache:  "/xdg/cache/spotify-cli",
+			state:  "/home/user/.local/state/spotify-cli",
+		},
+		{
+			os:     "darwin",
+			config: "/home/user/Library/Application Support/spotify-cli",
+			cache:  "/home/user/Library/Caches/spotify-cli",
+			state:  "/home/user/Library/Application Support/spotify-cli",
+		},
+		{
+			os:     "windows",
+			env:    map[string]string{"AppData": "/AppData/Roaming", "LocalAppData": "/AppData/Local"},
+			config: "/AppData/Roaming/spotify-cli",
+			cache:  "/AppData/Local/spotify-cli/cache",
+			state:  "/AppData/Local/spotify-cli/state",
+		},
+	} {
+		restore := fakeEnvironment(test.os, "/home/user", test.env)
+		config, _ := Config()
+		cache, _ := Cache()
+		state, _ := State()
+		restore()
+		if config != filepath.FromSlash(test.config) || cache != filepath.FromSlash(test.cache) || state != filepath.FromSlash(test.state) {
+			t.Fatalf("Expected directories on %s with %v to be %s, %s and %s, got %s, %s and %s",
+				test.os, test.env, test.config, test.cache, test.state, config, cache, state)
+		}
+	}
+}
+
+func TestRuntimeDirectory(t *testing.T) {
+	defer fakeEnvironment("linux", "/home/user", map[string]string{"XDG_RUNTIME_DIR": "/run/user/1000"})()
+	if dir, err := Runtime(); err != nil || dir != filepath.FromSlash("/run/user/1000/spotify-cli") {
+		t.Fatalf("Expected runtime directory under XDG_RUNTIME_DIR, got %s, %v", dir, err)
+	}
+	getenv = func(string) string { return "" }
+	if dir, err := Runtime(); err != nil || dir != filepath.FromSlash("/home/user/.local/state/spotify-cli") {
+		t.Fatalf("Expected state directory without XDG_RUNTIME_DIR, got %s, %v", dir, err)
+	}
+}
+
+func TestMigrateMovesFilesFromLegacyLocations(t *testing.T) {
+	home, err := ioutil.TempDir("", "spotify-cli-dirs")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(home)
+	defer fakeEnvironment("linux", home, map[string]string{"XDG_CACHE_HOME": filepath.Join(home, "xdg-cache")})()
+	legacy := map[string]string{
+		".config/spotify-cli/config.toml":             "config",
+		".config/spotify-cli/token.enc":               "token",
+		".config/spotify-cli/token-work.json":         "work token",
+		".cache/spotify-cli/library_albums.json":      "albums",
+		".local/state/spotify-cli/token-family.enc":   "family token",
+		".config/spotify-cli/token-family.enc":        "stale family token",
+		".cache/spotify-cli/profiles/work/home.json":  "home",
+		".config/spotify-cli/playlists/unrelated.txt": "unrelated",
+	}
+	for path, content := range legacy {
+		path = filepath.Join(home, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatalf("Did not expect to fail, but it did with %v", err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("Did not expect to fail, but it did with %v", err)
+		}
+	}
+
+	if err := Migrate(); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	for path, content := range map[string]string{
+		".config/spotify-cli/config.toml":               "config",
+		".local/state/spotify-cli/token.enc":            "token",
+		".local/state/spotify-cli/token-work.json":      "work token",
+		".local/state/spotify-cli/token-family.enc":     "family token",
+		".config/spotify-cli/token-family.enc":          "stale family token",
+		"xdg-cache/spotify-cli/library_albums.json":     "albums",
+		"xdg-cache/spotify-cli/profiles/work/home.json": "home",
+		".config/spotify-cli/playlists/unrelated.txt":   "unrelated",
+	} {
+		data, err := ioutil.ReadFile(filepath.Join(home, filepath.FromSlash(path)))
+		if err != nil || string(data) != content {
+			t.Fatalf("Expected %s to contain %q, got %q, %v", path, content, data, err)
+		}
+	}
+	for _, path := range []string{".config/spotify-cli/token.enc", ".cache/spotify-cli"} {
+		if _, err := os.Stat(filepath.Join(home, filepath.FromSlash(path))); !os.IsNotExist(err) {
+			t.Fatalf("Expected %s to be moved, got %v", path, err)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+// Package hooks runs commands given in the configuration when something happens in the
+// player, i.e. to show notifications or to log what was played.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"time"
+)
+
+// Hook is a command run with the shell, which is killed once it runs longer than the timeout.
+type Hook struct {
+	Command string
+	Timeout time.Duration
+}
+
+// Run runs the command with the given environment variables added to those of the application,
+// and waits for it to finish. Output of the command is discarded.
+func (hook Hook) Run(ctx context.Context, env map[string]string) error {
+	_, err := hook.run(ctx, env, false)
+	return err
+}
+
+// Output runs the command like Run, and returns what it printed.
+func (hook Hook) Output(ctx context.Context, env map[string]string) ([]byte, error) {
+	return hook.run(ctx, env, true)
+}
+
+func (hook Hook) run(ctx context.Context, env map[string]string, output bool) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, hook.Timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "sh", "-c", hook.Command)
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", hook.Command)
+	}
+	cmd.Env = os.Environ()
+	names := make([]string, 0, len(env))
+	for name := range env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		cmd.Env = append(cmd.Env, name+"="+env[name])
+	}
+	stdout := &bytes.Buffer{}
+	if output {
+		cmd.Stdout = stdout
+	}
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("hook %q was killed after %s", hook.Command, hook.Timeout)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("hook %q failed: %v", hook.Command, err)
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,52 @@
+package hooks
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHookRunsCommandWithEnvironment(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Hooks are run with cmd on Windows")
+	}
+	dir, err := ioutil.TempDir("", "spotify-cli-hooks")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "played.txt")
+
+	hook := Hook{Command: `echo "$SPOTIFY_ARTIST - $SPOTIFY_TITLE" >> ` + path, Timeout: 5 * time.Second}
+	if err := hook.Run(context.Background(), map[string]string{"SPOTIFY_ARTIST": "Radiohead", "SPOTIFY_TITLE": "Idioteque"}); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	played, err := ioutil.ReadFile(path)
+	if err != nil || string(played) != "Radiohead - Idioteque\n" {
+		t.Fatalf("Expected hook to write the track, got %q, %v", played, err)
+	}
+
+	if err := (Hook{Command: "exit 3", Timeout: 5 * time.Second}).Run(context.Background(), nil); err == nil {
+		t.Fatalf("Expected failing hook to fail")
+	}
+	started := time.Now()
+	err = (Hook{Command: "sleep 10", Timeout: 50 * time.Millisecond}).Run(context.Background(), nil)
+	if err == nil || !strings.Contains(err.Error(), "killed") || time.Since(started) > 5*time.Second {
+		t.Fatalf("Expected hook to be killed once it runs too long, got %v after %s", err, time.Since(started))
+	}
+}
+
+func TestHookOutput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Hooks are run with cmd on Windows")
+	}
+	output, err := (Hook{Command: `echo "$SPOTIFY_TITLE"`, Timeout: 5 * time.Second}).Output(context.Background(), map[string]string{"SPOTIFY_TITLE": "Idioteque"})
+	if err != nil || string(output) != "Idioteque\n" {
+		t.Fatalf("Expected output of the hook, got %q, %v", output, err)
+	}
+}
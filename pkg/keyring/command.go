@@ -0,0 +1,38 @@
+//go:build darwin || linux
+// +build darwin linux
+
+package keyring
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// commandError is an error of the keyring command which exited with the code.
+type commandError struct {
+	code   int
+	stderr string
+}
+
+func (err *commandError) Error() string {
+	return fmt.Sprintf("keyring command exited with %d: %s", err.code, strings.TrimSpace(err.stderr))
+}
+
+// run runs keyring command with the input, its output is returned. It is replaced in tests.
+var run = func(input string, name string, args ...string) (string, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = strings.NewReader(input)
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	err := cmd.Run()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return "", &commandError{code: exitErr.ExitCode(), stderr: stderr.String()}
+	}
+	if err != nil {
+		// the command is not installed
+		return "", ErrUnsupported
+	}
+	return stdout.String(), nil
+}
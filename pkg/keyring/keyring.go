@@ -0,0 +1,28 @@
+// Package keyring keeps secrets in the keyring of the operating system: Keychain
+// on macOS, Secret Service (i.e. GNOME Keyring or KWallet) on Linux, through
+// secret-tool of libsecret, and Credential Manager on Windows.
+package keyring
+
+import "errors"
+
+var (
+	// ErrNotFound is returned when there is no secret of the service and user.
+	ErrNotFound = errors.New("secret not found in keyring")
+	// ErrUnsupported is returned when keyring cannot be used, i.e. there is none on the OS.
+	ErrUnsupported = errors.New("keyring is not supported")
+)
+
+// Get returns secret of the user of the service.
+func Get(service, user string) (string, error) {
+	return get(service, user)
+}
+
+// Set keeps secret of the user of the service, replacing the previous one.
+func Set(service, user, secret string) error {
+	return set(service, user, secret)
+}
+
+// Delete removes secret of the user of the service, it is not an error when there is none.
+func Delete(service, user string) error {
+	return del(service, user)
+}
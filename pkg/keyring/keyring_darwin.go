@@ -0,0 +1,41 @@
+package keyring
+
+import (
+	"fmt"
+	"strings"
+)
+
+// security exits with 44 when the item is not found in Keychain.
+const securityNotFound = 44
+
+func get(service, user string) (string, error) {
+	secret, err := run("", "security", "find-generic-password", "-s", service, "-a", user, "-w")
+	if err, ok := err.(*commandError); ok && err.code == securityNotFound {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(secret, "\n"), nil
+}
+
+func set(service, user, secret string) error {
+	// command is given on the standard input of interactive mode, so that
+	// the secret is not visible in the list of processes
+	command := fmt.Sprintf("add-generic-password -U -s %s -a %s -w %s\n", quote(service), quote(user), quote(secret))
+	_, err := run(command, "security", "-i")
+	return err
+}
+
+func del(service, user string) error {
+	_, err := run("", "security", "delete-generic-password", "-s", service, "-a", user)
+	if err, ok := err.(*commandError); ok && err.code == securityNotFound {
+		return nil
+	}
+	return err
+}
+
+// quote quotes the argument of the command given in interactive mode.
+func quote(arg string) string {
+	return "'" + strings.Replace(arg, "'", `'"'"'`, -1) + "'"
+}
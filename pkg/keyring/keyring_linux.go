@@ -0,0 +1,28 @@
+package keyring
+
+import "strings"
+
+// secret-tool of libsecret talks with Secret Service over D-Bus, it exits with 1
+// without any message when the secret is not found.
+
+func get(service, user string) (string, error) {
+	secret, err := run("", "secret-tool", "lookup", "service", service, "account", user)
+	if err, ok := err.(*commandError); ok && err.code == 1 && err.stderr == "" {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(secret, "\n"), nil
+}
+
+func set(service, user, secret string) error {
+	// secret is given on the standard input, so that it is not visible in the list of processes
+	_, err := run(secret, "secret-tool", "store", "--label="+service+" ("+user+")", "service", service, "account", user)
+	return err
+}
+
+func del(service, user string) error {
+	_, err := run("", "secret-tool", "clear", "service", service, "account", user)
+	return err
+}
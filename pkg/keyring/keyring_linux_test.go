@@ -0,0 +1,63 @@
+package keyring
+
+import (
+	"reflect"
+	"testing"
+)
+
+// fakeSecretTool keeps secrets like secret-tool would, by their attributes.
+type fakeSecretTool struct {
+	secrets map[string]string
+	calls   [][]string
+}
+
+func (fake *fakeSecretTool) run(input string, name string, args ...string) (string, error) {
+	fake.calls = append(fake.calls, append([]string{name}, args...))
+	key := args[len(args)-3] + "/" + args[len(args)-1]
+	switch args[0] {
+	case "lookup":
+		secret, ok := fake.secrets[key]
+		if !ok {
+			return "", &commandError{code: 1}
+		}
+		return secret + "\n", nil
+	case "store":
+		fake.secrets[key] = input
+	case "clear":
+		delete(fake.secrets, key)
+	}
+	return "", nil
+}
+
+func TestSecretTool(t *testing.T) {
+	fake := &fakeSecretTool{secrets: map[string]string{}}
+	defer func(previous func(string, string, ...string) (string, error)) { run = previous }(run)
+	run = fake.run
+
+	if _, err := Get("spotify-cli", "work"); err != ErrNotFound {
+		t.Fatalf("Expected secret not to be found, got %v", err)
+	}
+	if err := Set("spotify-cli", "work", "secret"); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if expected := []string{"secret-tool", "store", "--label=spotify-cli (work)", "service", "spotify-cli", "account", "work"}; !reflect.DeepEqual(fake.calls[1], expected) {
+		t.Fatalf("Expected secret to be stored with %v, got %v", expected, fake.calls[1])
+	}
+	secret, err := Get("spotify-cli", "work")
+	if err != nil || secret != "secret" {
+		t.Fatalf("Expected secret to be found, got %q, %v", secret, err)
+	}
+	if err := Delete("spotify-cli", "work"); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if _, err := Get("spotify-cli", "work"); err != ErrNotFound {
+		t.Fatalf("Expected secret to be deleted, got %v", err)
+	}
+
+	run = func(string, string, ...string) (string, error) {
+		return "", &commandError{code: 1, stderr: "Cannot autolaunch D-Bus without X11 $DISPLAY"}
+	}
+	if _, err := Get("spotify-cli", "work"); err == nil || err == ErrNotFound {
+		t.Fatalf("Expected to fail when Secret Service is not running, got %v", err)
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !darwin && !linux && !windows
+// +build !darwin,!linux,!windows
+
+package keyring
+
+func get(service, user string) (string, error) {
+	return "", ErrUnsupported
+}
+
+func set(service, user, secret string) error {
+	return ErrUnsupported
+}
+
+func del(service, user string) error {
+	return ErrUnsupported
+}
@@ -0,0 +1,102 @@
+package keyring
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// Credential Manager is used through advapi32, generic credentials
+// are named after the service and the user.
+
+var (
+	advapi32       = syscall.NewLazyDLL("advapi32.dll")
+	procCredRead   = advapi32.NewProc("CredReadW")
+	procCredWrite  = advapi32.NewProc("CredWriteW")
+	procCredDelete = advapi32.NewProc("CredDeleteW")
+	procCredFree   = advapi32.NewProc("CredFree")
+)
+
+const (
+	credTypeGeneric         = 1
+	credPersistLocalMachine = 2
+	errorNotFound           = syscall.Errno(1168)
+	credentialMaxBlobSize   = 5 * 512
+)
+
+// credential is CREDENTIALW structure.
+type credential struct {
+	Flags              uint32
+	Type               uint32
+	TargetName         *uint16
+	Comment            *uint16
+	LastWritten        syscall.Filetime
+	CredentialBlobSize uint32
+	CredentialBlob     *byte
+	Persist            uint32
+	AttributeCount     uint32
+	Attributes         uintptr
+	TargetAlias        *uint16
+	UserName           *uint16
+}
+
+func target(service, user string) (*uint16, error) {
+	return syscall.UTF16PtrFromString(service + ":" + user)
+}
+
+func get(service, user string) (string, error) {
+	name, err := target(service, user)
+	if err != nil {
+		return "", err
+	}
+	var cred *credential
+	r, _, err := procCredRead.Call(uintptr(unsafe.Pointer(name)), credTypeGeneric, 0, uintptr(unsafe.Pointer(&cred)))
+	if r == 0 {
+		if err == errorNotFound {
+			return "", ErrNotFound
+		}
+		return "", err
+	}
+	defer procCredFree.Call(uintptr(unsafe.Pointer(cred)))
+	blob := make([]byte, cred.CredentialBlobSize)
+	copy(blob, (*[credentialMaxBlobSize]byte)(unsafe.Pointer(cred.CredentialBlob))[:cred.CredentialBlobSize:cred.CredentialBlobSize])
+	return string(blob), nil
+}
+
+func set(service, user, secret string) error {
+	name, err := target(service, user)
+	if err != nil {
+		return err
+	}
+	userName, err := syscall.UTF16PtrFromString(user)
+	if err != nil {
+		return err
+	}
+	blob := []byte(secret)
+	cred := credential{
+		Type:               credTypeGeneric,
+		TargetName:         name,
+		UserName:           userName,
+		CredentialBlobSize: uint32(len(blob)),
+		Persist:            credPersistLocalMachine,
+	}
+	if len(blob) > 0 {
+		cred.CredentialBlob = &blob[0]
+	}
+	r, _, err := procCredWrite.Call(uintptr(unsafe.Pointer(&cred)), 0)
+	if r == 0 {
+		return err
+	}
+	return nil
+}
+
+func del(service, user string) error {
+	name, err := target(service, user)
+	if err != nil {
+		return err
+	}
+	r, _, err := procCredDelete.Call(uintptr(unsafe.Pointer(name)), credTypeGeneric, 0)
+	if r == 0 && err != errorNotFound {
+		return err
+	}
+	return nil
+}
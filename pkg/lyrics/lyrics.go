@@ -0,0 +1,119 @@
+// Package lyrics fetches lyrics of tracks from a provider, shared by the lyrics command and
+// the lyrics view of the player.
+package lyrics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jedruniu/spotify-cli/pkg/hooks"
+)
+
+// DefaultLRCLIBURL is the URL of LRCLIB API used when no other is configured.
+var DefaultLRCLIBURL = "https://lrclib.net"
+
+// ErrNotFound is returned by providers which do not know lyrics of the track.
+var ErrNotFound = errors.New("lyrics were not found")
+
+// Track is described to providers by its artists, title, album and duration.
+type Track struct {
+	Artist   string
+	Title    string
+	Album    string
+	Duration time.Duration
+}
+
+// Provider fetches lyrics of tracks.
+type Provider interface {
+	Lyrics(ctx context.Context, track Track) (string, error)
+}
+
+// LRCLIB fetches lyrics from LRCLIB, an open database of lyrics which needs no account.
+type LRCLIB struct {
+	url  string
+	http *http.Client
+}
+
+// NewLRCLIB creates LRCLIB provider using API with the given URL, official one when empty.
+func NewLRCLIB(url string) *LRCLIB {
+	if url == "" {
+		url = DefaultLRCLIBURL
+	}
+	return &LRCLIB{url: strings.TrimSuffix(url, "/"), http: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// SetProxy sends requests to LRCLIB through the proxy, instead of the one given by
+// HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables.
+func (lrclib *LRCLIB) SetProxy(proxy *url.URL) {
+	lrclib.http = &http.Client{Timeout: 10 * time.Second, Transport: &http.Transport{Proxy: http.ProxyURL(proxy)}}
+}
+
+// Lyrics returns plain lyrics of the track, which LRCLIB matches by all its fields.
+func (lrclib *LRCLIB) Lyrics(ctx context.Context, track Track) (string, error) {
+	query := url.Values{}
+	query.Set("artist_name", track.Artist)
+	query.Set("track_name", track.Title)
+	query.Set("album_name", track.Album)
+	query.Set("duration", strconv.Itoa(int(track.Duration/time.Second)))
+	req, err := http.NewRequest(http.MethodGet, lrclib.url+"/api/get?"+query.Encode(), nil)
+	if err != nil {
+		return "", fmt.Errorf("could not create lyrics request: %v", err)
+	}
+	resp, err := lrclib.http.Do(req.WithContext(ctx))
+	if err != nil {
+		return "", fmt.Errorf("could not reach lrclib: %v", err)
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return "", ErrNotFound
+	default:
+		return "", fmt.Errorf("lrclib rejected lyrics request: HTTP %d", resp.StatusCode)
+	}
+	var found struct {
+		PlainLyrics  string `json:"plainLyrics"`
+		Instrumental bool   `json:"instrumental"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&found); err != nil {
+		return "", fmt.Errorf("could not decode lyrics: %v", err)
+	}
+	if found.Instrumental {
+		return "♪ Instrumental ♪", nil
+	}
+	if strings.TrimSpace(found.PlainLyrics) == "" {
+		return "", ErrNotFound
+	}
+	return found.PlainLyrics, nil
+}
+
+// Command fetches lyrics by running a command, i.e. a script asking another service, which
+// prints them. The track is given in SPOTIFY_ARTIST, SPOTIFY_TITLE, SPOTIFY_ALBUM and
+// SPOTIFY_DURATION (in seconds), nothing printed means lyrics were not found.
+type Command struct {
+	Hook hooks.Hook
+}
+
+// Lyrics runs the command and returns what it printed.
+func (command Command) Lyrics(ctx context.Context, track Track) (string, error) {
+	output, err := command.Hook.Output(ctx, map[string]string{
+		"SPOTIFY_ARTIST":   track.Artist,
+		"SPOTIFY_TITLE":    track.Title,
+		"SPOTIFY_ALBUM":    track.Album,
+		"SPOTIFY_DURATION": strconv.Itoa(int(track.Duration / time.Second)),
+	})
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(string(output)) == "" {
+		return "", ErrNotFound
+	}
+	return string(output), nil
+}
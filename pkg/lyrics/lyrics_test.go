@@ -0,0 +1,63 @@
+package lyrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/jedruniu/spotify-cli/pkg/hooks"
+)
+
+func TestLRCLIBLyrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if r.URL.Path != "/api/get" || query.Get("album_name") != "Kid A" || query.Get("duration") != "309" {
+			t.Errorf("Expected track to be described by all its fields, got %s", r.URL)
+		}
+		switch query.Get("track_name") {
+		case "Idioteque":
+			w.Write([]byte(`{"plainLyrics":"Who's in a bunker?\nWho's in a bunker?","syncedLyrics":"[00:20.00] Who's in a bunker?"}`))
+		case "Treefingers":
+			w.Write([]byte(`{"instrumental":true,"plainLyrics":null}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	lrclib := NewLRCLIB(server.URL + "/")
+	track := Track{Artist: "Radiohead", Title: "Idioteque", Album: "Kid A", Duration: 309 * time.Second}
+	lyrics, err := lrclib.Lyrics(context.Background(), track)
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if lyrics != "Who's in a bunker?\nWho's in a bunker?" {
+		t.Fatalf("Expected plain lyrics, got %q", lyrics)
+	}
+	track.Title = "Treefingers"
+	if lyrics, err := lrclib.Lyrics(context.Background(), track); err != nil || lyrics != "♪ Instrumental ♪" {
+		t.Fatalf("Expected instrumental track to be told, got %q, %v", lyrics, err)
+	}
+	track.Title = "Motion Picture Soundtrack"
+	if _, err := lrclib.Lyrics(context.Background(), track); err != ErrNotFound {
+		t.Fatalf("Expected lyrics not to be found, got %v", err)
+	}
+}
+
+func TestCommandLyrics(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Commands are run with cmd on Windows")
+	}
+	command := Command{Hook: hooks.Hook{Command: `echo "$SPOTIFY_TITLE by $SPOTIFY_ARTIST, $SPOTIFY_DURATION s"`, Timeout: 5 * time.Second}}
+	lyrics, err := command.Lyrics(context.Background(), Track{Artist: "Radiohead", Title: "Idioteque", Duration: 309 * time.Second})
+	if err != nil || lyrics != "Idioteque by Radiohead, 309 s\n" {
+		t.Fatalf("Expected output of the command, got %q, %v", lyrics, err)
+	}
+	command.Hook.Command = "true"
+	if _, err := command.Lyrics(context.Background(), Track{}); err != ErrNotFound {
+		t.Fatalf("Expected lyrics not to be found when nothing is printed, got %v", err)
+	}
+}
@@ -1,8 +1,11 @@
 package player
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"sort"
+	"strings"
+	"time"
 
 	tui "github.com/marcusolsson/tui-go"
 	"github.com/zmb3/spotify"
@@ -33,16 +36,33 @@ type pagination interface {
 
 	getCurrDataIdx() int
 	setLastTwoSelected([]int)
+	jumpTo(dataIdx, row int)
 }
 
 // AlbumList represents list of albums with underlying data,
 // table to display, box in which table is places, indexes
 // pointing to currently playing item, and last chosen items.
 type AlbumList struct {
+	ctx                context.Context
 	client             SpotifyClient
 	albumsDescriptions []albumDescription
-	Table              *tui.Table
+	Table              *countedTable
 	box                *tui.Box
+	// confirmation is asked before albums are removed on key press.
+	confirmation *Confirmation
+	// order is the order in which albums are listed, it is shared with the page renderer.
+	order *albumOrder
+	// artist is the artist whose albums are listed, empty if all albums are listed.
+	artist string
+	// hidden are albums which are not listed because of the artist filter, or because their group is collapsed.
+	hidden []albumDescription
+	// grouped tells whether albums are listed in groups of the same artist.
+	grouped bool
+	// collapsed tells which groups are collapsed, by lower case artist name.
+	collapsed map[string]bool
+	// pinned are IDs of albums listed before all the others, in the order they were pinned.
+	pinned   []spotify.ID
+	onPinned func([]string)
 
 	renderer
 	pageRenderer
@@ -51,9 +71,72 @@ type AlbumList struct {
 }
 
 type albumDescription struct {
-	artist string
-	title  string
-	uri    spotify.URI
+	artist      string
+	title       string
+	uri         spotify.URI
+	id          spotify.ID
+	releaseDate string
+	addedAt     string
+	// group tells whether it is a header of the group of artist albums, rather than an album.
+	group     bool
+	albums    int
+	collapsed bool
+	pinned    bool
+}
+
+// albumOrder is an order in which albums can be listed, column is the
+// header of the column which shows what albums are sorted by.
+type albumOrder struct {
+	column string
+	less   func(a, b albumDescription) bool
+}
+
+// albumOrders are switched with albumSortKey, the first one is the order
+// in which albums are fetched - most recently added first.
+var albumOrders = []albumOrder{
+	{"Added", func(a, b albumDescription) bool { return a.addedAt > b.addedAt }},
+	{"Artist", func(a, b albumDescription) bool { return strings.ToLower(a.artist) < strings.ToLower(b.artist) }},
+	{"Title", func(a, b albumDescription) bool { return strings.ToLower(a.title) < strings.ToLower(b.title) }},
+	{"Released", func(a, b albumDescription) bool { return a.releaseDate > b.releaseDate }},
+}
+
+var (
+	albumSortKey  = '>'
+	albumGroupKey = '*'
+	albumPinKey   = '^'
+	// albumSortAscending and albumSortDescending mark header of the column albums are sorted by.
+	albumSortAscending  = "▲"
+	albumSortDescending = "▼"
+	// albumGroupExpanded and albumGroupCollapsed mark headers of groups of artist albums.
+	albumGroupExpanded  = "▾"
+	albumGroupCollapsed = "▸"
+	// albumPinnedMark marks title of pinned albums.
+	albumPinnedMark = "★"
+)
+
+// albumListKeys wraps album list table, so that the order of albums is switched,
+// albums are grouped by artist, or the selected album is pinned, on key press.
+type albumListKeys struct {
+	tui.Widget
+	albumList *AlbumList
+}
+
+// OnKeyEvent switches order, grouping or pins of albums when table is focused, other keys are handled by the wrapped table.
+func (t *albumListKeys) OnKeyEvent(ev tui.KeyEvent) {
+	if t.IsFocused() && ev.Key == tui.KeyRune {
+		switch ev.Rune {
+		case albumSortKey:
+			t.albumList.NextOrder()
+			return
+		case albumGroupKey:
+			t.albumList.ToggleGrouped()
+			return
+		case albumPinKey:
+			t.albumList.TogglePinnedSelected()
+			return
+		}
+	}
+	t.Widget.OnKeyEvent(ev)
 }
 
 var (
@@ -64,9 +147,13 @@ var (
 )
 
 // NewSideBar creates struct which holds references to
-// SideBar Box and AlbumList placed inside SideBar
-func NewSideBar(client SpotifyClient) (*SideBar, error) {
-	al := newEmptyAlbumList(client)
+// SideBar Box and AlbumList placed inside SideBar. Removal
+// of albums is confirmed with the given confirmation. Albums are
+// kept between runs in the library cache, unless it is nil.
+func NewSideBar(ctx context.Context, client SpotifyClient, confirmation *Confirmation, library *LibraryCache) (*SideBar, error) {
+	al := newEmptyAlbumList(ctx, client)
+	al.confirmation = confirmation
+	al.dataFetcher = &fetchUserAlbumsStruct{ctx: ctx, client: client, library: library}
 	err := al.render()
 	if err != nil {
 		return nil, err
@@ -75,27 +162,412 @@ func NewSideBar(client SpotifyClient) (*SideBar, error) {
 	return &SideBar{AlbumList: al, Box: box}, nil
 }
 
-func newEmptyAlbumList(client SpotifyClient) *AlbumList {
-	table := tui.NewTable(0, 0)
+func newEmptyAlbumList(ctx context.Context, client SpotifyClient) *AlbumList {
+	table := newCountedTable()
 	table.SetColumnStretch(0, 1)
 	table.SetColumnStretch(1, 1)
 	table.SetColumnStretch(2, 4)
 
-	albumListBox := tui.NewVBox(table, tui.NewSpacer())
-	albumListBox.SetBorder(true)
-	albumListBox.SetTitle("User albums")
-	albumListBox.SetSizePolicy(tui.Preferred, tui.Expanding)
-
-	return &AlbumList{
+	order := albumOrders[0]
+	albumList := &AlbumList{
+		ctx:                ctx,
 		client:             client,
 		Table:              table,
-		box:                albumListBox,
 		albumsDescriptions: []albumDescription{},
+		order:              &order,
 
-		dataFetcher:  &fetchUserAlbumsStruct{client: client},
-		pageRenderer: &renderPageStruct{table: table},
+		dataFetcher:  &fetchUserAlbumsStruct{ctx: ctx, client: client},
+		pageRenderer: &renderPageStruct{table: table, order: &order},
 		pagination:   &paginatorStruct{table: table, lastTwoSelected: []int{-1, -1}, currDataIdx: 0},
 	}
+
+	var albumListBox *tui.Box
+	typeAhead := newTypeAheadTable(table, albumList.JumpTo, func(prefix string) {
+		albumListBox.SetTitle(typeAheadTitle(albumList.title(), prefix))
+	})
+	keys := &libraryKeys{Widget: typeAhead, onRemove: func() {
+		selected, ok := albumList.selectedAlbum()
+		if !ok {
+			return
+		}
+		album := albumList.albumsDescriptions[selected]
+		if album.group {
+			return
+		}
+		albumList.confirmation.Ask(fmt.Sprintf("Remove %s - %s from the library?", album.artist, album.title), func() {
+			err := albumList.RemoveAlbum(album.id)
+			if err != nil {
+				notifyError(fmt.Errorf("could not remove %s from the library: %v", album.title, err))
+				return
+			}
+			notify("Removed %s from the library", album.title)
+		})
+	}}
+	albumListBox = tui.NewVBox(&albumListKeys{Widget: keys, albumList: albumList}, tui.NewSpacer())
+	albumListBox.SetBorder(true)
+	albumListBox.SetTitle(albumList.title())
+	albumListBox.SetSizePolicy(tui.Preferred, tui.Expanding)
+	albumList.box = albumListBox
+	return albumList
+}
+
+// JumpTo selects first album which artist or title starts with the prefix,
+// it tells whether such album was found.
+func (albumList *AlbumList) JumpTo(prefix string) bool {
+	for i, album := range albumList.albumsDescriptions {
+		if hasTypeAheadPrefix(prefix, album.artist, album.title) {
+			return albumList.showAlbum(i)
+		}
+	}
+	return false
+}
+
+// title returns title of the album list box, which names the artist albums are filtered by.
+func (albumList *AlbumList) title() string {
+	if albumList.artist == "" {
+		return "User albums"
+	}
+	return "User albums - " + albumList.artist
+}
+
+// LibraryArtist is an artist of albums saved in the user's library.
+type LibraryArtist struct {
+	Name   string
+	Albums int
+}
+
+// Artists returns artists of all saved albums, also the ones which are
+// not listed because of the filter, sorted by name. Names differing only
+// in case are the same artist, like when filtering.
+func (albumList *AlbumList) Artists() []LibraryArtist {
+	indexes := map[string]int{}
+	artists := []LibraryArtist{}
+	for _, album := range albumList.albums() {
+		key := strings.ToLower(album.artist)
+		if _, ok := indexes[key]; !ok {
+			indexes[key] = len(artists)
+			artists = append(artists, LibraryArtist{Name: album.artist})
+		}
+		artists[indexes[key]].Albums++
+	}
+	sort.Slice(artists, func(i, j int) bool {
+		return strings.ToLower(artists[i].Name) < strings.ToLower(artists[j].Name)
+	})
+	return artists
+}
+
+// FilterArtist lists only albums of the given artist, the name is not case
+// sensitive. All albums are listed again when artist is empty.
+func (albumList *AlbumList) FilterArtist(artist string) error {
+	found := artist == ""
+	for _, album := range albumList.albums() {
+		if strings.EqualFold(album.artist, artist) {
+			// use the name as it is spelled in the library
+			artist = album.artist
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("there are no saved albums of %s", artist)
+	}
+	albumList.artist = artist
+	albumList.layout()
+	albumList.box.SetTitle(albumList.title())
+	albumList.showAlbum(0)
+	return nil
+}
+
+// NextOrder sorts albums in the next of albumOrders and selects the first one.
+func (albumList *AlbumList) NextOrder() {
+	next := 0
+	for i, order := range albumOrders {
+		if order.column == albumList.order.column {
+			next = (i + 1) % len(albumOrders)
+		}
+	}
+	*albumList.order = albumOrders[next]
+	albumList.layout()
+	if len(albumList.albumsDescriptions) > 0 {
+		albumList.showAlbum(0)
+	}
+}
+
+// ToggleGrouped switches between listing albums grouped by artist and the flat list.
+func (albumList *AlbumList) ToggleGrouped() {
+	albumList.grouped = !albumList.grouped
+	albumList.layout()
+	if len(albumList.albumsDescriptions) > 0 {
+		albumList.showAlbum(0)
+	}
+}
+
+// SetPinned pins albums with the given IDs, i.e. the ones kept in the config, other albums are unpinned.
+// IDs of albums which are not saved are kept, so that albums are pinned again once they are saved.
+func (albumList *AlbumList) SetPinned(ids []string) {
+	albumList.pinned = nil
+	for _, id := range ids {
+		albumList.pinned = append(albumList.pinned, spotify.ID(id))
+	}
+	selected, _ := albumList.selectedRow()
+	albumList.layout()
+	albumList.showRow(selected)
+}
+
+// OnPinned sets function called with IDs of pinned albums each time an album is pinned
+// or unpinned, so that they can be saved to the config.
+func (albumList *AlbumList) OnPinned(fn func([]string)) {
+	albumList.onPinned = fn
+}
+
+// TogglePinnedSelected pins the selected album, or unpins it when it is pinned already, it stays selected.
+func (albumList *AlbumList) TogglePinnedSelected() {
+	album, ok := albumList.selectedRow()
+	if !ok || album.group {
+		return
+	}
+	pinned := []spotify.ID{}
+	for _, id := range albumList.pinned {
+		if id != album.id {
+			pinned = append(pinned, id)
+		}
+	}
+	if !album.pinned {
+		pinned = append(pinned, album.id)
+	}
+	albumList.pinned = pinned
+	albumList.layout()
+	albumList.showRow(album)
+	if albumList.onPinned != nil {
+		ids := []string{}
+		for _, id := range albumList.pinned {
+			ids = append(ids, string(id))
+		}
+		albumList.onPinned(ids)
+	}
+}
+
+// toggleCollapsed hides albums of the group at the given index, or lists them again, the group stays selected.
+func (albumList *AlbumList) toggleCollapsed(i int) {
+	key := strings.ToLower(albumList.albumsDescriptions[i].artist)
+	if albumList.collapsed == nil {
+		albumList.collapsed = map[string]bool{}
+	}
+	albumList.collapsed[key] = !albumList.collapsed[key]
+	albumList.layout()
+	albumList.showRow(albumDescription{group: true, artist: key})
+}
+
+// albums returns all saved albums, listed and hidden, without group headers.
+func (albumList *AlbumList) albums() []albumDescription {
+	albums := []albumDescription{}
+	for _, album := range append(append([]albumDescription{}, albumList.albumsDescriptions...), albumList.hidden...) {
+		if !album.group {
+			albums = append(albums, album)
+		}
+	}
+	return albums
+}
+
+// layout lists albums of the filtered artist in the current order, grouped by
+// artist when grouping is on. Other albums, and albums of collapsed groups, are hidden.
+// Pinned albums are listed first, before any of the groups.
+func (albumList *AlbumList) layout() {
+	pinnedIDs := map[spotify.ID]bool{}
+	for _, id := range albumList.pinned {
+		pinnedIDs[id] = true
+	}
+	pinned := []albumDescription{}
+	listed := []albumDescription{}
+	hidden := []albumDescription{}
+	for _, album := range albumList.albums() {
+		album.pinned = pinnedIDs[album.id]
+		switch {
+		case albumList.artist != "" && !strings.EqualFold(album.artist, albumList.artist):
+			hidden = append(hidden, album)
+		case album.pinned:
+			pinned = append(pinned, album)
+		default:
+			listed = append(listed, album)
+		}
+	}
+	for _, albums := range [][]albumDescription{pinned, listed} {
+		sort.SliceStable(albums, func(i, j int) bool {
+			return albumList.order.less(albums[i], albums[j])
+		})
+	}
+	if albumList.grouped {
+		listed, hidden = albumList.groupByArtist(listed, hidden)
+	}
+	albumList.albumsDescriptions = append(pinned, listed...)
+	albumList.hidden = hidden
+}
+
+// groupByArtist puts header before albums of each artist, groups are sorted by the artist
+// name. Albums of collapsed groups are appended to hidden ones instead of being listed.
+func (albumList *AlbumList) groupByArtist(albums, hidden []albumDescription) ([]albumDescription, []albumDescription) {
+	groups := map[string][]albumDescription{}
+	keys := []string{}
+	for _, album := range albums {
+		key := strings.ToLower(album.artist)
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], album)
+	}
+	sort.Strings(keys)
+	grouped := []albumDescription{}
+	for _, key := range keys {
+		albums := groups[key]
+		collapsed := albumList.collapsed[key]
+		grouped = append(grouped, albumDescription{group: true, artist: albums[0].artist, albums: len(albums), collapsed: collapsed})
+		if collapsed {
+			hidden = append(hidden, albums...)
+			continue
+		}
+		grouped = append(grouped, albums...)
+	}
+	return grouped, hidden
+}
+
+// showRow shows the album, or the group header, equal to the given one, or the first row if there is no such row.
+// Group headers are equal when they group albums of the same artist.
+func (albumList *AlbumList) showRow(row albumDescription) {
+	if len(albumList.albumsDescriptions) == 0 {
+		// There is nothing to show but the header.
+		albumList.renderPage(albumList.albumsDescriptions, 0, visibleAlbums)
+		return
+	}
+	for i, listed := range albumList.albumsDescriptions {
+		if listed.group == row.group && listed.id == row.id && (!row.group || strings.EqualFold(listed.artist, row.artist)) {
+			albumList.showAlbum(i)
+			return
+		}
+	}
+	albumList.showAlbum(0)
+}
+
+// selectedRow returns the selected album or group header, if there is one.
+func (albumList *AlbumList) selectedRow() (albumDescription, bool) {
+	selected, ok := albumList.selectedAlbum()
+	if !ok {
+		return albumDescription{}, false
+	}
+	return albumList.albumsDescriptions[selected], true
+}
+
+// SaveAlbum saves album to the user's library, it is placed in the list according
+// to the current order, i.e. first when albums are ordered by the date added.
+func (albumList *AlbumList) SaveAlbum(album spotify.SimpleAlbum) error {
+	return albumList.SaveAlbums(album)
+}
+
+// SaveAlbums saves albums to the user's library with a single request, albums
+// which are already saved are skipped. See SaveAlbum.
+func (albumList *AlbumList) SaveAlbums(albums ...spotify.SimpleAlbum) error {
+	saved := map[spotify.ID]bool{}
+	for _, album := range albumList.albums() {
+		saved[album.id] = true
+	}
+	unsaved := []spotify.SimpleAlbum{}
+	ids := []spotify.ID{}
+	for _, album := range albums {
+		if saved[album.ID] {
+			continue
+		}
+		saved[album.ID] = true
+		unsaved = append(unsaved, album)
+		ids = append(ids, album.ID)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	err := albumList.client.AddAlbumsToLibrary(albumList.ctx, ids...)
+	if err != nil {
+		if len(unsaved) == 1 {
+			return fmt.Errorf("could not save album %s: %v", unsaved[0].Name, err)
+		}
+		return fmt.Errorf("could not save %d albums: %v", len(unsaved), err)
+	}
+	// Keep previously selected album selected
+	selected, _ := albumList.selectedRow()
+	addedAt := time.Now().UTC().Format(time.RFC3339)
+	for _, album := range unsaved {
+		artistName := ""
+		if len(album.Artists) > 0 {
+			artistName = album.Artists[0].Name
+		}
+		albumList.hidden = append(albumList.hidden, albumDescription{
+			artist:      artistName,
+			title:       album.Name,
+			uri:         album.URI,
+			id:          album.ID,
+			releaseDate: album.ReleaseDate,
+			addedAt:     addedAt,
+		})
+	}
+	albumList.layout()
+	albumList.showRow(selected)
+	return nil
+}
+
+// RemoveAlbum removes album from the user's library.
+func (albumList *AlbumList) RemoveAlbum(albumID spotify.ID) error {
+	err := albumList.client.RemoveAlbumsFromLibrary(albumList.ctx, albumID)
+	if err != nil {
+		return fmt.Errorf("could not remove album %s: %v", albumID, err)
+	}
+	for i, album := range albumList.hidden {
+		if album.id == albumID {
+			selected, _ := albumList.selectedRow()
+			albumList.hidden = append(albumList.hidden[:i], albumList.hidden[i+1:]...)
+			albumList.layout()
+			albumList.showRow(selected)
+			return nil
+		}
+	}
+	for i, album := range albumList.albumsDescriptions {
+		if album.id != albumID || album.group {
+			continue
+		}
+		albumList.albumsDescriptions = append(albumList.albumsDescriptions[:i], albumList.albumsDescriptions[i+1:]...)
+		albumList.layout()
+		if i >= len(albumList.albumsDescriptions) {
+			i = len(albumList.albumsDescriptions) - 1
+		}
+		if i < 0 {
+			// There is nothing left to show but the header.
+			albumList.renderPage(albumList.albumsDescriptions, 0, visibleAlbums)
+			return nil
+		}
+		albumList.showAlbum(i)
+		return nil
+	}
+	return nil
+}
+
+// showAlbum renders page with album at the given index and selects it.
+func (albumList *AlbumList) showAlbum(i int) bool {
+	start := (i / visibleAlbums) * visibleAlbums
+	err := albumList.renderPage(albumList.albumsDescriptions, start, start+visibleAlbums)
+	if err != nil {
+		notifyError(fmt.Errorf("could not render page of albums: %v", err))
+		return false
+	}
+	row := i - start + 1
+	albumList.pagination.jumpTo(i, row)
+	albumList.Table.SetSelected(row)
+	return true
+}
+
+// selectedAlbum returns index of the selected album, if there is one.
+func (albumList *AlbumList) selectedAlbum() (int, bool) {
+	// -2 for the same reason as when album is activated
+	selected := albumList.pagination.getCurrDataIdx() - 2
+	if albumList.Table.Selected() < 1 || selected < 0 || selected >= len(albumList.albumsDescriptions) {
+		return 0, false
+	}
+	return selected, true
 }
 
 func (albumList *AlbumList) render() error {
@@ -104,6 +576,9 @@ func (albumList *AlbumList) render() error {
 		return err
 	}
 	albumList.albumsDescriptions = albumsDescriptions
+	albumList.hidden = nil
+	albumList.artist = ""
+	albumList.layout()
 	err = albumList.pageRenderer.renderPage(albumList.albumsDescriptions, 0, visibleAlbums)
 	if err != nil {
 		return err
@@ -114,11 +589,40 @@ func (albumList *AlbumList) render() error {
 }
 
 type fetchUserAlbumsStruct struct {
+	ctx    context.Context
 	client SpotifyClient
+	// library caches albums between runs, when it is set.
+	library *LibraryCache
 }
 
 func (fetchUserAlbumsStruct *fetchUserAlbumsStruct) fetchUserAlbums() ([]albumDescription, error) {
-	initialPage, err := fetchUserAlbumsStruct.client.CurrentUsersAlbumsOpt(&spotify.Options{Limit: &spotifyAPIPageSize})
+	var userAlbums []spotify.SavedAlbum
+	var err error
+	if fetchUserAlbumsStruct.library != nil {
+		userAlbums, err = fetchUserAlbumsStruct.library.SavedAlbums(fetchUserAlbumsStruct.ctx, fetchUserAlbumsStruct.client)
+	} else {
+		userAlbums, err = fetchUserAlbumsStruct.fetchPages()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	albumsDescriptions := make([]albumDescription, 0)
+	for _, album := range userAlbums {
+		albumsDescriptions = append(albumsDescriptions, albumDescription{
+			artist:      album.Artists[0].Name,
+			title:       album.Name,
+			uri:         album.URI,
+			id:          album.ID,
+			releaseDate: album.ReleaseDate,
+			addedAt:     album.AddedAt,
+		})
+	}
+	return albumsDescriptions, nil
+}
+
+func (fetchUserAlbumsStruct *fetchUserAlbumsStruct) fetchPages() ([]spotify.SavedAlbum, error) {
+	initialPage, err := fetchUserAlbumsStruct.client.CurrentUsersAlbumsOpt(fetchUserAlbumsStruct.ctx, &spotify.Options{Limit: &spotifyAPIPageSize})
 	if err != nil {
 		return nil, fmt.Errorf("could not fetch current user albums: %v", err)
 	}
@@ -127,7 +631,7 @@ func (fetchUserAlbumsStruct *fetchUserAlbumsStruct) fetchUserAlbums() ([]albumDe
 
 	page := initialPage
 	for spotifyAPIPageOffset < initialPage.Total {
-		page, err = fetchUserAlbumsStruct.client.CurrentUsersAlbumsOpt(&spotify.Options{
+		page, err = fetchUserAlbumsStruct.client.CurrentUsersAlbumsOpt(fetchUserAlbumsStruct.ctx, &spotify.Options{
 			Limit:  &initialPage.Limit,
 			Offset: &spotifyAPIPageOffset,
 		})
@@ -137,12 +641,7 @@ func (fetchUserAlbumsStruct *fetchUserAlbumsStruct) fetchUserAlbums() ([]albumDe
 		spotifyAPIPageOffset += spotifyAPIPageSize
 		userAlbums = append(userAlbums, page.Albums...)
 	}
-
-	albumsDescriptions := make([]albumDescription, 0)
-	for _, album := range userAlbums {
-		albumsDescriptions = append(albumsDescriptions, albumDescription{album.Name, album.Artists[0].Name, album.URI})
-	}
-	return albumsDescriptions, nil
+	return userAlbums, nil
 }
 
 func (albumList *AlbumList) onSelectedChanged() func(*tui.Table) {
@@ -154,7 +653,7 @@ func (albumList *AlbumList) onSelectedChanged() func(*tui.Table) {
 				(albumList.getCurrDataIdx()/visibleAlbums)*visibleAlbums+visibleAlbums,
 			)
 			if err != nil {
-				log.Printf("Could not render next page of albums with %s", err)
+				notifyError(fmt.Errorf("could not render next page of albums: %v", err))
 				return
 			}
 			albumList.setLastTwoSelected([]int{-1, -1})
@@ -168,7 +667,7 @@ func (albumList *AlbumList) onSelectedChanged() func(*tui.Table) {
 				(albumList.getCurrDataIdx()/visibleAlbums)*visibleAlbums,
 			)
 			if err != nil {
-				log.Printf("Could not render previous page of albums with %s", err)
+				notifyError(fmt.Errorf("could not render previous page of albums: %v", err))
 				return
 			}
 			albumList.setLastTwoSelected([]int{visibleAlbums + 2, visibleAlbums + 1})
@@ -182,7 +681,7 @@ func (albumList *AlbumList) onSelectedChanged() func(*tui.Table) {
 type paginatorStruct struct {
 	currDataIdx     int
 	lastTwoSelected []int
-	table           *tui.Table
+	table           *countedTable
 }
 
 func (paginator *paginatorStruct) setLastTwoSelected(lastTwoSelected []int) {
@@ -193,6 +692,13 @@ func (paginator *paginatorStruct) getCurrDataIdx() int {
 	return paginator.currDataIdx
 }
 
+// jumpTo makes row showing album at dataIdx the selected one.
+func (paginator *paginatorStruct) jumpTo(dataIdx, row int) {
+	// +2 for the same reason activated item is at currDataIdx-2.
+	paginator.currDataIdx = dataIdx + 2
+	paginator.lastTwoSelected = []int{row, row}
+}
+
 func (paginator *paginatorStruct) nextPage() bool {
 	return paginator.lastTwoSelected[0] == visibleAlbums-1 && paginator.lastTwoSelected[1] == visibleAlbums
 }
@@ -216,39 +722,109 @@ func (paginator *paginatorStruct) updateIndexes() {
 func (albumList *AlbumList) onItemActivaed() func(*tui.Table) {
 	return func(t *tui.Table) {
 		// -2 because tui.Table starts counting at 1, and additional 1 is added because first row is a header
+		if album := albumList.albumsDescriptions[albumList.pagination.getCurrDataIdx()-2]; album.group {
+			albumList.toggleCollapsed(albumList.pagination.getCurrDataIdx() - 2)
+			return
+		}
 		uri := &albumList.albumsDescriptions[albumList.pagination.getCurrDataIdx()-2].uri
-		err := albumList.client.PlayOpt(&spotify.PlayOptions{PlaybackContext: uri})
+		err := albumList.client.PlayOpt(albumList.ctx, &spotify.PlayOptions{PlaybackContext: uri})
 		if err != nil {
-			log.Printf("Error occured while trying to play track with uri: %s", *uri)
+			notifyError(fmt.Errorf("could not play %s: %v", *uri, err))
 		}
 	}
 }
 
 type renderPageStruct struct {
-	table *tui.Table
+	table *countedTable
+	// order is shown in the header, albums are sorted by the first column when it is nil.
+	order *albumOrder
+}
+
+func (renderPageStruct *renderPageStruct) currentOrder() albumOrder {
+	if renderPageStruct.order == nil {
+		return albumOrders[0]
+	}
+	return *renderPageStruct.order
+}
+
+// header returns header row, column albums are sorted by is marked. When albums
+// are sorted by date, the date is shown in the additional column.
+func (renderPageStruct *renderPageStruct) header() []string {
+	header := []string{"Title", "Artist"}
+	order := renderPageStruct.currentOrder()
+	switch order.column {
+	case "Title":
+		header[0] += " " + albumSortAscending
+	case "Artist":
+		header[1] += " " + albumSortAscending
+	default:
+		header = append(header, order.column+" "+albumSortDescending)
+	}
+	return header
 }
 
 func (renderPageStruct *renderPageStruct) renderPage(albumsDescriptions []albumDescription, start, end int) error {
 	renderPageStruct.table.RemoveRows()
-	renderPageStruct.table.AppendRow(
-		tui.NewLabel("Title"),
-		tui.NewLabel("Artist"),
-	)
+	header := renderPageStruct.header()
+	labels := []tui.Widget{}
+	for _, column := range header {
+		labels = append(labels, tui.NewLabel(column))
+	}
+	renderPageStruct.table.AppendRow(labels...)
 	if len(albumsDescriptions) == 0 {
 		return fmt.Errorf("could not iterate over empty slice")
 	}
 	if len(albumsDescriptions) < end {
 		end = len(albumsDescriptions) // This means that there is less user albums than there is displayed at once on the page.
 	}
+	if start < 0 || start >= end {
+		return fmt.Errorf("page starting at %d is out of %d albums", start, len(albumsDescriptions))
+	}
 	for _, album := range albumsDescriptions[start:end] {
-		renderPageStruct.table.AppendRow(
+		if album.group {
+			mark := albumGroupExpanded
+			if album.collapsed {
+				mark = albumGroupCollapsed
+			}
+			row := []tui.Widget{
+				tui.NewLabel(mark + " " + trimWithCommasIfTooLong(album.artist, uiColumnWidth)),
+				tui.NewLabel(fmt.Sprintf("%d albums", album.albums)),
+			}
+			if len(header) > 2 {
+				row = append(row, tui.NewLabel(""))
+			}
+			renderPageStruct.table.AppendRow(row...)
+			continue
+		}
+		title := album.title
+		if album.pinned {
+			title = albumPinnedMark + " " + title
+		}
+		row := []tui.Widget{
+			tui.NewLabel(trimWithCommasIfTooLong(title, uiColumnWidth)),
 			tui.NewLabel(trimWithCommasIfTooLong(album.artist, uiColumnWidth)),
-			tui.NewLabel(trimWithCommasIfTooLong(album.title, uiColumnWidth)),
-		)
+		}
+		if len(header) > 2 {
+			date := album.addedAt
+			if renderPageStruct.currentOrder().column == "Released" {
+				date = album.releaseDate
+			}
+			row = append(row, tui.NewLabel(albumDate(date)))
+		}
+		renderPageStruct.table.AppendRow(row...)
 	}
 	return nil
 }
 
+// albumDate returns day part of the date, which is given either as RFC 3339
+// timestamp, or as a release date with year, month or day precision.
+func albumDate(date string) string {
+	if len(date) > len("2006-01-02") {
+		return date[:len("2006-01-02")]
+	}
+	return date
+}
+
 func trimWithCommasIfTooLong(text string, maxLength int) string {
 	if len(text) > maxLength {
 		text = text[:maxLength] + "..."
@@ -2,6 +2,7 @@ package player
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"log"
 	"reflect"
@@ -15,7 +16,7 @@ import (
 
 func TestNewSideBar(t *testing.T) {
 	client := NewDebugClient()
-	sideBar, err := NewSideBar(client)
+	sideBar, err := NewSideBar(context.Background(), client, NewConfirmation(), nil)
 	if err != nil {
 		t.Fatalf("Unexpected error occured: %s", err)
 	}
@@ -35,7 +36,7 @@ type AlbumFetcherMock struct {
 	callConfigs []CallConfig
 }
 
-func (fake *AlbumFetcherMock) CurrentUsersAlbumsOpt(opt *spotify.Options) (*spotify.SavedAlbumPage, error) {
+func (fake *AlbumFetcherMock) CurrentUsersAlbumsOpt(ctx context.Context, opt *spotify.Options) (*spotify.SavedAlbumPage, error) {
 	if fake.callConfigs[fake.call].executionError == true {
 		fake.call++
 		return nil, fmt.Errorf("err")
@@ -56,7 +57,7 @@ func TestFetchUserAlbumListFetchesNoPages(t *testing.T) {
 	}
 	client.UserAlbumFetcher = fetcherMock
 
-	albumList := newEmptyAlbumList(client)
+	albumList := newEmptyAlbumList(context.Background(), client)
 	albumList.fetchUserAlbums()
 
 	if len(albumList.albumsDescriptions) != 0 {
@@ -79,7 +80,7 @@ func TestFetchUserAlbumListFetchesSinglePage(t *testing.T) {
 	}
 	client.UserAlbumFetcher = fetcherMock
 
-	albumList := newEmptyAlbumList(client)
+	albumList := newEmptyAlbumList(context.Background(), client)
 	albumsDescriptions, err := albumList.fetchUserAlbums()
 	if err != nil {
 		t.Fatalf("Did not expect to fail, but it did")
@@ -111,7 +112,7 @@ func TestFetchUserAlbumListFetchesManyPages(t *testing.T) {
 	}
 	client.UserAlbumFetcher = fetcherMock
 
-	albumList := newEmptyAlbumList(client)
+	albumList := newEmptyAlbumList(context.Background(), client)
 	albumsDescriptions, err := albumList.fetchUserAlbums()
 	if err != nil {
 		t.Fatalf("Did not expect to fail, but it did")
@@ -135,7 +136,7 @@ func TestFetchUserAlbumListFailsOnFirstCall(t *testing.T) {
 	}
 	client.UserAlbumFetcher = fetcherMock
 
-	albumList := newEmptyAlbumList(client)
+	albumList := newEmptyAlbumList(context.Background(), client)
 	_, err := albumList.fetchUserAlbums()
 	if err == nil {
 		t.Fatalf("Expected to fail, but it didn't")
@@ -165,7 +166,7 @@ func TestFetchUserAlbumListFailsWhenFetchingNotFirstPage(t *testing.T) {
 	}
 	client.UserAlbumFetcher = fetcherMock
 
-	albumList := newEmptyAlbumList(client)
+	albumList := newEmptyAlbumList(context.Background(), client)
 	_, err := albumList.fetchUserAlbums()
 	if err == nil {
 		t.Fatalf("Expected to fail, but it didn't")
@@ -226,7 +227,7 @@ func TestRenderSucceds(t *testing.T) {
 	albumList := &AlbumList{
 		dataFetcher:  &fakeDataFetcher{ExecutionError: false},
 		pageRenderer: &fakePageRenderer{ExecutionError: false},
-		Table:        &tui.Table{},
+		Table:        &countedTable{Table: &tui.Table{}},
 	}
 	err := albumList.render()
 	if err != nil {
@@ -236,7 +237,7 @@ func TestRenderSucceds(t *testing.T) {
 }
 
 func TestNextPage(t *testing.T) {
-	testPaginator := &paginatorStruct{table: &tui.Table{}}
+	testPaginator := &paginatorStruct{table: &countedTable{Table: &tui.Table{}}}
 	cases := []struct {
 		lastTwoSelected    []int
 		shouldOpenNextPage bool
@@ -253,7 +254,7 @@ func TestNextPage(t *testing.T) {
 }
 
 func TestPreviousPage(t *testing.T) {
-	testPaginator := &paginatorStruct{table: &tui.Table{}}
+	testPaginator := &paginatorStruct{table: &countedTable{Table: &tui.Table{}}}
 	cases := []struct {
 		lastTwoSelected        []int
 		selectedTableItem      int
@@ -276,7 +277,7 @@ func TestPreviousPage(t *testing.T) {
 }
 
 func TestUpdateIndexes(t *testing.T) {
-	testPaginator := &paginatorStruct{table: &tui.Table{}}
+	testPaginator := &paginatorStruct{table: &countedTable{Table: &tui.Table{}}}
 	cases := []struct {
 		lastTwoSelected   []int
 		newTwoSelected    []int
@@ -309,6 +310,7 @@ type fakePaginatorStruct struct {
 	currDataIdx              int
 	updateIndexesCalled      bool
 	lastTwoSelectedArguments interface{}
+	jumpToArguments          []int
 }
 
 func (fake *fakePaginatorStruct) nextPage() bool {
@@ -324,6 +326,51 @@ func (fake *fakePaginatorStruct) getCurrDataIdx() int { return fake.currDataIdx
 func (fake *fakePaginatorStruct) setLastTwoSelected(lastTwo []int) {
 	fake.lastTwoSelectedArguments = lastTwo
 }
+func (fake *fakePaginatorStruct) jumpTo(dataIdx, row int) {
+	fake.jumpToArguments = []int{dataIdx, row}
+}
+
+func TestAlbumsJumpTo(t *testing.T) {
+	albumsDescriptions := []albumDescription{}
+	for i := 0; i < visibleAlbums; i++ {
+		albumsDescriptions = append(albumsDescriptions, albumDescription{artist: "Artist", title: "Title"})
+	}
+	albumsDescriptions = append(albumsDescriptions,
+		albumDescription{artist: "Queen", title: "Jazz"},
+		albumDescription{artist: "Pink Floyd", title: "Animals"},
+	)
+
+	cases := []struct {
+		prefix            string
+		found             bool
+		expectedStart     int
+		expectedArguments []int
+	}{
+		{"qu", true, visibleAlbums, []int{visibleAlbums, 1}}, // first album on the second page
+		{"anim", true, visibleAlbums, []int{visibleAlbums + 1, 2}},
+		{"title", true, 0, []int{0, 1}},
+		{"x", false, 0, nil},
+	}
+	for _, c := range cases {
+		fakePaginator := &fakePaginatorStruct{}
+		fakeRenderer := &fakePageRenderer{}
+		albumList := &AlbumList{
+			Table:              newCountedTable(),
+			albumsDescriptions: albumsDescriptions,
+			pagination:         fakePaginator,
+			pageRenderer:       fakeRenderer,
+		}
+		if found := albumList.JumpTo(c.prefix); found != c.found {
+			t.Fatalf("Expected jump to %q to return %v, but it returned %v", c.prefix, c.found, found)
+		}
+		if fakeRenderer.givenStart != c.expectedStart {
+			t.Errorf("Expected page starting at %d to be rendered for %q, but it started at %d", c.expectedStart, c.prefix, fakeRenderer.givenStart)
+		}
+		if !reflect.DeepEqual(fakePaginator.jumpToArguments, c.expectedArguments) {
+			t.Errorf("Expected arguments of jumpTo() to be %v for %q, but they were %v", c.expectedArguments, c.prefix, fakePaginator.jumpToArguments)
+		}
+	}
+}
 
 func TestOnSelectionChangeUpdatesIndexesWhenNoPageChange(t *testing.T) {
 	fakePaginator := &fakePaginatorStruct{nextPageReturnValue: false, previousPageReturnValue: false}
@@ -346,12 +393,12 @@ func TestOnSelectionChangeError(t *testing.T) {
 		{
 			// Error when fetching next page
 			fakePaginator: &fakePaginatorStruct{nextPageReturnValue: true, previousPageReturnValue: false},
-			expectedLog:   "Could not render next page of albums with error\n",
+			expectedLog:   "could not render next page of albums: error\n",
 		},
 		{
 			// Error when fetching previous page
 			fakePaginator: &fakePaginatorStruct{nextPageReturnValue: false, previousPageReturnValue: true},
-			expectedLog:   "Could not render previous page of albums with error\n",
+			expectedLog:   "could not render previous page of albums: error\n",
 		},
 	}
 
@@ -415,7 +462,7 @@ func TestAlbumsOnItemActivatedCallback(t *testing.T) {
 	}{
 		{
 			playOptError: true,
-			expectedLog:  "Error occured while trying to play track with uri: any\n",
+			expectedLog:  "could not play any: playback failed\n",
 		},
 		{
 			playOptError: false,
@@ -459,3 +506,196 @@ func TestTrimCommasIfTooLong(t *testing.T) {
 		}
 	}
 }
+
+type fakeLibraryEditor struct {
+	DebugLibraryEditor
+	added   []spotify.ID
+	removed []spotify.ID
+}
+
+func (fake *fakeLibraryEditor) AddAlbumsToLibrary(ctx context.Context, albumIDs ...spotify.ID) error {
+	fake.added = append(fake.added, albumIDs...)
+	return nil
+}
+
+func (fake *fakeLibraryEditor) RemoveAlbumsFromLibrary(ctx context.Context, albumIDs ...spotify.ID) error {
+	fake.removed = append(fake.removed, albumIDs...)
+	return nil
+}
+
+func TestAlbumsSaveAndRemove(t *testing.T) {
+	editor := &fakeLibraryEditor{}
+	client := NewDebugClient().(DebugClient)
+	client.LibraryEditor = editor
+	albumList := newEmptyAlbumList(context.Background(), client)
+	albumList.albumsDescriptions = []albumDescription{
+		{artist: "First", id: "first"},
+		{artist: "Second", id: "second"},
+	}
+
+	album := spotify.SimpleAlbum{Name: "Saved", ID: "saved"}
+	if err := albumList.SaveAlbum(album); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if err := albumList.SaveAlbum(album); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if !reflect.DeepEqual(editor.added, []spotify.ID{"saved"}) {
+		t.Fatalf("Expected album to be saved once, saved %v", editor.added)
+	}
+	if albumList.albumsDescriptions[0].id != "saved" || len(albumList.albumsDescriptions) != 3 {
+		t.Fatalf("Expected saved album to be listed first, got %v", albumList.albumsDescriptions)
+	}
+
+	if err := albumList.RemoveAlbum("first"); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if !reflect.DeepEqual(editor.removed, []spotify.ID{"first"}) {
+		t.Fatalf("Expected first album to be removed, removed %v", editor.removed)
+	}
+	ids := []spotify.ID{}
+	for _, album := range albumList.albumsDescriptions {
+		ids = append(ids, album.id)
+	}
+	if !reflect.DeepEqual(ids, []spotify.ID{"saved", "second"}) {
+		t.Fatalf("Expected albums %v to be left, got %v", []spotify.ID{"saved", "second"}, ids)
+	}
+}
+
+func TestAlbumsNextOrder(t *testing.T) {
+	albumList := newEmptyAlbumList(context.Background(), NewDebugClient())
+	albumList.albumsDescriptions = []albumDescription{
+		{artist: "queen", title: "Jazz", id: "jazz", releaseDate: "1978", addedAt: "2020-03-01T12:00:00Z"},
+		{artist: "Pink Floyd", title: "Animals", id: "animals", releaseDate: "1977-01-23", addedAt: "2020-02-01T12:00:00Z"},
+		{artist: "ABBA", title: "Voulez-Vous", id: "voulez", releaseDate: "1979-04", addedAt: "2020-01-01T12:00:00Z"},
+	}
+	renderer := albumList.pageRenderer.(*renderPageStruct)
+
+	cases := []struct {
+		expectedIDs    []spotify.ID
+		expectedHeader []string
+	}{
+		{[]spotify.ID{"voulez", "animals", "jazz"}, []string{"Title", "Artist " + albumSortAscending}},
+		{[]spotify.ID{"animals", "jazz", "voulez"}, []string{"Title " + albumSortAscending, "Artist"}},
+		{[]spotify.ID{"voulez", "jazz", "animals"}, []string{"Title", "Artist", "Released " + albumSortDescending}},
+		{[]spotify.ID{"jazz", "animals", "voulez"}, []string{"Title", "Artist", "Added " + albumSortDescending}},
+	}
+	for _, c := range cases {
+		albumList.NextOrder()
+		ids := []spotify.ID{}
+		for _, album := range albumList.albumsDescriptions {
+			ids = append(ids, album.id)
+		}
+		if !reflect.DeepEqual(ids, c.expectedIDs) {
+			t.Fatalf("Expected albums to be sorted as %v, got %v", c.expectedIDs, ids)
+		}
+		if header := renderer.header(); !reflect.DeepEqual(header, c.expectedHeader) {
+			t.Fatalf("Expected header %v, got %v", c.expectedHeader, header)
+		}
+	}
+}
+
+func TestAlbumsGroupedByArtist(t *testing.T) {
+	albumList := newEmptyAlbumList(context.Background(), NewDebugClient())
+	albumList.albumsDescriptions = []albumDescription{
+		{artist: "Queen", title: "Jazz", id: "jazz", addedAt: "2020-03-01T12:00:00Z"},
+		{artist: "Pink Floyd", title: "Animals", id: "animals", addedAt: "2020-02-01T12:00:00Z"},
+		{artist: "queen", title: "Innuendo", id: "innuendo", addedAt: "2020-01-01T12:00:00Z"},
+	}
+	rows := func() []string {
+		rows := []string{}
+		for _, album := range albumList.albumsDescriptions {
+			if album.group {
+				rows = append(rows, fmt.Sprintf("%s (%d)", album.artist, album.albums))
+			} else {
+				rows = append(rows, string(album.id))
+			}
+		}
+		return rows
+	}
+
+	albumList.ToggleGrouped()
+	if expected := []string{"Pink Floyd (1)", "animals", "Queen (2)", "jazz", "innuendo"}; !reflect.DeepEqual(rows(), expected) {
+		t.Fatalf("Expected albums grouped by artist %v, got %v", expected, rows())
+	}
+
+	albumList.toggleCollapsed(2)
+	if expected := []string{"Pink Floyd (1)", "animals", "Queen (2)"}; !reflect.DeepEqual(rows(), expected) {
+		t.Fatalf("Expected albums of collapsed group to be hidden %v, got %v", expected, rows())
+	}
+	if err := albumList.SaveAlbum(spotify.SimpleAlbum{ID: "opera", Name: "A Night at the Opera", Artists: []spotify.SimpleArtist{{Name: "Queen"}}}); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if err := albumList.RemoveAlbum("jazz"); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if expected := []string{"Pink Floyd (1)", "animals", "Queen (2)"}; !reflect.DeepEqual(rows(), expected) {
+		t.Fatalf("Expected albums saved to and removed from collapsed group to be counted %v, got %v", expected, rows())
+	}
+
+	albumList.toggleCollapsed(2)
+	if expected := []string{"Pink Floyd (1)", "animals", "Queen (2)", "opera", "innuendo"}; !reflect.DeepEqual(rows(), expected) {
+		t.Fatalf("Expected albums of expanded group to be listed %v, got %v", expected, rows())
+	}
+	albumList.ToggleGrouped()
+	if expected := []string{"opera", "animals", "innuendo"}; !reflect.DeepEqual(rows(), expected) {
+		t.Fatalf("Expected flat list of albums %v, got %v", expected, rows())
+	}
+}
+
+func TestAlbumsPinned(t *testing.T) {
+	albumList := newEmptyAlbumList(context.Background(), NewDebugClient())
+	albumList.albumsDescriptions = []albumDescription{
+		{artist: "Queen", title: "Jazz", id: "jazz", addedAt: "2020-03-01T12:00:00Z"},
+		{artist: "Pink Floyd", title: "Animals", id: "animals", addedAt: "2020-02-01T12:00:00Z"},
+		{artist: "queen", title: "Innuendo", id: "innuendo", addedAt: "2020-01-01T12:00:00Z"},
+	}
+	pinned := []string{}
+	albumList.OnPinned(func(ids []string) {
+		pinned = ids
+	})
+	rows := func() []string {
+		rows := []string{}
+		for _, album := range albumList.albumsDescriptions {
+			switch {
+			case album.group:
+				rows = append(rows, album.artist)
+			case album.pinned:
+				rows = append(rows, albumPinnedMark+string(album.id))
+			default:
+				rows = append(rows, string(album.id))
+			}
+		}
+		return rows
+	}
+
+	albumList.SetPinned([]string{"innuendo", "unsaved"})
+	if expected := []string{albumPinnedMark + "innuendo", "jazz", "animals"}; !reflect.DeepEqual(rows(), expected) {
+		t.Fatalf("Expected pinned album to be listed first %v, got %v", expected, rows())
+	}
+
+	albumList.showAlbum(2)
+	albumList.TogglePinnedSelected()
+	if expected := []string{albumPinnedMark + "animals", albumPinnedMark + "innuendo", "jazz"}; !reflect.DeepEqual(rows(), expected) {
+		t.Fatalf("Expected pinned albums to be listed first in the current order %v, got %v", expected, rows())
+	}
+	if expected := []string{"innuendo", "unsaved", "animals"}; !reflect.DeepEqual(pinned, expected) {
+		t.Fatalf("Expected pins %v to be saved, got %v", expected, pinned)
+	}
+	if selected, _ := albumList.selectedRow(); selected.id != "animals" {
+		t.Fatalf("Expected pinned album to stay selected, got %s", selected.id)
+	}
+
+	albumList.ToggleGrouped()
+	if expected := []string{albumPinnedMark + "animals", albumPinnedMark + "innuendo", "Queen", "jazz"}; !reflect.DeepEqual(rows(), expected) {
+		t.Fatalf("Expected pinned albums to be listed before groups %v, got %v", expected, rows())
+	}
+	albumList.showAlbum(0)
+	albumList.TogglePinnedSelected()
+	if expected := []string{albumPinnedMark + "innuendo", "Pink Floyd", "animals", "Queen", "jazz"}; !reflect.DeepEqual(rows(), expected) {
+		t.Fatalf("Expected unpinned album to be listed in its group %v, got %v", expected, rows())
+	}
+	if expected := []string{"innuendo", "unsaved"}; !reflect.DeepEqual(pinned, expected) {
+		t.Fatalf("Expected pins %v to be saved, got %v", expected, pinned)
+	}
+}
@@ -0,0 +1,162 @@
+package player
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/marcusolsson/tui-go"
+	"github.com/zmb3/spotify"
+)
+
+// FollowedArtists represents view with artists followed by the user,
+// activating an artist displays its albums and top tracks.
+type FollowedArtists struct {
+	Focusables []tui.Widget
+	Box        *tui.Box
+	topTracks  searchResultsInterface
+}
+
+var followedArtistsPageSize = 20
+
+type followedArtistsList struct {
+	ctx     context.Context
+	client  SpotifyClient
+	table   *countedTable
+	saved   *savedTracks
+	artists []spotify.FullArtist
+	after   string
+	hasNext bool
+	country string
+}
+
+// NewFollowedArtists creates view with the first page of artists followed by the user,
+// following pages are fetched when selection reaches the end of the list.
+func NewFollowedArtists(ctx context.Context, client SpotifyClient) (*FollowedArtists, error) {
+	artistAlbums := NewSearchResults(ctx, client, "Albums")
+	artistTopTracks := NewSearchResults(ctx, client, "Top tracks")
+
+	list := newFollowedArtistsList(ctx, client)
+	err := list.fetchNextPage()
+	if err != nil {
+		return nil, err
+	}
+	list.table.OnSelectionChanged(list.onSelectionChanged())
+	list.table.OnItemActivated(list.onItemActivated(artistAlbums, artistTopTracks))
+
+	// Artists unfollowed with - stay listed, so that they can be followed again with +
+	listBox := tui.NewVBox(list.saved.keys(list.table, 1), tui.NewSpacer())
+	listBox.SetTitle("Followed artists")
+	listBox.SetBorder(true)
+
+	details := tui.NewVBox(artistAlbums.getBox(), artistTopTracks.getBox())
+	details.SetSizePolicy(tui.Expanding, tui.Expanding)
+
+	return &FollowedArtists{
+		Focusables: []tui.Widget{list.table, artistAlbums.getTable(), artistTopTracks.getTable()},
+		Box:        tui.NewHBox(listBox, details),
+		topTracks:  artistTopTracks,
+	}, nil
+}
+
+// OnAddToPlaylist sets function called with top tracks which should be added to a playlist.
+func (followed *FollowedArtists) OnAddToPlaylist(fn func([]spotify.ID)) {
+	followed.topTracks.onAddToPlaylist(fn)
+}
+
+func newFollowedArtistsList(ctx context.Context, client SpotifyClient) *followedArtistsList {
+	table := newCountedTable()
+	table.AppendRow(
+		tui.NewLabel(""),
+		tui.NewLabel("Artist"),
+		tui.NewLabel("Genres"),
+	)
+	return &followedArtistsList{
+		ctx:     ctx,
+		client:  client,
+		table:   table,
+		saved:   &savedTracks{ctx: ctx, client: client},
+		artists: []spotify.FullArtist{},
+		hasNext: true,
+	}
+}
+
+func (list *followedArtistsList) fetchNextPage() error {
+	if !list.hasNext {
+		return nil
+	}
+	page, err := list.client.CurrentUsersFollowedArtistsOpt(list.ctx, followedArtistsPageSize, list.after)
+	if err != nil {
+		return fmt.Errorf("could not fetch followed artists: %v", err)
+	}
+	for _, artist := range page.Artists {
+		mark := list.saved.addArtist(artist.ID)
+		list.saved.setMark(len(list.saved.artists)-1, true)
+		list.table.AppendRow(
+			mark,
+			tui.NewLabel(trimWithCommasIfTooLong(artist.Name, uiColumnWidth)),
+			tui.NewLabel(trimWithCommasIfTooLong(strings.Join(artist.Genres, ", "), uiColumnWidth)),
+		)
+	}
+	list.artists = append(list.artists, page.Artists...)
+	list.after = page.Cursor.After
+	list.hasNext = page.Next != "" && page.Cursor.After != ""
+	return nil
+}
+
+func (list *followedArtistsList) onSelectionChanged() func(*tui.Table) {
+	return func(t *tui.Table) {
+		if t.Selected() != len(list.artists) {
+			return
+		}
+		err := list.fetchNextPage()
+		if err != nil {
+			log.Printf("Could not fetch next page of followed artists with %s", err)
+		}
+	}
+}
+
+func (list *followedArtistsList) onItemActivated(albums, topTracks appendReseter) func(*tui.Table) {
+	return func(t *tui.Table) {
+		selectedRow := t.Selected()
+		if selectedRow == 0 {
+			return // Selecting table header
+		}
+		if list.country == "" {
+			user, err := list.client.CurrentUser(list.ctx)
+			if err != nil {
+				log.Printf("Could not fetch current user with %s", err)
+				return
+			}
+			list.country = user.Country
+		}
+		artist := list.artists[selectedRow-1]
+		err := showArtistDetails(list.ctx, list.client, artist.ID, list.country, albums, topTracks)
+		if err != nil {
+			log.Printf("Could not show details of artist %s with %s", artist.Name, err)
+		}
+	}
+}
+
+func showArtistDetails(ctx context.Context, client SpotifyClient, artistID spotify.ID, country string, albums, topTracks appendReseter) error {
+	albumsPage, err := client.GetArtistAlbums(ctx, artistID)
+	if err != nil {
+		return fmt.Errorf("could not fetch artist albums: %v", err)
+	}
+	tracks, err := client.GetArtistsTopTracks(ctx, artistID, country)
+	if err != nil {
+		return fmt.Errorf("could not fetch artist top tracks: %v", err)
+	}
+
+	albums.resetSearchResults()
+	for _, album := range albumsPage.Albums {
+		albums.appendSearchResult(URIName{Name: album.Name, URI: album.URI})
+	}
+	topTracks.resetSearchResults()
+	for _, track := range tracks {
+		topTracks.appendSearchResult(URIName{Name: track.Name, URI: track.URI})
+	}
+	topTracks.markSavedTracks()
+	return nil
+}
@@ -0,0 +1,72 @@
+package player
+
+import (
+	"context"
+	"testing"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+func TestNewFollowedArtists(t *testing.T) {
+	followedArtists, err := NewFollowedArtists(context.Background(), NewDebugClient())
+	if err != nil {
+		t.Fatalf("Unexpected error occured: %s", err)
+	}
+	if len(followedArtists.Focusables) != 3 {
+		t.Fatalf("Expected to have 3 focusables elements, got %d", len(followedArtists.Focusables))
+	}
+}
+
+func TestFollowedArtistsListFetchesPagesUsingCursor(t *testing.T) {
+	list := newFollowedArtistsList(context.Background(), NewDebugClient())
+	for i := 0; i < 5; i++ {
+		err := list.fetchNextPage()
+		if err != nil {
+			t.Fatalf("Did not expect to fail, but it did with %v", err)
+		}
+	}
+	// DebugClient follows 45 artists, which gives 3 pages of size 20
+	if len(list.artists) != 45 {
+		t.Fatalf("Expected to fetch 45 artists, fetched %d", len(list.artists))
+	}
+	if list.hasNext {
+		t.Fatalf("Expected not to have next page after fetching all artists")
+	}
+}
+
+func TestFollowedArtistsListFetchesNextPageWhenLastRowIsSelected(t *testing.T) {
+	list := newFollowedArtistsList(context.Background(), NewDebugClient())
+	list.fetchNextPage()
+	callback := list.onSelectionChanged()
+
+	table := &tui.Table{}
+	table.SetSelected(1)
+	callback(table)
+	if len(list.artists) != 20 {
+		t.Fatalf("Expected not to fetch next page, but have %d artists", len(list.artists))
+	}
+
+	table.SetSelected(20)
+	callback(table)
+	if len(list.artists) != 40 {
+		t.Fatalf("Expected to fetch next page, but have %d artists", len(list.artists))
+	}
+}
+
+func TestShowArtistDetails(t *testing.T) {
+	albums := &FakeSearchResult{}
+	topTracks := &FakeSearchResult{}
+	err := showArtistDetails(context.Background(), NewDebugClient(), "artist1", "PL", albums, topTracks)
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if albums.resetCalls != 1 || topTracks.resetCalls != 1 {
+		t.Fatalf("Expected to reset old results once")
+	}
+	if albums.appendCalls != 2 {
+		t.Fatalf("Expected to append albums 2 times, got %d appends", albums.appendCalls)
+	}
+	if topTracks.appendCalls != 1 {
+		t.Fatalf("Expected to append top tracks once, got %d appends", topTracks.appendCalls)
+	}
+}
@@ -0,0 +1,198 @@
+package player
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/marcusolsson/tui-go"
+	"github.com/zmb3/spotify"
+)
+
+// Audiobook is an audiobook as described by Spotify Web API,
+// spotify library does not support them.
+type Audiobook struct {
+	ID            spotify.ID        `json:"id"`
+	Name          string            `json:"name"`
+	URI           spotify.URI       `json:"uri"`
+	Authors       []AudiobookPerson `json:"authors"`
+	Narrators     []AudiobookPerson `json:"narrators"`
+	Publisher     string            `json:"publisher"`
+	Description   string            `json:"description"`
+	TotalChapters int               `json:"total_chapters"`
+}
+
+// AudiobookPerson is an author or a narrator of an audiobook.
+type AudiobookPerson struct {
+	Name string `json:"name"`
+}
+
+// AudiobookPage contains audiobooks returned by the Web API.
+type AudiobookPage struct {
+	Audiobooks []Audiobook `json:"items"`
+	Total      int         `json:"total"`
+	Next       string      `json:"next"`
+}
+
+// Chapter is a single chapter of an audiobook.
+type Chapter struct {
+	ID            spotify.ID  `json:"id"`
+	Name          string      `json:"name"`
+	URI           spotify.URI `json:"uri"`
+	ChapterNumber int         `json:"chapter_number"`
+	DurationMs    int         `json:"duration_ms"`
+	ReleaseDate   string      `json:"release_date"`
+	Description   string      `json:"description"`
+}
+
+// ChapterPage contains chapters of an audiobook returned by the Web API.
+type ChapterPage struct {
+	Chapters []Chapter `json:"items"`
+	Total    int       `json:"total"`
+	Next     string    `json:"next"`
+}
+
+// Audiobooks represents view with audiobooks saved by the user, activating
+// an audiobook lists its chapters, which can be played.
+type Audiobooks struct {
+	Focusables []tui.Widget
+	Box        *tui.Box
+}
+
+var (
+	audiobooksPageSize = 50
+	chaptersPageSize   = 20
+)
+
+type chaptersList struct {
+	ctx       context.Context
+	client    SpotifyClient
+	table     *countedTable
+	audiobook *Audiobook
+	chapters  []Chapter
+	hasNext   bool
+}
+
+// NewAudiobooks creates view with audiobooks saved in the user's library.
+// Audiobooks are not available in all markets, in which case it fails.
+func NewAudiobooks(ctx context.Context, client SpotifyClient) (*Audiobooks, error) {
+	page, err := client.CurrentUsersAudiobooksOpt(ctx, &spotify.Options{Limit: &audiobooksPageSize})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch saved audiobooks: %v", err)
+	}
+	audiobooks := page.Audiobooks
+
+	audiobooksTable := newCountedTable()
+	audiobooksTable.AppendRow(
+		tui.NewLabel("Audiobook"),
+		tui.NewLabel("Author"),
+	)
+	for _, audiobook := range audiobooks {
+		audiobooksTable.AppendRow(
+			tui.NewLabel(trimWithCommasIfTooLong(audiobook.Name, uiColumnWidth)),
+			tui.NewLabel(trimWithCommasIfTooLong(audiobookPeopleNames(audiobook.Authors), uiColumnWidth)),
+		)
+	}
+	audiobooksBox := tui.NewVBox(audiobooksTable, tui.NewSpacer())
+	audiobooksBox.SetTitle("Saved audiobooks")
+	audiobooksBox.SetBorder(true)
+
+	chapters := &chaptersList{ctx: ctx, client: client, table: newCountedTable()}
+	chapters.table.OnSelectionChanged(chapters.onSelectionChanged())
+	chapters.table.OnItemActivated(chapters.onItemActivated())
+	audiobooksTable.OnItemActivated(func(t *tui.Table) {
+		selectedRow := t.Selected()
+		if selectedRow == 0 {
+			return // Selecting table header
+		}
+		audiobook := &audiobooks[selectedRow-1]
+		err := chapters.show(audiobook)
+		if err != nil {
+			log.Printf("Could not show chapters of %s with %s", audiobook.Name, err)
+		}
+	})
+
+	chaptersBox := tui.NewVBox(chapters.table, tui.NewSpacer())
+	chaptersBox.SetTitle("Chapters")
+	chaptersBox.SetBorder(true)
+	chaptersBox.SetSizePolicy(tui.Expanding, tui.Expanding)
+
+	return &Audiobooks{
+		Focusables: []tui.Widget{audiobooksTable, chapters.table},
+		Box:        tui.NewHBox(audiobooksBox, chaptersBox),
+	}, nil
+}
+
+// show replaces listed chapters with the first page of chapters of the given audiobook.
+func (list *chaptersList) show(audiobook *Audiobook) error {
+	list.audiobook = audiobook
+	list.chapters = []Chapter{}
+	list.hasNext = true
+	list.table.RemoveRows()
+	list.table.AppendRow(
+		tui.NewLabel("#"),
+		tui.NewLabel("Duration"),
+		tui.NewLabel("Chapter"),
+	)
+	return list.fetchNextPage()
+}
+
+func (list *chaptersList) fetchNextPage() error {
+	if !list.hasNext {
+		return nil
+	}
+	offset := len(list.chapters)
+	page, err := list.client.GetAudiobookChaptersOpt(list.ctx, &spotify.Options{Limit: &chaptersPageSize, Offset: &offset}, list.audiobook.ID)
+	if err != nil {
+		return fmt.Errorf("could not fetch chapters: %v", err)
+	}
+	for _, chapter := range page.Chapters {
+		list.table.AppendRow(
+			tui.NewLabel(fmt.Sprintf("%d", chapter.ChapterNumber+1)),
+			tui.NewLabel(formatEpisodeDuration(chapter.DurationMs)),
+			tui.NewLabel(trimWithCommasIfTooLong(chapter.Name, uiColumnWidth)),
+		)
+	}
+	list.chapters = append(list.chapters, page.Chapters...)
+	list.hasNext = page.Next != "" && len(page.Chapters) > 0
+	return nil
+}
+
+func (list *chaptersList) onSelectionChanged() func(*tui.Table) {
+	return func(t *tui.Table) {
+		if t.Selected() != len(list.chapters) {
+			return
+		}
+		err := list.fetchNextPage()
+		if err != nil {
+			log.Printf("Could not fetch next page of chapters with %s", err)
+		}
+	}
+}
+
+func (list *chaptersList) onItemActivated() func(*tui.Table) {
+	return func(t *tui.Table) {
+		selectedRow := t.Selected()
+		if selectedRow <= 0 || selectedRow > len(list.chapters) {
+			return // Selecting table header
+		}
+		chapter := list.chapters[selectedRow-1]
+		// Playing chapter in context of the audiobook makes following chapters play next.
+		err := list.client.PlayOpt(list.ctx, &spotify.PlayOptions{
+			PlaybackContext: &list.audiobook.URI,
+			PlaybackOffset:  &spotify.PlaybackOffset{URI: chapter.URI},
+		})
+		if err != nil {
+			notifyError(fmt.Errorf("could not play chapter %s: %v", chapter.Name, err))
+		}
+	}
+}
+
+func audiobookPeopleNames(people []AudiobookPerson) string {
+	names := make([]string, 0, len(people))
+	for _, person := range people {
+		names = append(names, person.Name)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,40 @@
+package player
+
+import (
+	"context"
+	"testing"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+func TestNewAudiobooks(t *testing.T) {
+	audiobooks, err := NewAudiobooks(context.Background(), NewDebugClient())
+	if err != nil {
+		t.Fatalf("Unexpected error occured: %s", err)
+	}
+	if len(audiobooks.Focusables) != 2 {
+		t.Fatalf("Expected to have 2 focusables elements, got %d", len(audiobooks.Focusables))
+	}
+}
+
+func TestChaptersListFetchesPagesUsingOffset(t *testing.T) {
+	list := &chaptersList{client: NewDebugClient(), table: newCountedTable()}
+	err := list.show(&Audiobook{ID: "audiobook1"})
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if len(list.chapters) != 20 {
+		t.Fatalf("Expected to fetch first page of 20 chapters, fetched %d", len(list.chapters))
+	}
+
+	table := &tui.Table{}
+	table.SetSelected(20)
+	list.onSelectionChanged()(table)
+	// DebugClient returns 30 chapters for each audiobook
+	if len(list.chapters) != 30 {
+		t.Fatalf("Expected to fetch next page, but have %d chapters", len(list.chapters))
+	}
+	if list.hasNext {
+		t.Fatalf("Expected not to have next page after fetching all chapters")
+	}
+}
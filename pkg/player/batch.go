@@ -0,0 +1,45 @@
+package player
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jedruniu/spotify-cli/pkg/config"
+)
+
+// RunBatch runs commands read from in, one in a line, i.e. `queue add spotify:track:...`, with
+// run in the order they are given. Arguments with spaces are quoted with " or ', empty lines and
+// lines starting with # are skipped, lines starting with an alias are expanded. Running stops at
+// the first command which fails, the error tells its line and has its exit code.
+func RunBatch(in io.Reader, aliases config.Aliases, run func(name string, args []string) error) error {
+	scanner := bufio.NewScanner(in)
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words, err := config.SplitCommandLine(line)
+		if err != nil {
+			return usageErrorf("line %d: %v", number, err)
+		}
+		words, err = aliases.Expand(words)
+		if err != nil {
+			return usageErrorf("line %d: %v", number, err)
+		}
+		if len(words) == 0 {
+			return usageErrorf("line %d: alias %s stands for no command", number, line)
+		}
+		if !IsCommand(words[0]) {
+			return usageErrorf("line %d: unknown command %s, known are %v", number, words[0], Commands())
+		}
+		if err := run(words[0], words[1:]); err != nil {
+			return commandError(err, "line %d: could not run %s command", number, words[0])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("could not read commands: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package player
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jedruniu/spotify-cli/pkg/config"
+	"github.com/zmb3/spotify"
+)
+
+func TestRunBatch(t *testing.T) {
+	input := `# generated by a script
+play spotify:album:album1
+
+queue add spotify:track:1 'spotify:track:2'
+devices transfer "Living Room"
+volume set 40
+kitchen
+`
+	aliases := config.Aliases{"kitchen": `devices transfer "Kitchen speaker"`}
+	run := [][]string{}
+	err := RunBatch(strings.NewReader(input), aliases, func(name string, args []string) error {
+		run = append(run, append([]string{name}, args...))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	expected := [][]string{
+		{"play", "spotify:album:album1"},
+		{"queue", "add", "spotify:track:1", "spotify:track:2"},
+		{"devices", "transfer", "Living Room"},
+		{"volume", "set", "40"},
+		{"devices", "transfer", "Kitchen speaker"},
+	}
+	if !reflect.DeepEqual(run, expected) {
+		t.Fatalf("Expected commands %v, got %v", expected, run)
+	}
+}
+
+func TestRunBatchStopsAtFailure(t *testing.T) {
+	run := 0
+	err := RunBatch(strings.NewReader("next\nvolume up\nnext\n"), nil, func(name string, args []string) error {
+		run++
+		if name == "volume" {
+			return spotify.Error{Status: 404, Message: "Player command failed: No active device found"}
+		}
+		return nil
+	})
+	if run != 2 {
+		t.Fatalf("Expected commands after the failed one not to run, %d were run", run)
+	}
+	if err == nil || ExitCode(err) != ExitNoDevice || !strings.HasPrefix(err.Error(), "line 2: could not run volume command") {
+		t.Fatalf("Expected failure of the second line with its exit code, got %v", err)
+	}
+
+	for _, input := range []string{"rewind\n", "devices transfer \"Living Room\n"} {
+		err := RunBatch(strings.NewReader(input), nil, func(name string, args []string) error { return nil })
+		if ExitCode(err) != ExitUsage {
+			t.Fatalf("Expected %q to be rejected, got %v", input, err)
+		}
+	}
+}
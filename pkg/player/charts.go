@@ -0,0 +1,357 @@
+package player
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jedruniu/spotify-cli/pkg/cache"
+	"github.com/jedruniu/spotify-cli/pkg/config"
+
+	"github.com/marcusolsson/tui-go"
+	"github.com/zmb3/spotify"
+)
+
+// Charts represents view with official chart playlists (Top 50, Viral 50)
+// for the user's market. Activating a playlist displays its ranked tracks
+// along with rank changes since the previous time the playlist was displayed.
+type Charts struct {
+	Focusables []tui.Widget
+	Box        *tui.Box
+	list       *chartsList
+	table      *countedTable
+}
+
+var (
+	chartsCategoryID   = "toplists"
+	chartsOwnerID      = "spotify"
+	chartsNamePrefixes = []string{"Top 50", "Viral 50"}
+	chartsCacheEntry   = "charts"
+	// chartsUpdatedCacheEntry keeps time at which ranks of each chart were saved.
+	chartsUpdatedCacheEntry = "charts_updated"
+	chartsTimeFormat        = "2006-01-02 15:04 MST"
+	// chartsGlobalTop50 is always available as a shortcut.
+	chartsGlobalTop50 = spotify.SimplePlaylist{
+		ID:   "37i9dQZEVXbMDoHDwVN2tF",
+		Name: "Top 50 - Global",
+		URI:  "spotify:playlist:37i9dQZEVXbMDoHDwVN2tF",
+	}
+	chartsShortcutMark = "★ "
+)
+
+// chartRanks keeps rank of each track for each of the chart playlists.
+type chartRanks map[spotify.ID]map[spotify.ID]int
+
+type chartEntry struct {
+	rank   int
+	change string
+	track  spotify.FullTrack
+}
+
+type chartsList struct {
+	ctx       context.Context
+	client    SpotifyClient
+	store     *cache.Store
+	location  *time.Location
+	playlists []spotify.SimplePlaylist
+	tracks    *countedTable
+	tracksBox *tui.Box
+	entries   []chartEntry
+	shown     *spotify.SimplePlaylist
+	saved     *savedTracks
+}
+
+// NewCharts creates view with chart playlists for the market of the current user,
+// preceded by shortcuts to well-known charts. Times of previous rankings are
+// displayed in the given location.
+func NewCharts(ctx context.Context, client SpotifyClient, store *cache.Store, location *time.Location, cfg config.Charts) (*Charts, error) {
+	user, err := client.CurrentUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch current user: %v", err)
+	}
+	marketPlaylists, err := findChartPlaylists(ctx, client, user.Country)
+	if err != nil {
+		return nil, err
+	}
+	shortcuts := chartShortcuts(ctx, client, cfg)
+	playlists := mergeChartPlaylists(shortcuts, marketPlaylists)
+
+	playlistsTable := newCountedTable()
+	for i, playlist := range playlists {
+		name := playlist.Name
+		if i < len(shortcuts) {
+			name = chartsShortcutMark + name
+		}
+		playlistsTable.AppendRow(tui.NewLabel(name))
+	}
+	var playlistsBox *tui.Box
+	playlistsTypeAhead := newTypeAheadTable(playlistsTable, jumpToPlaylist(playlistsTable, playlists), func(prefix string) {
+		playlistsBox.SetTitle(typeAheadTitle("Charts", prefix))
+	})
+	playlistsBox = tui.NewVBox(playlistsTypeAhead, tui.NewSpacer())
+	playlistsBox.SetTitle("Charts")
+	playlistsBox.SetBorder(true)
+
+	tracksTable := newCountedTable()
+	tracksTable.SetColumnStretch(2, 4)
+	saved := &savedTracks{ctx: ctx, client: client}
+	tracksBox := tui.NewVBox(saved.keys(tracksTable, 1), tui.NewSpacer())
+	tracksBox.SetTitle("Ranking")
+	tracksBox.SetBorder(true)
+	tracksBox.SetSizePolicy(tui.Expanding, tui.Expanding)
+
+	list := &chartsList{
+		ctx:       ctx,
+		client:    client,
+		store:     store,
+		location:  location,
+		playlists: playlists,
+		tracks:    tracksTable,
+		tracksBox: tracksBox,
+		saved:     saved,
+	}
+	playlistsTable.OnItemActivated(func(t *tui.Table) {
+		err := list.showChart(&list.playlists[t.Selected()])
+		if err != nil {
+			log.Printf("Could not show chart with %s", err)
+		}
+	})
+	tracksTable.OnItemActivated(list.onTrackActivated())
+
+	return &Charts{
+		Focusables: []tui.Widget{playlistsTable, tracksTable},
+		Box:        tui.NewHBox(playlistsBox, tracksBox),
+		list:       list,
+		table:      playlistsTable,
+	}, nil
+}
+
+// OnAddToPlaylist sets function called with chart tracks which should be added to a playlist.
+func (charts *Charts) OnAddToPlaylist(fn func([]spotify.ID)) {
+	charts.list.saved.addToPlaylist = fn
+}
+
+// ShowChart displays ranking of the chart whose name contains given text,
+// i.e. "global" shows Top 50 - Global.
+func (charts *Charts) ShowChart(name string) error {
+	for i, playlist := range charts.list.playlists {
+		if strings.Contains(strings.ToLower(playlist.Name), strings.ToLower(name)) {
+			charts.table.SetSelected(i)
+			return charts.list.showChart(&charts.list.playlists[i])
+		}
+	}
+	return fmt.Errorf("there is no chart named %q", name)
+}
+
+// chartShortcuts returns Top 50 Global, Top 50 of the configured country, resolved
+// by searching toplists of its market, and charts configured by their IDs.
+func chartShortcuts(ctx context.Context, client SpotifyClient, cfg config.Charts) []spotify.SimplePlaylist {
+	shortcuts := []spotify.SimplePlaylist{chartsGlobalTop50}
+	if cfg.Country != "" {
+		playlists, err := findChartPlaylists(ctx, client, cfg.Country)
+		if err != nil {
+			log.Printf("Could not find charts of %s: %s", cfg.Country, err)
+		}
+		for _, playlist := range playlists {
+			if strings.HasPrefix(playlist.Name, "Top 50") && playlist.ID != chartsGlobalTop50.ID {
+				shortcuts = append(shortcuts, playlist)
+				break
+			}
+		}
+	}
+
+	names := make([]string, 0, len(cfg.Shortcuts))
+	for name := range cfg.Shortcuts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		id := cfg.Shortcuts[name]
+		shortcuts = append(shortcuts, spotify.SimplePlaylist{
+			ID:   spotify.ID(id),
+			Name: name,
+			URI:  spotify.URI("spotify:playlist:" + id),
+		})
+	}
+	return shortcuts
+}
+
+// mergeChartPlaylists appends playlists to shortcuts skipping the ones already there.
+func mergeChartPlaylists(shortcuts, playlists []spotify.SimplePlaylist) []spotify.SimplePlaylist {
+	merged := append([]spotify.SimplePlaylist{}, shortcuts...)
+	for _, playlist := range playlists {
+		duplicate := false
+		for _, shortcut := range shortcuts {
+			if shortcut.ID == playlist.ID {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			merged = append(merged, playlist)
+		}
+	}
+	return merged
+}
+
+func findChartPlaylists(ctx context.Context, client SpotifyClient, country string) ([]spotify.SimplePlaylist, error) {
+	opt := &spotify.Options{}
+	if country != "" {
+		opt.Country = &country
+	}
+	page, err := client.GetCategoryPlaylistsOpt(ctx, chartsCategoryID, opt)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch chart playlists: %v", err)
+	}
+	charts := make([]spotify.SimplePlaylist, 0)
+	for _, playlist := range page.Playlists {
+		if isChartPlaylist(playlist) {
+			charts = append(charts, playlist)
+		}
+	}
+	return charts, nil
+}
+
+func isChartPlaylist(playlist spotify.SimplePlaylist) bool {
+	if playlist.Owner.ID != chartsOwnerID {
+		return false
+	}
+	for _, prefix := range chartsNamePrefixes {
+		if strings.HasPrefix(playlist.Name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (list *chartsList) showChart(playlist *spotify.SimplePlaylist) error {
+	page, err := list.client.GetPlaylistTracks(list.ctx, playlist.ID)
+	if err != nil {
+		return fmt.Errorf("could not fetch tracks of %s: %v", playlist.Name, err)
+	}
+
+	ranks := chartRanks{}
+	err = list.store.Load(chartsCacheEntry, &ranks)
+	if err != nil {
+		log.Printf("Could not load previous chart ranks, rank changes won't be shown: %s", err)
+	}
+	updated := map[spotify.ID]time.Time{}
+	err = list.store.Load(chartsUpdatedCacheEntry, &updated)
+	if err != nil {
+		log.Printf("Could not load time of previous chart ranks: %s", err)
+	}
+	entries, currentRanks := rankChartTracks(page.Tracks, ranks[playlist.ID])
+	list.setRankingTitle(updated[playlist.ID])
+	ranks[playlist.ID] = currentRanks
+	updated[playlist.ID] = time.Now().UTC()
+	err = list.store.Save(chartsCacheEntry, ranks)
+	if err != nil {
+		log.Printf("Could not save chart ranks: %s", err)
+	}
+	err = list.store.Save(chartsUpdatedCacheEntry, updated)
+	if err != nil {
+		log.Printf("Could not save time of chart ranks: %s", err)
+	}
+
+	list.entries = entries
+	list.shown = playlist
+	list.tracks.RemoveRows()
+	list.tracks.AppendRow(
+		tui.NewLabel("#"),
+		tui.NewLabel("Change"),
+		tui.NewLabel("Title"),
+		tui.NewLabel("Artist"),
+		tui.NewLabel(savedTrackMark),
+	)
+	list.saved.reset()
+	for _, entry := range entries {
+		list.tracks.AppendRow(
+			tui.NewLabel(fmt.Sprintf("%d", entry.rank)),
+			tui.NewLabel(entry.change),
+			tui.NewLabel(trimWithCommasIfTooLong(entry.track.Name, uiColumnWidth)),
+			tui.NewLabel(trimWithCommasIfTooLong(artistsNames(entry.track.Artists), uiColumnWidth)),
+			list.saved.add(entry.track.ID),
+		)
+	}
+	err = list.saved.fetch()
+	if err != nil {
+		log.Printf("Could not mark saved chart tracks with %s", err)
+	}
+	return nil
+}
+
+// setRankingTitle tells since when rank changes are counted,
+// previous is zero if chart was not ranked before.
+func (list *chartsList) setRankingTitle(previous time.Time) {
+	if list.tracksBox == nil {
+		return
+	}
+	if previous.IsZero() || list.location == nil {
+		list.tracksBox.SetTitle("Ranking")
+		return
+	}
+	list.tracksBox.SetTitle("Ranking - changes since " + previous.In(list.location).Format(chartsTimeFormat))
+}
+
+func (list *chartsList) onTrackActivated() func(*tui.Table) {
+	return func(t *tui.Table) {
+		selectedRow := t.Selected()
+		if selectedRow == 0 || list.shown == nil {
+			return // Selecting table header
+		}
+		track := list.entries[selectedRow-1].track
+		err := list.client.PlayOpt(list.ctx, &spotify.PlayOptions{
+			PlaybackContext: &list.shown.URI,
+			PlaybackOffset:  &spotify.PlaybackOffset{URI: track.URI},
+		})
+		if err != nil {
+			notifyError(fmt.Errorf("could not play %s: %v", track.Name, err))
+		}
+	}
+}
+
+// rankChartTracks assigns ranks to tracks in order of their appearance and
+// compares them with previous ranks. Returned ranks should be used as previous
+// ones next time chart is ranked.
+func rankChartTracks(tracks []spotify.PlaylistTrack, previousRanks map[spotify.ID]int) ([]chartEntry, map[spotify.ID]int) {
+	entries := make([]chartEntry, 0, len(tracks))
+	ranks := make(map[spotify.ID]int, len(tracks))
+	for i, playlistTrack := range tracks {
+		rank := i + 1
+		ranks[playlistTrack.Track.ID] = rank
+		entries = append(entries, chartEntry{
+			rank:   rank,
+			change: rankChange(previousRanks, playlistTrack.Track.ID, rank),
+			track:  playlistTrack.Track,
+		})
+	}
+	return entries, ranks
+}
+
+func rankChange(previousRanks map[spotify.ID]int, id spotify.ID, rank int) string {
+	if previousRanks == nil {
+		return ""
+	}
+	previousRank, ok := previousRanks[id]
+	switch {
+	case !ok:
+		return "NEW"
+	case previousRank > rank:
+		return fmt.Sprintf("▲%d", previousRank-rank)
+	case previousRank < rank:
+		return fmt.Sprintf("▼%d", rank-previousRank)
+	default:
+		return "="
+	}
+}
+
+func artistsNames(artists []spotify.SimpleArtist) string {
+	names := make([]string, 0, len(artists))
+	for _, artist := range artists {
+		names = append(names, artist.Name)
+	}
+	return strings.Join(names, ", ")
+}
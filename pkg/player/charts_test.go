@@ -0,0 +1,155 @@
+package player
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jedruniu/spotify-cli/pkg/cache"
+	"github.com/jedruniu/spotify-cli/pkg/config"
+
+	"github.com/marcusolsson/tui-go"
+	"github.com/zmb3/spotify"
+)
+
+func TestFindChartPlaylists(t *testing.T) {
+	playlists, err := findChartPlaylists(context.Background(), NewDebugClient(), "PL")
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	// DebugClient returns 3 playlists in toplists category, but only 2 of them are charts
+	if len(playlists) != 2 {
+		t.Fatalf("Expected to find 2 chart playlists, found %d", len(playlists))
+	}
+}
+
+func TestIsChartPlaylist(t *testing.T) {
+	cases := []struct {
+		playlist spotify.SimplePlaylist
+		isChart  bool
+	}{
+		{spotify.SimplePlaylist{Name: "Top 50 - Poland", Owner: spotify.User{ID: "spotify"}}, true},
+		{spotify.SimplePlaylist{Name: "Viral 50 - Global", Owner: spotify.User{ID: "spotify"}}, true},
+		{spotify.SimplePlaylist{Name: "Top 50 - Poland", Owner: spotify.User{ID: "someone"}}, false},
+		{spotify.SimplePlaylist{Name: "Rock Classics", Owner: spotify.User{ID: "spotify"}}, false},
+	}
+	for _, c := range cases {
+		if isChart := isChartPlaylist(c.playlist); isChart != c.isChart {
+			t.Errorf("Expected %s owned by %s to be chart: %v, got %v", c.playlist.Name, c.playlist.Owner.ID, c.isChart, isChart)
+		}
+	}
+}
+
+func TestRankChartTracks(t *testing.T) {
+	tracks := []spotify.PlaylistTrack{}
+	for _, id := range []spotify.ID{"a", "b", "c", "d"} {
+		track := spotify.PlaylistTrack{}
+		track.Track.ID = id
+		tracks = append(tracks, track)
+	}
+	previousRanks := map[spotify.ID]int{"a": 3, "b": 1, "c": 3}
+
+	entries, ranks := rankChartTracks(tracks, previousRanks)
+
+	changes := []string{}
+	for _, entry := range entries {
+		changes = append(changes, entry.change)
+	}
+	expectedChanges := []string{"▲2", "▼1", "=", "NEW"}
+	if !reflect.DeepEqual(changes, expectedChanges) {
+		t.Fatalf("Expected rank changes to be %v, got %v", expectedChanges, changes)
+	}
+	expectedRanks := map[spotify.ID]int{"a": 1, "b": 2, "c": 3, "d": 4}
+	if !reflect.DeepEqual(ranks, expectedRanks) {
+		t.Fatalf("Expected ranks to be %v, got %v", expectedRanks, ranks)
+	}
+
+	entries, _ = rankChartTracks(tracks, nil)
+	if entries[0].change != "" {
+		t.Fatalf("Expected no rank change when chart is ranked for the first time, got %s", entries[0].change)
+	}
+}
+
+func TestShowChartKeepsRanksBetweenRefreshes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spotify-cli-charts")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	store := cache.NewStore(dir)
+	playlists, _ := findChartPlaylists(context.Background(), NewDebugClient(), "PL")
+	tracksBox := tui.NewVBox()
+	list := &chartsList{client: NewDebugClient(), store: store, tracks: newCountedTable(), tracksBox: tracksBox, location: time.UTC, saved: &savedTracks{client: NewDebugClient()}}
+	for i := 0; i < 2; i++ {
+		if err := list.showChart(&playlists[0]); err != nil {
+			t.Fatalf("Did not expect to fail, but it did with %v", err)
+		}
+	}
+	if list.entries[0].change != "=" {
+		t.Fatalf("Expected rank not to change between refreshes, got %s", list.entries[0].change)
+	}
+	updated := map[spotify.ID]time.Time{}
+	if err := store.Load(chartsUpdatedCacheEntry, &updated); err != nil || updated[playlists[0].ID].IsZero() {
+		t.Fatalf("Expected time of ranking to be saved, got %v, %v", updated, err)
+	}
+}
+
+func TestChartShortcuts(t *testing.T) {
+	shortcuts := chartShortcuts(context.Background(), NewDebugClient(), config.Charts{})
+	if len(shortcuts) != 1 || shortcuts[0].ID != chartsGlobalTop50.ID {
+		t.Fatalf("Expected only Top 50 Global shortcut without configuration, got %v", shortcuts)
+	}
+
+	shortcuts = chartShortcuts(context.Background(), NewDebugClient(), config.Charts{
+		Country:   "PL",
+		Shortcuts: map[string]string{"Top 50 - Sweden": "sweden", "Viral 50 - Sweden": "viralsweden"},
+	})
+	names := []string{}
+	for _, shortcut := range shortcuts {
+		names = append(names, shortcut.Name)
+	}
+	// DebugClient returns "Top 50 - Global" as Top 50 of every country
+	expectedNames := []string{"Top 50 - Global", "Top 50 - Global", "Top 50 - Sweden", "Viral 50 - Sweden"}
+	if !reflect.DeepEqual(names, expectedNames) {
+		t.Fatalf("Expected shortcuts %v, got %v", expectedNames, names)
+	}
+	if shortcuts[2].URI != "spotify:playlist:sweden" {
+		t.Fatalf("Expected configured shortcut to have playlist URI, got %s", shortcuts[2].URI)
+	}
+}
+
+func TestMergeChartPlaylistsSkipsShortcuts(t *testing.T) {
+	shortcuts := []spotify.SimplePlaylist{{ID: "a"}, {ID: "b"}}
+	playlists := []spotify.SimplePlaylist{{ID: "b"}, {ID: "c"}}
+	merged := mergeChartPlaylists(shortcuts, playlists)
+	expected := []spotify.SimplePlaylist{{ID: "a"}, {ID: "b"}, {ID: "c"}}
+	if !reflect.DeepEqual(merged, expected) {
+		t.Fatalf("Expected merged playlists to be %v, got %v", expected, merged)
+	}
+}
+
+func TestChartsShowChart(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spotify-cli-charts")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	charts, err := NewCharts(context.Background(), NewDebugClient(), cache.NewStore(dir), time.UTC, config.Charts{})
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if err := charts.ShowChart("viral"); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if charts.list.shown.ID != "viral50global" {
+		t.Fatalf("Expected Viral 50 to be shown, got %s", charts.list.shown.Name)
+	}
+	if err := charts.ShowChart("not existing"); err == nil {
+		t.Fatalf("Expected to fail for not existing chart, but it didn't")
+	}
+}
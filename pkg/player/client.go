@@ -0,0 +1,509 @@
+package player
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/zmb3/spotify"
+)
+
+// Client is a SpotifyClient talking with Spotify Web API. It sends requests
+// with spotify.Client, along with requests which spotify library does not support.
+type Client struct {
+	http *http.Client
+	// rewriting sends requests of spotify library to the base URL.
+	rewriting *http.Client
+	baseURL   string
+	// market and locale are added to requests whose results depend on the region.
+	market, locale string
+}
+
+var spotifyAPIBaseURL = "https://api.spotify.com/v1/"
+
+// NewClient creates Client which sends requests with the given
+// HTTP client, it is expected to authorize these requests.
+func NewClient(httpClient *http.Client) *Client {
+	c := &Client{
+		http:    httpClient,
+		baseURL: spotifyAPIBaseURL,
+	}
+	// spotify library always sends requests to Spotify Web API, they are sent to the base URL instead
+	rewriting := *httpClient
+	rewriting.Transport = &baseURLTransport{client: c, base: httpClient.Transport}
+	c.rewriting = &rewriting
+	return c
+}
+
+// api returns spotify.Client whose requests are cancelled once the context is done,
+// spotify library does not take the context on its own.
+func (c *Client) api(ctx context.Context) *spotify.Client {
+	httpClient := *c.rewriting
+	httpClient.Transport = &contextTransport{ctx: ctx, base: c.rewriting.Transport}
+	client := spotify.NewClient(&httpClient)
+	return &client
+}
+
+// contextTransport sends requests with the context.
+type contextTransport struct {
+	ctx  context.Context
+	base http.RoundTripper
+}
+
+// RoundTrip sends the request with the base transport, it is cancelled once the context is done.
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.base.RoundTrip(req.WithContext(t.ctx))
+}
+
+// SetBaseURL sends requests to another Spotify Web API, i.e. a local mock server or a gateway.
+func (c *Client) SetBaseURL(baseURL string) {
+	c.baseURL = strings.TrimSuffix(baseURL, "/") + "/"
+}
+
+// SetMarket gives search and browse results for the market, i.e. PL, instead of the one Spotify
+// guesses, and names browse categories in the language of the locale, i.e. pl_PL. Either of them
+// can be empty. Market given by the request itself, i.e. for charts of another country, is kept.
+func (c *Client) SetMarket(market, locale string) {
+	c.market, c.locale = market, locale
+}
+
+// regionalParams are names of query parameters of the market and the locale, by path of requests
+// which take them, locale is not taken when its name is empty.
+var regionalParams = []struct {
+	path           *regexp.Regexp
+	market, locale string
+}{
+	{regexp.MustCompile(`/search$`), "market", ""},
+	{regexp.MustCompile(`/browse/(featured-playlists|categories(/[^/]+)?)$`), "country", "locale"},
+	{regexp.MustCompile(`/browse/(new-releases|categories/[^/]+/playlists)$`), "country", ""},
+}
+
+// regional adds the market and the locale to the query of the request, unless it has them already.
+func (c *Client) regional(req *http.Request) *http.Request {
+	for _, params := range regionalParams {
+		if !params.path.MatchString(req.URL.Path) {
+			continue
+		}
+		query := req.URL.Query()
+		for name, value := range map[string]string{params.market: c.market, params.locale: c.locale} {
+			if name != "" && value != "" && query.Get(name) == "" {
+				query.Set(name, value)
+			}
+		}
+		regional := *req
+		regional.URL = &url.URL{}
+		*regional.URL = *req.URL
+		regional.URL.RawQuery = query.Encode()
+		return &regional
+	}
+	return req
+}
+
+// baseURLTransport sends requests to Spotify Web API to the base URL of the client instead,
+// with the market and the locale of the client.
+type baseURLTransport struct {
+	client *Client
+	base   http.RoundTripper
+}
+
+// RoundTrip sends the request with the base transport, to the base URL of the client.
+func (t *baseURLTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	req = t.client.regional(req)
+	if t.client.baseURL == spotifyAPIBaseURL || !strings.HasPrefix(req.URL.String(), spotifyAPIBaseURL) {
+		return base.RoundTrip(req)
+	}
+	rewritten, err := url.Parse(t.client.baseURL + strings.TrimPrefix(req.URL.String(), spotifyAPIBaseURL))
+	if err != nil {
+		return nil, err
+	}
+	redirected := *req
+	redirected.URL = rewritten
+	redirected.Host = ""
+	return base.RoundTrip(&redirected)
+}
+
+// PlayerCurrentlyPlaying gets information about currently playing item,
+// unlike spotify.Client it handles podcast episodes as well as tracks.
+func (c *Client) PlayerCurrentlyPlaying(ctx context.Context) (*PlaybackItem, error) {
+	var result struct {
+		Timestamp       int64                   `json:"timestamp"`
+		PlaybackContext spotify.PlaybackContext `json:"context"`
+		Progress        int                     `json:"progress_ms"`
+		Playing         bool                    `json:"is_playing"`
+		Type            string                  `json:"currently_playing_type"`
+		Item            json.RawMessage         `json:"item"`
+	}
+	err := c.get(ctx, "me/player/currently-playing", url.Values{"additional_types": {"track,episode"}}, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	item := &PlaybackItem{CurrentlyPlaying: spotify.CurrentlyPlaying{
+		Timestamp:       result.Timestamp,
+		PlaybackContext: result.PlaybackContext,
+		Progress:        result.Progress,
+		Playing:         result.Playing,
+	}}
+	if len(result.Item) == 0 || string(result.Item) == "null" {
+		return item, nil
+	}
+	switch result.Type {
+	case "episode":
+		item.Episode = &spotify.EpisodePage{}
+		err = json.Unmarshal(result.Item, item.Episode)
+	default:
+		item.Item = &spotify.FullTrack{}
+		err = json.Unmarshal(result.Item, item.Item)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not decode currently playing %s: %v", result.Type, err)
+	}
+	return item, nil
+}
+
+// CurrentUsersAudiobooksOpt gets audiobooks saved in the user's library.
+func (c *Client) CurrentUsersAudiobooksOpt(ctx context.Context, opt *spotify.Options) (*AudiobookPage, error) {
+	var result AudiobookPage
+	err := c.get(ctx, "me/audiobooks", optionsValues(opt), &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetAudiobookChaptersOpt gets chapters of the audiobook available in the user's market.
+func (c *Client) GetAudiobookChaptersOpt(ctx context.Context, opt *spotify.Options, id spotify.ID) (*ChapterPage, error) {
+	values := optionsValues(opt)
+	if values.Get("market") == "" {
+		values.Set("market", "from_token")
+	}
+	var result ChapterPage
+	err := c.get(ctx, "audiobooks/"+string(id)+"/chapters", values, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetAlbumCredits gets album along with its label and copyrights.
+func (c *Client) GetAlbumCredits(ctx context.Context, albumID spotify.ID) (*AlbumCredits, error) {
+	var result AlbumCredits
+	err := c.get(ctx, "albums/"+string(albumID), nil, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// AddAlbumsToLibrary saves albums to the user's library.
+func (c *Client) AddAlbumsToLibrary(ctx context.Context, albumIDs ...spotify.ID) error {
+	return c.modifyLibrary(ctx, http.MethodPut, "me/albums", albumIDs)
+}
+
+// RemoveAlbumsFromLibrary removes albums from the user's library.
+func (c *Client) RemoveAlbumsFromLibrary(ctx context.Context, albumIDs ...spotify.ID) error {
+	return c.modifyLibrary(ctx, http.MethodDelete, "me/albums", albumIDs)
+}
+
+// ChangePlaylistCollaborative lets other users edit the playlist, or stops it. Only
+// private playlists can be collaborative, so the playlist is made private as well.
+func (c *Client) ChangePlaylistCollaborative(ctx context.Context, playlistID spotify.ID, collaborative bool) error {
+	body := struct {
+		Collaborative bool  `json:"collaborative"`
+		Public        *bool `json:"public,omitempty"`
+	}{Collaborative: collaborative}
+	if collaborative {
+		public := false
+		body.Public = &public
+	}
+	bodyJSON, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPut, c.baseURL+"playlists/"+string(playlistID), bytes.NewReader(bodyJSON))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.http.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return decodeError(resp)
+	}
+	return nil
+}
+
+func (c *Client) modifyLibrary(ctx context.Context, method, path string, ids []spotify.ID) error {
+	values := make([]string, 0, len(ids))
+	for _, id := range ids {
+		values = append(values, string(id))
+	}
+	spotifyURL := c.baseURL + path + "?" + url.Values{"ids": {strings.Join(values, ",")}}.Encode()
+	req, err := http.NewRequest(method, spotifyURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.http.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return decodeError(resp)
+	}
+	return nil
+}
+
+func optionsValues(opt *spotify.Options) url.Values {
+	values := url.Values{}
+	if opt == nil {
+		return values
+	}
+	if opt.Country != nil {
+		values.Set("market", *opt.Country)
+	}
+	if opt.Limit != nil {
+		values.Set("limit", strconv.Itoa(*opt.Limit))
+	}
+	if opt.Offset != nil {
+		values.Set("offset", strconv.Itoa(*opt.Offset))
+	}
+	return values
+}
+
+func (c *Client) get(ctx context.Context, path string, values url.Values, result interface{}) error {
+	spotifyURL := c.baseURL + path
+	if params := values.Encode(); params != "" {
+		spotifyURL += "?" + params
+	}
+	req, err := http.NewRequest(http.MethodGet, spotifyURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.http.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return decodeError(resp)
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+func decodeError(resp *http.Response) error {
+	var e struct {
+		E spotify.Error `json:"error"`
+	}
+	err := json.NewDecoder(resp.Body).Decode(&e)
+	if err != nil || e.E.Message == "" {
+		return fmt.Errorf("spotify: HTTP %d: %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+	return e.E
+}
+
+// Requests which spotify library supports are sent with its client.
+
+func (c *Client) Pause(ctx context.Context) error {
+	return c.api(ctx).Pause()
+}
+
+func (c *Client) Previous(ctx context.Context) error {
+	return c.api(ctx).Previous()
+}
+
+func (c *Client) Next(ctx context.Context) error {
+	return c.api(ctx).Next()
+}
+
+func (c *Client) Volume(ctx context.Context, percent int) error {
+	return c.api(ctx).Volume(percent)
+}
+
+func (c *Client) Seek(ctx context.Context, position int) error {
+	return c.api(ctx).Seek(position)
+}
+
+func (c *Client) Shuffle(ctx context.Context, shuffle bool) error {
+	return c.api(ctx).Shuffle(shuffle)
+}
+
+func (c *Client) Repeat(ctx context.Context, state string) error {
+	return c.api(ctx).Repeat(state)
+}
+
+func (c *Client) PlayerState(ctx context.Context) (*spotify.PlayerState, error) {
+	return c.api(ctx).PlayerState()
+}
+
+func (c *Client) QueueSong(ctx context.Context, trackID spotify.ID) error {
+	return c.api(ctx).QueueSong(trackID)
+}
+
+// PlayerQueue gets the tracks which are played next, spotify.Client cannot request them.
+func (c *Client) PlayerQueue(ctx context.Context) (*Queue, error) {
+	var result Queue
+	if err := c.get(ctx, "me/player/queue", nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c *Client) PlayerDevices(ctx context.Context) ([]spotify.PlayerDevice, error) {
+	return c.api(ctx).PlayerDevices()
+}
+
+func (c *Client) TransferPlayback(ctx context.Context, deviceID spotify.ID, play bool) error {
+	return c.api(ctx).TransferPlayback(deviceID, play)
+}
+
+func (c *Client) CurrentUser(ctx context.Context) (*spotify.PrivateUser, error) {
+	return c.api(ctx).CurrentUser()
+}
+
+func (c *Client) Play(ctx context.Context) error {
+	return c.api(ctx).Play()
+}
+
+func (c *Client) PlayOpt(ctx context.Context, opt *spotify.PlayOptions) error {
+	return c.api(ctx).PlayOpt(opt)
+}
+
+func (c *Client) Search(ctx context.Context, query string, searchType spotify.SearchType) (*spotify.SearchResult, error) {
+	return c.api(ctx).Search(query, searchType)
+}
+
+func (c *Client) CurrentUsersAlbumsOpt(ctx context.Context, opt *spotify.Options) (*spotify.SavedAlbumPage, error) {
+	return c.api(ctx).CurrentUsersAlbumsOpt(opt)
+}
+
+func (c *Client) CurrentUsersFollowedArtistsOpt(ctx context.Context, limit int, after string) (*spotify.FullArtistCursorPage, error) {
+	return c.api(ctx).CurrentUsersFollowedArtistsOpt(limit, after)
+}
+
+func (c *Client) GetArtistAlbums(ctx context.Context, artistID spotify.ID) (*spotify.SimpleAlbumPage, error) {
+	return c.api(ctx).GetArtistAlbums(artistID)
+}
+
+func (c *Client) GetArtistsTopTracks(ctx context.Context, artistID spotify.ID, country string) ([]spotify.FullTrack, error) {
+	return c.api(ctx).GetArtistsTopTracks(artistID, country)
+}
+
+func (c *Client) GetRelatedArtists(ctx context.Context, artistID spotify.ID) ([]spotify.FullArtist, error) {
+	return c.api(ctx).GetRelatedArtists(artistID)
+}
+
+func (c *Client) FollowArtist(ctx context.Context, artistIDs ...spotify.ID) error {
+	return c.api(ctx).FollowArtist(artistIDs...)
+}
+
+func (c *Client) UnfollowArtist(ctx context.Context, artistIDs ...spotify.ID) error {
+	return c.api(ctx).UnfollowArtist(artistIDs...)
+}
+
+func (c *Client) CurrentUserFollows(ctx context.Context, t string, ids ...spotify.ID) ([]bool, error) {
+	return c.api(ctx).CurrentUserFollows(t, ids...)
+}
+
+func (c *Client) CurrentUsersPlaylistsOpt(ctx context.Context, opt *spotify.Options) (*spotify.SimplePlaylistPage, error) {
+	return c.api(ctx).CurrentUsersPlaylistsOpt(opt)
+}
+
+func (c *Client) GetCategoryPlaylistsOpt(ctx context.Context, catID string, opt *spotify.Options) (*spotify.SimplePlaylistPage, error) {
+	return c.api(ctx).GetCategoryPlaylistsOpt(catID, opt)
+}
+
+func (c *Client) GetPlaylistTracks(ctx context.Context, playlistID spotify.ID) (*spotify.PlaylistTrackPage, error) {
+	return c.api(ctx).GetPlaylistTracks(playlistID)
+}
+
+func (c *Client) CurrentUsersShowsOpt(ctx context.Context, opt *spotify.Options) (*spotify.SavedShowPage, error) {
+	return c.api(ctx).CurrentUsersShowsOpt(opt)
+}
+
+func (c *Client) GetShowEpisodesOpt(ctx context.Context, opt *spotify.Options, id string) (*spotify.SimpleEpisodePage, error) {
+	return c.api(ctx).GetShowEpisodesOpt(opt, id)
+}
+
+func (c *Client) CurrentUsersTopTracksOpt(ctx context.Context, opt *spotify.Options) (*spotify.FullTrackPage, error) {
+	return c.api(ctx).CurrentUsersTopTracksOpt(opt)
+}
+
+func (c *Client) CurrentUsersTopArtistsOpt(ctx context.Context, opt *spotify.Options) (*spotify.FullArtistPage, error) {
+	return c.api(ctx).CurrentUsersTopArtistsOpt(opt)
+}
+
+func (c *Client) GetRecommendations(ctx context.Context, seeds spotify.Seeds, trackAttributes *spotify.TrackAttributes, opt *spotify.Options) (*spotify.Recommendations, error) {
+	return c.api(ctx).GetRecommendations(seeds, trackAttributes, opt)
+}
+
+func (c *Client) PlayerRecentlyPlayedOpt(ctx context.Context, opt *spotify.RecentlyPlayedOptions) ([]spotify.RecentlyPlayedItem, error) {
+	return c.api(ctx).PlayerRecentlyPlayedOpt(opt)
+}
+
+func (c *Client) GetPlaylistOpt(ctx context.Context, playlistID spotify.ID, fields string) (*spotify.FullPlaylist, error) {
+	return c.api(ctx).GetPlaylistOpt(playlistID, fields)
+}
+
+func (c *Client) GetPlaylistTracksOpt(ctx context.Context, playlistID spotify.ID, opt *spotify.Options, fields string) (*spotify.PlaylistTrackPage, error) {
+	return c.api(ctx).GetPlaylistTracksOpt(playlistID, opt, fields)
+}
+
+func (c *Client) AddTracksToPlaylist(ctx context.Context, playlistID spotify.ID, trackIDs ...spotify.ID) (string, error) {
+	return c.api(ctx).AddTracksToPlaylist(playlistID, trackIDs...)
+}
+
+func (c *Client) RemoveTracksFromPlaylistOpt(ctx context.Context, playlistID spotify.ID, tracks []spotify.TrackToRemove, snapshotID string) (string, error) {
+	return c.api(ctx).RemoveTracksFromPlaylistOpt(playlistID, tracks, snapshotID)
+}
+
+func (c *Client) ReorderPlaylistTracks(ctx context.Context, playlistID spotify.ID, opt spotify.PlaylistReorderOptions) (string, error) {
+	return c.api(ctx).ReorderPlaylistTracks(playlistID, opt)
+}
+
+func (c *Client) CreatePlaylistForUser(ctx context.Context, userID string, playlistName string, description string, public bool) (*spotify.FullPlaylist, error) {
+	return c.api(ctx).CreatePlaylistForUser(userID, playlistName, description, public)
+}
+
+func (c *Client) ChangePlaylistNameAccessAndDescription(ctx context.Context, playlistID spotify.ID, newName string, newDescription string, public bool) error {
+	return c.api(ctx).ChangePlaylistNameAccessAndDescription(playlistID, newName, newDescription, public)
+}
+
+func (c *Client) AddTracksToLibrary(ctx context.Context, trackIDs ...spotify.ID) error {
+	return c.api(ctx).AddTracksToLibrary(trackIDs...)
+}
+
+func (c *Client) RemoveTracksFromLibrary(ctx context.Context, trackIDs ...spotify.ID) error {
+	return c.api(ctx).RemoveTracksFromLibrary(trackIDs...)
+}
+
+func (c *Client) UserHasTracks(ctx context.Context, trackIDs ...spotify.ID) ([]bool, error) {
+	return c.api(ctx).UserHasTracks(trackIDs...)
+}
+
+func (c *Client) CurrentUsersTracksOpt(ctx context.Context, opt *spotify.Options) (*spotify.SavedTrackPage, error) {
+	return c.api(ctx).CurrentUsersTracksOpt(opt)
+}
+
+func (c *Client) GetAudioFeatures(ctx context.Context, ids ...spotify.ID) ([]*spotify.AudioFeatures, error) {
+	return c.api(ctx).GetAudioFeatures(ids...)
+}
@@ -0,0 +1,266 @@
+package player
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/zmb3/spotify"
+)
+
+func newTestClient(handler http.HandlerFunc) (*Client, func()) {
+	server := httptest.NewServer(handler)
+	client := NewClient(server.Client())
+	client.baseURL = server.URL + "/"
+	return client, server.Close
+}
+
+func TestClientPlayerCurrentlyPlaying(t *testing.T) {
+	cases := []struct {
+		response        string
+		expectedTrack   string
+		expectedEpisode string
+	}{
+		{
+			response:      `{"is_playing": true, "currently_playing_type": "track", "item": {"name": "Track", "album": {"name": "Album"}}}`,
+			expectedTrack: "Track",
+		},
+		{
+			response:        `{"is_playing": true, "currently_playing_type": "episode", "item": {"name": "Episode", "show": {"name": "Show"}}}`,
+			expectedEpisode: "Episode",
+		},
+		{
+			response: `{"is_playing": false, "currently_playing_type": "unknown", "item": null}`,
+		},
+	}
+	for _, c := range cases {
+		client, closeServer := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/me/player/currently-playing" {
+				t.Errorf("Unexpected request to %s", r.URL.Path)
+			}
+			if r.URL.Query().Get("additional_types") != "track,episode" {
+				t.Errorf("Expected to ask for episodes as well, asked for %s", r.URL.Query().Get("additional_types"))
+			}
+			w.Write([]byte(c.response))
+		})
+		item, err := client.PlayerCurrentlyPlaying(context.Background())
+		closeServer()
+		if err != nil {
+			t.Fatalf("Did not expect to fail, but it did with %v", err)
+		}
+		if c.expectedTrack != "" && (item.Item == nil || item.Item.Name != c.expectedTrack) {
+			t.Errorf("Expected track %s to be played, got %#v", c.expectedTrack, item.Item)
+		}
+		if c.expectedEpisode != "" && (item.Episode == nil || item.Episode.Name != c.expectedEpisode) {
+			t.Errorf("Expected episode %s to be played, got %#v", c.expectedEpisode, item.Episode)
+		}
+		if c.expectedTrack == "" && c.expectedEpisode == "" && (item.Item != nil || item.Episode != nil) {
+			t.Errorf("Expected nothing to be played, got %#v", item)
+		}
+	}
+}
+
+func TestClientPlayerCurrentlyPlayingNothing(t *testing.T) {
+	client, closeServer := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	defer closeServer()
+	item, err := client.PlayerCurrentlyPlaying(context.Background())
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if item.Item != nil || item.Episode != nil {
+		t.Fatalf("Expected nothing to be played, got %#v", item)
+	}
+}
+
+func TestClientSendsRequestsToBaseURL(t *testing.T) {
+	requested := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.Method+" "+r.URL.RequestURI())
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	client := NewClient(server.Client())
+	client.SetBaseURL(server.URL + "/mock/v1")
+
+	// Next is sent by spotify library, the other one by Client itself
+	if err := client.Next(context.Background()); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if err := client.AddAlbumsToLibrary(context.Background(), "album"); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	expected := []string{"POST /mock/v1/me/player/next", "PUT /mock/v1/me/albums?ids=album"}
+	if !reflect.DeepEqual(requested, expected) {
+		t.Fatalf("Expected requests %v, got %v", expected, requested)
+	}
+}
+
+func TestClientSendsMarketWithSearchAndBrowse(t *testing.T) {
+	requested := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.RequestURI())
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	client := NewClient(server.Client())
+	client.SetBaseURL(server.URL)
+	client.SetMarket("PL", "pl_PL")
+
+	if _, err := client.Search(context.Background(), "abba", spotify.SearchTypeTrack); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	country := "SE"
+	if _, err := client.GetCategoryPlaylistsOpt(context.Background(), "toplists", &spotify.Options{Country: &country}); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if _, err := client.CurrentUsersAlbumsOpt(context.Background(), nil); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	expected := []string{
+		"/search?market=PL&q=abba&type=track",
+		"/browse/categories/toplists/playlists?country=SE",
+		"/me/albums",
+	}
+	if !reflect.DeepEqual(requested, expected) {
+		t.Fatalf("Expected requests %v, got %v", expected, requested)
+	}
+}
+
+func TestClientCancelsRequestsWithContext(t *testing.T) {
+	requested := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested++
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	client := NewClient(server.Client())
+	client.SetBaseURL(server.URL)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Next is sent by spotify library, the other one by Client itself
+	if err := client.Next(ctx); err == nil {
+		t.Fatalf("Expected request of spotify library to fail once cancelled")
+	}
+	if _, err := client.PlayerCurrentlyPlaying(ctx); err == nil {
+		t.Fatalf("Expected request to fail once cancelled")
+	}
+	if requested != 0 {
+		t.Fatalf("Expected cancelled requests not to be sent, got %d", requested)
+	}
+}
+
+func TestClientDecodesErrors(t *testing.T) {
+	client, closeServer := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error": {"status": 401, "message": "The access token expired"}}`))
+	})
+	defer closeServer()
+	_, err := client.PlayerCurrentlyPlaying(context.Background())
+	if err == nil || err.Error() != "The access token expired" {
+		t.Fatalf("Expected to fail with message from Spotify, got %v", err)
+	}
+}
+
+func TestClientGetAudiobookChaptersOpt(t *testing.T) {
+	client, closeServer := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/audiobooks/book/chapters" {
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("market") != "from_token" || r.URL.Query().Get("limit") != "2" {
+			t.Errorf("Unexpected query %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"items": [{"name": "Chapter 1", "chapter_number": 0}, {"name": "Chapter 2", "chapter_number": 1}], "total": 2}`))
+	})
+	defer closeServer()
+	limit := 2
+	page, err := client.GetAudiobookChaptersOpt(context.Background(), &spotify.Options{Limit: &limit}, "book")
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if len(page.Chapters) != 2 || page.Chapters[1].Name != "Chapter 2" {
+		t.Fatalf("Expected to decode 2 chapters, got %#v", page.Chapters)
+	}
+}
+
+func TestClientPlayerQueue(t *testing.T) {
+	client, closeServer := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/me/player/queue" {
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"currently_playing": {"name": "Idioteque"}, "queue": [{"name": "Morning Bell"}, {"name": "Motion Picture Soundtrack"}]}`))
+	})
+	defer closeServer()
+	queue, err := client.PlayerQueue(context.Background())
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if queue.CurrentlyPlaying == nil || queue.CurrentlyPlaying.Name != "Idioteque" || len(queue.Queue) != 2 || queue.Queue[1].Name != "Motion Picture Soundtrack" {
+		t.Fatalf("Expected to decode played and 2 queued tracks, got %#v", queue)
+	}
+}
+
+func TestClientModifiesAlbumsInLibrary(t *testing.T) {
+	requests := []string{}
+	client, closeServer := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/me/albums" {
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+		requests = append(requests, r.Method+" "+r.URL.Query().Get("ids"))
+	})
+	defer closeServer()
+	if err := client.AddAlbumsToLibrary(context.Background(), "first", "second"); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if err := client.RemoveAlbumsFromLibrary(context.Background(), "first"); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	expected := []string{"PUT first,second", "DELETE first"}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Fatalf("Expected requests %v, got %v", expected, requests)
+	}
+}
+
+func TestClientChangePlaylistCollaborative(t *testing.T) {
+	bodies := []string{}
+	client, closeServer := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/playlists/playlist" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+	})
+	defer closeServer()
+	if err := client.ChangePlaylistCollaborative(context.Background(), "playlist", true); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if err := client.ChangePlaylistCollaborative(context.Background(), "playlist", false); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	expected := []string{`{"collaborative":true,"public":false}`, `{"collaborative":false}`}
+	if !reflect.DeepEqual(bodies, expected) {
+		t.Fatalf("Expected bodies %v, got %v", expected, bodies)
+	}
+}
+
+func TestClientGetAlbumCredits(t *testing.T) {
+	client, closeServer := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/albums/album" {
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"name": "Album", "label": "Label", "copyrights": [{"text": "2020 Label", "type": "C"}], "tracks": {"items": [{"name": "Track"}]}}`))
+	})
+	defer closeServer()
+	album, err := client.GetAlbumCredits(context.Background(), "album")
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if album.Label != "Label" || len(album.Copyrights) != 1 || len(album.Tracks.Tracks) != 1 {
+		t.Fatalf("Expected to decode album label, copyrights and tracks, got %#v", album)
+	}
+}
@@ -0,0 +1,860 @@
+package player
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jedruniu/spotify-cli/pkg/atomicfile"
+
+	"github.com/zmb3/spotify"
+)
+
+// Command is run from the command line instead of starting the player, i.e. with
+// `spotify-cli next` bound to a key of the window manager. Its output is written to out.
+type Command func(ctx context.Context, client SpotifyClient, args []string, out io.Writer) error
+
+// commands are run by their name, given as the first argument.
+var commands = map[string]Command{
+	"play":     playCommand,
+	"pause":    withoutArguments("pause", func(ctx context.Context, client SpotifyClient) error { return client.Pause(ctx) }),
+	"next":     withoutArguments("next", func(ctx context.Context, client SpotifyClient) error { return client.Next(ctx) }),
+	"prev":     withoutArguments("prev", func(ctx context.Context, client SpotifyClient) error { return client.Previous(ctx) }),
+	"toggle":   withoutArguments("toggle", toggle),
+	"status":   statusCommand,
+	"watch":    watchCommand,
+	"search":   searchCommand,
+	"devices":  devicesCommand,
+	"queue":    queueCommand,
+	"volume":   volumeCommand,
+	"like":     likeCommand,
+	"lyrics":   lyricsCommand,
+	"playlist": playlistCommand,
+	"pick":     pickCommand,
+	"seek":     seekCommand,
+	"shuffle":  shuffleCommand,
+	"repeat":   repeatCommand,
+}
+
+// IsCommand tells whether there is a command with the given name.
+func IsCommand(name string) bool {
+	_, ok := commands[name]
+	return ok
+}
+
+// Commands returns names of all commands, sorted.
+func Commands() []string {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RunCommand runs the command with the given name and arguments.
+func RunCommand(ctx context.Context, client SpotifyClient, name string, args []string, out io.Writer) error {
+	command, ok := commands[name]
+	if !ok {
+		return usageErrorf("unknown command %s, known are %v", name, Commands())
+	}
+	return command(ctx, client, args, out)
+}
+
+// withoutArguments turns action into command which takes no arguments.
+func withoutArguments(name string, action func(context.Context, SpotifyClient) error) Command {
+	return func(ctx context.Context, client SpotifyClient, args []string, out io.Writer) error {
+		if len(args) != 0 {
+			return usageErrorf("%s command takes no arguments, got %v", name, args)
+		}
+		return action(ctx, client)
+	}
+}
+
+// playCommand resumes playback, or plays the item given as Spotify URI or link, either as the
+// argument or with --uri.
+func playCommand(ctx context.Context, client SpotifyClient, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("play", flag.ContinueOnError)
+	flags.SetOutput(out)
+	value := flags.String("uri", "", "Spotify URI or link of the item to play, - reads it from the standard input, i.e. picked with rofi.")
+	if err := flags.Parse(args); err != nil {
+		return &CommandError{Code: ExitUsage, Err: err}
+	}
+	switch {
+	case flags.NArg() > 1 || flags.NArg() == 1 && *value != "":
+		return usageErrorf("play command takes at most one URI or link, got %v", args)
+	case flags.NArg() == 1:
+		*value = flags.Arg(0)
+	case *value == "":
+		return client.Play(ctx)
+	}
+	uri, err := ParseURI(ctx, *value)
+	if err != nil {
+		return err
+	}
+	if err := playURI(ctx, client, uri); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(out, "Playing %s\n", uri)
+	return err
+}
+
+// toggle pauses playback when something is played, resumes it otherwise.
+func toggle(ctx context.Context, client SpotifyClient) error {
+	playing, err := client.PlayerCurrentlyPlaying(ctx)
+	if err != nil {
+		return commandError(err, "could not get currently playing item")
+	}
+	if playing.Playing {
+		return client.Pause(ctx)
+	}
+	return client.Play(ctx)
+}
+
+// PlaybackStatus describes what is played and where, for scripts and status bars. Episodes
+// are described by their show in place of the album and its publisher in place of the artist.
+type PlaybackStatus struct {
+	Track    string      `json:"track"`
+	Artist   string      `json:"artist"`
+	Album    string      `json:"album"`
+	URI      spotify.URI `json:"uri"`
+	Device   string      `json:"device"`
+	Progress int         `json:"progress_ms"`
+	Duration int         `json:"duration_ms"`
+	Playing  bool        `json:"playing"`
+}
+
+// CurrentPlaybackStatus gets what is currently played, on the active device.
+func CurrentPlaybackStatus(ctx context.Context, client SpotifyClient) (*PlaybackStatus, error) {
+	item, err := client.PlayerCurrentlyPlaying(ctx)
+	if err != nil {
+		return nil, commandError(err, "could not get currently playing item")
+	}
+	devices, err := client.PlayerDevices(ctx)
+	if err != nil {
+		return nil, commandError(err, "could not list devices")
+	}
+	status := &PlaybackStatus{URI: playbackItemURI(item), Progress: item.Progress, Playing: item.Playing}
+	switch {
+	case item.Item != nil:
+		status.Track = item.Item.Name
+		status.Artist = artistsNames(item.Item.Artists)
+		status.Album = item.Item.Album.Name
+		status.Duration = item.Item.Duration
+	case item.Episode != nil:
+		status.Track = item.Episode.Name
+		status.Artist = item.Episode.Show.Publisher
+		status.Album = item.Episode.Show.Name
+		status.Duration = item.Episode.Duration_ms
+	}
+	for _, device := range devices {
+		if device.Active {
+			status.Device = device.Name
+		}
+	}
+	return status, nil
+}
+
+// String describes the status in lines readable to the user.
+func (status *PlaybackStatus) String() string {
+	if status.Track == "" {
+		return "Nothing is played"
+	}
+	return fmt.Sprintf("Track:    %s\nArtist:   %s\nAlbum:    %s\nDevice:   %s\nProgress: %s / %s\nState:    %s",
+		status.Track, status.Artist, status.Album, status.Device,
+		formatEpisodeDuration(status.Progress), formatEpisodeDuration(status.Duration), status.state())
+}
+
+// statusPlaceholders are replaced in --format of the status command, i.e. in
+// '{artist} – {title} [{progress}/{duration}]'.
+var statusPlaceholders = map[string]func(*PlaybackStatus) string{
+	"title":    func(status *PlaybackStatus) string { return status.Track },
+	"artist":   func(status *PlaybackStatus) string { return status.Artist },
+	"album":    func(status *PlaybackStatus) string { return status.Album },
+	"device":   func(status *PlaybackStatus) string { return status.Device },
+	"progress": func(status *PlaybackStatus) string { return formatEpisodeDuration(status.Progress) },
+	"duration": func(status *PlaybackStatus) string { return formatEpisodeDuration(status.Duration) },
+	"state":    func(status *PlaybackStatus) string { return status.state() },
+}
+
+var statusPlaceholder = regexp.MustCompile(`\{(\w+)\}`)
+
+// validateStatusFormat checks that the format has only known placeholders.
+func validateStatusFormat(format string) error {
+	for _, match := range statusPlaceholder.FindAllStringSubmatch(format, -1) {
+		if _, ok := statusPlaceholders[match[1]]; !ok {
+			names := make([]string, 0, len(statusPlaceholders))
+			for name := range statusPlaceholders {
+				names = append(names, "{"+name+"}")
+			}
+			sort.Strings(names)
+			return usageErrorf("unknown placeholder %s, known are %s", match[0], strings.Join(names, ", "))
+		}
+	}
+	return nil
+}
+
+// Format replaces placeholders of the format with the status, unknown ones are left as they are.
+func (status *PlaybackStatus) Format(format string) string {
+	return statusPlaceholder.ReplaceAllStringFunc(format, func(placeholder string) string {
+		value, ok := statusPlaceholders[placeholder[1:len(placeholder)-1]]
+		if !ok {
+			return placeholder
+		}
+		return value(status)
+	})
+}
+
+// tmuxStates are marks of the state in the status line of tmux, coloured with its styles.
+var tmuxStates = map[bool]string{
+	true:  "#[fg=green]▶#[default] ",
+	false: "#[fg=yellow]⏸#[default] ",
+}
+
+// Tmux describes the status in the status line of tmux: marked state, the artist and the title,
+// cut to width characters. It is empty when nothing is played.
+func (status *PlaybackStatus) Tmux(width int) string {
+	if status.Track == "" {
+		return ""
+	}
+	text := []rune(status.Artist + " – " + status.Track)
+	if len(text) > width {
+		text = append(text[:width-1], '…')
+	}
+	// tmux treats # as start of styles, it is given as ## to be printed
+	return tmuxStates[status.Playing] + strings.Replace(string(text), "#", "##", -1)
+}
+
+func (status *PlaybackStatus) state() string {
+	if status.Playing {
+		return "playing"
+	}
+	return "paused"
+}
+
+// statusCommand prints what is currently played, as JSON with --json, formatted with --format,
+// or for the status line of tmux with --tmux. With --follow it is printed again each time it
+// changes, until the context is done.
+func statusCommand(ctx context.Context, client SpotifyClient, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("status", flag.ContinueOnError)
+	flags.SetOutput(out)
+	asJSON := flags.Bool("json", false, "Print the status as JSON, i.e. for scripts.")
+	format := flags.String("format", "", "Print the status in a line with placeholders replaced, i.e. '{artist} – {title} [{progress}/{duration}]'.")
+	tmux := flags.Bool("tmux", false, "Print the status shortly, with colours of tmux, i.e. in its status line.")
+	width := flags.Int("width", 40, "How many characters the artist and the title take at most with --tmux.")
+	follow := flags.Bool("follow", false, "Print the status again each time it changes, i.e. for status bars.")
+	interval := flags.Duration("interval", time.Second, "How often the status is checked for changes with --follow.")
+	if err := flags.Parse(args); err != nil {
+		return &CommandError{Code: ExitUsage, Err: err}
+	}
+	if flags.NArg() != 0 {
+		return usageErrorf("status command takes no arguments but flags, got %v", flags.Args())
+	}
+	if err := validateStatusFormat(*format); err != nil {
+		return err
+	}
+	outputs := 0
+	for _, given := range []bool{*asJSON, *format != "", *tmux} {
+		if given {
+			outputs++
+		}
+	}
+	if outputs > 1 {
+		return usageErrorf("status command takes only one of --json, --format and --tmux")
+	}
+	if *width < 1 {
+		return usageErrorf("width has to be positive, got %d", *width)
+	}
+	if *interval <= 0 {
+		return usageErrorf("interval has to be positive, got %s", *interval)
+	}
+	render := func(current *PlaybackStatus) (string, error) {
+		switch {
+		case *asJSON:
+			text, err := json.Marshal(current)
+			return string(text), err
+		case *format != "":
+			return current.Format(*format), nil
+		case *tmux:
+			return current.Tmux(*width), nil
+		default:
+			return current.String(), nil
+		}
+	}
+
+	printed := ""
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for {
+		current, err := CurrentPlaybackStatus(ctx, client)
+		if err != nil && !*follow {
+			return err
+		}
+		// status bars keep showing the last status when it cannot be checked for a while
+		if err != nil {
+			log.Printf("Could not check status with %s", err)
+		} else if text, err := render(current); err != nil {
+			return err
+		} else if text != printed {
+			if _, err := fmt.Fprintln(out, text); err != nil {
+				return err
+			}
+			printed = text
+		}
+		if !*follow {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// PlayerEvent tells what changed in the player: another track plays ("track"), playback
+// was resumed or paused ("play", "pause"), or it was moved to another device ("device").
+type PlayerEvent struct {
+	Event string `json:"event"`
+	PlaybackStatus
+}
+
+// String describes the event in a line.
+func (event PlayerEvent) String() string {
+	if event.Event == "device" {
+		return fmt.Sprintf("device: %s", event.Device)
+	}
+	return fmt.Sprintf("%s: %s by %s", event.Event, event.Track, event.Artist)
+}
+
+// playerEvents returns events which took place between the statuses.
+func playerEvents(previous, current *PlaybackStatus) []PlayerEvent {
+	events := []PlayerEvent{}
+	if current.URI != "" && current.URI != previous.URI {
+		events = append(events, PlayerEvent{Event: "track", PlaybackStatus: *current})
+	}
+	if current.Playing != previous.Playing {
+		event := PlayerEvent{Event: "pause", PlaybackStatus: *current}
+		if current.Playing {
+			event.Event = "play"
+		}
+		events = append(events, event)
+	}
+	if current.Device != previous.Device {
+		events = append(events, PlayerEvent{Event: "device", PlaybackStatus: *current})
+	}
+	return events
+}
+
+// watchCommand prints a line, or JSON object with --json, each time another track plays,
+// playback is resumed or paused, or it is moved to another device, until the context is done.
+func watchCommand(ctx context.Context, client SpotifyClient, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("watch", flag.ContinueOnError)
+	flags.SetOutput(out)
+	asJSON := flags.Bool("json", false, "Print the events as JSON objects, one in a line.")
+	interval := flags.Duration("interval", time.Second, "How often the player is checked for changes.")
+	if err := flags.Parse(args); err != nil {
+		return &CommandError{Code: ExitUsage, Err: err}
+	}
+	if flags.NArg() != 0 {
+		return usageErrorf("watch command takes no arguments but flags, got %v", flags.Args())
+	}
+	if *interval <= 0 {
+		return usageErrorf("interval has to be positive, got %s", *interval)
+	}
+
+	var previous *PlaybackStatus
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for {
+		current, err := CurrentPlaybackStatus(ctx, client)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("Could not check player with %s", err)
+		}
+		// what is played when watching starts is not an event
+		if err == nil && previous != nil {
+			for _, event := range playerEvents(previous, current) {
+				line := event.String()
+				if *asJSON {
+					text, err := json.Marshal(event)
+					if err != nil {
+						return err
+					}
+					line = string(text)
+				}
+				if _, err := fmt.Fprintln(out, line); err != nil {
+					return err
+				}
+			}
+		}
+		if err == nil {
+			previous = current
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// searchTypes are types of items which can be searched for from the command line.
+var searchTypes = map[string]spotify.SearchType{
+	"track":    spotify.SearchTypeTrack,
+	"album":    spotify.SearchTypeAlbum,
+	"artist":   spotify.SearchTypeArtist,
+	"playlist": spotify.SearchTypePlaylist,
+}
+
+// FoundItem is a search result, the artist of a playlist is its owner.
+type FoundItem struct {
+	URI    spotify.URI `json:"uri"`
+	Artist string      `json:"artist"`
+	Title  string      `json:"title"`
+}
+
+// foundItems lists results of the given type, in the order given by Spotify.
+func foundItems(result *spotify.SearchResult, searchType string) []FoundItem {
+	items := []FoundItem{}
+	switch {
+	case searchType == "track" && result.Tracks != nil:
+		for _, track := range result.Tracks.Tracks {
+			items = append(items, FoundItem{URI: track.URI, Artist: artistsNames(track.Artists), Title: track.Name})
+		}
+	case searchType == "album" && result.Albums != nil:
+		for _, album := range result.Albums.Albums {
+			items = append(items, FoundItem{URI: album.URI, Artist: artistsNames(album.Artists), Title: album.Name})
+		}
+	case searchType == "artist" && result.Artists != nil:
+		for _, artist := range result.Artists.Artists {
+			items = append(items, FoundItem{URI: artist.URI, Artist: artist.Name})
+		}
+	case searchType == "playlist" && result.Playlists != nil:
+		for _, playlist := range result.Playlists.Playlists {
+			items = append(items, FoundItem{URI: playlist.URI, Artist: playlist.Owner.DisplayName, Title: playlist.Name})
+		}
+	}
+	return items
+}
+
+// searchCommand prints items found by the query as a table, or as JSON with --json. With
+// --play N the Nth of them is played instead.
+func searchCommand(ctx context.Context, client SpotifyClient, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("search", flag.ContinueOnError)
+	flags.SetOutput(out)
+	searchType := flags.String("type", "track", "Type of items searched for: track, album, artist or playlist.")
+	asJSON := flags.Bool("json", false, "Print the results as JSON, i.e. for scripts.")
+	play := flags.Int("play", 0, "Play the result with the given number, counted from 1, instead of printing the results.")
+	if err := flags.Parse(args); err != nil {
+		return &CommandError{Code: ExitUsage, Err: err}
+	}
+	query := strings.Join(flags.Args(), " ")
+	if query == "" {
+		return usageErrorf("search command takes query, i.e. search --type album \"kid a\"")
+	}
+	t, ok := searchTypes[*searchType]
+	if !ok {
+		return usageErrorf("unknown search type %s, known are track, album, artist and playlist", *searchType)
+	}
+	result, err := client.Search(ctx, query, t)
+	if err != nil {
+		return commandError(err, "could not search")
+	}
+	items := foundItems(result, *searchType)
+
+	if *play != 0 {
+		if *play < 0 || *play > len(items) {
+			return exitErrorf(ExitNotFound, "there is no result %d, %d were found", *play, len(items))
+		}
+		item := items[*play-1]
+		if err := playURI(ctx, client, item.URI); err != nil {
+			return err
+		}
+		_, err := fmt.Fprintf(out, "Playing %s\n", item.URI)
+		return err
+	}
+	if *asJSON {
+		return json.NewEncoder(out).Encode(items)
+	}
+	table := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "#\tURI\tARTIST\tTITLE")
+	for i, item := range items {
+		fmt.Fprintf(table, "%d\t%s\t%s\t%s\n", i+1, item.URI, item.Artist, item.Title)
+	}
+	return table.Flush()
+}
+
+// activeDeviceMark marks the device which plays in the list of devices.
+var activeDeviceMark = "*"
+
+// devicesCommand lists devices, marking the active one, or transfers playback to the device
+// with the given name or ID.
+func devicesCommand(ctx context.Context, client SpotifyClient, args []string, out io.Writer) error {
+	usage := usageErrorf("devices command takes list, or transfer with name or ID of the device")
+	if len(args) == 0 {
+		return usage
+	}
+	switch args[0] {
+	case "list":
+		if len(args) != 1 {
+			return usageErrorf("devices list takes no arguments, got %v", args[1:])
+		}
+		devices, err := client.PlayerDevices(ctx)
+		if err != nil {
+			return commandError(err, "could not list devices")
+		}
+		table := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(table, "ACTIVE\tID\tNAME\tTYPE\tVOLUME")
+		for _, device := range devices {
+			active := ""
+			if device.Active {
+				active = activeDeviceMark
+			}
+			fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%d%%\n", active, device.ID, device.Name, device.Type, device.Volume)
+		}
+		return table.Flush()
+	case "transfer":
+		// names often have spaces, they do not have to be quoted
+		name := strings.Join(args[1:], " ")
+		if name == "" {
+			return usageErrorf("devices transfer takes name or ID of the device")
+		}
+		device, err := transferPlaybackToDeviceNamed(ctx, client, name)
+		if err != nil {
+			return commandError(err, "could not transfer playback")
+		}
+		_, err = fmt.Fprintf(out, "Playback transferred to %s\n", device.Name)
+		return err
+	default:
+		return usage
+	}
+}
+
+// queueCommand adds tracks given as Spotify URIs or links to the queue, or lists tracks which
+// are played next.
+func queueCommand(ctx context.Context, client SpotifyClient, args []string, out io.Writer) error {
+	usage := usageErrorf("queue command takes add with URIs or links of tracks, or list")
+	if len(args) == 0 {
+		return usage
+	}
+	switch args[0] {
+	case "add":
+		if len(args) == 1 {
+			return usageErrorf("queue add takes URIs or links of tracks")
+		}
+		ids := []spotify.ID{}
+		for _, arg := range args[1:] {
+			uri, err := ParseURI(ctx, arg)
+			if err != nil {
+				return err
+			}
+			id := trackID(uri)
+			if id == "" {
+				return usageErrorf("%s is not a track", arg)
+			}
+			ids = append(ids, id)
+		}
+		for _, id := range ids {
+			if err := client.QueueSong(ctx, id); err != nil {
+				return commandError(err, "could not queue track %s", id)
+			}
+		}
+		_, err := fmt.Fprintf(out, "Queued %d tracks\n", len(ids))
+		return err
+	case "list":
+		if len(args) != 1 {
+			return usageErrorf("queue list takes no arguments, got %v", args[1:])
+		}
+		queue, err := client.PlayerQueue(ctx)
+		if err != nil {
+			return commandError(err, "could not get queue")
+		}
+		table := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(table, "#\tURI\tARTIST\tTITLE")
+		for i, track := range queue.Queue {
+			fmt.Fprintf(table, "%d\t%s\t%s\t%s\n", i+1, track.URI, artistsNames(track.Artists), track.Name)
+		}
+		return table.Flush()
+	default:
+		return usage
+	}
+}
+
+// volumeStep is how much volume is turned up or down when no step is given.
+var volumeStep = 10
+
+// volumeCommand sets volume of the active device, or turns it up or down by the given
+// step, in percents.
+func volumeCommand(ctx context.Context, client SpotifyClient, args []string, out io.Writer) error {
+	usage := usageErrorf("volume command takes set with percents, or up or down with optional step")
+	if len(args) == 0 || len(args) > 2 || !(args[0] == "set" && len(args) == 2 || args[0] == "up" || args[0] == "down") {
+		return usage
+	}
+	value := volumeStep
+	if len(args) == 2 {
+		var err error
+		if value, err = strconv.Atoi(args[1]); err != nil || value < 0 || value > 100 {
+			return usageErrorf("volume has to be given in percents from 0 to 100, got %s", args[1])
+		}
+	}
+	devices, err := client.PlayerDevices(ctx)
+	if err != nil {
+		return commandError(err, "could not list devices")
+	}
+	var active *spotify.PlayerDevice
+	for i := range devices {
+		if devices[i].Active {
+			active = &devices[i]
+		}
+	}
+	if active == nil {
+		return exitErrorf(ExitNoDevice, "there is no active device")
+	}
+
+	volume := active.Volume
+	switch args[0] {
+	case "set":
+		volume = value
+	case "up":
+		volume += value
+	case "down":
+		volume -= value
+	}
+	if volume > 100 {
+		volume = 100
+	}
+	if volume < 0 {
+		volume = 0
+	}
+	if err := client.Volume(ctx, volume); err != nil {
+		return commandError(err, "could not set volume of %s", active.Name)
+	}
+	_, err = fmt.Fprintf(out, "Volume of %s set to %d%%\n", active.Name, volume)
+	return err
+}
+
+// likeCommand saves the currently played track to Liked Songs, or removes it with --remove.
+func likeCommand(ctx context.Context, client SpotifyClient, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("like", flag.ContinueOnError)
+	flags.SetOutput(out)
+	remove := flags.Bool("remove", false, "Remove the track from Liked Songs instead.")
+	if err := flags.Parse(args); err != nil {
+		return &CommandError{Code: ExitUsage, Err: err}
+	}
+	if flags.NArg() != 0 {
+		return usageErrorf("like command takes no arguments but --remove, got %v", flags.Args())
+	}
+	playing, err := client.PlayerCurrentlyPlaying(ctx)
+	if err != nil {
+		return commandError(err, "could not get currently playing item")
+	}
+	if playing.Item == nil {
+		return exitErrorf(ExitNotFound, "no track is played, only tracks can be liked")
+	}
+	track := playing.Item
+	if *remove {
+		if err := client.RemoveTracksFromLibrary(ctx, track.ID); err != nil {
+			return commandError(err, "could not remove %s from Liked Songs", track.Name)
+		}
+		_, err = fmt.Fprintf(out, "Removed %s by %s from Liked Songs\n", track.Name, artistsNames(track.Artists))
+		return err
+	}
+	if err := client.AddTracksToLibrary(ctx, track.ID); err != nil {
+		return commandError(err, "could not save %s to Liked Songs", track.Name)
+	}
+	_, err = fmt.Fprintf(out, "Saved %s by %s to Liked Songs\n", track.Name, artistsNames(track.Artists))
+	return err
+}
+
+// playlistCommand writes all tracks of the playlist given by its name, ID, URI or link, as JSON,
+// CSV or M3U chosen with --format, to out or to the file given with --output.
+func playlistCommand(ctx context.Context, client SpotifyClient, args []string, out io.Writer) error {
+	if len(args) == 0 || args[0] != "export" {
+		return usageErrorf("playlist command takes export with name or ID of the playlist")
+	}
+	flags := flag.NewFlagSet("playlist export", flag.ContinueOnError)
+	flags.SetOutput(out)
+	format := flags.String("format", "json", "Format of the export: json, csv or m3u.")
+	output := flags.String("output", "", "Write the export to the file instead, it is replaced once written whole.")
+	// names often have spaces, they do not have to be quoted, flags can follow them
+	words, err := parseInterspersed(flags, args[1:])
+	if err != nil {
+		return err
+	}
+	name := strings.Join(words, " ")
+	if name == "" {
+		return usageErrorf("playlist export takes name or ID of the playlist")
+	}
+	encode, ok := playlistExportFormats[*format]
+	if !ok {
+		return usageErrorf("unknown format %s, known are json, csv and m3u", *format)
+	}
+	playlist, err := findPlaylist(ctx, client, name)
+	if err != nil {
+		return err
+	}
+	exported, err := ExportPlaylist(ctx, client, playlist)
+	if err != nil {
+		return err
+	}
+	data, err := encode(exported)
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		_, err = out.Write(data)
+		return err
+	}
+	if err := atomicfile.WriteFile(*output, data, 0644); err != nil {
+		return fmt.Errorf("could not write playlist export: %v", err)
+	}
+	_, err = fmt.Fprintf(out, "Exported %d tracks of %s to %s\n", len(exported.Tracks), exported.Name, *output)
+	return err
+}
+
+// parseInterspersed parses flags given before, between and after the arguments, which are returned.
+func parseInterspersed(flags *flag.FlagSet, args []string) ([]string, error) {
+	words := []string{}
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, &CommandError{Code: ExitUsage, Err: err}
+		}
+		args = flags.Args()
+		for len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+			words, args = append(words, args[0]), args[1:]
+		}
+		if len(args) == 0 {
+			return words, nil
+		}
+	}
+}
+
+// parseSeekPosition parses position given as m:ss or h:mm:ss, or as duration, i.e. 90s or 1m30s.
+// Position preceded by + or - is relative to the current one, moving forward or back.
+func parseSeekPosition(value string) (position time.Duration, relative bool, err error) {
+	sign := time.Duration(1)
+	switch {
+	case strings.HasPrefix(value, "+"):
+		value, relative = value[1:], true
+	case strings.HasPrefix(value, "-"):
+		value, relative, sign = value[1:], true, -1
+	}
+	if strings.Contains(value, ":") {
+		parts := strings.Split(value, ":")
+		if len(parts) > 3 {
+			return 0, false, fmt.Errorf("%s is not a position", value)
+		}
+		for i, part := range parts {
+			n, err := strconv.Atoi(part)
+			if err != nil || n < 0 || i > 0 && (len(part) != 2 || n > 59) {
+				return 0, false, fmt.Errorf("%s is not a position", value)
+			}
+			position = position*60 + time.Duration(n)*time.Second
+		}
+		return sign * position, relative, nil
+	}
+	if position, err = time.ParseDuration(value); err != nil || position < 0 {
+		return 0, false, fmt.Errorf("%s is neither a position nor a duration", value)
+	}
+	return sign * position, relative, nil
+}
+
+// seekCommand moves playback to the given position, i.e. 1:23, or forward or back by the given
+// duration, i.e. +30s or -10s, within the played item.
+func seekCommand(ctx context.Context, client SpotifyClient, args []string, out io.Writer) error {
+	if len(args) != 1 {
+		return usageErrorf("seek command takes position, i.e. 1:23, or duration to move by, i.e. +30s or -10s")
+	}
+	position, relative, err := parseSeekPosition(args[0])
+	if err != nil {
+		return usageErrorf("could not seek: %v", err)
+	}
+	playing, err := client.PlayerCurrentlyPlaying(ctx)
+	if err != nil {
+		return commandError(err, "could not get currently playing item")
+	}
+	duration := playbackItemDuration(playing)
+	if duration == 0 {
+		return exitErrorf(ExitNotFound, "nothing is played, there is nothing to seek in")
+	}
+	ms := int(position / time.Millisecond)
+	if relative {
+		ms += playing.Progress
+	}
+	if ms < 0 {
+		ms = 0
+	}
+	if ms > duration {
+		ms = duration
+	}
+	if err := client.Seek(ctx, ms); err != nil {
+		return commandError(err, "could not seek")
+	}
+	_, err = fmt.Fprintf(out, "Moved to %s of %s\n", formatEpisodeDuration(ms), formatEpisodeDuration(duration))
+	return err
+}
+
+// activePlayerState gets state of the player, failing when no device is active.
+func activePlayerState(ctx context.Context, client SpotifyClient) (*spotify.PlayerState, error) {
+	state, err := client.PlayerState(ctx)
+	if err != nil {
+		return nil, commandError(err, "could not get player state")
+	}
+	// Spotify responds with no content when no device is active
+	if state.Device.ID == "" {
+		return nil, exitErrorf(ExitNoDevice, "there is no active device")
+	}
+	return state, nil
+}
+
+// shuffleCommand turns shuffle on or off, or toggles it.
+func shuffleCommand(ctx context.Context, client SpotifyClient, args []string, out io.Writer) error {
+	if len(args) != 1 || args[0] != "on" && args[0] != "off" && args[0] != "toggle" {
+		return usageErrorf("shuffle command takes on, off or toggle")
+	}
+	shuffle := args[0] == "on"
+	if args[0] == "toggle" {
+		state, err := activePlayerState(ctx, client)
+		if err != nil {
+			return err
+		}
+		shuffle = !state.ShuffleState
+	}
+	if err := client.Shuffle(ctx, shuffle); err != nil {
+		return commandError(err, "could not change shuffle")
+	}
+	turned := "off"
+	if shuffle {
+		turned = "on"
+	}
+	_, err := fmt.Fprintf(out, "Shuffle is %s\n", turned)
+	return err
+}
+
+// repeatStates are states of repeat, as Spotify names them.
+var repeatStates = map[string]string{
+	"off":     "Repeat is off",
+	"track":   "Repeating the track",
+	"context": "Repeating the album or playlist",
+}
+
+// repeatCommand turns repeat off, or repeats the track or the album or playlist it is played from.
+func repeatCommand(ctx context.Context, client SpotifyClient, args []string, out io.Writer) error {
+	if len(args) != 1 || repeatStates[args[0]] == "" {
+		return usageErrorf("repeat command takes off, track or context")
+	}
+	if err := client.Repeat(ctx, args[0]); err != nil {
+		return commandError(err, "could not change repeat")
+	}
+	_, err := fmt.Fprintln(out, repeatStates[args[0]])
+	return err
+}
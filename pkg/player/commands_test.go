@@ -0,0 +1,555 @@
+package player
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zmb3/spotify"
+)
+
+// playbackClient records playback actions, something is played until it is paused.
+type playbackClient struct {
+	DebugClient
+	playing bool
+	actions []string
+}
+
+func (client *playbackClient) Play(ctx context.Context) error {
+	client.playing = true
+	client.actions = append(client.actions, "play")
+	return nil
+}
+
+func (client *playbackClient) Pause(ctx context.Context) error {
+	client.playing = false
+	client.actions = append(client.actions, "pause")
+	return nil
+}
+
+func (client *playbackClient) Next(ctx context.Context) error {
+	client.actions = append(client.actions, "next")
+	return nil
+}
+
+func (client *playbackClient) Previous(ctx context.Context) error {
+	client.actions = append(client.actions, "previous")
+	return nil
+}
+
+func (client *playbackClient) PlayerCurrentlyPlaying(ctx context.Context) (*PlaybackItem, error) {
+	return &PlaybackItem{CurrentlyPlaying: spotify.CurrentlyPlaying{Playing: client.playing}}, nil
+}
+
+func TestRunCommand(t *testing.T) {
+	client := &playbackClient{}
+	for _, name := range []string{"play", "toggle", "toggle", "next", "prev", "pause"} {
+		if err := RunCommand(context.Background(), client, name, nil, &bytes.Buffer{}); err != nil {
+			t.Fatalf("Did not expect to fail, but it did with %v", err)
+		}
+	}
+	if expected := []string{"play", "pause", "play", "next", "previous", "pause"}; !reflect.DeepEqual(client.actions, expected) {
+		t.Fatalf("Expected actions %v, got %v", expected, client.actions)
+	}
+
+	if err := RunCommand(context.Background(), client, "next", []string{"2"}, &bytes.Buffer{}); err == nil {
+		t.Fatalf("Expected command to fail with arguments")
+	}
+	if err := RunCommand(context.Background(), client, "rewind", nil, &bytes.Buffer{}); err == nil {
+		t.Fatalf("Expected unknown command to fail")
+	}
+}
+
+// activeDeviceClient plays on the Laptop.
+type activeDeviceClient struct {
+	DebugClient
+}
+
+func (client activeDeviceClient) PlayerDevices(ctx context.Context) ([]spotify.PlayerDevice, error) {
+	return []spotify.PlayerDevice{{Name: "Phone"}, {Name: "Laptop", Active: true}}, nil
+}
+
+func TestStatusCommand(t *testing.T) {
+	out := &bytes.Buffer{}
+	if err := RunCommand(context.Background(), activeDeviceClient{}, "status", nil, out); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	expected := "Track:    Currently Playing Song\nArtist:   Currently Playing Artist\nAlbum:    Currently Playing Album\nDevice:   Laptop\nProgress: 0:00 / 0:00\nState:    paused\n"
+	if out.String() != expected {
+		t.Fatalf("Expected status %q, got %q", expected, out.String())
+	}
+
+	out.Reset()
+	if err := RunCommand(context.Background(), activeDeviceClient{}, "status", []string{"--json"}, out); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	expected = `{"track":"Currently Playing Song","artist":"Currently Playing Artist","album":"Currently Playing Album","uri":"","device":"Laptop","progress_ms":0,"duration_ms":0,"playing":false}` + "\n"
+	if out.String() != expected {
+		t.Fatalf("Expected status %q, got %q", expected, out.String())
+	}
+}
+
+func TestStatusTmux(t *testing.T) {
+	status := &PlaybackStatus{Track: "Track #1", Artist: "Radiohead", Playing: true}
+	if expected := "#[fg=green]▶#[default] Radiohead – Track ##1"; status.Tmux(40) != expected {
+		t.Fatalf("Expected %q, got %q", expected, status.Tmux(40))
+	}
+	status.Playing = false
+	if expected := "#[fg=yellow]⏸#[default] Radiohead – T…"; status.Tmux(14) != expected {
+		t.Fatalf("Expected %q, got %q", expected, status.Tmux(14))
+	}
+	if text := (&PlaybackStatus{}).Tmux(40); text != "" {
+		t.Fatalf("Expected nothing when nothing is played, got %q", text)
+	}
+
+	out := &bytes.Buffer{}
+	if err := RunCommand(context.Background(), activeDeviceClient{}, "status", []string{"--tmux", "--width", "20"}, out); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if expected := "#[fg=yellow]⏸#[default] Currently Playing A…\n"; out.String() != expected {
+		t.Fatalf("Expected status %q, got %q", expected, out.String())
+	}
+	for _, args := range [][]string{{"--tmux", "--json"}, {"--json", "--format", "{title}"}, {"--format", "{title}", "--tmux"}} {
+		if err := RunCommand(context.Background(), activeDeviceClient{}, "status", args, out); ExitCode(err) != ExitUsage {
+			t.Fatalf("Expected %v to be rejected, got %v", args, err)
+		}
+	}
+}
+
+// searchCommandClient finds two tracks and records what is played.
+type searchCommandClient struct {
+	DebugClient
+	played []*spotify.PlayOptions
+}
+
+func (client *searchCommandClient) Search(ctx context.Context, query string, t spotify.SearchType) (*spotify.SearchResult, error) {
+	return &spotify.SearchResult{Tracks: &spotify.FullTrackPage{Tracks: []spotify.FullTrack{
+		{SimpleTrack: spotify.SimpleTrack{URI: "spotify:track:1", Name: "Idioteque", Artists: []spotify.SimpleArtist{{Name: "Radiohead"}}}},
+		{SimpleTrack: spotify.SimpleTrack{URI: "spotify:track:2", Name: "Everything In Its Right Place", Artists: []spotify.SimpleArtist{{Name: "Radiohead"}}}},
+	}}}, nil
+}
+
+func (client *searchCommandClient) PlayOpt(ctx context.Context, opt *spotify.PlayOptions) error {
+	client.played = append(client.played, opt)
+	return nil
+}
+
+func TestSearchCommand(t *testing.T) {
+	client := &searchCommandClient{}
+	out := &bytes.Buffer{}
+	if err := RunCommand(context.Background(), client, "search", []string{"kid", "a"}, out); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	expected := "#  URI              ARTIST     TITLE\n" +
+		"1  spotify:track:1  Radiohead  Idioteque\n" +
+		"2  spotify:track:2  Radiohead  Everything In Its Right Place\n"
+	if out.String() != expected {
+		t.Fatalf("Expected results %q, got %q", expected, out.String())
+	}
+
+	out.Reset()
+	if err := RunCommand(context.Background(), client, "search", []string{"--json", "kid a"}, out); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	expected = `[{"uri":"spotify:track:1","artist":"Radiohead","title":"Idioteque"},{"uri":"spotify:track:2","artist":"Radiohead","title":"Everything In Its Right Place"}]` + "\n"
+	if out.String() != expected {
+		t.Fatalf("Expected results %q, got %q", expected, out.String())
+	}
+
+	if err := RunCommand(context.Background(), client, "search", []string{"--play", "2", "kid a"}, &bytes.Buffer{}); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if len(client.played) != 1 || !reflect.DeepEqual(client.played[0].URIs, []spotify.URI{"spotify:track:2"}) {
+		t.Fatalf("Expected second track to be played, got %v", client.played)
+	}
+	if err := RunCommand(context.Background(), client, "search", []string{"--play", "3", "kid a"}, &bytes.Buffer{}); err == nil {
+		t.Fatalf("Expected playing result which was not found to fail")
+	}
+	if err := RunCommand(context.Background(), client, "search", []string{"--type", "show", "kid a"}, &bytes.Buffer{}); err == nil {
+		t.Fatalf("Expected unknown type to fail")
+	}
+}
+
+// transferringClient records devices playback is transferred to.
+type transferringClient struct {
+	activeDeviceClient
+	transferred []spotify.ID
+}
+
+func (client *transferringClient) PlayerDevices(ctx context.Context) ([]spotify.PlayerDevice, error) {
+	return []spotify.PlayerDevice{
+		{ID: "phone", Name: "Phone", Type: "Smartphone", Volume: 40},
+		{ID: "laptop", Name: "Living Room", Type: "Computer", Volume: 100, Active: true},
+	}, nil
+}
+
+func (client *transferringClient) TransferPlayback(ctx context.Context, deviceID spotify.ID, play bool) error {
+	client.transferred = append(client.transferred, deviceID)
+	return nil
+}
+
+func TestDevicesCommand(t *testing.T) {
+	client := &transferringClient{}
+	out := &bytes.Buffer{}
+	if err := RunCommand(context.Background(), client, "devices", []string{"list"}, out); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	expected := "ACTIVE  ID      NAME         TYPE        VOLUME\n" +
+		"        phone   Phone        Smartphone  40%\n" +
+		"*       laptop  Living Room  Computer    100%\n"
+	if out.String() != expected {
+		t.Fatalf("Expected devices %q, got %q", expected, out.String())
+	}
+
+	for _, args := range [][]string{{"transfer", "living", "room"}, {"transfer", "phone"}} {
+		if err := RunCommand(context.Background(), client, "devices", args, &bytes.Buffer{}); err != nil {
+			t.Fatalf("Did not expect to fail, but it did with %v", err)
+		}
+	}
+	if expected := []spotify.ID{"laptop", "phone"}; !reflect.DeepEqual(client.transferred, expected) {
+		t.Fatalf("Expected playback to be transferred to %v, got %v", expected, client.transferred)
+	}
+	if err := RunCommand(context.Background(), client, "devices", []string{"transfer", "Kitchen"}, &bytes.Buffer{}); err == nil {
+		t.Fatalf("Expected transfer to device which does not exist to fail")
+	}
+}
+
+// followedClient plays a track which is paused on the third check, the context is cancelled on the fourth.
+type followedClient struct {
+	activeDeviceClient
+	checks int
+	cancel context.CancelFunc
+}
+
+func (client *followedClient) PlayerCurrentlyPlaying(ctx context.Context) (*PlaybackItem, error) {
+	client.checks++
+	if client.checks == 4 {
+		client.cancel()
+	}
+	return &PlaybackItem{CurrentlyPlaying: spotify.CurrentlyPlaying{
+		Playing:  client.checks < 3,
+		Progress: 61000,
+		Item: &spotify.FullTrack{
+			SimpleTrack: spotify.SimpleTrack{Name: "Idioteque", Artists: []spotify.SimpleArtist{{Name: "Radiohead"}}, Duration: 309000},
+		},
+	}}, nil
+}
+
+func TestStatusCommandFollowsFormattedStatus(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := &followedClient{cancel: cancel}
+	out := &bytes.Buffer{}
+	args := []string{"--follow", "--interval", "1ms", "--format", "{artist} – {title} [{progress}/{duration}] {state} on {device}"}
+	if err := RunCommand(ctx, client, "status", args, out); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	expected := "Radiohead – Idioteque [1:01/5:09] playing on Laptop\n" +
+		"Radiohead – Idioteque [1:01/5:09] paused on Laptop\n"
+	if out.String() != expected {
+		t.Fatalf("Expected status to be printed when it changes %q, got %q", expected, out.String())
+	}
+
+	if err := RunCommand(context.Background(), client, "status", []string{"--format", "{title} {year}"}, &bytes.Buffer{}); err == nil {
+		t.Fatalf("Expected unknown placeholder to fail")
+	}
+}
+
+// queueingClient records queued tracks.
+type queueingClient struct {
+	DebugClient
+	queued []spotify.ID
+}
+
+func (client *queueingClient) QueueSong(ctx context.Context, trackID spotify.ID) error {
+	client.queued = append(client.queued, trackID)
+	return nil
+}
+
+func TestQueueCommand(t *testing.T) {
+	client := &queueingClient{}
+	args := []string{"add", "spotify:track:1", "https://open.spotify.com/track/2?si=shared"}
+	if err := RunCommand(context.Background(), client, "queue", args, &bytes.Buffer{}); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if expected := []spotify.ID{"1", "2"}; !reflect.DeepEqual(client.queued, expected) {
+		t.Fatalf("Expected tracks %v to be queued, got %v", expected, client.queued)
+	}
+	args = []string{"add", "spotify:track:3", "spotify:album:4"}
+	if err := RunCommand(context.Background(), client, "queue", args, &bytes.Buffer{}); err == nil || len(client.queued) != 2 {
+		t.Fatalf("Expected nothing to be queued when one of the tracks is not a track, got %v, %v", client.queued, err)
+	}
+
+	out := &bytes.Buffer{}
+	if err := RunCommand(context.Background(), client, "queue", []string{"list"}, out); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	expected := "#  URI                    ARTIST         TITLE\n" +
+		"1  spotify:track:queued1  Queued Artist  Queued Song 1\n" +
+		"2  spotify:track:queued2  Queued Artist  Queued Song 2\n" +
+		"3  spotify:track:queued3  Queued Artist  Queued Song 3\n"
+	if out.String() != expected {
+		t.Fatalf("Expected queue %q, got %q", expected, out.String())
+	}
+}
+
+// volumeClient plays on a device with the volume which was set last.
+type volumeClient struct {
+	DebugClient
+	volume int
+}
+
+func (client *volumeClient) PlayerDevices(ctx context.Context) ([]spotify.PlayerDevice, error) {
+	return []spotify.PlayerDevice{{Name: "Phone", Volume: 100}, {Name: "Laptop", Volume: client.volume, Active: true}}, nil
+}
+
+func (client *volumeClient) Volume(ctx context.Context, percent int) error {
+	client.volume = percent
+	return nil
+}
+
+func TestVolumeCommand(t *testing.T) {
+	client := &volumeClient{volume: 50}
+	for _, test := range []struct {
+		args   []string
+		volume int
+	}{
+		{[]string{"up"}, 60},
+		{[]string{"down", "25"}, 35},
+		{[]string{"set", "95"}, 95},
+		{[]string{"up", "20"}, 100},
+		{[]string{"set", "10"}, 10},
+		{[]string{"down"}, 0},
+	} {
+		if err := RunCommand(context.Background(), client, "volume", test.args, &bytes.Buffer{}); err != nil {
+			t.Fatalf("Did not expect to fail, but it did with %v", err)
+		}
+		if client.volume != test.volume {
+			t.Fatalf("Expected volume %d after volume %v, got %d", test.volume, test.args, client.volume)
+		}
+	}
+	for _, args := range [][]string{{"set"}, {"set", "120"}, {"up", "loud"}, {"mute"}} {
+		if err := RunCommand(context.Background(), client, "volume", args, &bytes.Buffer{}); err == nil {
+			t.Fatalf("Expected volume %v to fail", args)
+		}
+	}
+}
+
+func TestPlayCommandPlaysLinks(t *testing.T) {
+	client := &searchCommandClient{}
+	for _, arg := range []string{"https://open.spotify.com/album/kida?si=shared", "spotify:track:idioteque"} {
+		if err := RunCommand(context.Background(), client, "play", []string{arg}, &bytes.Buffer{}); err != nil {
+			t.Fatalf("Did not expect to fail, but it did with %v", err)
+		}
+	}
+	if len(client.played) != 2 || *client.played[0].PlaybackContext != "spotify:album:kida" || !reflect.DeepEqual(client.played[1].URIs, []spotify.URI{"spotify:track:idioteque"}) {
+		t.Fatalf("Expected album to be played from its start and the track on its own, got %v", client.played)
+	}
+	if err := RunCommand(context.Background(), client, "play", []string{"kid a"}, &bytes.Buffer{}); err == nil {
+		t.Fatalf("Expected playing what is neither URI nor link to fail")
+	}
+}
+
+// watchedState is what is played and where.
+type watchedState struct {
+	uri     spotify.URI
+	playing bool
+	device  string
+}
+
+// watchedClient goes through the states, one on each check, the context is cancelled after the last one.
+type watchedClient struct {
+	DebugClient
+	states []watchedState
+	checks int
+	cancel context.CancelFunc
+}
+
+func (client *watchedClient) PlayerCurrentlyPlaying(ctx context.Context) (*PlaybackItem, error) {
+	state := client.states[client.checks]
+	return &PlaybackItem{CurrentlyPlaying: spotify.CurrentlyPlaying{Playing: state.playing, Item: &spotify.FullTrack{
+		SimpleTrack: spotify.SimpleTrack{URI: state.uri, Name: string(state.uri), Artists: []spotify.SimpleArtist{{Name: "Radiohead"}}},
+	}}}, nil
+}
+
+func (client *watchedClient) PlayerDevices(ctx context.Context) ([]spotify.PlayerDevice, error) {
+	state := client.states[client.checks]
+	client.checks++
+	if client.checks == len(client.states) {
+		client.cancel()
+	}
+	return []spotify.PlayerDevice{{Name: state.device, Active: true}}, nil
+}
+
+func TestWatchCommand(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := &watchedClient{cancel: cancel, states: []watchedState{
+		{"Idioteque", true, "Laptop"},
+		{"Idioteque", true, "Laptop"},
+		{"Idioteque", false, "Laptop"},
+		{"Morning Bell", true, "Laptop"},
+		{"Morning Bell", true, "Phone"},
+	}}
+	out := &bytes.Buffer{}
+	if err := RunCommand(ctx, client, "watch", []string{"--interval", "1ms"}, out); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	expected := "pause: Idioteque by Radiohead\n" +
+		"track: Morning Bell by Radiohead\n" +
+		"play: Morning Bell by Radiohead\n" +
+		"device: Phone\n"
+	if out.String() != expected {
+		t.Fatalf("Expected events %q, got %q", expected, out.String())
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	client.checks, client.cancel = 3, cancel
+	out.Reset()
+	if err := RunCommand(ctx, client, "watch", []string{"--json", "--interval", "1ms"}, out); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	expected = `{"event":"device","track":"Morning Bell","artist":"Radiohead","album":"","uri":"Morning Bell","device":"Phone","progress_ms":0,"duration_ms":0,"playing":true}` + "\n"
+	if out.String() != expected {
+		t.Fatalf("Expected events %q, got %q", expected, out.String())
+	}
+}
+
+// likingClient records tracks saved to and removed from Liked Songs.
+type likingClient struct {
+	DebugClient
+	liked, removed []spotify.ID
+}
+
+func (client *likingClient) PlayerCurrentlyPlaying(ctx context.Context) (*PlaybackItem, error) {
+	return &PlaybackItem{CurrentlyPlaying: spotify.CurrentlyPlaying{Item: &spotify.FullTrack{
+		SimpleTrack: spotify.SimpleTrack{ID: "idioteque", Name: "Idioteque", Artists: []spotify.SimpleArtist{{Name: "Radiohead"}}},
+	}}}, nil
+}
+
+func (client *likingClient) AddTracksToLibrary(ctx context.Context, trackIDs ...spotify.ID) error {
+	client.liked = append(client.liked, trackIDs...)
+	return nil
+}
+
+func (client *likingClient) RemoveTracksFromLibrary(ctx context.Context, trackIDs ...spotify.ID) error {
+	client.removed = append(client.removed, trackIDs...)
+	return nil
+}
+
+func TestLikeCommand(t *testing.T) {
+	client := &likingClient{}
+	out := &bytes.Buffer{}
+	if err := RunCommand(context.Background(), client, "like", nil, out); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if err := RunCommand(context.Background(), client, "like", []string{"--remove"}, out); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if !reflect.DeepEqual(client.liked, []spotify.ID{"idioteque"}) || !reflect.DeepEqual(client.removed, []spotify.ID{"idioteque"}) {
+		t.Fatalf("Expected played track to be saved and removed, got %v and %v", client.liked, client.removed)
+	}
+	expected := "Saved Idioteque by Radiohead to Liked Songs\n" +
+		"Removed Idioteque by Radiohead from Liked Songs\n"
+	if out.String() != expected {
+		t.Fatalf("Expected output %q, got %q", expected, out.String())
+	}
+	if err := RunCommand(context.Background(), &playbackClient{}, "like", nil, out); err == nil {
+		t.Fatalf("Expected liking to fail when nothing is played")
+	}
+}
+
+// seekingClient plays a track of 5 minutes, a minute in, and records positions it is moved to.
+type seekingClient struct {
+	DebugClient
+	positions []int
+}
+
+func (client *seekingClient) PlayerCurrentlyPlaying(ctx context.Context) (*PlaybackItem, error) {
+	return &PlaybackItem{CurrentlyPlaying: spotify.CurrentlyPlaying{Progress: 60000, Item: &spotify.FullTrack{
+		SimpleTrack: spotify.SimpleTrack{Name: "Idioteque", Duration: 300000},
+	}}}, nil
+}
+
+func (client *seekingClient) Seek(ctx context.Context, position int) error {
+	client.positions = append(client.positions, position)
+	return nil
+}
+
+func TestSeekCommand(t *testing.T) {
+	client := &seekingClient{}
+	out := &bytes.Buffer{}
+	for _, position := range []string{"+30s", "-10s", "1:23", "-2m", "+1:00:00", "90s"} {
+		if err := RunCommand(context.Background(), client, "seek", []string{position}, out); err != nil {
+			t.Fatalf("Did not expect to fail, but it did with %v", err)
+		}
+	}
+	if expected := []int{90000, 50000, 83000, 0, 300000, 90000}; !reflect.DeepEqual(client.positions, expected) {
+		t.Fatalf("Expected positions %v, got %v", expected, client.positions)
+	}
+	if line := strings.SplitN(out.String(), "\n", 2)[0]; line != "Moved to 1:30 of 5:00" {
+		t.Fatalf("Expected new position to be printed, got %q", line)
+	}
+
+	for _, position := range []string{"1:2", "soon", "1:60", "+-3s"} {
+		if err := RunCommand(context.Background(), client, "seek", []string{position}, out); ExitCode(err) != ExitUsage {
+			t.Fatalf("Expected position %s to be rejected, got %v", position, err)
+		}
+	}
+	if err := RunCommand(context.Background(), &playbackClient{}, "seek", []string{"1:00"}, out); ExitCode(err) != ExitNotFound {
+		t.Fatalf("Expected seeking to fail when nothing is played, got %v", err)
+	}
+}
+
+// shufflingClient records shuffle and repeat states it is changed to.
+type shufflingClient struct {
+	DebugClient
+	state   spotify.PlayerState
+	changes []string
+}
+
+func (client *shufflingClient) PlayerState(ctx context.Context) (*spotify.PlayerState, error) {
+	state := client.state
+	return &state, nil
+}
+
+func (client *shufflingClient) Shuffle(ctx context.Context, shuffle bool) error {
+	client.state.ShuffleState = shuffle
+	client.changes = append(client.changes, fmt.Sprintf("shuffle %t", shuffle))
+	return nil
+}
+
+func (client *shufflingClient) Repeat(ctx context.Context, state string) error {
+	client.changes = append(client.changes, "repeat "+state)
+	return nil
+}
+
+func TestShuffleAndRepeatCommands(t *testing.T) {
+	client := &shufflingClient{state: spotify.PlayerState{Device: spotify.PlayerDevice{ID: "laptop"}}}
+	out := &bytes.Buffer{}
+	for _, args := range [][]string{{"shuffle", "on"}, {"shuffle", "toggle"}, {"shuffle", "toggle"}, {"repeat", "track"}, {"repeat", "off"}} {
+		if err := RunCommand(context.Background(), client, args[0], args[1:], out); err != nil {
+			t.Fatalf("Did not expect to fail, but it did with %v", err)
+		}
+	}
+	if expected := []string{"shuffle true", "shuffle false", "shuffle true", "repeat track", "repeat off"}; !reflect.DeepEqual(client.changes, expected) {
+		t.Fatalf("Expected changes %v, got %v", expected, client.changes)
+	}
+	if expected := "Shuffle is on\nShuffle is off\nShuffle is on\nRepeating the track\nRepeat is off\n"; out.String() != expected {
+		t.Fatalf("Expected output %q, got %q", expected, out.String())
+	}
+
+	for _, args := range [][]string{{"shuffle"}, {"shuffle", "maybe"}, {"repeat", "album"}} {
+		if err := RunCommand(context.Background(), client, args[0], args[1:], out); ExitCode(err) != ExitUsage {
+			t.Fatalf("Expected %v to be rejected, got %v", args, err)
+		}
+	}
+	client.state.Device.ID = ""
+	if err := RunCommand(context.Background(), client, "shuffle", []string{"toggle"}, out); ExitCode(err) != ExitNoDevice {
+		t.Fatalf("Expected toggling to fail without active device, got %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package player
+
+import (
+	"fmt"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+var confirmationKey = 'y'
+
+// Confirmation asks the user to confirm destructive actions, i.e. removing an
+// album from the library. It is modal: while a question is pending, keys are
+// not passed to the wrapped widget and the focus is moved to the question, so
+// that only confirmationKey makes the action, and any other key, including a
+// stray Enter, cancels it.
+type Confirmation struct {
+	Focusables []tui.Widget
+	Box        *tui.Box
+	question   *tui.Label
+	// onConfirm is the action awaiting confirmation, nil if none.
+	onConfirm func()
+	onAsk     func()
+	onAnswer  func()
+}
+
+// confirmationModal wraps a widget, so that it does not receive keys while
+// a question of the confirmation is pending.
+type confirmationModal struct {
+	tui.Widget
+	confirmation *Confirmation
+}
+
+// OnKeyEvent answers the pending question, other keys are handled by the wrapped widget.
+func (m *confirmationModal) OnKeyEvent(ev tui.KeyEvent) {
+	if m.confirmation.Pending() {
+		m.confirmation.answer(ev.Key == tui.KeyRune && ev.Rune == confirmationKey)
+		return
+	}
+	m.Widget.OnKeyEvent(ev)
+}
+
+// NewConfirmation creates confirmation with no pending question.
+func NewConfirmation() *Confirmation {
+	question := tui.NewLabel("")
+	question.SetSizePolicy(tui.Expanding, tui.Minimum)
+	box := tui.NewHBox(question)
+	return &Confirmation{
+		Focusables: []tui.Widget{question},
+		Box:        box,
+		question:   question,
+	}
+}
+
+// Modal wraps the root widget of the ui, so that keys are answers to the
+// pending question instead of being handled by the root.
+func (c *Confirmation) Modal(root tui.Widget) tui.Widget {
+	return &confirmationModal{Widget: root, confirmation: c}
+}
+
+// OnAsk sets function called when a question is asked, it should move the focus to Focusables.
+func (c *Confirmation) OnAsk(fn func()) {
+	c.onAsk = fn
+}
+
+// OnAnswer sets function called once the question is answered, it should restore the focus.
+func (c *Confirmation) OnAnswer(fn func()) {
+	c.onAnswer = fn
+}
+
+// Pending tells whether a question awaits the answer.
+func (c *Confirmation) Pending() bool {
+	return c.onConfirm != nil
+}
+
+// Ask asks the question, onConfirm is called only if the user confirms.
+// Question asked while another one is pending replaces it.
+func (c *Confirmation) Ask(question string, onConfirm func()) {
+	c.onConfirm = onConfirm
+	c.question.SetText(fmt.Sprintf("%s Press %c to confirm, any other key to cancel", question, confirmationKey))
+	if c.onAsk != nil {
+		c.onAsk()
+	}
+}
+
+// answer makes the pending action when it is confirmed and clears the question.
+func (c *Confirmation) answer(confirmed bool) {
+	onConfirm := c.onConfirm
+	c.onConfirm = nil
+	c.question.SetText("")
+	if c.onAnswer != nil {
+		c.onAnswer()
+	}
+	if confirmed && onConfirm != nil {
+		onConfirm()
+	}
+}
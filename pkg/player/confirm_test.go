@@ -0,0 +1,45 @@
+package player
+
+import (
+	"testing"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+type countingKeys struct {
+	tui.Widget
+	keys int
+}
+
+func (w *countingKeys) OnKeyEvent(ev tui.KeyEvent) {
+	w.keys++
+}
+
+func TestConfirmationIsModal(t *testing.T) {
+	confirmation := NewConfirmation()
+	root := &countingKeys{Widget: tui.NewLabel("")}
+	modal := confirmation.Modal(root)
+	asked, answered, confirmed := 0, 0, 0
+	confirmation.OnAsk(func() { asked++ })
+	confirmation.OnAnswer(func() { answered++ })
+
+	modal.OnKeyEvent(tui.KeyEvent{Key: tui.KeyEnter})
+	if root.keys != 1 {
+		t.Fatalf("Expected key to be passed to the root when nothing is asked")
+	}
+
+	confirmation.Ask("Remove?", func() { confirmed++ })
+	modal.OnKeyEvent(tui.KeyEvent{Key: tui.KeyEnter})
+	if confirmed != 0 || confirmation.Pending() || root.keys != 1 {
+		t.Fatalf("Expected Enter to cancel the action without reaching the root, confirmed %d times", confirmed)
+	}
+
+	confirmation.Ask("Remove?", func() { confirmed++ })
+	modal.OnKeyEvent(tui.KeyEvent{Key: tui.KeyRune, Rune: confirmationKey})
+	if confirmed != 1 || confirmation.Pending() || root.keys != 1 {
+		t.Fatalf("Expected action to be confirmed once, confirmed %d times", confirmed)
+	}
+	if asked != 2 || answered != 2 {
+		t.Fatalf("Expected 2 questions and answers, got %d and %d", asked, answered)
+	}
+}
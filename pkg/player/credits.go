@@ -0,0 +1,121 @@
+package player
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/marcusolsson/tui-go"
+	"github.com/zmb3/spotify"
+)
+
+// AlbumCredits is an album with credits as described by Spotify Web API,
+// spotify library does not expose its record label.
+type AlbumCredits struct {
+	ID          spotify.ID              `json:"id"`
+	Name        string                  `json:"name"`
+	Artists     []spotify.SimpleArtist  `json:"artists"`
+	Label       string                  `json:"label"`
+	ReleaseDate string                  `json:"release_date"`
+	Copyrights  []spotify.Copyright     `json:"copyrights"`
+	Tracks      spotify.SimpleTrackPage `json:"tracks"`
+}
+
+// Credits represents view with credits of the album of currently playing track,
+// i.e. its performers, label and copyrights. Spotify does not expose
+// songwriters, so they are not listed.
+type Credits struct {
+	Focusables []tui.Widget
+	Box        *tui.Box
+	ctx        context.Context
+	client     SpotifyClient
+	table      *countedTable
+	rows       [][2]string
+}
+
+// copyrightTypes names types of album copyrights used by Spotify.
+var copyrightTypes = map[string]string{
+	"C": "Copyright",
+	"P": "Phonogram",
+}
+
+// NewCredits creates view with album credits, it is empty until credits are shown.
+func NewCredits(ctx context.Context, client SpotifyClient) *Credits {
+	table := newCountedTable()
+	table.SetColumnStretch(0, 1)
+	table.SetColumnStretch(1, 3)
+
+	box := tui.NewVBox(table, tui.NewSpacer())
+	box.SetTitle("Credits")
+	box.SetBorder(true)
+	box.SetSizePolicy(tui.Expanding, tui.Expanding)
+
+	return &Credits{
+		Focusables: []tui.Widget{table},
+		Box:        box,
+		ctx:        ctx,
+		client:     client,
+		table:      table,
+	}
+}
+
+// ShowPlaying shows credits of the currently playing track and its album.
+func (credits *Credits) ShowPlaying() error {
+	playing, err := credits.client.PlayerCurrentlyPlaying(credits.ctx)
+	if err != nil {
+		return fmt.Errorf("could not fetch currently playing track: %v", err)
+	}
+	if playing.Item == nil {
+		return fmt.Errorf("there is no track playing")
+	}
+	album, err := credits.client.GetAlbumCredits(credits.ctx, playing.Item.Album.ID)
+	if err != nil {
+		return fmt.Errorf("could not fetch album credits: %v", err)
+	}
+	credits.show(&playing.Item.SimpleTrack, album)
+	return nil
+}
+
+// show lists credits of the track, when given, and of its album. Fields which
+// are not known are skipped.
+func (credits *Credits) show(track *spotify.SimpleTrack, album *AlbumCredits) {
+	credits.table.RemoveRows()
+	credits.rows = creditRows(track, album)
+	if len(credits.rows) == 0 {
+		credits.table.AppendRow(tui.NewLabel("No credits available"))
+		return
+	}
+	for _, row := range credits.rows {
+		credits.table.AppendRow(tui.NewLabel(row[0]), tui.NewLabel(row[1]))
+	}
+}
+
+// creditRows returns pairs of role and credited name.
+func creditRows(track *spotify.SimpleTrack, album *AlbumCredits) [][2]string {
+	rows := [][2]string{}
+	add := func(role, name string) {
+		if name = strings.TrimSpace(name); name != "" {
+			rows = append(rows, [2]string{role, name})
+		}
+	}
+	if track != nil {
+		add("Track", track.Name)
+		for _, artist := range track.Artists {
+			add("Performer", artist.Name)
+		}
+	}
+	add("Album", album.Name)
+	for _, artist := range album.Artists {
+		add("Album artist", artist.Name)
+	}
+	add("Released", album.ReleaseDate)
+	add("Label", album.Label)
+	for _, copyright := range album.Copyrights {
+		role, ok := copyrightTypes[copyright.Type]
+		if !ok {
+			role = "Copyright"
+		}
+		add(role, copyright.Text)
+	}
+	return rows
+}
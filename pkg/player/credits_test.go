@@ -0,0 +1,44 @@
+package player
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/zmb3/spotify"
+)
+
+func TestCreditsShowPlaying(t *testing.T) {
+	credits := NewCredits(context.Background(), NewDebugClient())
+	if err := credits.ShowPlaying(); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	// Track, performer, album, album artist, release date, label and copyright
+	if len(credits.rows) != 7 {
+		t.Fatalf("Expected 7 credits of the playing track, got %v", credits.rows)
+	}
+}
+
+func TestCreditRowsSkipMissingFields(t *testing.T) {
+	album := &AlbumCredits{
+		Name:       "Album",
+		Copyrights: []spotify.Copyright{{Text: "2020 Label", Type: "P"}, {Text: " ", Type: "C"}},
+	}
+	track := &spotify.SimpleTrack{Name: "Track", Artists: []spotify.SimpleArtist{{Name: "First"}, {Name: "Second"}}}
+	expected := [][2]string{
+		{"Track", "Track"},
+		{"Performer", "First"},
+		{"Performer", "Second"},
+		{"Album", "Album"},
+		{"Phonogram", "2020 Label"},
+	}
+	if rows := creditRows(track, album); !reflect.DeepEqual(rows, expected) {
+		t.Fatalf("Expected credits %v, got %v", expected, rows)
+	}
+
+	credits := NewCredits(context.Background(), NewDebugClient())
+	credits.show(nil, &AlbumCredits{})
+	if len(credits.rows) != 0 {
+		t.Fatalf("Expected no credits for album without details, got %v", credits.rows)
+	}
+}
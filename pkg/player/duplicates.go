@@ -0,0 +1,316 @@
+package player
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/marcusolsson/tui-go"
+	"github.com/zmb3/spotify"
+)
+
+// Duplicates represents view listing saved albums and tracks which have the
+// same name and artist, but different IDs, i.e. deluxe editions of an album.
+// The first saved of the duplicates is kept, the others are marked to be
+// removed from the library, which is done in bulk once confirmed.
+type Duplicates struct {
+	Focusables   []tui.Widget
+	Box          *tui.Box
+	ctx          context.Context
+	client       SpotifyClient
+	library      AlbumLibrary
+	confirmation *Confirmation
+	table        *countedTable
+	status       *tui.Label
+	// rows are duplicates as listed in the table, row by row.
+	rows []duplicate
+}
+
+// libraryItem is a saved album or track.
+type libraryItem struct {
+	album   bool
+	id      spotify.ID
+	name    string
+	artist  string
+	addedAt string
+}
+
+// kind tells whether the item is an album or a track.
+func (item libraryItem) kind() string {
+	if item.album {
+		return "album"
+	}
+	return "track"
+}
+
+// duplicate is a library item with a mark telling whether it is removed.
+type duplicate struct {
+	libraryItem
+	remove bool
+}
+
+var (
+	duplicatesToggleKey = 'x'
+	duplicatesRemoveKey = 'd'
+	// savedItemsPageSize is the limit of saved albums or tracks fetched with one request.
+	savedItemsPageSize = 50
+	// duplicatesRemoveBatchSize is the limit of tracks removed from the library with one request.
+	duplicatesRemoveBatchSize = 50
+	// editionSuffix matches parts of names which differ between editions, i.e.
+	// "(Deluxe Edition)", "[Remastered]" or "- 2011 Remaster".
+	editionSuffix = regexp.MustCompile(`\s*(\([^)]*\)|\[[^\]]*\]|\s-\s.*)$`)
+)
+
+// duplicatesTable is a table of duplicates, which changes marks and removes marked duplicates on key press.
+type duplicatesTable struct {
+	*countedTable
+	duplicates *Duplicates
+}
+
+// OnKeyEvent marks or removes duplicates when table is focused, other keys are handled by the table.
+func (t *duplicatesTable) OnKeyEvent(ev tui.KeyEvent) {
+	if t.IsFocused() && ev.Key == tui.KeyRune {
+		switch ev.Rune {
+		case duplicatesToggleKey:
+			t.duplicates.toggle(t.Selected())
+			return
+		case duplicatesRemoveKey:
+			t.duplicates.askRemove()
+			return
+		}
+	}
+	t.Table.OnKeyEvent(ev)
+}
+
+// NewDuplicates creates view of duplicates in the library, it is empty until refreshed. Albums
+// are removed from the given library, removal is confirmed with the given confirmation.
+func NewDuplicates(ctx context.Context, client SpotifyClient, library AlbumLibrary, confirmation *Confirmation) *Duplicates {
+	table := newCountedTable()
+	table.SetColumnStretch(2, 4)
+	table.SetColumnStretch(3, 2)
+	status := tui.NewLabel("")
+	status.SetWordWrap(true)
+
+	duplicates := &Duplicates{
+		ctx:          ctx,
+		client:       client,
+		library:      library,
+		confirmation: confirmation,
+		table:        table,
+		status:       status,
+	}
+	box := tui.NewVBox(&duplicatesTable{countedTable: table, duplicates: duplicates}, tui.NewSpacer(), status)
+	box.SetTitle("Duplicates")
+	box.SetBorder(true)
+	box.SetSizePolicy(tui.Expanding, tui.Expanding)
+
+	duplicates.Focusables = []tui.Widget{table}
+	duplicates.Box = box
+	return duplicates
+}
+
+// Refresh fetches all saved albums and tracks, and lists the duplicates among them.
+func (duplicates *Duplicates) Refresh() error {
+	albums, err := fetchSavedAlbums(duplicates.ctx, duplicates.client)
+	if err != nil {
+		return err
+	}
+	tracks, err := fetchSavedTracks(duplicates.ctx, duplicates.client)
+	if err != nil {
+		return err
+	}
+	items := []libraryItem{}
+	for _, album := range albums {
+		items = append(items, libraryItem{album: true, id: album.ID, name: album.Name, artist: artistsNames(album.Artists), addedAt: album.AddedAt})
+	}
+	for _, track := range tracks {
+		items = append(items, libraryItem{id: track.ID, name: track.Name, artist: artistsNames(track.Artists), addedAt: track.AddedAt})
+	}
+	duplicates.rows = nil
+	for _, group := range findDuplicates(items) {
+		for i, item := range group {
+			duplicates.rows = append(duplicates.rows, duplicate{libraryItem: item, remove: i > 0})
+		}
+	}
+	duplicates.render()
+	if len(duplicates.rows) == 0 {
+		duplicates.status.SetText("There are no duplicates in your library")
+	} else {
+		duplicates.status.SetText(fmt.Sprintf("Duplicates marked with x are removed, the first saved ones are kept. %c - keep or remove, %c - remove marked",
+			duplicatesToggleKey, duplicatesRemoveKey))
+	}
+	return nil
+}
+
+func (duplicates *Duplicates) render() {
+	selected := duplicates.table.Selected()
+	duplicates.table.RemoveRows()
+	duplicates.table.AppendRow(
+		tui.NewLabel(""),
+		tui.NewLabel("Type"),
+		tui.NewLabel("Name"),
+		tui.NewLabel("Artist"),
+	)
+	for _, row := range duplicates.rows {
+		mark := ""
+		if row.remove {
+			mark = "x"
+		}
+		duplicates.table.AppendRow(
+			tui.NewLabel(mark),
+			tui.NewLabel(row.kind()),
+			tui.NewLabel(trimWithCommasIfTooLong(row.name, 2*uiColumnWidth)),
+			tui.NewLabel(trimWithCommasIfTooLong(row.artist, uiColumnWidth)),
+		)
+	}
+	if selected > len(duplicates.rows) {
+		selected = len(duplicates.rows)
+	}
+	if selected < 1 && len(duplicates.rows) > 0 {
+		selected = 1
+	}
+	duplicates.table.SetSelected(selected)
+}
+
+// toggle switches between keeping and removing duplicate at the selected row.
+func (duplicates *Duplicates) toggle(selectedRow int) {
+	// -1 for the header
+	i := selectedRow - 1
+	if i < 0 || i >= len(duplicates.rows) {
+		return
+	}
+	duplicates.rows[i].remove = !duplicates.rows[i].remove
+	duplicates.render()
+}
+
+// askRemove asks to confirm removal of the marked duplicates.
+func (duplicates *Duplicates) askRemove() {
+	marked := 0
+	for _, row := range duplicates.rows {
+		if row.remove {
+			marked++
+		}
+	}
+	if marked == 0 {
+		duplicates.status.SetText("There are no duplicates marked to be removed")
+		return
+	}
+	duplicates.confirmation.Ask(fmt.Sprintf("Remove %d duplicates from the library?", marked), func() {
+		duplicates.status.SetText(duplicates.remove())
+	})
+}
+
+// remove removes marked duplicates from the library, tracks in batches and albums one by one,
+// so that they are removed from the album list as well. Returned text describes the outcome.
+func (duplicates *Duplicates) remove() string {
+	tracks := []spotify.ID{}
+	for _, row := range duplicates.rows {
+		if row.remove && !row.album {
+			tracks = append(tracks, row.id)
+		}
+	}
+	for start := 0; start < len(tracks); start += duplicatesRemoveBatchSize {
+		end := start + duplicatesRemoveBatchSize
+		if end > len(tracks) {
+			end = len(tracks)
+		}
+		if err := duplicates.client.RemoveTracksFromLibrary(duplicates.ctx, tracks[start:end]...); err != nil {
+			return fmt.Sprintf("Could not remove tracks: %v", err)
+		}
+	}
+	left := []duplicate{}
+	albums := 0
+	var err error
+	for _, row := range duplicates.rows {
+		switch {
+		case row.remove && !row.album:
+			// removed above
+		case !row.remove || err != nil:
+			left = append(left, row)
+		default:
+			if err = duplicates.library.RemoveAlbum(row.id); err != nil {
+				left = append(left, row)
+				continue
+			}
+			albums++
+		}
+	}
+	duplicates.rows = left
+	duplicates.render()
+	if err != nil {
+		return fmt.Sprintf("Removed %d tracks, but could not remove albums: %v", len(tracks), err)
+	}
+	return fmt.Sprintf("Removed %d albums and %d tracks", albums, len(tracks))
+}
+
+// findDuplicates groups items which have the same kind, artist and name, not counting the
+// edition, i.e. "(Deluxe Edition)". Albums are listed before tracks, items in a group are
+// sorted from the first saved one, groups with a single item are skipped.
+func findDuplicates(items []libraryItem) [][]libraryItem {
+	groups := map[string][]libraryItem{}
+	keys := []string{}
+	for _, item := range items {
+		key := fmt.Sprintf("%s\x00%s\x00%s", item.kind(), strings.ToLower(item.artist), duplicateName(item.name))
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], item)
+	}
+	sort.Strings(keys)
+	duplicates := [][]libraryItem{}
+	for _, key := range keys {
+		group := groups[key]
+		if len(group) < 2 {
+			continue
+		}
+		sort.SliceStable(group, func(i, j int) bool { return group[i].addedAt < group[j].addedAt })
+		duplicates = append(duplicates, group)
+	}
+	return duplicates
+}
+
+// duplicateName returns name without edition suffixes, in lower case.
+func duplicateName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for {
+		trimmed := editionSuffix.ReplaceAllString(name, "")
+		if trimmed == name || trimmed == "" {
+			return name
+		}
+		name = trimmed
+	}
+}
+
+// fetchSavedAlbums fetches all albums saved in the user's library.
+func fetchSavedAlbums(ctx context.Context, client SpotifyClient) ([]spotify.SavedAlbum, error) {
+	albums := []spotify.SavedAlbum{}
+	for {
+		offset := len(albums)
+		page, err := client.CurrentUsersAlbumsOpt(ctx, &spotify.Options{Limit: &savedItemsPageSize, Offset: &offset})
+		if err != nil {
+			return nil, fmt.Errorf("could not fetch saved albums: %v", err)
+		}
+		albums = append(albums, page.Albums...)
+		if page.Next == "" || len(page.Albums) == 0 {
+			return albums, nil
+		}
+	}
+}
+
+// fetchSavedTracks fetches all tracks saved in the user's Liked Songs.
+func fetchSavedTracks(ctx context.Context, client SpotifyClient) ([]spotify.SavedTrack, error) {
+	tracks := []spotify.SavedTrack{}
+	for {
+		offset := len(tracks)
+		page, err := client.CurrentUsersTracksOpt(ctx, &spotify.Options{Limit: &savedItemsPageSize, Offset: &offset})
+		if err != nil {
+			return nil, fmt.Errorf("could not fetch saved tracks: %v", err)
+		}
+		tracks = append(tracks, page.Tracks...)
+		if page.Next == "" || len(page.Tracks) == 0 {
+			return tracks, nil
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package player
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/zmb3/spotify"
+)
+
+func TestFindDuplicates(t *testing.T) {
+	items := []libraryItem{
+		{album: true, id: "deluxe", name: "Jazz (Deluxe Edition)", artist: "Queen", addedAt: "2020-02-01T00:00:00Z"},
+		{album: true, id: "jazz", name: "Jazz", artist: "Queen", addedAt: "2020-01-01T00:00:00Z"},
+		{album: true, id: "remaster", name: "Jazz - 2011 Remaster", artist: "queen", addedAt: "2020-03-01T00:00:00Z"},
+		{album: true, id: "other", name: "Jazz", artist: "Other", addedAt: "2020-01-01T00:00:00Z"},
+		{id: "track", name: "Jazz", artist: "Queen", addedAt: "2020-01-01T00:00:00Z"},
+		{album: true, id: "only", name: "(What's the Story) Morning Glory?", artist: "Oasis"},
+	}
+	groups := findDuplicates(items)
+	if len(groups) != 1 {
+		t.Fatalf("Expected one group of duplicates, got %v", groups)
+	}
+	ids := []spotify.ID{}
+	for _, item := range groups[0] {
+		ids = append(ids, item.id)
+	}
+	if !reflect.DeepEqual(ids, []spotify.ID{"jazz", "deluxe", "remaster"}) {
+		t.Fatalf("Expected editions of the album sorted from the first saved, got %v", ids)
+	}
+	if name := duplicateName("(What's the Story) Morning Glory?"); name != "(what's the story) morning glory?" {
+		t.Fatalf("Expected name to be kept when it is not an edition suffix, got %q", name)
+	}
+}
+
+type fakeDuplicatesEditor struct {
+	*DebugLibraryEditor
+	albumsRemoved []spotify.ID
+	tracksRemoved []spotify.ID
+}
+
+func (fake *fakeDuplicatesEditor) CurrentUsersTracksOpt(ctx context.Context, opt *spotify.Options) (*spotify.SavedTrackPage, error) {
+	page := &spotify.SavedTrackPage{}
+	for _, id := range []spotify.ID{"song", "song-live"} {
+		track := spotify.SavedTrack{AddedAt: string(id)}
+		track.ID = id
+		track.Name = "Song"
+		track.Artists = []spotify.SimpleArtist{{Name: "Artist"}}
+		page.Tracks = append(page.Tracks, track)
+	}
+	return page, nil
+}
+
+func (fake *fakeDuplicatesEditor) RemoveAlbumsFromLibrary(ctx context.Context, albumIDs ...spotify.ID) error {
+	fake.albumsRemoved = append(fake.albumsRemoved, albumIDs...)
+	return nil
+}
+
+func (fake *fakeDuplicatesEditor) RemoveTracksFromLibrary(ctx context.Context, trackIDs ...spotify.ID) error {
+	fake.tracksRemoved = append(fake.tracksRemoved, trackIDs...)
+	return nil
+}
+
+type fakeDuplicatesAlbumFetcher struct{}
+
+func (fake fakeDuplicatesAlbumFetcher) CurrentUsersAlbumsOpt(ctx context.Context, opt *spotify.Options) (*spotify.SavedAlbumPage, error) {
+	page := &spotify.SavedAlbumPage{}
+	for _, id := range []spotify.ID{"album", "album-deluxe", "unique"} {
+		album := spotify.SavedAlbum{AddedAt: string(id)}
+		album.ID = id
+		album.Name = "Album"
+		if id == "album-deluxe" {
+			album.Name = "Album (Deluxe)"
+		}
+		if id == "unique" {
+			album.Name = "Unique"
+		}
+		album.Artists = []spotify.SimpleArtist{{Name: "Artist"}}
+		page.Albums = append(page.Albums, album)
+	}
+	return page, nil
+}
+
+func TestDuplicatesRemovesMarkedOnes(t *testing.T) {
+	editor := &fakeDuplicatesEditor{DebugLibraryEditor: &DebugLibraryEditor{}}
+	client := NewDebugClient().(DebugClient)
+	client.LibraryEditor = editor
+	client.UserAlbumFetcher = fakeDuplicatesAlbumFetcher{}
+	albumList := newEmptyAlbumList(context.Background(), client)
+	albumList.albumsDescriptions = []albumDescription{{id: "album"}, {id: "album-deluxe"}, {id: "unique"}}
+	confirmation := NewConfirmation()
+	duplicates := NewDuplicates(context.Background(), client, albumList, confirmation)
+
+	if err := duplicates.Refresh(); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if len(duplicates.rows) != 4 || duplicates.rows[0].remove || !duplicates.rows[1].remove {
+		t.Fatalf("Expected 2 groups of duplicates with the first saved ones kept, got %v", duplicates.rows)
+	}
+	// keep the live version of the song as well
+	duplicates.toggle(4)
+
+	duplicates.askRemove()
+	confirmation.answer(true)
+	if !reflect.DeepEqual(editor.albumsRemoved, []spotify.ID{"album-deluxe"}) || len(editor.tracksRemoved) != 0 {
+		t.Fatalf("Expected only deluxe album to be removed, removed albums %v and tracks %v", editor.albumsRemoved, editor.tracksRemoved)
+	}
+	if len(albumList.albumsDescriptions) != 2 {
+		t.Fatalf("Expected removed album not to be listed anymore, got %v", albumList.albumsDescriptions)
+	}
+	if len(duplicates.rows) != 3 || duplicates.status.Text() != "Removed 1 albums and 0 tracks" {
+		t.Fatalf("Expected removed duplicate not to be listed, got %v with status %q", duplicates.rows, duplicates.status.Text())
+	}
+}
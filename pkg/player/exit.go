@@ -0,0 +1,81 @@
+package player
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/zmb3/spotify"
+	"golang.org/x/oauth2"
+)
+
+// Exit codes of commands, so that scripts can tell why they failed. They are documented in README.
+const (
+	ExitOK          = 0
+	ExitFailure     = 1
+	ExitUsage       = 2
+	ExitAuth        = 3
+	ExitNoDevice    = 4
+	ExitNotFound    = 5
+	ExitRateLimited = 6
+)
+
+// CommandError is an error of a command along with the code the application exits with.
+type CommandError struct {
+	Code int
+	Err  error
+}
+
+func (e *CommandError) Error() string {
+	return e.Err.Error()
+}
+
+// ExitCode returns the code of the error.
+func (e *CommandError) ExitCode() int {
+	return e.Code
+}
+
+// ExitCode returns the code the application exits with after the error: ExitOK when there is
+// none, the code of errors which tell it, i.e. errors of commands run by the daemon, or the code
+// of the reason Spotify gave, ExitFailure when it is not known.
+func ExitCode(err error) int {
+	switch e := err.(type) {
+	case nil:
+		return ExitOK
+	case interface{ ExitCode() int }:
+		return e.ExitCode()
+	case spotify.Error:
+		switch e.Status {
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return ExitAuth
+		case http.StatusNotFound:
+			if strings.Contains(strings.ToLower(e.Message), "no active device") {
+				return ExitNoDevice
+			}
+			return ExitNotFound
+		case http.StatusTooManyRequests:
+			return ExitRateLimited
+		}
+	case *url.Error:
+		if _, rejected := e.Err.(*oauth2.RetrieveError); rejected {
+			return ExitAuth
+		}
+	}
+	return ExitFailure
+}
+
+// exitErrorf formats error of a command which exits with the given code.
+func exitErrorf(code int, format string, args ...interface{}) error {
+	return &CommandError{Code: code, Err: fmt.Errorf(format, args...)}
+}
+
+// usageErrorf formats error of a command given wrong arguments.
+func usageErrorf(format string, args ...interface{}) error {
+	return exitErrorf(ExitUsage, format, args...)
+}
+
+// commandError describes why the command failed, keeping the exit code of the reason.
+func commandError(err error, format string, args ...interface{}) error {
+	return exitErrorf(ExitCode(err), "%s: %v", fmt.Sprintf(format, args...), err)
+}
@@ -0,0 +1,55 @@
+package player
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/zmb3/spotify"
+	"golang.org/x/oauth2"
+)
+
+func TestExitCode(t *testing.T) {
+	for _, tc := range []struct {
+		err      error
+		expected int
+	}{
+		{nil, ExitOK},
+		{errors.New("broken pipe"), ExitFailure},
+		{spotify.Error{Status: 401, Message: "The access token expired"}, ExitAuth},
+		{spotify.Error{Status: 403, Message: "Insufficient client scope"}, ExitAuth},
+		{spotify.Error{Status: 404, Message: "Player command failed: No active device found"}, ExitNoDevice},
+		{spotify.Error{Status: 404, Message: "Non existing id"}, ExitNotFound},
+		{spotify.Error{Status: 429, Message: "API rate limit exceeded"}, ExitRateLimited},
+		{spotify.Error{Status: 502, Message: "Bad gateway"}, ExitFailure},
+		{&url.Error{Op: "Post", URL: "https://accounts.spotify.com/api/token", Err: &oauth2.RetrieveError{}}, ExitAuth},
+		{&url.Error{Op: "Get", URL: "https://api.spotify.com/v1/me", Err: errors.New("no route to host")}, ExitFailure},
+		{commandError(spotify.Error{Status: 429}, "could not search"), ExitRateLimited},
+		{usageErrorf("search command takes query"), ExitUsage},
+	} {
+		if code := ExitCode(tc.err); code != tc.expected {
+			t.Errorf("Expected exit code %d for %v, got %d", tc.expected, tc.err, code)
+		}
+	}
+}
+
+func TestCommandsExitCodes(t *testing.T) {
+	for _, tc := range []struct {
+		args     []string
+		expected int
+	}{
+		{[]string{"rewind"}, ExitUsage},
+		{[]string{"next", "2"}, ExitUsage},
+		{[]string{"status", "--colour"}, ExitUsage},
+		{[]string{"volume", "set", "loud"}, ExitUsage},
+		{[]string{"volume", "up"}, ExitNoDevice},
+		{[]string{"devices", "transfer", "Fridge"}, ExitNotFound},
+	} {
+		err := RunCommand(context.Background(), &DebugClient{}, tc.args[0], tc.args[1:], &bytes.Buffer{})
+		if code := ExitCode(err); code != tc.expected {
+			t.Errorf("Expected %v to exit with %d, got %d with %v", tc.args, tc.expected, code, err)
+		}
+	}
+}
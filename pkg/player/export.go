@@ -0,0 +1,170 @@
+package player
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/jedruniu/spotify-cli/pkg/atomicfile"
+
+	"github.com/zmb3/spotify"
+)
+
+// LibraryExport is a copy of the user's library: saved albums, Liked Songs
+// and playlists with their tracks, written to a file for backup and analysis.
+type LibraryExport struct {
+	Albums    []ExportedAlbum    `json:"albums"`
+	Tracks    []ExportedTrack    `json:"tracks"`
+	Playlists []ExportedPlaylist `json:"playlists"`
+}
+
+// ExportedAlbum is a saved album.
+type ExportedAlbum struct {
+	URI         spotify.URI `json:"uri"`
+	Name        string      `json:"name"`
+	Artists     string      `json:"artists"`
+	ReleaseDate string      `json:"release_date"`
+	AddedAt     string      `json:"added_at"`
+}
+
+// ExportedTrack is a saved track, or a track of a playlist.
+type ExportedTrack struct {
+	URI      spotify.URI `json:"uri"`
+	Name     string      `json:"name"`
+	Artists  string      `json:"artists"`
+	Album    string      `json:"album"`
+	Duration int         `json:"duration_ms"`
+	AddedAt  string      `json:"added_at"`
+}
+
+// ExportedPlaylist is a playlist of the user, either owned or followed.
+type ExportedPlaylist struct {
+	URI    spotify.URI     `json:"uri"`
+	Name   string          `json:"name"`
+	Owner  string          `json:"owner"`
+	Tracks []ExportedTrack `json:"tracks"`
+}
+
+// exportCSVHeader names columns of the CSV export, in which each row is an album,
+// a saved track, or a track of the playlist named in the playlist column.
+var exportCSVHeader = []string{"type", "playlist", "uri", "name", "artists", "album", "release_date", "added_at"}
+
+// ExportLibrary fetches all saved albums, Liked Songs and playlists with their tracks,
+// only changes are fetched when they are kept in the library cache.
+func ExportLibrary(ctx context.Context, client SpotifyClient, library *LibraryCache) (*LibraryExport, error) {
+	export := &LibraryExport{}
+	albums, err := library.SavedAlbums(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	for _, album := range albums {
+		export.Albums = append(export.Albums, ExportedAlbum{
+			URI:         album.URI,
+			Name:        album.Name,
+			Artists:     artistsNames(album.Artists),
+			ReleaseDate: album.ReleaseDate,
+			AddedAt:     album.AddedAt,
+		})
+	}
+	tracks, err := library.SavedTracks(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	for _, track := range tracks {
+		export.Tracks = append(export.Tracks, exportedTrack(track.FullTrack, track.AddedAt))
+	}
+	playlists, err := fetchPlaylists(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	for _, playlist := range playlists {
+		tracks, err := library.PlaylistTracks(ctx, client, playlist)
+		if err != nil {
+			return nil, fmt.Errorf("could not export playlist %s: %v", playlist.Name, err)
+		}
+		exported := ExportedPlaylist{URI: playlist.URI, Name: playlist.Name, Owner: playlist.Owner.DisplayName}
+		for _, track := range tracks {
+			exported.Tracks = append(exported.Tracks, exportedTrack(track.Track, track.AddedAt))
+		}
+		export.Playlists = append(export.Playlists, exported)
+	}
+	return export, nil
+}
+
+func exportedTrack(track spotify.FullTrack, addedAt string) ExportedTrack {
+	return ExportedTrack{
+		URI:      track.URI,
+		Name:     track.Name,
+		Artists:  artistsNames(track.Artists),
+		Album:    track.Album.Name,
+		Duration: track.Duration,
+		AddedAt:  addedAt,
+	}
+}
+
+// ExportLibraryToFile exports the library to the file, which extension
+// (.json or .csv) tells the format. The file is written atomically.
+func ExportLibraryToFile(ctx context.Context, client SpotifyClient, library *LibraryCache, path string) (*LibraryExport, error) {
+	format := strings.ToLower(filepath.Ext(path))
+	if format != ".json" && format != ".csv" {
+		return nil, fmt.Errorf("could not export library to %s, only .json and .csv files are supported", path)
+	}
+	export, err := ExportLibrary(ctx, client, library)
+	if err != nil {
+		return nil, err
+	}
+	var data []byte
+	if format == ".json" {
+		data, err = export.JSON()
+	} else {
+		data, err = export.CSV()
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = atomicfile.WriteFile(path, data, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("could not write library export: %v", err)
+	}
+	return export, nil
+}
+
+// JSON encodes the export as indented JSON.
+func (export *LibraryExport) JSON() ([]byte, error) {
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("could not encode library export: %v", err)
+	}
+	return data, nil
+}
+
+// CSV encodes the export as CSV with exportCSVHeader columns.
+func (export *LibraryExport) CSV() ([]byte, error) {
+	rows := [][]string{exportCSVHeader}
+	for _, album := range export.Albums {
+		rows = append(rows, []string{"album", "", string(album.URI), album.Name, album.Artists, "", album.ReleaseDate, album.AddedAt})
+	}
+	for _, track := range export.Tracks {
+		rows = append(rows, []string{"track", "", string(track.URI), track.Name, track.Artists, track.Album, "", track.AddedAt})
+	}
+	for _, playlist := range export.Playlists {
+		for _, track := range playlist.Tracks {
+			rows = append(rows, []string{"playlist_track", playlist.Name, string(track.URI), track.Name, track.Artists, track.Album, "", track.AddedAt})
+		}
+	}
+	buf := &bytes.Buffer{}
+	err := csv.NewWriter(buf).WriteAll(rows)
+	if err != nil {
+		return nil, fmt.Errorf("could not encode library export: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Summary describes what was exported.
+func (export *LibraryExport) Summary() string {
+	return fmt.Sprintf("%d albums, %d saved tracks and %d playlists", len(export.Albums), len(export.Tracks), len(export.Playlists))
+}
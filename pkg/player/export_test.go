@@ -0,0 +1,60 @@
+package player
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExportLibraryToFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spotify-cli-export")
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	defer os.RemoveAll(dir)
+	client := NewDebugClient()
+	client.AddTracksToLibrary(context.Background(), "liked")
+
+	path := filepath.Join(dir, "library.json")
+	export, err := ExportLibraryToFile(context.Background(), client, nil, path)
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	// DebugClient has 135 saved albums and 3 playlists with 10 tracks each
+	if len(export.Albums) != 135 || len(export.Tracks) != 1 || len(export.Playlists) != 3 || len(export.Playlists[0].Tracks) != 10 {
+		t.Fatalf("Unexpected export: %s", export.Summary())
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	decoded := LibraryExport{}
+	if err := json.Unmarshal(data, &decoded); err != nil || len(decoded.Albums) != 135 || decoded.Tracks[0].Name != "Track liked" {
+		t.Fatalf("Expected exported JSON to decode to the same library, got %s with %v", decoded.Summary(), err)
+	}
+
+	path = filepath.Join(dir, "library.CSV")
+	if _, err := ExportLibraryToFile(context.Background(), client, nil, path); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	data, err = ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	rows, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if len(rows) != 1+135+1+30 || rows[0][0] != "type" || rows[136][0] != "track" || rows[137][0] != "playlist_track" {
+		t.Fatalf("Expected header, albums, tracks and playlist tracks, got %d rows", len(rows))
+	}
+
+	if _, err := ExportLibraryToFile(context.Background(), client, nil, filepath.Join(dir, "library.txt")); err == nil {
+		t.Fatalf("Expected to fail exporting to unsupported format")
+	}
+}
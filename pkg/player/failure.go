@@ -0,0 +1,68 @@
+package player
+
+import (
+	"fmt"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+// Actions offered when the application could not start.
+const (
+	FailureRetry = "Retry"
+	FailureLogIn = "Log in again"
+	FailureDebug = "Start in debug mode"
+	FailureQuit  = "Quit"
+)
+
+// failureActions are listed in the order they are offered.
+var failureActions = []string{FailureRetry, FailureLogIn, FailureDebug, FailureQuit}
+
+// Failure represents view shown instead of the player when authentication or fetching
+// of the library fails on start. It gives the reason and lets the user choose what to do.
+type Failure struct {
+	Focusables []tui.Widget
+	Box        *tui.Box
+	table      *countedTable
+	onChoose   func(string)
+}
+
+// NewFailure creates view of the reason why the application could not start.
+func NewFailure(reason error) *Failure {
+	table := newCountedTable()
+	for _, action := range failureActions {
+		table.AppendRow(tui.NewLabel(action))
+	}
+	table.SetSelected(0)
+
+	message := tui.NewLabel(fmt.Sprintf("Could not start: %v", reason))
+	message.SetWordWrap(true)
+	hint := tui.NewLabel("Press Enter to choose what to do, the log is kept in log.txt in the state directory")
+
+	failure := &Failure{table: table}
+	table.OnItemActivated(func(t *tui.Table) {
+		failure.choose(t.Selected())
+	})
+
+	box := tui.NewVBox(message, tui.NewSpacer(), table, tui.NewSpacer(), hint)
+	box.SetTitle("Failure")
+	box.SetBorder(true)
+	box.SetSizePolicy(tui.Expanding, tui.Expanding)
+
+	failure.Focusables = []tui.Widget{table}
+	failure.Box = box
+	return failure
+}
+
+// OnChoose sets function called with the chosen action, one of FailureRetry,
+// FailureLogIn, FailureDebug and FailureQuit.
+func (failure *Failure) OnChoose(fn func(string)) {
+	failure.onChoose = fn
+}
+
+// choose calls the function with the action at the given row.
+func (failure *Failure) choose(row int) {
+	if row < 0 || row >= len(failureActions) || failure.onChoose == nil {
+		return
+	}
+	failure.onChoose(failureActions[row])
+}
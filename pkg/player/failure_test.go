@@ -0,0 +1,24 @@
+package player
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestFailure(t *testing.T) {
+	failure := NewFailure(errors.New("token was revoked"))
+	failure.choose(0)
+
+	chosen := []string{}
+	failure.OnChoose(func(action string) {
+		chosen = append(chosen, action)
+	})
+	failure.choose(1)
+	failure.choose(3)
+	failure.choose(len(failureActions))
+
+	if expected := []string{FailureLogIn, FailureQuit}; !reflect.DeepEqual(chosen, expected) {
+		t.Fatalf("Expected %v to be chosen, got %v", expected, chosen)
+	}
+}
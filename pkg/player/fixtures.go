@@ -1,7 +1,9 @@
 package player
 
 import (
+	"context"
 	"fmt"
+	"sort"
 
 	"github.com/zmb3/spotify"
 	"golang.org/x/oauth2"
@@ -11,9 +13,19 @@ import (
 // It does not communicate with Spotify API.
 func NewDebugClient() SpotifyClient {
 	return DebugClient{
-		Player:           &DebugPlayer{},
-		Searcher:         &DebugSearcher{},
-		UserAlbumFetcher: &DebugUserAlbumFetcher{},
+		Player:                &DebugPlayer{},
+		Searcher:              &DebugSearcher{},
+		UserAlbumFetcher:      &DebugUserAlbumFetcher{},
+		ArtistBrowser:         &DebugArtistBrowser{},
+		PlaylistFetcher:       &DebugPlaylistFetcher{},
+		ShowBrowser:           &DebugShowBrowser{},
+		AudiobookBrowser:      &DebugAudiobookBrowser{},
+		AlbumBrowser:          &DebugAlbumBrowser{},
+		TopFetcher:            &DebugTopFetcher{},
+		RecommendationFetcher: &DebugRecommendationFetcher{},
+		PlaylistEditor:        NewDebugPlaylistEditor(),
+		LibraryEditor:         &DebugLibraryEditor{},
+		AudioFeatureFetcher:   &DebugAudioFeatureFetcher{},
 	}
 }
 
@@ -22,32 +34,42 @@ type DebugClient struct {
 	Player
 	Searcher
 	UserAlbumFetcher
+	ArtistBrowser
+	PlaylistFetcher
+	ShowBrowser
+	AudiobookBrowser
+	AlbumBrowser
+	TopFetcher
+	RecommendationFetcher
+	PlaylistEditor
+	LibraryEditor
+	AudioFeatureFetcher
 }
 
 type DebugPlayer struct {
 }
 
 // Play is a dummy implementation used when running in debug mode
-func (dp DebugPlayer) Play() error {
+func (dp DebugPlayer) Play(ctx context.Context) error {
 	return nil
 }
 
 // PlayOpt is a dummy implementation used when running in debug mode
-func (dp DebugPlayer) PlayOpt(opt *spotify.PlayOptions) error {
+func (dp DebugPlayer) PlayOpt(ctx context.Context, opt *spotify.PlayOptions) error {
 	return nil
 }
 
 type DebugSearcher struct{}
 
 // Search is a dummy implementation used when running in debug mode
-func (ds DebugSearcher) Search(query string, t spotify.SearchType) (*spotify.SearchResult, error) {
+func (ds DebugSearcher) Search(ctx context.Context, query string, t spotify.SearchType) (*spotify.SearchResult, error) {
 	return nil, nil
 }
 
 type DebugUserAlbumFetcher struct{}
 
 // CurrentUsersAlbumsOpt is a dummy implementation used when running in debug mode
-func (debugFetcher DebugUserAlbumFetcher) CurrentUsersAlbumsOpt(options *spotify.Options) (*spotify.SavedAlbumPage, error) {
+func (debugFetcher DebugUserAlbumFetcher) CurrentUsersAlbumsOpt(ctx context.Context, options *spotify.Options) (*spotify.SavedAlbumPage, error) {
 	return &spotify.SavedAlbumPage{
 		Albums: constructNSpotifySavedAlbums(visibleAlbums * 3),
 	}, nil
@@ -59,53 +81,656 @@ func constructNSpotifySavedAlbums(n int) []spotify.SavedAlbum {
 		album := spotify.SavedAlbum{}
 		album.Name = fmt.Sprintf("Album Name %d", i)
 		album.Artists = []spotify.SimpleArtist{spotify.SimpleArtist{Name: fmt.Sprintf("Artist Name %d", i)}}
+		album.ReleaseDate = fmt.Sprintf("%d", 1960+i%60)
+		// most recently added first, like Spotify API lists them
+		album.AddedAt = fmt.Sprintf("2020-%02d-%02dT12:00:00Z", 12-i/28%12, 28-i%28)
 		albums = append(albums, album)
 	}
 	return albums
 }
 
+type DebugArtistBrowser struct{}
+
+var debugFollowedArtistsCount = 45
+
+// CurrentUsersFollowedArtistsOpt is a dummy implementation used when running in debug mode
+func (debugBrowser DebugArtistBrowser) CurrentUsersFollowedArtistsOpt(ctx context.Context, limit int, after string) (*spotify.FullArtistCursorPage, error) {
+	start := 0
+	if after != "" {
+		fmt.Sscanf(after, "artist%d", &start)
+	}
+	end := start + limit
+	if end > debugFollowedArtistsCount {
+		end = debugFollowedArtistsCount
+	}
+	page := &spotify.FullArtistCursorPage{}
+	for i := start + 1; i <= end; i++ {
+		artist := spotify.FullArtist{Genres: []string{"rock", "jazz"}}
+		artist.ID = spotify.ID(fmt.Sprintf("artist%d", i))
+		artist.Name = fmt.Sprintf("Followed Artist %d", i)
+		page.Artists = append(page.Artists, artist)
+	}
+	page.Total = debugFollowedArtistsCount
+	if end < debugFollowedArtistsCount {
+		page.Cursor = spotify.Cursor{After: fmt.Sprintf("artist%d", end)}
+		page.Next = "next"
+	}
+	return page, nil
+}
+
+// GetArtistAlbums is a dummy implementation used when running in debug mode
+func (debugBrowser DebugArtistBrowser) GetArtistAlbums(ctx context.Context, artistID spotify.ID) (*spotify.SimpleAlbumPage, error) {
+	return &spotify.SimpleAlbumPage{Albums: []spotify.SimpleAlbum{
+		{Name: fmt.Sprintf("First Album of %s", artistID)},
+		{Name: fmt.Sprintf("Second Album of %s", artistID)},
+	}}, nil
+}
+
+// GetArtistsTopTracks is a dummy implementation used when running in debug mode
+func (debugBrowser DebugArtistBrowser) GetArtistsTopTracks(ctx context.Context, artistID spotify.ID, country string) ([]spotify.FullTrack, error) {
+	return []spotify.FullTrack{
+		{SimpleTrack: spotify.SimpleTrack{Name: fmt.Sprintf("Top Track of %s", artistID)}},
+	}, nil
+}
+
+var debugRelatedArtistsCount = 5
+
+// GetRelatedArtists is a dummy implementation used when running in debug mode
+func (debugBrowser DebugArtistBrowser) GetRelatedArtists(ctx context.Context, artistID spotify.ID) ([]spotify.FullArtist, error) {
+	artists := []spotify.FullArtist{}
+	for i := 1; i <= debugRelatedArtistsCount; i++ {
+		artist := spotify.FullArtist{Genres: []string{"rock"}}
+		artist.ID = spotify.ID(fmt.Sprintf("%srelated%d", artistID, i))
+		artist.Name = fmt.Sprintf("Artist Related to %s %d", artistID, i)
+		artists = append(artists, artist)
+	}
+	return artists, nil
+}
+
+// FollowArtist is a dummy implementation used when running in debug mode
+func (debugBrowser DebugArtistBrowser) FollowArtist(ctx context.Context, artistIDs ...spotify.ID) error {
+	return nil
+}
+
+// UnfollowArtist is a dummy implementation used when running in debug mode
+func (debugBrowser DebugArtistBrowser) UnfollowArtist(ctx context.Context, artistIDs ...spotify.ID) error {
+	return nil
+}
+
+// CurrentUserFollows is a dummy implementation used when running in debug mode,
+// only the followed artists are followed.
+func (debugBrowser DebugArtistBrowser) CurrentUserFollows(ctx context.Context, t string, ids ...spotify.ID) ([]bool, error) {
+	follows := make([]bool, 0, len(ids))
+	for _, id := range ids {
+		var i int
+		_, err := fmt.Sscanf(string(id), "artist%d", &i)
+		follows = append(follows, t == "artist" && err == nil && fmt.Sprintf("artist%d", i) == string(id))
+	}
+	return follows, nil
+}
+
+type DebugPlaylistFetcher struct{}
+
+// CurrentUsersPlaylistsOpt is a dummy implementation used when running in debug mode
+func (debugFetcher DebugPlaylistFetcher) CurrentUsersPlaylistsOpt(ctx context.Context, opt *spotify.Options) (*spotify.SimplePlaylistPage, error) {
+	page := &spotify.SimplePlaylistPage{}
+	for i := 1; i <= 3; i++ {
+		page.Playlists = append(page.Playlists, spotify.SimplePlaylist{
+			ID:    spotify.ID(fmt.Sprintf("playlist%d", i)),
+			Name:  fmt.Sprintf("Playlist Name %d", i),
+			URI:   spotify.URI(fmt.Sprintf("spotify:playlist:playlist%d", i)),
+			Owner: spotify.User{ID: debugUserID},
+		})
+	}
+	return page, nil
+}
+
+// GetCategoryPlaylistsOpt is a dummy implementation used when running in debug mode
+func (debugFetcher DebugPlaylistFetcher) GetCategoryPlaylistsOpt(ctx context.Context, catID string, opt *spotify.Options) (*spotify.SimplePlaylistPage, error) {
+	spotifyOwner := spotify.User{ID: "spotify"}
+	return &spotify.SimplePlaylistPage{Playlists: []spotify.SimplePlaylist{
+		{ID: "top50global", Name: "Top 50 - Global", Owner: spotifyOwner, URI: "spotify:playlist:top50global"},
+		{ID: "viral50global", Name: "Viral 50 - Global", Owner: spotifyOwner, URI: "spotify:playlist:viral50global"},
+		{ID: "hits", Name: "Today's Top Hits", Owner: spotifyOwner, URI: "spotify:playlist:hits"},
+	}}, nil
+}
+
+// GetPlaylistTracks is a dummy implementation used when running in debug mode
+func (debugFetcher DebugPlaylistFetcher) GetPlaylistTracks(ctx context.Context, playlistID spotify.ID) (*spotify.PlaylistTrackPage, error) {
+	page := &spotify.PlaylistTrackPage{}
+	for i := 1; i <= 50; i++ {
+		track := spotify.PlaylistTrack{}
+		track.Track.ID = spotify.ID(fmt.Sprintf("%strack%d", playlistID, i))
+		track.Track.Name = fmt.Sprintf("Track Name %d", i)
+		track.Track.URI = spotify.URI(fmt.Sprintf("spotify:track:%strack%d", playlistID, i))
+		track.Track.Artists = []spotify.SimpleArtist{{Name: fmt.Sprintf("Artist Name %d", i)}}
+		page.Tracks = append(page.Tracks, track)
+	}
+	return page, nil
+}
+
+type DebugShowBrowser struct{}
+
+// CurrentUsersShowsOpt is a dummy implementation used when running in debug mode
+func (debugBrowser DebugShowBrowser) CurrentUsersShowsOpt(ctx context.Context, opt *spotify.Options) (*spotify.SavedShowPage, error) {
+	page := &spotify.SavedShowPage{}
+	for i := 1; i <= 5; i++ {
+		show := spotify.SavedShow{}
+		show.ID = spotify.ID(fmt.Sprintf("show%d", i))
+		show.Name = fmt.Sprintf("Show Name %d", i)
+		show.Publisher = fmt.Sprintf("Publisher Name %d", i)
+		page.Shows = append(page.Shows, show)
+	}
+	return page, nil
+}
+
+var debugShowEpisodesCount = 25
+
+// GetShowEpisodesOpt is a dummy implementation used when running in debug mode
+func (debugBrowser DebugShowBrowser) GetShowEpisodesOpt(ctx context.Context, opt *spotify.Options, id string) (*spotify.SimpleEpisodePage, error) {
+	start, end := 0, debugShowEpisodesCount
+	if opt != nil && opt.Offset != nil {
+		start = *opt.Offset
+	}
+	if opt != nil && opt.Limit != nil && start+*opt.Limit < end {
+		end = start + *opt.Limit
+	}
+	page := &spotify.SimpleEpisodePage{}
+	for i := start + 1; i <= end; i++ {
+		page.Episodes = append(page.Episodes, spotify.EpisodePage{
+			ID:          spotify.ID(fmt.Sprintf("%sepisode%d", id, i)),
+			Name:        fmt.Sprintf("Episode Name %d", i),
+			URI:         spotify.URI(fmt.Sprintf("spotify:episode:%sepisode%d", id, i)),
+			Description: fmt.Sprintf("Description of episode %d", i),
+			ReleaseDate: fmt.Sprintf("2020-01-%02d", i),
+			Duration_ms: i * 3 * 60 * 1000,
+		})
+	}
+	page.Total = debugShowEpisodesCount
+	if end < debugShowEpisodesCount {
+		page.Next = "next"
+	}
+	return page, nil
+}
+
+type DebugAudiobookBrowser struct{}
+
+// CurrentUsersAudiobooksOpt is a dummy implementation used when running in debug mode
+func (debugBrowser DebugAudiobookBrowser) CurrentUsersAudiobooksOpt(ctx context.Context, opt *spotify.Options) (*AudiobookPage, error) {
+	page := &AudiobookPage{}
+	for i := 1; i <= 3; i++ {
+		page.Audiobooks = append(page.Audiobooks, Audiobook{
+			ID:      spotify.ID(fmt.Sprintf("audiobook%d", i)),
+			Name:    fmt.Sprintf("Audiobook Name %d", i),
+			URI:     spotify.URI(fmt.Sprintf("spotify:show:audiobook%d", i)),
+			Authors: []AudiobookPerson{{Name: fmt.Sprintf("Author Name %d", i)}},
+		})
+	}
+	page.Total = len(page.Audiobooks)
+	return page, nil
+}
+
+var debugAudiobookChaptersCount = 30
+
+// GetAudiobookChaptersOpt is a dummy implementation used when running in debug mode
+func (debugBrowser DebugAudiobookBrowser) GetAudiobookChaptersOpt(ctx context.Context, opt *spotify.Options, id spotify.ID) (*ChapterPage, error) {
+	start, end := 0, debugAudiobookChaptersCount
+	if opt != nil && opt.Offset != nil {
+		start = *opt.Offset
+	}
+	if opt != nil && opt.Limit != nil && start+*opt.Limit < end {
+		end = start + *opt.Limit
+	}
+	page := &ChapterPage{}
+	for i := start + 1; i <= end; i++ {
+		page.Chapters = append(page.Chapters, Chapter{
+			ID:            spotify.ID(fmt.Sprintf("%schapter%d", id, i)),
+			Name:          fmt.Sprintf("Chapter %d", i),
+			URI:           spotify.URI(fmt.Sprintf("spotify:episode:%schapter%d", id, i)),
+			ChapterNumber: i - 1,
+			DurationMs:    20 * 60 * 1000,
+		})
+	}
+	page.Total = debugAudiobookChaptersCount
+	if end < debugAudiobookChaptersCount {
+		page.Next = "next"
+	}
+	return page, nil
+}
+
+type DebugAlbumBrowser struct{}
+
+// GetAlbumCredits is a dummy implementation used when running in debug mode
+func (debugBrowser DebugAlbumBrowser) GetAlbumCredits(ctx context.Context, albumID spotify.ID) (*AlbumCredits, error) {
+	return &AlbumCredits{
+		ID:          albumID,
+		Name:        "Currently Playing Album",
+		Artists:     []spotify.SimpleArtist{{Name: "Currently Playing Artist", ID: "currentArtist"}},
+		Label:       "Debug Records",
+		ReleaseDate: "2001-02-03",
+		Copyrights:  []spotify.Copyright{{Text: "2001 Debug Records", Type: "C"}},
+	}, nil
+}
+
+type DebugTopFetcher struct{}
+
+// CurrentUsersTopTracksOpt is a dummy implementation used when running in debug mode
+func (debugFetcher DebugTopFetcher) CurrentUsersTopTracksOpt(ctx context.Context, opt *spotify.Options) (*spotify.FullTrackPage, error) {
+	timeRange := "medium"
+	if opt != nil && opt.Timerange != nil {
+		timeRange = *opt.Timerange
+	}
+	page := &spotify.FullTrackPage{}
+	for i := 1; i <= 10; i++ {
+		track := spotify.FullTrack{}
+		track.Name = fmt.Sprintf("Top Track %d (%s term)", i, timeRange)
+		track.URI = spotify.URI(fmt.Sprintf("spotify:track:top%s%d", timeRange, i))
+		page.Tracks = append(page.Tracks, track)
+	}
+	return page, nil
+}
+
+// CurrentUsersTopArtistsOpt is a dummy implementation used when running in debug mode
+func (debugFetcher DebugTopFetcher) CurrentUsersTopArtistsOpt(ctx context.Context, opt *spotify.Options) (*spotify.FullArtistPage, error) {
+	timeRange := "medium"
+	if opt != nil && opt.Timerange != nil {
+		timeRange = *opt.Timerange
+	}
+	page := &spotify.FullArtistPage{}
+	for i := 1; i <= 5; i++ {
+		artist := spotify.FullArtist{}
+		artist.Name = fmt.Sprintf("Top Artist %d (%s term)", i, timeRange)
+		artist.URI = spotify.URI(fmt.Sprintf("spotify:artist:top%s%d", timeRange, i))
+		page.Artists = append(page.Artists, artist)
+	}
+	return page, nil
+}
+
+type DebugRecommendationFetcher struct{}
+
+// GetRecommendations is a dummy implementation used when running in debug mode
+func (debugFetcher DebugRecommendationFetcher) GetRecommendations(ctx context.Context, seeds spotify.Seeds, trackAttributes *spotify.TrackAttributes, opt *spotify.Options) (*spotify.Recommendations, error) {
+	recommendations := &spotify.Recommendations{}
+	for i := 1; i <= 10; i++ {
+		recommendations.Tracks = append(recommendations.Tracks, spotify.SimpleTrack{
+			ID:   spotify.ID(fmt.Sprintf("recommended%d", i)),
+			Name: fmt.Sprintf("Recommended Track %d", i),
+			URI:  spotify.URI(fmt.Sprintf("spotify:track:recommended%d", i)),
+		})
+	}
+	return recommendations, nil
+}
+
+// PlayerRecentlyPlayedOpt is a dummy implementation used when running in debug mode
+func (debugFetcher DebugRecommendationFetcher) PlayerRecentlyPlayedOpt(ctx context.Context, opt *spotify.RecentlyPlayedOptions) ([]spotify.RecentlyPlayedItem, error) {
+	played := []spotify.RecentlyPlayedItem{}
+	for i := 1; i <= 10; i++ {
+		track := spotify.SimpleTrack{
+			ID:      spotify.ID(fmt.Sprintf("played%d", i%5)),
+			Name:    fmt.Sprintf("Played Track %d", i%5),
+			Artists: []spotify.SimpleArtist{{ID: spotify.ID(fmt.Sprintf("artist%d", i%2)), Name: fmt.Sprintf("Artist Name %d", i%2)}},
+		}
+		played = append(played, spotify.RecentlyPlayedItem{Track: track})
+	}
+	return played, nil
+}
+
+// DebugLibraryEditor keeps saved tracks in memory, none of them is saved initially.
+type DebugLibraryEditor struct {
+	savedTracks map[spotify.ID]bool
+}
+
+// AddAlbumsToLibrary is a dummy implementation used when running in debug mode
+func (debugEditor DebugLibraryEditor) AddAlbumsToLibrary(ctx context.Context, albumIDs ...spotify.ID) error {
+	return nil
+}
+
+// RemoveAlbumsFromLibrary is a dummy implementation used when running in debug mode
+func (debugEditor DebugLibraryEditor) RemoveAlbumsFromLibrary(ctx context.Context, albumIDs ...spotify.ID) error {
+	return nil
+}
+
+// AddTracksToLibrary is a dummy implementation used when running in debug mode
+func (debugEditor *DebugLibraryEditor) AddTracksToLibrary(ctx context.Context, trackIDs ...spotify.ID) error {
+	return debugEditor.modifyTracks(true, trackIDs)
+}
+
+// RemoveTracksFromLibrary is a dummy implementation used when running in debug mode
+func (debugEditor *DebugLibraryEditor) RemoveTracksFromLibrary(ctx context.Context, trackIDs ...spotify.ID) error {
+	return debugEditor.modifyTracks(false, trackIDs)
+}
+
+// UserHasTracks is a dummy implementation used when running in debug mode
+func (debugEditor *DebugLibraryEditor) UserHasTracks(ctx context.Context, trackIDs ...spotify.ID) ([]bool, error) {
+	if len(trackIDs) > 50 {
+		return nil, fmt.Errorf("spotify: UserHasTracks supports 1 to 50 IDs per call")
+	}
+	saved := make([]bool, 0, len(trackIDs))
+	for _, id := range trackIDs {
+		saved = append(saved, debugEditor.savedTracks[id])
+	}
+	return saved, nil
+}
+
+// CurrentUsersTracksOpt is a dummy implementation used when running in debug mode
+func (debugEditor *DebugLibraryEditor) CurrentUsersTracksOpt(ctx context.Context, opt *spotify.Options) (*spotify.SavedTrackPage, error) {
+	ids := []string{}
+	for id, saved := range debugEditor.savedTracks {
+		if saved {
+			ids = append(ids, string(id))
+		}
+	}
+	sort.Strings(ids)
+	page := &spotify.SavedTrackPage{}
+	for _, id := range ids {
+		track := spotify.SavedTrack{}
+		track.ID = spotify.ID(id)
+		track.URI = spotify.URI("spotify:track:" + id)
+		track.Name = "Track " + id
+		track.Artists = []spotify.SimpleArtist{{Name: "Debug Artist"}}
+		page.Tracks = append(page.Tracks, track)
+	}
+	page.Total = len(page.Tracks)
+	return page, nil
+}
+
+func (debugEditor *DebugLibraryEditor) modifyTracks(saved bool, trackIDs []spotify.ID) error {
+	if debugEditor.savedTracks == nil {
+		debugEditor.savedTracks = map[spotify.ID]bool{}
+	}
+	for _, id := range trackIDs {
+		debugEditor.savedTracks[id] = saved
+	}
+	return nil
+}
+
+type DebugAudioFeatureFetcher struct{}
+
+// GetAudioFeatures is a dummy implementation used when running in debug mode,
+// features are derived from the track ID, so that they differ between tracks.
+func (debugFetcher DebugAudioFeatureFetcher) GetAudioFeatures(ctx context.Context, ids ...spotify.ID) ([]*spotify.AudioFeatures, error) {
+	features := []*spotify.AudioFeatures{}
+	for _, id := range ids {
+		sum := 0
+		for _, c := range id {
+			sum += int(c)
+		}
+		features = append(features, &spotify.AudioFeatures{
+			ID:           id,
+			Tempo:        float32(60 + sum%120),
+			Energy:       float32(sum%10) / 10,
+			Danceability: float32(sum%7) / 7,
+			Valence:      float32(sum%5) / 5,
+			Loudness:     -float32(sum % 20),
+		})
+	}
+	return features, nil
+}
+
+var debugUserID = "debug"
+
+// DebugPlaylistEditor keeps edited playlists in memory, each of them initially has 10 tracks.
+type DebugPlaylistEditor struct {
+	playlists map[spotify.ID]*debugPlaylist
+}
+
+type debugPlaylist struct {
+	snapshot      int
+	tracks        []spotify.PlaylistTrack
+	name          string
+	description   string
+	public        bool
+	collaborative bool
+}
+
+// NewDebugPlaylistEditor creates editor of in-memory playlists.
+func NewDebugPlaylistEditor() *DebugPlaylistEditor {
+	return &DebugPlaylistEditor{playlists: map[spotify.ID]*debugPlaylist{}}
+}
+
+func (debugEditor *DebugPlaylistEditor) playlist(playlistID spotify.ID) *debugPlaylist {
+	playlist, ok := debugEditor.playlists[playlistID]
+	if !ok {
+		playlist = &debugPlaylist{name: fmt.Sprintf("Playlist %s", playlistID)}
+		for i := 1; i <= 10; i++ {
+			playlist.tracks = append(playlist.tracks, debugPlaylistTrack(spotify.ID(fmt.Sprintf("%strack%d", playlistID, i))))
+		}
+		debugEditor.playlists[playlistID] = playlist
+	}
+	return playlist
+}
+
+func (playlist *debugPlaylist) snapshotID() string {
+	return fmt.Sprintf("snapshot%d", playlist.snapshot)
+}
+
+func debugPlaylistTrack(id spotify.ID) spotify.PlaylistTrack {
+	track := spotify.PlaylistTrack{}
+	track.Track.ID = id
+	track.Track.Name = fmt.Sprintf("Track %s", id)
+	track.Track.URI = spotify.URI("spotify:track:" + string(id))
+	return track
+}
+
+// GetPlaylistOpt is a dummy implementation used when running in debug mode
+func (debugEditor *DebugPlaylistEditor) GetPlaylistOpt(ctx context.Context, playlistID spotify.ID, fields string) (*spotify.FullPlaylist, error) {
+	edited := debugEditor.playlist(playlistID)
+	playlist := &spotify.FullPlaylist{Description: edited.description}
+	playlist.ID = playlistID
+	playlist.Name = edited.name
+	playlist.IsPublic = edited.public
+	playlist.Collaborative = edited.collaborative
+	playlist.Owner.ID = debugUserID
+	playlist.SnapshotID = edited.snapshotID()
+	return playlist, nil
+}
+
+// GetPlaylistTracksOpt is a dummy implementation used when running in debug mode
+func (debugEditor *DebugPlaylistEditor) GetPlaylistTracksOpt(ctx context.Context, playlistID spotify.ID, opt *spotify.Options, fields string) (*spotify.PlaylistTrackPage, error) {
+	tracks := debugEditor.playlist(playlistID).tracks
+	start, end := 0, len(tracks)
+	if opt != nil && opt.Offset != nil {
+		start = *opt.Offset
+	}
+	if start > end {
+		start = end
+	}
+	if opt != nil && opt.Limit != nil && start+*opt.Limit < end {
+		end = start + *opt.Limit
+	}
+	page := &spotify.PlaylistTrackPage{Tracks: append([]spotify.PlaylistTrack{}, tracks[start:end]...)}
+	page.Total = len(tracks)
+	if end < len(tracks) {
+		page.Next = "next"
+	}
+	return page, nil
+}
+
+// AddTracksToPlaylist is a dummy implementation used when running in debug mode
+func (debugEditor *DebugPlaylistEditor) AddTracksToPlaylist(ctx context.Context, playlistID spotify.ID, trackIDs ...spotify.ID) (string, error) {
+	playlist := debugEditor.playlist(playlistID)
+	for _, id := range trackIDs {
+		playlist.tracks = append(playlist.tracks, debugPlaylistTrack(id))
+	}
+	playlist.snapshot++
+	return playlist.snapshotID(), nil
+}
+
+// RemoveTracksFromPlaylistOpt is a dummy implementation used when running in debug mode
+func (debugEditor *DebugPlaylistEditor) RemoveTracksFromPlaylistOpt(ctx context.Context, playlistID spotify.ID, tracks []spotify.TrackToRemove, snapshotID string) (string, error) {
+	playlist := debugEditor.playlist(playlistID)
+	if snapshotID != playlist.snapshotID() {
+		return "", fmt.Errorf("snapshot %s is out of date", snapshotID)
+	}
+	removed := map[int]bool{}
+	for _, track := range tracks {
+		for _, position := range track.Positions {
+			if position >= len(playlist.tracks) || string(playlist.tracks[position].Track.URI) != track.URI {
+				return "", fmt.Errorf("there is no %s at position %d", track.URI, position)
+			}
+			removed[position] = true
+		}
+	}
+	kept := []spotify.PlaylistTrack{}
+	for position, track := range playlist.tracks {
+		if !removed[position] {
+			kept = append(kept, track)
+		}
+	}
+	playlist.tracks = kept
+	playlist.snapshot++
+	return playlist.snapshotID(), nil
+}
+
+// ReorderPlaylistTracks is a dummy implementation used when running in debug mode
+func (debugEditor *DebugPlaylistEditor) ReorderPlaylistTracks(ctx context.Context, playlistID spotify.ID, opt spotify.PlaylistReorderOptions) (string, error) {
+	playlist := debugEditor.playlist(playlistID)
+	if opt.SnapshotID != "" && opt.SnapshotID != playlist.snapshotID() {
+		return "", fmt.Errorf("snapshot %s is out of date", opt.SnapshotID)
+	}
+	length := opt.RangeLength
+	if length == 0 {
+		length = 1
+	}
+	if opt.RangeStart < 0 || opt.RangeStart+length > len(playlist.tracks) || opt.InsertBefore < 0 || opt.InsertBefore > len(playlist.tracks) {
+		return "", fmt.Errorf("reorder range is out of playlist")
+	}
+	moved := append([]spotify.PlaylistTrack{}, playlist.tracks[opt.RangeStart:opt.RangeStart+length]...)
+	rest := append(append([]spotify.PlaylistTrack{}, playlist.tracks[:opt.RangeStart]...), playlist.tracks[opt.RangeStart+length:]...)
+	insertAt := opt.InsertBefore
+	if insertAt > opt.RangeStart {
+		insertAt -= length
+	}
+	reordered := append(append(append([]spotify.PlaylistTrack{}, rest[:insertAt]...), moved...), rest[insertAt:]...)
+	playlist.tracks = reordered
+	playlist.snapshot++
+	return playlist.snapshotID(), nil
+}
+
+// CreatePlaylistForUser is a dummy implementation used when running in debug mode
+func (debugEditor *DebugPlaylistEditor) CreatePlaylistForUser(ctx context.Context, userID, playlistName, description string, public bool) (*spotify.FullPlaylist, error) {
+	id := spotify.ID(fmt.Sprintf("created%d", len(debugEditor.playlists)))
+	debugEditor.playlists[id] = &debugPlaylist{name: playlistName, description: description, public: public}
+	playlist := &spotify.FullPlaylist{Description: description}
+	playlist.ID = id
+	playlist.Name = playlistName
+	playlist.IsPublic = public
+	playlist.URI = spotify.URI("spotify:playlist:" + string(id))
+	playlist.SnapshotID = debugEditor.playlists[id].snapshotID()
+	return playlist, nil
+}
+
+// ChangePlaylistNameAccessAndDescription is a dummy implementation used when running in debug mode
+func (debugEditor *DebugPlaylistEditor) ChangePlaylistNameAccessAndDescription(ctx context.Context, playlistID spotify.ID, newName, newDescription string, public bool) error {
+	playlist := debugEditor.playlist(playlistID)
+	playlist.name = newName
+	playlist.description = newDescription
+	playlist.public = public
+	return nil
+}
+
+// ChangePlaylistCollaborative is a dummy implementation used when running in debug mode
+func (debugEditor *DebugPlaylistEditor) ChangePlaylistCollaborative(ctx context.Context, playlistID spotify.ID, collaborative bool) error {
+	playlist := debugEditor.playlist(playlistID)
+	playlist.collaborative = collaborative
+	if collaborative {
+		playlist.public = false
+	}
+	return nil
+}
+
 // Previous is a dummy implementation used when running in debug mode
-func (fc DebugClient) Previous() error {
+func (fc DebugClient) Previous(ctx context.Context) error {
 	return nil
 }
 
 // Pause is a dummy implementation used when running in debug mode
-func (fc DebugClient) Pause() error {
+func (fc DebugClient) Pause(ctx context.Context) error {
 	return nil
 }
 
 // Next is a dummy implementation used when running in debug mode
-func (fc DebugClient) Next() error {
+func (fc DebugClient) Next(ctx context.Context) error {
+	return nil
+}
+
+// Volume is a dummy implementation used when running in debug mode
+func (fc DebugClient) Volume(ctx context.Context, percent int) error {
+	return nil
+}
+
+// Seek is a dummy implementation used when running in debug mode
+func (fc DebugClient) Seek(ctx context.Context, position int) error {
+	return nil
+}
+
+// Shuffle is a dummy implementation used when running in debug mode
+func (fc DebugClient) Shuffle(ctx context.Context, shuffle bool) error {
+	return nil
+}
+
+// Repeat is a dummy implementation used when running in debug mode
+func (fc DebugClient) Repeat(ctx context.Context, state string) error {
+	return nil
+}
+
+// PlayerState is a dummy implementation used when running in debug mode
+func (fc DebugClient) PlayerState(ctx context.Context) (*spotify.PlayerState, error) {
+	playing, err := fc.PlayerCurrentlyPlaying(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &spotify.PlayerState{CurrentlyPlaying: playing.CurrentlyPlaying, Device: spotify.PlayerDevice{ID: "debug", Name: "Debug", Active: true}, RepeatState: "off"}, nil
+}
+
+// QueueSong is a dummy implementation used when running in debug mode
+func (fc DebugClient) QueueSong(ctx context.Context, trackID spotify.ID) error {
 	return nil
 }
 
 // PlayerCurrentlyPlaying is a dummy implementation used when running in debug mode
-func (fc DebugClient) PlayerCurrentlyPlaying() (*spotify.CurrentlyPlaying, error) {
-	return &spotify.CurrentlyPlaying{Item: &spotify.FullTrack{SimpleTrack: spotify.SimpleTrack{
+func (fc DebugClient) PlayerCurrentlyPlaying(ctx context.Context) (*PlaybackItem, error) {
+	return &PlaybackItem{CurrentlyPlaying: spotify.CurrentlyPlaying{Item: &spotify.FullTrack{SimpleTrack: spotify.SimpleTrack{
 		Name:    "Currently Playing Song",
-		Artists: []spotify.SimpleArtist{{Name: "Currently Playing Artist"}}},
-		Album: spotify.SimpleAlbum{Name: "Currently Playing Album"}},
-	}, nil
+		Artists: []spotify.SimpleArtist{{Name: "Currently Playing Artist", ID: "currentArtist"}}},
+		Album: spotify.SimpleAlbum{Name: "Currently Playing Album", ID: "currentAlbum"}},
+	}}, nil
+}
+
+// PlayerQueue is a dummy implementation used when running in debug mode
+func (fc DebugClient) PlayerQueue(ctx context.Context) (*Queue, error) {
+	playing, _ := fc.PlayerCurrentlyPlaying(ctx)
+	queue := &Queue{CurrentlyPlaying: playing.Item}
+	for i := 1; i <= 3; i++ {
+		queue.Queue = append(queue.Queue, spotify.FullTrack{SimpleTrack: spotify.SimpleTrack{
+			Name:    fmt.Sprintf("Queued Song %d", i),
+			URI:     spotify.URI(fmt.Sprintf("spotify:track:queued%d", i)),
+			Artists: []spotify.SimpleArtist{{Name: "Queued Artist"}}},
+		})
+	}
+	return queue, nil
 }
 
 // PlayerDevices is a dummy implementation used when running in debug mode
-func (fc DebugClient) PlayerDevices() ([]spotify.PlayerDevice, error) {
+func (fc DebugClient) PlayerDevices(ctx context.Context) ([]spotify.PlayerDevice, error) {
 
 	return []spotify.PlayerDevice{
-		{Name: "iPad", Type: "Tablet"},
-		{Name: "iPhone", Type: "Smarthphone"},
-		{Name: "Mac", Type: "App Player"},
+		{ID: "ipad", Name: "iPad", Type: "Tablet"},
+		{ID: "iphone", Name: "iPhone", Type: "Smarthphone"},
+		{ID: "mac", Name: "Mac", Type: "App Player"},
 	}, nil
 }
 
 // TransferPlayback is a dummy implementation used when running in debug mode
-func (fc DebugClient) TransferPlayback(id spotify.ID, play bool) error {
+func (fc DebugClient) TransferPlayback(ctx context.Context, id spotify.ID, play bool) error {
 	return nil
 }
 
 // CurrentUser is a dummy implementation used when running in debug mode
-func (fc DebugClient) CurrentUser() (*spotify.PrivateUser, error) {
-	return &spotify.PrivateUser{}, nil
+func (fc DebugClient) CurrentUser(ctx context.Context) (*spotify.PrivateUser, error) {
+	user := &spotify.PrivateUser{}
+	user.ID = debugUserID
+	return user, nil
 }
 
 // Token is a dummy implementation used when running in debug mode
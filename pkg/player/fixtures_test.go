@@ -1,6 +1,7 @@
 package player
 
 import (
+	"context"
 	"testing"
 
 	"github.com/zmb3/spotify"
@@ -9,17 +10,17 @@ import (
 func TestFixturesForDebugMode(t *testing.T) {
 	debugClient := NewDebugClient()
 
-	err := debugClient.PlayOpt(&spotify.PlayOptions{})
+	err := debugClient.PlayOpt(context.Background(), &spotify.PlayOptions{})
 	if err != nil {
 		t.Errorf("Expected not to return error, but got %v", err)
 	}
 
-	err = debugClient.Play()
+	err = debugClient.Play(context.Background())
 	if err != nil {
 		t.Errorf("Expected not to return error, but got %v", err)
 	}
 
-	albumsPage, err := debugClient.CurrentUsersAlbumsOpt(&spotify.Options{})
+	albumsPage, err := debugClient.CurrentUsersAlbumsOpt(context.Background(), &spotify.Options{})
 	expectedAlbumsCount := 135 // 3*pageSize
 	if len(albumsPage.Albums) != expectedAlbumsCount {
 		t.Errorf("Expected to have %d fake albums, have %d", expectedAlbumsCount, len(albumsPage.Albums))
@@ -28,27 +29,27 @@ func TestFixturesForDebugMode(t *testing.T) {
 		t.Errorf("Expected not to return error, but got %v", err)
 	}
 
-	err = debugClient.Previous()
+	err = debugClient.Previous(context.Background())
 	if err != nil {
 		t.Errorf("Expected not to return error, but got %v", err)
 	}
 
-	err = debugClient.Pause()
+	err = debugClient.Pause(context.Background())
 	if err != nil {
 		t.Errorf("Expected not to return error, but got %v", err)
 	}
 
-	err = debugClient.Next()
+	err = debugClient.Next(context.Background())
 	if err != nil {
 		t.Errorf("Expected not to return error, but got %v", err)
 	}
 
-	_, err = debugClient.PlayerCurrentlyPlaying()
+	_, err = debugClient.PlayerCurrentlyPlaying(context.Background())
 	if err != nil {
 		t.Errorf("Expected not to return error, but got %v", err)
 	}
 
-	devices, err := debugClient.PlayerDevices()
+	devices, err := debugClient.PlayerDevices(context.Background())
 	expectedDevicesCount := 3
 	if len(devices) != expectedDevicesCount {
 		t.Errorf("Expected to have %d fake devices, have %d", expectedDevicesCount, len(devices))
@@ -57,22 +58,30 @@ func TestFixturesForDebugMode(t *testing.T) {
 		t.Errorf("Expected not to return error, but got %v", err)
 	}
 
-	err = debugClient.TransferPlayback("id", true)
+	err = debugClient.TransferPlayback(context.Background(), "id", true)
 	if err != nil {
 		t.Errorf("Expected not to return error, but got %v", err)
 	}
 
-	// _, err = debugClient.CurrentUser()
-	// if err != nil {
-	// 	t.Errorf("Expected not to return error, but got %v", err)
-	// }
+	_, err = debugClient.CurrentUser(context.Background())
+	if err != nil {
+		t.Errorf("Expected not to return error, but got %v", err)
+	}
 
 	// _, err = debugClient.Token()
 	// if err != nil {
 	// 	t.Errorf("Expected not to return error, but got %v", err)
 	// }
 
-	_, err = debugClient.Search("query", spotify.SearchTypeArtist)
+	followed, err := debugClient.CurrentUsersFollowedArtistsOpt(context.Background(), 20, "")
+	if len(followed.Artists) != 20 {
+		t.Errorf("Expected to have 20 fake followed artists on the first page, have %d", len(followed.Artists))
+	}
+	if err != nil {
+		t.Errorf("Expected not to return error, but got %v", err)
+	}
+
+	_, err = debugClient.Search(context.Background(), "query", spotify.SearchTypeArtist)
 	if err != nil {
 		t.Errorf("Expected not to return error, but got %v", err)
 	}
@@ -0,0 +1,67 @@
+package player
+
+import "github.com/marcusolsson/tui-go"
+
+// FocusChain is a ring of focusable widgets which, in addition to what
+// tui.SimpleFocusChain does, allows to move focus to the chosen widget.
+// Keys are passed to the focused widget recovering from its panics, see Recover.
+type FocusChain struct {
+	tui.SimpleFocusChain
+	focused tui.Widget
+	widgets []tui.Widget
+	// recovering are widgets of the chain by the widgets they wrap, they are kept
+	// once the chain is set again, so that the focused one is found in the new chain
+	recovering map[tui.Widget]tui.Widget
+}
+
+// Set sets widgets of the chain, in order they are focused.
+func (chain *FocusChain) Set(ws ...tui.Widget) {
+	if chain.recovering == nil {
+		chain.recovering = map[tui.Widget]tui.Widget{}
+	}
+	wrapped := make([]tui.Widget, 0, len(ws))
+	for _, w := range ws {
+		recovering, ok := chain.recovering[w]
+		if !ok {
+			recovering = &recoveringWidget{Widget: w}
+			chain.recovering[w] = recovering
+		}
+		wrapped = append(wrapped, recovering)
+	}
+	chain.widgets = wrapped
+	chain.SimpleFocusChain.Set(wrapped...)
+}
+
+// FocusDefault returns widget chosen with Focus, or the first widget of the
+// chain if none was chosen yet.
+func (chain *FocusChain) FocusDefault() tui.Widget {
+	if chain.focused != nil {
+		return chain.focused
+	}
+	return chain.SimpleFocusChain.FocusDefault()
+}
+
+// Focus moves focus of the ui to the given widget.
+func (chain *FocusChain) Focus(ui tui.UI, w tui.Widget) {
+	if recovering, ok := chain.recovering[w]; ok {
+		w = recovering
+	}
+	chain.focused = w
+	ui.SetFocusChain(chain)
+}
+
+// Move moves focus of the ui to the widget after the focused one, or before it when back is true.
+func (chain *FocusChain) Move(ui tui.UI, back bool) {
+	for i, w := range chain.widgets {
+		if !w.IsFocused() {
+			continue
+		}
+		step := 1
+		if back {
+			step = len(chain.widgets) - 1
+		}
+		chain.focused = chain.widgets[(i+step)%len(chain.widgets)]
+		ui.SetFocusChain(chain)
+		return
+	}
+}
@@ -0,0 +1,96 @@
+package player
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/marcusolsson/tui-go"
+	"github.com/zmb3/spotify"
+)
+
+// historyTracks is how many recently played tracks are listed, the most the API gives.
+var historyTracks = 50
+
+// History represents view listing recently played tracks, most recent first.
+// Pressing Enter plays the selected track.
+type History struct {
+	Focusables []tui.Widget
+	Box        *tui.Box
+	ctx        context.Context
+	client     SpotifyClient
+	table      *countedTable
+	location   *time.Location
+	// played are recently played tracks as listed in the table, row by row.
+	played []spotify.RecentlyPlayedItem
+}
+
+// NewHistory creates view of recently played tracks, times they were played at are
+// shown in the given location. It is empty until refreshed.
+func NewHistory(ctx context.Context, client SpotifyClient, location *time.Location) *History {
+	if location == nil {
+		location = time.Local
+	}
+	table := newCountedTable()
+	table.SetColumnStretch(0, 2)
+	table.SetColumnStretch(1, 2)
+	table.SetColumnStretch(2, 1)
+
+	history := &History{
+		ctx:      ctx,
+		client:   client,
+		table:    table,
+		location: location,
+	}
+	table.OnItemActivated(func(t *tui.Table) {
+		history.play(t.Selected())
+	})
+
+	box := tui.NewVBox(table, tui.NewSpacer())
+	box.SetTitle("Recently played")
+	box.SetBorder(true)
+	box.SetSizePolicy(tui.Expanding, tui.Expanding)
+
+	history.Focusables = []tui.Widget{table}
+	history.Box = box
+	return history
+}
+
+// Refresh lists tracks played recently again.
+func (history *History) Refresh() error {
+	played, err := history.client.PlayerRecentlyPlayedOpt(history.ctx, &spotify.RecentlyPlayedOptions{Limit: historyTracks})
+	if err != nil {
+		return fmt.Errorf("could not get recently played tracks: %v", err)
+	}
+	history.played = played
+	history.table.RemoveRows()
+	for _, item := range history.played {
+		playedAt := ""
+		if !item.PlayedAt.IsZero() {
+			playedAt = item.PlayedAt.In(history.location).Format("Jan 2 15:04")
+		}
+		history.table.AppendRow(
+			tui.NewLabel(trimWithCommasIfTooLong(item.Track.Name, 2*uiColumnWidth)),
+			tui.NewLabel(trimWithCommasIfTooLong(artistsNames(item.Track.Artists), 2*uiColumnWidth)),
+			tui.NewLabel(playedAt),
+		)
+	}
+	if len(history.played) == 0 {
+		history.table.AppendRow(tui.NewLabel("Nothing was played recently"))
+	}
+	history.table.SetSelected(0)
+	return nil
+}
+
+// play plays the track at the given row.
+func (history *History) play(row int) {
+	if row < 0 || row >= len(history.played) {
+		return
+	}
+	track := history.played[row].Track
+	if err := history.client.PlayOpt(history.ctx, &spotify.PlayOptions{URIs: []spotify.URI{track.URI}}); err != nil {
+		notifyError(fmt.Errorf("could not play %s: %v", track.Name, err))
+		return
+	}
+	notify("Playing %s - %s", artistsNames(track.Artists), track.Name)
+}
@@ -0,0 +1,26 @@
+package player
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestHistoryPlaysRecentlyPlayedTrack(t *testing.T) {
+	client := NewDebugClient().(DebugClient)
+	player := &FakePlayer{}
+	client.Player = player
+	history := NewHistory(context.Background(), client, time.UTC)
+	if err := history.Refresh(); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if len(history.played) != 10 || history.table.Selected() != 0 {
+		t.Fatalf("Expected 10 recently played tracks with the first selected, got %d with %d selected", len(history.played), history.table.Selected())
+	}
+
+	history.play(1)
+	history.play(len(history.played))
+	if player.playOptCalls != 1 {
+		t.Fatalf("Expected track to be played once, got %d", player.playOptCalls)
+	}
+}
@@ -0,0 +1,156 @@
+package player
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jedruniu/spotify-cli/pkg/cache"
+
+	"github.com/marcusolsson/tui-go"
+	"github.com/zmb3/spotify"
+)
+
+// Home represents landing view with tracks suggested because of recently
+// played ones, i.e. "Because you listened to X". Suggestions are cached
+// and fetched again once a day.
+type Home struct {
+	Focusables  []tui.Widget
+	Box         *tui.Box
+	ctx         context.Context
+	client      SpotifyClient
+	recommender Recommender
+	store       *cache.Store
+	location    *time.Location
+	groups      []searchResultsInterface
+	suggested   []homeSuggested
+	now         func() time.Time
+}
+
+// homeSuggestions are suggestions cached for the day they were fetched on.
+type homeSuggestions struct {
+	Day    string          `json:"day"`
+	Groups []homeSuggested `json:"groups"`
+}
+
+// homeSuggested are tracks suggested because of the seed track.
+type homeSuggested struct {
+	Seed   string    `json:"seed"`
+	Tracks []URIName `json:"tracks"`
+}
+
+var (
+	homeCacheEntry     = "home"
+	homeSeedsCount     = 3
+	homeTracksPerSeed  = 10
+	homeRecentlyPlayed = 50
+	homeDayFormat      = "2006-01-02"
+)
+
+// NewHome creates landing view with suggestions of the given recommender, days
+// are counted in the given location. It is empty until refreshed.
+func NewHome(ctx context.Context, client SpotifyClient, recommender Recommender, store *cache.Store, location *time.Location) *Home {
+	if location == nil {
+		location = time.Local
+	}
+	home := &Home{
+		ctx:         ctx,
+		client:      client,
+		recommender: recommender,
+		store:       store,
+		location:    location,
+		now:         time.Now,
+	}
+	box := tui.NewVBox()
+	box.SetSizePolicy(tui.Expanding, tui.Expanding)
+	for i := 0; i < homeSeedsCount; i++ {
+		group := NewSearchResults(ctx, client, "Suggested")
+		home.groups = append(home.groups, group)
+		home.Focusables = append(home.Focusables, group.getTable())
+		box.Append(group.getBox())
+	}
+	home.Box = box
+	return home
+}
+
+// OnAddToPlaylist sets function called with suggested tracks which should be added to a playlist.
+func (home *Home) OnAddToPlaylist(fn func([]spotify.ID)) {
+	for _, group := range home.groups {
+		group.onAddToPlaylist(fn)
+	}
+}
+
+// Refresh shows today's suggestions, they are fetched only when
+// suggestions cached earlier are from another day.
+func (home *Home) Refresh() error {
+	today := home.now().In(home.location).Format(homeDayFormat)
+	cached := homeSuggestions{}
+	err := home.store.Load(homeCacheEntry, &cached)
+	if err != nil {
+		return err
+	}
+	if cached.Day != today || len(cached.Groups) == 0 {
+		groups, err := home.suggest()
+		if err != nil {
+			return err
+		}
+		cached = homeSuggestions{Day: today, Groups: groups}
+		err = home.store.Save(homeCacheEntry, cached)
+		if err != nil {
+			return err
+		}
+	}
+	home.show(cached.Groups)
+	return nil
+}
+
+// suggest recommends tracks for each of the most recently played distinct tracks.
+func (home *Home) suggest() ([]homeSuggested, error) {
+	played, err := home.client.PlayerRecentlyPlayedOpt(home.ctx, &spotify.RecentlyPlayedOptions{Limit: homeRecentlyPlayed})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch recently played tracks: %v", err)
+	}
+	seen := map[spotify.ID]bool{}
+	groups := []homeSuggested{}
+	for _, item := range played {
+		if len(groups) == homeSeedsCount {
+			break
+		}
+		if seen[item.Track.ID] {
+			continue
+		}
+		seen[item.Track.ID] = true
+		tracks, err := home.recommender.Recommend(spotify.FullTrack{SimpleTrack: item.Track})
+		if err != nil {
+			return nil, err
+		}
+		group := homeSuggested{Seed: item.Track.Name}
+		if len(item.Track.Artists) > 0 {
+			group.Seed += " by " + item.Track.Artists[0].Name
+		}
+		for _, track := range tracks {
+			if len(group.Tracks) == homeTracksPerSeed {
+				break
+			}
+			group.Tracks = append(group.Tracks, URIName{Name: track.Name, URI: track.URI})
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+func (home *Home) show(groups []homeSuggested) {
+	home.suggested = groups
+	for i, results := range home.groups {
+		results.resetSearchResults()
+		if i >= len(groups) {
+			results.getBox().SetTitle("Suggested")
+			continue
+		}
+		results.getBox().SetTitle("Because you listened to " + groups[i].Seed)
+		for _, track := range groups[i].Tracks {
+			results.appendSearchResult(track)
+		}
+		results.markSavedTracks()
+	}
+}
@@ -0,0 +1,70 @@
+package player
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jedruniu/spotify-cli/pkg/cache"
+
+	"github.com/zmb3/spotify"
+)
+
+type countingRecommender struct {
+	Recommender
+	seeds []spotify.ID
+}
+
+func (r *countingRecommender) Recommend(seed spotify.FullTrack) ([]spotify.SimpleTrack, error) {
+	r.seeds = append(r.seeds, seed.ID)
+	return r.Recommender.Recommend(seed)
+}
+
+func TestHomeRefreshesSuggestionsDaily(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spotify-cli-home")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	client := NewDebugClient()
+	spotifyRecommender, _ := NewRecommender(context.Background(), "spotify", client)
+	recommender := &countingRecommender{Recommender: spotifyRecommender}
+	now := time.Date(2020, 6, 1, 23, 0, 0, 0, time.UTC)
+	home := NewHome(context.Background(), client, recommender, cache.NewStore(dir), time.UTC)
+	home.now = func() time.Time { return now }
+
+	if err := home.Refresh(); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	// DebugClient recently played 5 distinct tracks, only 3 of them are seeds
+	if len(recommender.seeds) != homeSeedsCount || recommender.seeds[0] != "played1" {
+		t.Fatalf("Expected suggestions for %d most recently played tracks, got %v", homeSeedsCount, recommender.seeds)
+	}
+	if seed := home.suggested[0].Seed; seed != "Played Track 1 by Artist Name 1" {
+		t.Fatalf("Unexpected seed of suggestions: %q", seed)
+	}
+	if len(home.groups[0].getData()) != 10 {
+		t.Fatalf("Expected 10 suggested tracks, got %d", len(home.groups[0].getData()))
+	}
+
+	// Another view created the same day uses cached suggestions
+	another := NewHome(context.Background(), client, recommender, cache.NewStore(dir), time.UTC)
+	another.now = func() time.Time { return now.Add(30 * time.Minute) }
+	if err := another.Refresh(); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if len(recommender.seeds) != homeSeedsCount || len(another.groups[2].getData()) != 10 {
+		t.Fatalf("Expected cached suggestions to be shown, recommended for %v", recommender.seeds)
+	}
+
+	another.now = func() time.Time { return now.Add(2 * time.Hour) }
+	if err := another.Refresh(); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if len(recommender.seeds) != 2*homeSeedsCount {
+		t.Fatalf("Expected suggestions to be fetched again the next day, recommended for %v", recommender.seeds)
+	}
+}
@@ -0,0 +1,121 @@
+package player
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/marcusolsson/tui-go"
+	"github.com/zmb3/spotify"
+)
+
+// Inbox represents view with log of song requests. Requests are tracks
+// which others add to a collaborative playlist, each of them is queued on
+// the active device and removed from the playlist.
+type Inbox struct {
+	Focusables []tui.Widget
+	Box        *tui.Box
+	ctx        context.Context
+	client     SpotifyClient
+	session    *PlaylistSession
+	userID     string
+	requests   *countedTable
+	status     *tui.Label
+	now        func() time.Time
+}
+
+// NewInbox creates song request inbox for the collaborative playlist, it is empty until polled.
+func NewInbox(ctx context.Context, client SpotifyClient, playlistID spotify.ID) (*Inbox, error) {
+	user, err := client.CurrentUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch current user: %v", err)
+	}
+	session, err := NewPlaylistSession(ctx, client, playlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	requests := newCountedTable()
+	requests.SetColumnStretch(2, 2)
+	requests.AppendRow(
+		tui.NewLabel("Time"),
+		tui.NewLabel("Requested by"),
+		tui.NewLabel("Track"),
+	)
+	status := tui.NewLabel("Waiting for requests")
+
+	box := tui.NewVBox(requests, tui.NewSpacer(), status)
+	box.SetTitle("Song requests")
+	box.SetBorder(true)
+	box.SetSizePolicy(tui.Expanding, tui.Expanding)
+
+	return &Inbox{
+		Focusables: []tui.Widget{requests},
+		Box:        box,
+		ctx:        ctx,
+		client:     client,
+		session:    session,
+		userID:     user.ID,
+		requests:   requests,
+		status:     status,
+		now:        time.Now,
+	}, nil
+}
+
+// Poll queues tracks added to the playlist by others and removes them from the playlist.
+func (inbox *Inbox) Poll() error {
+	err := inbox.session.Reload()
+	if err != nil {
+		return err
+	}
+	queued := []int{}
+	for position, track := range inbox.session.Tracks {
+		if track.AddedBy.ID == inbox.userID {
+			continue // Tracks added by the user are not requests
+		}
+		err := inbox.client.QueueSong(inbox.ctx, track.Track.ID)
+		if err != nil {
+			log.Printf("Could not queue requested track %s with %s", track.Track.Name, err)
+			continue
+		}
+		requester := requesterName(track.AddedBy)
+		log.Printf("Queued %s requested by %s", track.Track.Name, requester)
+		inbox.requests.AppendRow(
+			tui.NewLabel(inbox.now().Format("15:04")),
+			tui.NewLabel(trimWithCommasIfTooLong(requester, uiColumnWidth)),
+			tui.NewLabel(trimWithCommasIfTooLong(track.Track.Name+" - "+artistsNames(track.Track.Artists), 2*uiColumnWidth)),
+		)
+		queued = append(queued, position)
+	}
+	if len(queued) == 0 {
+		return nil
+	}
+	inbox.status.SetText(fmt.Sprintf("Queued %d requested tracks at %s", len(queued), inbox.now().Format("15:04")))
+	return inbox.session.Remove(queued...)
+}
+
+// Watch polls the playlist at the given interval.
+func (inbox *Inbox) Watch(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := inbox.Poll(); err != nil {
+			log.Printf("Could not check song requests with %s", err)
+		}
+		<-ticker.C
+	}
+}
+
+// requesterName returns name of the user who added track, Spotify
+// does not always give display names of playlist collaborators.
+func requesterName(user spotify.User) string {
+	switch {
+	case user.DisplayName != "":
+		return user.DisplayName
+	case user.ID != "":
+		return user.ID
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,68 @@
+package player
+
+import (
+	"context"
+	"testing"
+
+	"github.com/zmb3/spotify"
+)
+
+type fakeQueue struct {
+	DebugClient
+	queued []spotify.ID
+}
+
+func (fake *fakeQueue) QueueSong(ctx context.Context, trackID spotify.ID) error {
+	fake.queued = append(fake.queued, trackID)
+	return nil
+}
+
+func TestInboxQueuesRequestsOfOthers(t *testing.T) {
+	editor := NewDebugPlaylistEditor()
+	client := NewDebugClient().(DebugClient)
+	client.PlaylistEditor = editor
+	queue := &fakeQueue{DebugClient: client}
+	// Track added by the user is not a request
+	editor.playlist("inbox").tracks[0].AddedBy.ID = debugUserID
+	editor.playlist("inbox").tracks[1].AddedBy.DisplayName = "Friend"
+
+	inbox, err := NewInbox(context.Background(), queue, "inbox")
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if err := inbox.Poll(); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+
+	if len(queue.queued) != 9 || queue.queued[0] != "inboxtrack2" {
+		t.Fatalf("Expected 9 requested tracks to be queued starting with inboxtrack2, got %v", queue.queued)
+	}
+	left := editor.playlist("inbox").tracks
+	if len(left) != 1 || left[0].Track.ID != "inboxtrack1" {
+		t.Fatalf("Expected only track added by the user to be left, got %d tracks", len(left))
+	}
+
+	// Requests are gone, nothing is queued again
+	if err := inbox.Poll(); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if len(queue.queued) != 9 {
+		t.Fatalf("Expected requests to be queued once, got %v", queue.queued)
+	}
+}
+
+func TestRequesterName(t *testing.T) {
+	cases := []struct {
+		user     spotify.User
+		expected string
+	}{
+		{spotify.User{DisplayName: "Friend", ID: "friend"}, "Friend"},
+		{spotify.User{ID: "friend"}, "friend"},
+		{spotify.User{}, "unknown"},
+	}
+	for _, c := range cases {
+		if name := requesterName(c.user); name != c.expected {
+			t.Errorf("Expected requester name %q, got %q", c.expected, name)
+		}
+	}
+}
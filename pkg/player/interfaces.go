@@ -1,30 +1,130 @@
 package player
 
-import "github.com/zmb3/spotify"
+import (
+	"context"
+
+	"github.com/zmb3/spotify"
+)
 
 // SpotifyClient is a wrapper interface around spotify.client
-// used in order to improve testability of the code.
+// used in order to improve testability of the code. Requests
+// are cancelled once the context given to its methods is done.
 type SpotifyClient interface {
 	UserAlbumFetcher
 	Player
 	Searcher
-	Pause() error
-	Previous() error
-	Next() error
-	PlayerCurrentlyPlaying() (*spotify.CurrentlyPlaying, error)
-	PlayerDevices() ([]spotify.PlayerDevice, error)
-	TransferPlayback(spotify.ID, bool) error
+	ArtistBrowser
+	PlaylistFetcher
+	ShowBrowser
+	AudiobookBrowser
+	AlbumBrowser
+	TopFetcher
+	RecommendationFetcher
+	PlaylistEditor
+	LibraryEditor
+	AudioFeatureFetcher
+	Pause(ctx context.Context) error
+	Previous(ctx context.Context) error
+	Next(ctx context.Context) error
+	Volume(ctx context.Context, percent int) error
+	Seek(ctx context.Context, position int) error
+	Shuffle(ctx context.Context, shuffle bool) error
+	Repeat(ctx context.Context, state string) error
+	PlayerState(ctx context.Context) (*spotify.PlayerState, error)
+	QueueSong(ctx context.Context, trackID spotify.ID) error
+	PlayerQueue(ctx context.Context) (*Queue, error)
+	PlayerCurrentlyPlaying(ctx context.Context) (*PlaybackItem, error)
+	PlayerDevices(ctx context.Context) ([]spotify.PlayerDevice, error)
+	TransferPlayback(ctx context.Context, deviceID spotify.ID, play bool) error
+	CurrentUser(ctx context.Context) (*spotify.PrivateUser, error)
+}
+
+// PlaybackItem describes what is currently played, which is either
+// a track (Item) or a podcast episode (Episode).
+type PlaybackItem struct {
+	spotify.CurrentlyPlaying
+	Episode *spotify.EpisodePage
+}
+
+// Queue is what is currently played and the tracks which are played next.
+type Queue struct {
+	CurrentlyPlaying *spotify.FullTrack  `json:"currently_playing"`
+	Queue            []spotify.FullTrack `json:"queue"`
 }
 
 type Player interface {
-	Play() error
-	PlayOpt(opt *spotify.PlayOptions) error
+	Play(ctx context.Context) error
+	PlayOpt(ctx context.Context, opt *spotify.PlayOptions) error
 }
 
 type Searcher interface {
-	Search(string, spotify.SearchType) (*spotify.SearchResult, error)
+	Search(ctx context.Context, query string, t spotify.SearchType) (*spotify.SearchResult, error)
 }
 
 type UserAlbumFetcher interface {
-	CurrentUsersAlbumsOpt(opt *spotify.Options) (*spotify.SavedAlbumPage, error)
+	CurrentUsersAlbumsOpt(ctx context.Context, opt *spotify.Options) (*spotify.SavedAlbumPage, error)
+}
+
+type ArtistBrowser interface {
+	CurrentUsersFollowedArtistsOpt(ctx context.Context, limit int, after string) (*spotify.FullArtistCursorPage, error)
+	GetArtistAlbums(ctx context.Context, artistID spotify.ID) (*spotify.SimpleAlbumPage, error)
+	GetArtistsTopTracks(ctx context.Context, artistID spotify.ID, country string) ([]spotify.FullTrack, error)
+	GetRelatedArtists(ctx context.Context, artistID spotify.ID) ([]spotify.FullArtist, error)
+	FollowArtist(ctx context.Context, artistIDs ...spotify.ID) error
+	UnfollowArtist(ctx context.Context, artistIDs ...spotify.ID) error
+	CurrentUserFollows(ctx context.Context, t string, ids ...spotify.ID) ([]bool, error)
+}
+
+type PlaylistFetcher interface {
+	CurrentUsersPlaylistsOpt(ctx context.Context, opt *spotify.Options) (*spotify.SimplePlaylistPage, error)
+	GetCategoryPlaylistsOpt(ctx context.Context, catID string, opt *spotify.Options) (*spotify.SimplePlaylistPage, error)
+	GetPlaylistTracks(ctx context.Context, playlistID spotify.ID) (*spotify.PlaylistTrackPage, error)
+}
+
+type ShowBrowser interface {
+	CurrentUsersShowsOpt(ctx context.Context, opt *spotify.Options) (*spotify.SavedShowPage, error)
+	GetShowEpisodesOpt(ctx context.Context, opt *spotify.Options, id string) (*spotify.SimpleEpisodePage, error)
+}
+
+type AudiobookBrowser interface {
+	CurrentUsersAudiobooksOpt(ctx context.Context, opt *spotify.Options) (*AudiobookPage, error)
+	GetAudiobookChaptersOpt(ctx context.Context, opt *spotify.Options, id spotify.ID) (*ChapterPage, error)
+}
+
+type AlbumBrowser interface {
+	GetAlbumCredits(ctx context.Context, albumID spotify.ID) (*AlbumCredits, error)
+}
+
+type TopFetcher interface {
+	CurrentUsersTopTracksOpt(ctx context.Context, opt *spotify.Options) (*spotify.FullTrackPage, error)
+	CurrentUsersTopArtistsOpt(ctx context.Context, opt *spotify.Options) (*spotify.FullArtistPage, error)
+}
+
+type RecommendationFetcher interface {
+	GetRecommendations(ctx context.Context, seeds spotify.Seeds, trackAttributes *spotify.TrackAttributes, opt *spotify.Options) (*spotify.Recommendations, error)
+	PlayerRecentlyPlayedOpt(ctx context.Context, opt *spotify.RecentlyPlayedOptions) ([]spotify.RecentlyPlayedItem, error)
+}
+
+type PlaylistEditor interface {
+	GetPlaylistOpt(ctx context.Context, playlistID spotify.ID, fields string) (*spotify.FullPlaylist, error)
+	GetPlaylistTracksOpt(ctx context.Context, playlistID spotify.ID, opt *spotify.Options, fields string) (*spotify.PlaylistTrackPage, error)
+	AddTracksToPlaylist(ctx context.Context, playlistID spotify.ID, trackIDs ...spotify.ID) (string, error)
+	RemoveTracksFromPlaylistOpt(ctx context.Context, playlistID spotify.ID, tracks []spotify.TrackToRemove, snapshotID string) (string, error)
+	ReorderPlaylistTracks(ctx context.Context, playlistID spotify.ID, opt spotify.PlaylistReorderOptions) (string, error)
+	CreatePlaylistForUser(ctx context.Context, userID, playlistName, description string, public bool) (*spotify.FullPlaylist, error)
+	ChangePlaylistNameAccessAndDescription(ctx context.Context, playlistID spotify.ID, newName, newDescription string, public bool) error
+	ChangePlaylistCollaborative(ctx context.Context, playlistID spotify.ID, collaborative bool) error
+}
+
+type LibraryEditor interface {
+	AddAlbumsToLibrary(ctx context.Context, albumIDs ...spotify.ID) error
+	RemoveAlbumsFromLibrary(ctx context.Context, albumIDs ...spotify.ID) error
+	AddTracksToLibrary(ctx context.Context, trackIDs ...spotify.ID) error
+	RemoveTracksFromLibrary(ctx context.Context, trackIDs ...spotify.ID) error
+	UserHasTracks(ctx context.Context, trackIDs ...spotify.ID) ([]bool, error)
+	CurrentUsersTracksOpt(ctx context.Context, opt *spotify.Options) (*spotify.SavedTrackPage, error)
+}
+
+type AudioFeatureFetcher interface {
+	GetAudioFeatures(ctx context.Context, ids ...spotify.ID) ([]*spotify.AudioFeatures, error)
 }
@@ -0,0 +1,161 @@
+package player
+
+import (
+	"context"
+	"fmt"
+	"image"
+
+	"github.com/jedruniu/spotify-cli/pkg/config"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+// KeyHelp represents view listing keys bound to actions, it is generated from
+// the keymap, so that it lists keys configured by the user.
+type KeyHelp struct {
+	Focusables []tui.Widget
+	Box        *tui.Box
+	table      *countedTable
+	bindings   []config.Binding
+}
+
+// NewKeyHelp creates view listing the given bindings.
+func NewKeyHelp(bindings []config.Binding) *KeyHelp {
+	table := newCountedTable()
+	table.SetColumnStretch(1, 1)
+	for _, binding := range bindings {
+		table.AppendRow(tui.NewLabel(binding.Key), tui.NewLabel(binding.Description))
+	}
+	table.SetSelected(0)
+
+	box := tui.NewVBox(table, tui.NewSpacer(), tui.NewLabel("Keys are bound to other actions in [keys] section of the config file"))
+	box.SetTitle("Keys")
+	box.SetBorder(true)
+	box.SetSizePolicy(tui.Expanding, tui.Expanding)
+
+	return &KeyHelp{
+		Focusables: []tui.Widget{table},
+		Box:        box,
+		table:      table,
+		bindings:   bindings,
+	}
+}
+
+// helpKey shows the help overlay, it is not bound in the keymap as keys without modifiers
+// are typed into entries.
+var helpKey = '?'
+
+// HelpOverlay shows keys bound to actions over the whole window, once helpKey is pressed
+// anywhere but in an entry. While it is shown keys are not passed to the wrapped widget,
+// Esc or helpKey close it.
+type HelpOverlay struct {
+	Focusables []tui.Widget
+	help       *KeyHelp
+	shown      bool
+	typing     func() bool
+	onShow     func()
+	onHide     func()
+}
+
+// helpOverlayWidget wraps a widget, so that the overlay is drawn instead of it while shown.
+type helpOverlayWidget struct {
+	tui.Widget
+	overlay *HelpOverlay
+}
+
+// Draw draws the overlay while it is shown, the wrapped widget otherwise.
+func (w *helpOverlayWidget) Draw(p *tui.Painter) {
+	if w.overlay.shown {
+		w.overlay.help.Box.Draw(p)
+		return
+	}
+	w.Widget.Draw(p)
+}
+
+// Resize resizes both the wrapped widget and the overlay, so that it can be shown right away.
+func (w *helpOverlayWidget) Resize(size image.Point) {
+	w.Widget.Resize(size)
+	w.overlay.help.Box.Resize(size)
+}
+
+// OnKeyEvent shows or closes the overlay, other keys are handled by the wrapped widget
+// while it is not shown.
+func (w *helpOverlayWidget) OnKeyEvent(ev tui.KeyEvent) {
+	help := ev.Key == tui.KeyRune && ev.Rune == helpKey
+	switch {
+	case w.overlay.shown && (help || ev.Key == tui.KeyEsc):
+		w.overlay.Hide()
+	case w.overlay.shown:
+	case help && !w.overlay.typing():
+		w.overlay.Show()
+	default:
+		w.Widget.OnKeyEvent(ev)
+	}
+}
+
+// NewHelpOverlay creates hidden overlay listing the given bindings, typing tells whether
+// an entry is focused, so that helpKey is typed into it instead.
+func NewHelpOverlay(bindings []config.Binding, typing func() bool) *HelpOverlay {
+	help := NewKeyHelp(bindings)
+	help.Box.SetTitle(fmt.Sprintf("Keys - press Esc or %c to close", helpKey))
+	return &HelpOverlay{
+		Focusables: help.Focusables,
+		help:       help,
+		typing:     typing,
+	}
+}
+
+// Wrap wraps the root widget of the ui, so that the overlay is shown over it.
+func (o *HelpOverlay) Wrap(root tui.Widget) tui.Widget {
+	return &helpOverlayWidget{Widget: root, overlay: o}
+}
+
+// OnShow sets function called when the overlay is shown, it should move the focus to Focusables.
+func (o *HelpOverlay) OnShow(fn func()) {
+	o.onShow = fn
+}
+
+// OnHide sets function called once the overlay is closed, it should restore the focus.
+func (o *HelpOverlay) OnHide(fn func()) {
+	o.onHide = fn
+}
+
+// Shown tells whether the overlay is shown.
+func (o *HelpOverlay) Shown() bool {
+	return o.shown
+}
+
+// Show shows the overlay, unless it is shown already.
+func (o *HelpOverlay) Show() {
+	if o.shown {
+		return
+	}
+	o.shown = true
+	o.help.table.SetSelected(0)
+	if o.onShow != nil {
+		o.onShow()
+	}
+}
+
+// Hide closes the overlay, unless it is closed already.
+func (o *HelpOverlay) Hide() {
+	if !o.shown {
+		return
+	}
+	o.shown = false
+	if o.onHide != nil {
+		o.onHide()
+	}
+}
+
+// TogglePlayback pauses playback when something is played, and resumes it otherwise.
+func TogglePlayback(ctx context.Context, client SpotifyClient) error {
+	playing, err := client.PlayerCurrentlyPlaying(ctx)
+	if err != nil {
+		return err
+	}
+	if playing.Playing {
+		return client.Pause(ctx)
+	}
+	return client.Play(ctx)
+}
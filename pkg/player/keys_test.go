@@ -0,0 +1,99 @@
+package player
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jedruniu/spotify-cli/pkg/config"
+
+	"github.com/marcusolsson/tui-go"
+	"github.com/zmb3/spotify"
+)
+
+func TestKeyHelp(t *testing.T) {
+	keys := config.Keys{"quit": "Ctrl+Q"}
+	help := NewKeyHelp(keys.Bindings())
+	if len(help.bindings) != len(config.Actions) || help.table.Selected() != 0 {
+		t.Fatalf("Expected all actions to be listed, got %v", help.bindings)
+	}
+	quit := help.bindings[len(help.bindings)-1]
+	if quit.Name != "quit" || quit.Key != "Ctrl+Q" {
+		t.Fatalf("Expected configured key to be listed, got %v", quit)
+	}
+}
+
+func TestHelpOverlay(t *testing.T) {
+	typing := false
+	overlay := NewHelpOverlay(config.Keys{}.Bindings(), func() bool { return typing })
+	shown, hidden := 0, 0
+	overlay.OnShow(func() { shown++ })
+	overlay.OnHide(func() { hidden++ })
+	root := &keyCountingWidget{}
+	w := overlay.Wrap(root)
+
+	typing = true
+	w.OnKeyEvent(tui.KeyEvent{Key: tui.KeyRune, Rune: '?'})
+	if overlay.Shown() || root.keys != 1 {
+		t.Fatalf("Expected ? to be typed into the entry, got overlay shown %v", overlay.Shown())
+	}
+	typing = false
+	w.OnKeyEvent(tui.KeyEvent{Key: tui.KeyRune, Rune: '?'})
+	if !overlay.Shown() || shown != 1 || root.keys != 1 {
+		t.Fatalf("Expected overlay to be shown, got %v", overlay.Shown())
+	}
+	w.OnKeyEvent(tui.KeyEvent{Key: tui.KeyEnter})
+	if !overlay.Shown() || root.keys != 1 {
+		t.Fatalf("Expected keys not to be passed on while overlay is shown")
+	}
+	w.OnKeyEvent(tui.KeyEvent{Key: tui.KeyEsc})
+	if overlay.Shown() || hidden != 1 {
+		t.Fatalf("Expected overlay to be closed with Esc")
+	}
+	w.OnKeyEvent(tui.KeyEvent{Key: tui.KeyEsc})
+	if root.keys != 2 || hidden != 1 {
+		t.Fatalf("Expected Esc to be passed on once overlay is closed")
+	}
+}
+
+// keyCountingWidget counts keys it received.
+type keyCountingWidget struct {
+	tui.Label
+	keys int
+}
+
+func (w *keyCountingWidget) OnKeyEvent(ev tui.KeyEvent) {
+	w.keys++
+}
+
+// togglingClient tells whether something is played and counts pauses and plays.
+type togglingClient struct {
+	DebugClient
+	playing bool
+	paused  int
+	played  int
+}
+
+func (client *togglingClient) PlayerCurrentlyPlaying(ctx context.Context) (*PlaybackItem, error) {
+	return &PlaybackItem{CurrentlyPlaying: spotify.CurrentlyPlaying{Playing: client.playing}}, nil
+}
+
+func (client *togglingClient) Pause(ctx context.Context) error {
+	client.paused++
+	return nil
+}
+
+func (client *togglingClient) Play(ctx context.Context) error {
+	client.played++
+	return nil
+}
+
+func TestTogglePlayback(t *testing.T) {
+	client := &togglingClient{DebugClient: NewDebugClient().(DebugClient), playing: true}
+	if err := TogglePlayback(context.Background(), client); err != nil || client.paused != 1 {
+		t.Fatalf("Expected playback to be paused, got %v and %d pauses", err, client.paused)
+	}
+	client.playing = false
+	if err := TogglePlayback(context.Background(), client); err != nil || client.played != 1 {
+		t.Fatalf("Expected playback to be resumed, got %v and %d plays", err, client.played)
+	}
+}
@@ -0,0 +1,150 @@
+package player
+
+import (
+	"context"
+	"crypto/subtle"
+	"log"
+
+	"github.com/marcusolsson/tui-go"
+	"github.com/zmb3/spotify"
+)
+
+// Kiosk represents locked-down jukebox view, in which tracks can only
+// be searched and added to the queue. It is meant to be run on a shared machine.
+type Kiosk struct {
+	Focusables []tui.Widget
+	Box        *tui.Box
+	// Unlock is an entry for the PIN which has to be given to leave the kiosk,
+	// it is not focusable until unlocking is requested.
+	Unlock   *tui.Entry
+	pin      string
+	onUnlock func(bool)
+}
+
+type kioskQueue struct {
+	ctx    context.Context
+	client SpotifyClient
+	found  []spotify.FullTrack
+	queue  *countedTable
+	status *tui.Label
+}
+
+// NewKiosk creates kiosk view, pin is required to unlock it unless it is empty.
+func NewKiosk(ctx context.Context, client SpotifyClient, pin string, nowPlaying *NowPlaying) *Kiosk {
+	q := &kioskQueue{
+		ctx:    ctx,
+		client: client,
+		queue:  newCountedTable(),
+		status: tui.NewLabel("Search for a song and press Enter to add it to the queue"),
+	}
+
+	results := newCountedTable()
+	searchInput := tui.NewEntry()
+	searchInput.SetSizePolicy(tui.Expanding, tui.Minimum)
+	searchInput.OnSubmit(func(e *tui.Entry) {
+		err := q.search(e.Text(), results)
+		if err != nil {
+			log.Printf("Could not search for %s with %s", e.Text(), err)
+			q.status.SetText("Search failed, try again")
+		}
+	})
+	results.OnItemActivated(func(t *tui.Table) {
+		q.enqueue(t.Selected())
+	})
+
+	searchBox := tui.NewVBox(searchInput)
+	searchBox.SetTitle("Search")
+	searchBox.SetBorder(true)
+	resultsBox := tui.NewVBox(results, tui.NewSpacer())
+	resultsBox.SetTitle("Songs")
+	resultsBox.SetBorder(true)
+	queueBox := tui.NewVBox(q.queue, tui.NewSpacer())
+	queueBox.SetTitle("Queued")
+	queueBox.SetBorder(true)
+	nowPlayingBox := tui.NewVBox(nowPlaying.Label)
+	nowPlayingBox.SetTitle("Now playing")
+	nowPlayingBox.SetBorder(true)
+
+	unlock := tui.NewEntry()
+	unlock.SetEchoMode(tui.EchoModePassword)
+	unlockBox := tui.NewHBox(tui.NewLabel("PIN: "), unlock)
+
+	box := tui.NewVBox(
+		searchBox,
+		tui.NewHBox(resultsBox, tui.NewVBox(nowPlayingBox, queueBox)),
+		q.status,
+		unlockBox,
+	)
+	box.SetSizePolicy(tui.Expanding, tui.Expanding)
+
+	kiosk := &Kiosk{
+		Focusables: []tui.Widget{searchInput, results},
+		Box:        box,
+		Unlock:     unlock,
+		pin:        pin,
+	}
+	unlock.OnSubmit(func(e *tui.Entry) {
+		ok := kiosk.checkPIN(e.Text())
+		e.SetText("")
+		if !ok {
+			q.status.SetText("Wrong PIN")
+		}
+		if kiosk.onUnlock != nil {
+			kiosk.onUnlock(ok)
+		}
+	})
+	return kiosk
+}
+
+// RequiresPIN tells whether PIN has to be given to leave the kiosk.
+func (kiosk *Kiosk) RequiresPIN() bool {
+	return kiosk.pin != ""
+}
+
+// OnUnlock sets function called each time PIN is submitted,
+// with information whether it was correct.
+func (kiosk *Kiosk) OnUnlock(fn func(bool)) {
+	kiosk.onUnlock = fn
+}
+
+func (kiosk *Kiosk) checkPIN(pin string) bool {
+	return subtle.ConstantTimeCompare([]byte(pin), []byte(kiosk.pin)) == 1
+}
+
+func (q *kioskQueue) search(query string, results *countedTable) error {
+	result, err := q.client.Search(q.ctx, query, spotify.SearchTypeTrack)
+	if err != nil {
+		return err
+	}
+	q.found = q.found[:0]
+	results.RemoveRows()
+	if result == nil || result.Tracks == nil {
+		return nil
+	}
+	for _, track := range result.Tracks.Tracks {
+		results.AppendRow(
+			tui.NewLabel(trimWithCommasIfTooLong(track.Name, uiColumnWidth)),
+			tui.NewLabel(trimWithCommasIfTooLong(artistsNames(track.Artists), uiColumnWidth)),
+		)
+		q.found = append(q.found, track)
+	}
+	return nil
+}
+
+func (q *kioskQueue) enqueue(selectedRow int) {
+	if selectedRow < 0 || selectedRow >= len(q.found) {
+		return
+	}
+	track := q.found[selectedRow]
+	err := q.client.QueueSong(q.ctx, track.ID)
+	if err != nil {
+		log.Printf("Could not queue track with uri: %s, %s", track.URI, err)
+		q.status.SetText("Could not add " + track.Name + " to the queue")
+		return
+	}
+	q.queue.AppendRow(
+		tui.NewLabel(trimWithCommasIfTooLong(track.Name, uiColumnWidth)),
+		tui.NewLabel(trimWithCommasIfTooLong(artistsNames(track.Artists), uiColumnWidth)),
+	)
+	q.status.SetText("Added " + track.Name + " to the queue")
+}
@@ -0,0 +1,44 @@
+package player
+
+import (
+	"context"
+	"testing"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+func TestKioskChecksPIN(t *testing.T) {
+	kiosk := NewKiosk(context.Background(), NewDebugClient(), "1234", NewNowPlaying())
+	if !kiosk.RequiresPIN() {
+		t.Fatalf("Expected kiosk to require PIN")
+	}
+	if kiosk.checkPIN("4321") || kiosk.checkPIN("") {
+		t.Fatalf("Expected wrong PIN not to unlock the kiosk")
+	}
+	if !kiosk.checkPIN("1234") {
+		t.Fatalf("Expected correct PIN to unlock the kiosk")
+	}
+	if NewKiosk(context.Background(), NewDebugClient(), "", NewNowPlaying()).RequiresPIN() {
+		t.Fatalf("Expected kiosk without PIN not to require it")
+	}
+}
+
+func TestKioskQueuesFoundTracks(t *testing.T) {
+	client := DebugClient{Searcher: &FakeSearcher{}}
+	q := &kioskQueue{client: client, queue: newCountedTable(), status: tui.NewLabel("")}
+	results := newCountedTable()
+	err := q.search("track", results)
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	// FakeSearcher finds single track
+	if len(q.found) != 1 {
+		t.Fatalf("Expected to find 1 track, found %d", len(q.found))
+	}
+
+	q.enqueue(0)
+	q.enqueue(1)
+	if q.status.Text() != "Added Track to the queue" {
+		t.Fatalf("Expected to add found track to the queue, got status %q", q.status.Text())
+	}
+}
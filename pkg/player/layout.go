@@ -0,0 +1,195 @@
+package player
+
+import (
+	"image"
+
+	"github.com/jedruniu/spotify-cli/pkg/config"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+const (
+	// sidebarWidthStep is the percent of the window width the sidebar is widened or narrowed by.
+	sidebarWidthStep = 5
+	// minSidebarWidth and maxSidebarWidth limit the percent of the window width the sidebar takes.
+	minSidebarWidth = 10
+	maxSidebarWidth = 80
+)
+
+// Pane is a part of the window which can be hidden, it takes no space then.
+// Its width can be fixed as well.
+type Pane struct {
+	tui.Widget
+	hidden bool
+	width  int
+}
+
+// NewPane creates shown pane with the widget, taking the width the widget needs.
+func NewPane(w tui.Widget) *Pane {
+	return &Pane{Widget: w}
+}
+
+// Hidden tells whether the pane is hidden.
+func (pane *Pane) Hidden() bool {
+	return pane.hidden
+}
+
+// Draw draws the widget unless the pane is hidden.
+func (pane *Pane) Draw(p *tui.Painter) {
+	if !pane.hidden {
+		pane.Widget.Draw(p)
+	}
+}
+
+// MinSizeHint is nothing while the pane is hidden.
+func (pane *Pane) MinSizeHint() image.Point {
+	if pane.hidden {
+		return image.Point{}
+	}
+	hint := pane.Widget.MinSizeHint()
+	if pane.width > 0 {
+		hint.X = pane.width
+	}
+	return hint
+}
+
+// SizeHint is nothing while the pane is hidden.
+func (pane *Pane) SizeHint() image.Point {
+	if pane.hidden {
+		return image.Point{}
+	}
+	hint := pane.Widget.SizeHint()
+	if pane.width > 0 {
+		hint.X = pane.width
+	}
+	return hint
+}
+
+// SizePolicy keeps the pane from growing while it is hidden, or its width is fixed.
+func (pane *Pane) SizePolicy() (tui.SizePolicy, tui.SizePolicy) {
+	if pane.hidden {
+		return tui.Maximum, tui.Maximum
+	}
+	horizontal, vertical := pane.Widget.SizePolicy()
+	if pane.width > 0 {
+		horizontal = tui.Maximum
+	}
+	return horizontal, vertical
+}
+
+// Panes shows and hides panes of the window, and changes width of the sidebar, as told
+// by the layout.
+type Panes struct {
+	Sidebar  *Pane
+	Devices  *Pane
+	Related  *Pane
+	Queue    *Pane
+	layout   config.Layout
+	width    int
+	onChange func(config.Layout)
+}
+
+// NewPanes arranges panes as told by the layout, i.e. the one saved before.
+func NewPanes(layout config.Layout, sidebar, devices, related, queue *Pane) *Panes {
+	panes := &Panes{Sidebar: sidebar, Devices: devices, Related: related, Queue: queue, layout: layout}
+	panes.apply()
+	return panes
+}
+
+// Wrap wraps the root widget of the ui, so that width of the sidebar follows width of the window.
+func (panes *Panes) Wrap(root tui.Widget) tui.Widget {
+	return &panesWidget{Widget: root, panes: panes}
+}
+
+// panesWidget changes width of the sidebar before the window is laid out.
+type panesWidget struct {
+	tui.Widget
+	panes *Panes
+}
+
+// Resize lays out the window with the sidebar taking its part of the width.
+func (w *panesWidget) Resize(size image.Point) {
+	w.panes.width = size.X
+	w.panes.apply()
+	w.Widget.Resize(size)
+}
+
+// OnChange sets function called with the layout each time it changes, i.e. to save it and
+// to focus only widgets of shown panes.
+func (panes *Panes) OnChange(fn func(config.Layout)) {
+	panes.onChange = fn
+}
+
+// Layout returns the current layout.
+func (panes *Panes) Layout() config.Layout {
+	return panes.layout
+}
+
+// ToggleSidebar shows or hides albums in the sidebar.
+func (panes *Panes) ToggleSidebar() {
+	panes.change(func(layout *config.Layout) { layout.HideSidebar = !layout.HideSidebar })
+}
+
+// ToggleDevices shows or hides devices.
+func (panes *Panes) ToggleDevices() {
+	panes.change(func(layout *config.Layout) { layout.HideDevices = !layout.HideDevices })
+}
+
+// ToggleRelated shows or hides related artists.
+func (panes *Panes) ToggleRelated() {
+	panes.change(func(layout *config.Layout) { layout.HideRelated = !layout.HideRelated })
+}
+
+// ToggleQueue shows or hides the queue.
+func (panes *Panes) ToggleQueue() {
+	panes.change(func(layout *config.Layout) { layout.ShowQueue = !layout.ShowQueue })
+}
+
+// WidenSidebar makes the sidebar take more of the window width, it is shown when it is hidden.
+func (panes *Panes) WidenSidebar() {
+	panes.resizeSidebar(sidebarWidthStep)
+}
+
+// NarrowSidebar makes the sidebar take less of the window width, it is shown when it is hidden.
+func (panes *Panes) NarrowSidebar() {
+	panes.resizeSidebar(-sidebarWidthStep)
+}
+
+func (panes *Panes) resizeSidebar(percent int) {
+	panes.change(func(layout *config.Layout) {
+		if layout.HideSidebar {
+			layout.HideSidebar = false
+			return
+		}
+		width := layout.SidebarWidth
+		// the sidebar takes what albums need until its width is changed for the first time
+		if width == 0 && panes.width > 0 {
+			width = panes.Sidebar.Size().X * 100 / panes.width
+		}
+		width += percent
+		switch {
+		case width < minSidebarWidth:
+			width = minSidebarWidth
+		case width > maxSidebarWidth:
+			width = maxSidebarWidth
+		}
+		layout.SidebarWidth = width
+	})
+}
+
+func (panes *Panes) change(fn func(layout *config.Layout)) {
+	fn(&panes.layout)
+	panes.apply()
+	if panes.onChange != nil {
+		panes.onChange(panes.layout)
+	}
+}
+
+// apply shows and hides the panes as told by the layout.
+func (panes *Panes) apply() {
+	panes.Sidebar.hidden = panes.layout.HideSidebar
+	panes.Devices.hidden = panes.layout.HideDevices
+	panes.Related.hidden = panes.layout.HideRelated
+	panes.Queue.hidden = !panes.layout.ShowQueue
+	panes.Sidebar.width = panes.width * panes.layout.SidebarWidth / 100
+}
@@ -0,0 +1,76 @@
+package player
+
+import (
+	"image"
+	"testing"
+
+	"github.com/jedruniu/spotify-cli/pkg/config"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+func newTestPanes(layout config.Layout) *Panes {
+	return NewPanes(layout, NewPane(tui.NewLabel("albums")), NewPane(tui.NewLabel("devices")), NewPane(tui.NewLabel("related")), NewPane(tui.NewLabel("queue")))
+}
+
+func TestPanesShowAndHide(t *testing.T) {
+	panes := newTestPanes(config.Layout{})
+	if panes.Sidebar.Hidden() || panes.Devices.Hidden() || panes.Related.Hidden() || !panes.Queue.Hidden() {
+		t.Fatalf("Expected all panes but the queue to be shown at first, got %+v", panes.Layout())
+	}
+	changes := 0
+	var saved config.Layout
+	panes.OnChange(func(layout config.Layout) {
+		changes++
+		saved = layout
+	})
+	panes.ToggleDevices()
+	panes.ToggleQueue()
+	if !panes.Devices.Hidden() || panes.Queue.Hidden() || changes != 2 {
+		t.Fatalf("Expected devices to be hidden and queue shown, got %+v after %d changes", panes.Layout(), changes)
+	}
+	if hint := panes.Devices.SizeHint(); hint != (image.Point{}) {
+		t.Fatalf("Expected hidden pane to take no space, got %v", hint)
+	}
+
+	reloaded := newTestPanes(saved)
+	if reloaded.Layout() != panes.Layout() || !reloaded.Devices.Hidden() || reloaded.Queue.Hidden() {
+		t.Fatalf("Expected layout %+v to be restored, got %+v", panes.Layout(), reloaded.Layout())
+	}
+}
+
+func TestPanesResizeSidebar(t *testing.T) {
+	panes := newTestPanes(config.Layout{})
+	root := panes.Wrap(tui.NewHBox(panes.Sidebar, tui.NewSpacer()))
+	root.Resize(image.Point{100, 10})
+
+	// the window is 100 columns wide, so columns are percents
+	before := panes.Sidebar.Size().X
+	panes.WidenSidebar()
+	if width := panes.Layout().SidebarWidth; width != before+sidebarWidthStep && width != minSidebarWidth {
+		t.Fatalf("Expected sidebar of %d%% to be widened, got %d%%", before, width)
+	}
+	root.Resize(image.Point{100, 10})
+	if panes.Sidebar.Size().X != panes.Layout().SidebarWidth {
+		t.Fatalf("Expected sidebar to take %d columns, got %d", panes.Layout().SidebarWidth, panes.Sidebar.Size().X)
+	}
+
+	for i := 0; i < 100/sidebarWidthStep; i++ {
+		panes.NarrowSidebar()
+	}
+	if width := panes.Layout().SidebarWidth; width != minSidebarWidth {
+		t.Fatalf("Expected sidebar to be %d%% wide at least, got %d%%", minSidebarWidth, width)
+	}
+	for i := 0; i < 100/sidebarWidthStep; i++ {
+		panes.WidenSidebar()
+	}
+	if width := panes.Layout().SidebarWidth; width != maxSidebarWidth {
+		t.Fatalf("Expected sidebar to be %d%% wide at most, got %d%%", maxSidebarWidth, width)
+	}
+
+	panes.ToggleSidebar()
+	panes.NarrowSidebar()
+	if panes.Sidebar.Hidden() || panes.Layout().SidebarWidth != maxSidebarWidth {
+		t.Fatalf("Expected hidden sidebar to be shown without resizing, got %+v", panes.Layout())
+	}
+}
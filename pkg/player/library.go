@@ -0,0 +1,307 @@
+package player
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/marcusolsson/tui-go"
+	"github.com/zmb3/spotify"
+)
+
+var (
+	librarySaveKey   = '+'
+	libraryRemoveKey = '-'
+	// playlistAddKey adds the selected track to a playlist, playlistAddAllKey adds all listed tracks.
+	playlistAddKey    = 'a'
+	playlistAddAllKey = 'A'
+	// librarySelectKey marks the selected item, librarySaveSelectedKey saves all the marked ones.
+	librarySelectKey       = 'x'
+	librarySaveSelectedKey = 'S'
+)
+
+// AlbumLibrary keeps albums saved in the user's library, i.e. AlbumList.
+type AlbumLibrary interface {
+	SaveAlbum(spotify.SimpleAlbum) error
+	SaveAlbums(...spotify.SimpleAlbum) error
+	RemoveAlbum(spotify.ID) error
+}
+
+// libraryKeys wraps a table, so that selected item is saved to or
+// removed from the user's library, or added to a playlist, on key press.
+// Where supported, several items are marked and saved at once.
+type libraryKeys struct {
+	tui.Widget
+	onSave         func()
+	onRemove       func()
+	onAdd          func()
+	onAddAll       func()
+	onSelect       func()
+	onSaveSelected func()
+}
+
+// OnKeyEvent saves, removes or adds to a playlist selected item when table
+// is focused, other keys are handled by the wrapped table.
+func (t *libraryKeys) OnKeyEvent(ev tui.KeyEvent) {
+	if t.IsFocused() && ev.Key == tui.KeyRune {
+		switch {
+		case ev.Rune == librarySaveKey && t.onSave != nil:
+			t.onSave()
+			return
+		case ev.Rune == libraryRemoveKey && t.onRemove != nil:
+			t.onRemove()
+			return
+		case ev.Rune == playlistAddKey && t.onAdd != nil:
+			t.onAdd()
+			return
+		case ev.Rune == playlistAddAllKey && t.onAddAll != nil:
+			t.onAddAll()
+			return
+		case ev.Rune == librarySelectKey && t.onSelect != nil:
+			t.onSelect()
+			return
+		case ev.Rune == librarySaveSelectedKey && t.onSaveSelected != nil:
+			t.onSaveSelected()
+			return
+		}
+	}
+	t.Widget.OnKeyEvent(ev)
+}
+
+var (
+	savedTrackMark     = "♥"
+	followedArtistMark = "✓"
+	// userHasTracksBatchSize is the limit of tracks checked at once by Spotify.
+	userHasTracksBatchSize = 50
+	// userFollowsBatchSize is the limit of artists checked at once by Spotify.
+	userFollowsBatchSize = 50
+	// librarySaveTracksBatchSize and librarySaveAlbumsBatchSize are the limits of
+	// tracks and albums saved to the library with one request.
+	librarySaveTracksBatchSize = 50
+	librarySaveAlbumsBatchSize = 20
+)
+
+// savedTracks shows, in a column of track listing, which tracks are saved
+// in the user's Liked Songs, and saves or removes them. Artists listed
+// along with tracks are marked when followed, and followed or unfollowed
+// the same way. Listed tracks are also passed to addToPlaylist, when it is set.
+type savedTracks struct {
+	ctx    context.Context
+	client SpotifyClient
+	ids    []spotify.ID
+	// artists holds IDs of listed artists, it is empty for other items.
+	artists       []spotify.ID
+	marks         []*tui.Label
+	addToPlaylist func([]spotify.ID)
+}
+
+func (saved *savedTracks) reset() {
+	saved.ids = saved.ids[:0]
+	saved.artists = saved.artists[:0]
+	saved.marks = saved.marks[:0]
+}
+
+// add returns label marking whether track is saved, it is empty until fetched.
+// Items which are not tracks are given empty ID.
+func (saved *savedTracks) add(trackID spotify.ID) *tui.Label {
+	return saved.addItem(trackID, "")
+}
+
+// addArtist returns label marking whether artist is followed, it is empty until fetched.
+func (saved *savedTracks) addArtist(artistID spotify.ID) *tui.Label {
+	return saved.addItem("", artistID)
+}
+
+func (saved *savedTracks) addItem(trackID, artistID spotify.ID) *tui.Label {
+	mark := tui.NewLabel("")
+	saved.ids = append(saved.ids, trackID)
+	saved.artists = append(saved.artists, artistID)
+	saved.marks = append(saved.marks, mark)
+	return mark
+}
+
+// fetch checks which of the added tracks are saved and which of the artists
+// are followed, in batches.
+func (saved *savedTracks) fetch() error {
+	err := saved.fetchMarks(saved.ids, userHasTracksBatchSize, func(ids ...spotify.ID) ([]bool, error) {
+		return saved.client.UserHasTracks(saved.ctx, ids...)
+	})
+	if err != nil {
+		return fmt.Errorf("could not check saved tracks: %v", err)
+	}
+	err = saved.fetchMarks(saved.artists, userFollowsBatchSize, func(ids ...spotify.ID) ([]bool, error) {
+		return saved.client.CurrentUserFollows(saved.ctx, "artist", ids...)
+	})
+	if err != nil {
+		return fmt.Errorf("could not check followed artists: %v", err)
+	}
+	return nil
+}
+
+// fetchMarks marks items which check tells about, items with empty ID are skipped.
+func (saved *savedTracks) fetchMarks(itemIDs []spotify.ID, batchSize int, check func(...spotify.ID) ([]bool, error)) error {
+	indexes := []int{}
+	for i, id := range itemIDs {
+		if id != "" {
+			indexes = append(indexes, i)
+		}
+	}
+	for start := 0; start < len(indexes); start += batchSize {
+		end := start + batchSize
+		if end > len(indexes) {
+			end = len(indexes)
+		}
+		ids := make([]spotify.ID, 0, end-start)
+		for _, i := range indexes[start:end] {
+			ids = append(ids, itemIDs[i])
+		}
+		has, err := check(ids...)
+		if err != nil {
+			return err
+		}
+		for j, i := range indexes[start:end] {
+			if j < len(has) {
+				saved.setMark(i, has[j])
+			}
+		}
+	}
+	return nil
+}
+
+// save saves track at the given index to Liked Songs, or removes it from there.
+// Artist at the given index is followed or unfollowed instead.
+func (saved *savedTracks) save(i int, save bool) error {
+	if i < 0 || i >= len(saved.ids) {
+		return nil
+	}
+	if saved.artists[i] != "" {
+		return saved.follow(i, save)
+	}
+	if saved.ids[i] == "" {
+		return nil
+	}
+	var err error
+	if save {
+		err = saved.client.AddTracksToLibrary(saved.ctx, saved.ids[i])
+	} else {
+		err = saved.client.RemoveTracksFromLibrary(saved.ctx, saved.ids[i])
+	}
+	if err != nil {
+		return fmt.Errorf("could not change saved state of track %s: %v", saved.ids[i], err)
+	}
+	saved.setMark(i, save)
+	return nil
+}
+
+// saveAll saves tracks at the given indexes to Liked Songs in batches, items which are
+// not tracks are skipped. done is called with the number of tracks saved so far after each batch.
+func (saved *savedTracks) saveAll(indexes []int, done func(int)) error {
+	tracks := []int{}
+	for _, i := range indexes {
+		if i >= 0 && i < len(saved.ids) && saved.ids[i] != "" {
+			tracks = append(tracks, i)
+		}
+	}
+	for start := 0; start < len(tracks); start += librarySaveTracksBatchSize {
+		end := start + librarySaveTracksBatchSize
+		if end > len(tracks) {
+			end = len(tracks)
+		}
+		ids := make([]spotify.ID, 0, end-start)
+		for _, i := range tracks[start:end] {
+			ids = append(ids, saved.ids[i])
+		}
+		if err := saved.client.AddTracksToLibrary(saved.ctx, ids...); err != nil {
+			return fmt.Errorf("could not save %d tracks: %v", len(ids), err)
+		}
+		for _, i := range tracks[start:end] {
+			saved.setMark(i, true)
+		}
+		done(end)
+	}
+	return nil
+}
+
+// follow follows artist at the given index, or unfollows it.
+func (saved *savedTracks) follow(i int, follow bool) error {
+	var err error
+	if follow {
+		err = saved.client.FollowArtist(saved.ctx, saved.artists[i])
+	} else {
+		err = saved.client.UnfollowArtist(saved.ctx, saved.artists[i])
+	}
+	if err != nil {
+		return fmt.Errorf("could not change followed state of artist %s: %v", saved.artists[i], err)
+	}
+	saved.setMark(i, follow)
+	return nil
+}
+
+func (saved *savedTracks) setMark(i int, isSaved bool) {
+	switch {
+	case !isSaved:
+		saved.marks[i].SetText("")
+	case saved.artists[i] != "":
+		saved.marks[i].SetText(followedArtistMark)
+	default:
+		saved.marks[i].SetText(savedTrackMark)
+	}
+}
+
+// trackIDs returns IDs of all the added tracks, skipping items which are not tracks.
+func (saved *savedTracks) trackIDs() []spotify.ID {
+	ids := []spotify.ID{}
+	for _, id := range saved.ids {
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// addTracks gives tracks to addToPlaylist, nothing happens when there are no tracks.
+func (saved *savedTracks) addTracks(ids []spotify.ID) {
+	if saved.addToPlaylist != nil && len(ids) > 0 {
+		saved.addToPlaylist(ids)
+	}
+}
+
+// keys returns table wrapper saving, removing or adding to a playlist track
+// at the selected row, offset is the number of rows above the first track, i.e. 1 for header.
+func (saved *savedTracks) keys(table *countedTable, offset int) *libraryKeys {
+	change := func(save bool) {
+		if err := saved.save(table.Selected()-offset, save); err != nil {
+			notifyError(fmt.Errorf("could not change saved state: %v", err))
+		}
+	}
+	return &libraryKeys{
+		Widget:   table,
+		onSave:   func() { change(true) },
+		onRemove: func() { change(false) },
+		onAdd: func() {
+			i := table.Selected() - offset
+			if i >= 0 && i < len(saved.ids) && saved.ids[i] != "" {
+				saved.addTracks([]spotify.ID{saved.ids[i]})
+			}
+		},
+		onAddAll: func() { saved.addTracks(saved.trackIDs()) },
+	}
+}
+
+// trackID returns ID of the track with the given URI, it is empty for other items.
+func trackID(uri spotify.URI) spotify.ID {
+	return uriID(uri, "track")
+}
+
+// artistID returns ID of the artist with the given URI, it is empty for other items.
+func artistID(uri spotify.URI) spotify.ID {
+	return uriID(uri, "artist")
+}
+
+func uriID(uri spotify.URI, itemType string) spotify.ID {
+	prefix := "spotify:" + itemType + ":"
+	if !strings.HasPrefix(string(uri), prefix) {
+		return ""
+	}
+	return spotify.ID(strings.TrimPrefix(string(uri), prefix))
+}
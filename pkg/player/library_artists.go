@@ -0,0 +1,101 @@
+package player
+
+import (
+	"fmt"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+// ArtistFilter represents view listing artists of the saved albums, choosing
+// one of them restricts the album list to albums of that artist. Typing
+// letters jumps to the artist starting with them.
+type ArtistFilter struct {
+	Focusables []tui.Widget
+	Box        *tui.Box
+	albumList  *AlbumList
+	table      *countedTable
+	status     *tui.Label
+	artists    []LibraryArtist
+	onFiltered func()
+}
+
+var artistFilterAllArtists = "All artists"
+
+// NewArtistFilter creates view filtering the given album list, it is empty until refreshed.
+func NewArtistFilter(albumList *AlbumList) *ArtistFilter {
+	table := newCountedTable()
+	table.SetColumnStretch(0, 4)
+	table.SetColumnStretch(1, 1)
+	status := tui.NewLabel("Press Enter to list albums of the selected artist")
+
+	filter := &ArtistFilter{
+		albumList: albumList,
+		table:     table,
+		status:    status,
+	}
+	table.OnItemActivated(func(t *tui.Table) {
+		filter.status.SetText(filter.choose(t.Selected()))
+	})
+
+	var box *tui.Box
+	typeAhead := newTypeAheadTable(table, filter.jumpTo, func(prefix string) {
+		box.SetTitle(typeAheadTitle("Library artists", prefix))
+	})
+	box = tui.NewVBox(typeAhead, tui.NewSpacer(), status)
+	box.SetTitle("Library artists")
+	box.SetBorder(true)
+	box.SetSizePolicy(tui.Expanding, tui.Expanding)
+
+	filter.Focusables = []tui.Widget{table}
+	filter.Box = box
+	return filter
+}
+
+// OnFiltered sets function called once the album list is filtered.
+func (filter *ArtistFilter) OnFiltered(fn func()) {
+	filter.onFiltered = fn
+}
+
+// Refresh lists artists of albums saved in the library, the first row lists albums of all of them.
+func (filter *ArtistFilter) Refresh() {
+	filter.artists = filter.albumList.Artists()
+	filter.table.RemoveRows()
+	filter.table.AppendRow(tui.NewLabel(artistFilterAllArtists), tui.NewLabel(""))
+	for _, artist := range filter.artists {
+		filter.table.AppendRow(
+			tui.NewLabel(trimWithCommasIfTooLong(artist.Name, 2*uiColumnWidth)),
+			tui.NewLabel(fmt.Sprintf("%d", artist.Albums)),
+		)
+	}
+	filter.table.SetSelected(0)
+}
+
+// choose filters album list by the artist at the given row, returned text describes the outcome.
+func (filter *ArtistFilter) choose(row int) string {
+	artist := ""
+	if row > 0 && row <= len(filter.artists) {
+		artist = filter.artists[row-1].Name
+	}
+	if err := filter.albumList.FilterArtist(artist); err != nil {
+		return fmt.Sprintf("Could not filter albums: %v", err)
+	}
+	if filter.onFiltered != nil {
+		filter.onFiltered()
+	}
+	if artist == "" {
+		return "Listing albums of all artists"
+	}
+	return "Listing albums of " + artist
+}
+
+// jumpTo selects the first artist which name starts with the prefix.
+func (filter *ArtistFilter) jumpTo(prefix string) bool {
+	for i, artist := range filter.artists {
+		if hasTypeAheadPrefix(prefix, artist.Name) {
+			// +1 for the row listing all artists
+			filter.table.SetSelected(i + 1)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package player
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/zmb3/spotify"
+)
+
+func TestArtistFilterListsAlbumsOfChosenArtist(t *testing.T) {
+	albumList := newEmptyAlbumList(context.Background(), NewDebugClient())
+	albumList.albumsDescriptions = []albumDescription{
+		{artist: "Queen", title: "Jazz", id: "jazz"},
+		{artist: "Pink Floyd", title: "Animals", id: "animals"},
+		{artist: "queen", title: "Innuendo", id: "innuendo"},
+	}
+	filter := NewArtistFilter(albumList)
+	filter.Refresh()
+	expected := []LibraryArtist{{Name: "Pink Floyd", Albums: 1}, {Name: "Queen", Albums: 2}}
+	if !reflect.DeepEqual(filter.artists, expected) {
+		t.Fatalf("Expected artists %v, got %v", expected, filter.artists)
+	}
+
+	if status := filter.choose(2); status != "Listing albums of Queen" {
+		t.Fatalf("Unexpected status after choosing artist: %q", status)
+	}
+	if ids := albumIDs(albumList.albumsDescriptions); !reflect.DeepEqual(ids, []spotify.ID{"jazz", "innuendo"}) {
+		t.Fatalf("Expected only albums of Queen to be listed, got %v", ids)
+	}
+	if albumList.title() != "User albums - Queen" {
+		t.Fatalf("Expected title to name the artist, got %q", albumList.title())
+	}
+
+	if err := albumList.SaveAlbum(spotify.SimpleAlbum{ID: "wall", Name: "The Wall", Artists: []spotify.SimpleArtist{{Name: "Pink Floyd"}}}); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if len(albumList.albumsDescriptions) != 2 || len(albumList.Artists()) != 2 || albumList.Artists()[0].Albums != 2 {
+		t.Fatalf("Expected album of another artist to be saved, but not listed, got %v", albumList.Artists())
+	}
+
+	if status := filter.choose(0); status != "Listing albums of all artists" {
+		t.Fatalf("Unexpected status after choosing all artists: %q", status)
+	}
+	if len(albumList.albumsDescriptions) != 4 || albumList.title() != "User albums" {
+		t.Fatalf("Expected all 4 albums to be listed, got %v", albumIDs(albumList.albumsDescriptions))
+	}
+	if err := albumList.FilterArtist("Nobody"); err == nil {
+		t.Fatalf("Expected to fail filtering by artist without albums")
+	}
+}
+
+func albumIDs(albums []albumDescription) []spotify.ID {
+	ids := []spotify.ID{}
+	for _, album := range albums {
+		ids = append(ids, album.id)
+	}
+	return ids
+}
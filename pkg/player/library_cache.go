@@ -0,0 +1,173 @@
+package player
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/jedruniu/spotify-cli/pkg/cache"
+
+	"github.com/zmb3/spotify"
+)
+
+// LibraryCache keeps saved albums, Liked Songs and tracks of playlists
+// between runs, so that only changes are fetched afterwards. Saved albums
+// and tracks are listed from the most recently added, so pages are fetched
+// until the first cached item is met; when the count does not match then,
+// i.e. because something was removed, everything is fetched again. Tracks
+// of a playlist are fetched again only when its snapshot ID changes.
+// A nil LibraryCache fetches everything each time.
+type LibraryCache struct {
+	store *cache.Store
+}
+
+var (
+	libraryAlbumsCacheEntry = "library_albums"
+	libraryTracksCacheEntry = "library_tracks"
+	// libraryPlaylistTracksCacheEntry is followed by ID of the playlist.
+	libraryPlaylistTracksCacheEntry = "library_playlist_tracks_"
+)
+
+// cachedPlaylistTracks are tracks of the playlist in the version told by the snapshot ID.
+type cachedPlaylistTracks struct {
+	SnapshotID string                  `json:"snapshot_id"`
+	Tracks     []spotify.PlaylistTrack `json:"tracks"`
+}
+
+// NewLibraryCache creates cache keeping the library in the given store.
+func NewLibraryCache(store *cache.Store) *LibraryCache {
+	return &LibraryCache{store: store}
+}
+
+// load decodes cache entry, entry which cannot be loaded is treated as missing.
+func (library *LibraryCache) load(name string, v interface{}) {
+	if err := library.store.Load(name, v); err != nil {
+		log.Printf("Could not load cached library with %s", err)
+	}
+}
+
+// save saves cache entry, failing to do so only makes the next start slower.
+func (library *LibraryCache) save(name string, v interface{}) {
+	if err := library.store.Save(name, v); err != nil {
+		log.Printf("Could not cache library with %s", err)
+	}
+}
+
+// SavedAlbums returns all albums saved in the user's library, tracks of the albums are left out.
+func (library *LibraryCache) SavedAlbums(ctx context.Context, client SpotifyClient) ([]spotify.SavedAlbum, error) {
+	if library == nil {
+		return fetchSavedAlbums(ctx, client)
+	}
+	cached := []spotify.SavedAlbum{}
+	library.load(libraryAlbumsCacheEntry, &cached)
+	cachedIDs := make([]spotify.ID, 0, len(cached))
+	for _, album := range cached {
+		cachedIDs = append(cachedIDs, album.ID)
+	}
+	albums := []spotify.SavedAlbum{}
+	keep, reuse, err := syncNewestFirst(cachedIDs, func(offset int) ([]spotify.ID, int, bool, error) {
+		page, err := client.CurrentUsersAlbumsOpt(ctx, &spotify.Options{Limit: &savedItemsPageSize, Offset: &offset})
+		if err != nil {
+			return nil, 0, false, fmt.Errorf("could not fetch saved albums: %v", err)
+		}
+		ids := make([]spotify.ID, 0, len(page.Albums))
+		for _, album := range page.Albums {
+			album.Tracks = spotify.SimpleTrackPage{}
+			albums = append(albums, album)
+			ids = append(ids, album.ID)
+		}
+		return ids, page.Total, page.Next == "", nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	albums = albums[:keep]
+	if reuse {
+		albums = append(albums, cached...)
+	}
+	library.save(libraryAlbumsCacheEntry, albums)
+	return albums, nil
+}
+
+// SavedTracks returns all tracks saved in the user's Liked Songs.
+func (library *LibraryCache) SavedTracks(ctx context.Context, client SpotifyClient) ([]spotify.SavedTrack, error) {
+	if library == nil {
+		return fetchSavedTracks(ctx, client)
+	}
+	cached := []spotify.SavedTrack{}
+	library.load(libraryTracksCacheEntry, &cached)
+	cachedIDs := make([]spotify.ID, 0, len(cached))
+	for _, track := range cached {
+		cachedIDs = append(cachedIDs, track.ID)
+	}
+	tracks := []spotify.SavedTrack{}
+	keep, reuse, err := syncNewestFirst(cachedIDs, func(offset int) ([]spotify.ID, int, bool, error) {
+		page, err := client.CurrentUsersTracksOpt(ctx, &spotify.Options{Limit: &savedItemsPageSize, Offset: &offset})
+		if err != nil {
+			return nil, 0, false, fmt.Errorf("could not fetch saved tracks: %v", err)
+		}
+		ids := make([]spotify.ID, 0, len(page.Tracks))
+		for _, track := range page.Tracks {
+			tracks = append(tracks, track)
+			ids = append(ids, track.ID)
+		}
+		return ids, page.Total, page.Next == "", nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	tracks = tracks[:keep]
+	if reuse {
+		tracks = append(tracks, cached...)
+	}
+	library.save(libraryTracksCacheEntry, tracks)
+	return tracks, nil
+}
+
+// syncNewestFirst fetches pages of a list ordered from the most recently added, until
+// the first of the cached items is met. fetch returns IDs of items at the offset, the
+// number of all items and whether it was the last page. Returned keep is the number of
+// fetched items which should be kept, reuse tells whether cached items follow them.
+// When cached items do not add up to the number of all items, all pages are fetched.
+func syncNewestFirst(cached []spotify.ID, fetch func(offset int) ([]spotify.ID, int, bool, error)) (keep int, reuse bool, err error) {
+	fetched := 0
+	for {
+		ids, total, last, err := fetch(fetched)
+		if err != nil {
+			return 0, false, err
+		}
+		for i, id := range ids {
+			if len(cached) > 0 && id != "" && id == cached[0] {
+				if fetched+i+len(cached) == total {
+					return fetched + i, true, nil
+				}
+				// something was removed, the cache cannot be trusted
+				cached = nil
+				break
+			}
+		}
+		fetched += len(ids)
+		if last || len(ids) == 0 {
+			return fetched, false, nil
+		}
+	}
+}
+
+// PlaylistTracks returns all tracks of the playlist, they are fetched again only when its snapshot ID changes.
+func (library *LibraryCache) PlaylistTracks(ctx context.Context, client SpotifyClient, playlist spotify.SimplePlaylist) ([]spotify.PlaylistTrack, error) {
+	if library == nil {
+		return fetchPlaylistTracks(ctx, client, playlist.ID)
+	}
+	entry := libraryPlaylistTracksCacheEntry + string(playlist.ID)
+	cached := cachedPlaylistTracks{}
+	library.load(entry, &cached)
+	if playlist.SnapshotID != "" && cached.SnapshotID == playlist.SnapshotID {
+		return cached.Tracks, nil
+	}
+	tracks, err := fetchPlaylistTracks(ctx, client, playlist.ID)
+	if err != nil {
+		return nil, err
+	}
+	library.save(entry, cachedPlaylistTracks{SnapshotID: playlist.SnapshotID, Tracks: tracks})
+	return tracks, nil
+}
@@ -0,0 +1,142 @@
+package player
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/jedruniu/spotify-cli/pkg/cache"
+
+	"github.com/zmb3/spotify"
+)
+
+type fakeSavedAlbumFetcher struct {
+	DebugUserAlbumFetcher
+	// ids are IDs of saved albums, from the most recently added.
+	ids      []spotify.ID
+	requests int
+}
+
+func (fake *fakeSavedAlbumFetcher) CurrentUsersAlbumsOpt(ctx context.Context, opt *spotify.Options) (*spotify.SavedAlbumPage, error) {
+	fake.requests++
+	page := &spotify.SavedAlbumPage{}
+	page.Total = len(fake.ids)
+	for i := *opt.Offset; i < len(fake.ids) && i < *opt.Offset+*opt.Limit; i++ {
+		album := spotify.SavedAlbum{}
+		album.ID = fake.ids[i]
+		album.Name = fmt.Sprintf("Album %s", fake.ids[i])
+		album.Tracks.Total = 10
+		page.Albums = append(page.Albums, album)
+	}
+	if *opt.Offset+len(page.Albums) < len(fake.ids) {
+		page.Next = "next"
+	}
+	return page, nil
+}
+
+func albumIDsRange(from, to int) []spotify.ID {
+	ids := []spotify.ID{}
+	for i := from; i < to; i++ {
+		ids = append(ids, spotify.ID(fmt.Sprintf("album%d", i)))
+	}
+	return ids
+}
+
+func savedAlbumIDs(albums []spotify.SavedAlbum) []spotify.ID {
+	ids := []spotify.ID{}
+	for _, album := range albums {
+		ids = append(ids, album.ID)
+	}
+	return ids
+}
+
+func TestLibraryCacheFetchesOnlyNewAlbums(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spotify-cli-library")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	fetcher := &fakeSavedAlbumFetcher{ids: albumIDsRange(0, 120)}
+	client := NewDebugClient().(DebugClient)
+	client.UserAlbumFetcher = fetcher
+	library := NewLibraryCache(cache.NewStore(dir))
+
+	albums, err := library.SavedAlbums(context.Background(), client)
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if fetcher.requests != 3 || len(albums) != 120 {
+		t.Fatalf("Expected all 120 albums to be fetched with 3 requests, got %d albums with %d requests", len(albums), fetcher.requests)
+	}
+	if albums[0].Tracks.Total != 0 {
+		t.Fatalf("Expected tracks of albums to be left out")
+	}
+
+	// two albums added since
+	fetcher.ids = append([]spotify.ID{"new1", "new0"}, fetcher.ids...)
+	fetcher.requests = 0
+	albums, err = library.SavedAlbums(context.Background(), client)
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if fetcher.requests != 1 || !reflect.DeepEqual(savedAlbumIDs(albums), fetcher.ids) {
+		t.Fatalf("Expected only the first page to be fetched, got %d requests and albums %v", fetcher.requests, savedAlbumIDs(albums))
+	}
+
+	// an album removed from the middle
+	fetcher.ids = append(append([]spotify.ID{}, fetcher.ids[:60]...), fetcher.ids[61:]...)
+	fetcher.requests = 0
+	albums, err = library.SavedAlbums(context.Background(), client)
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if fetcher.requests != 3 || !reflect.DeepEqual(savedAlbumIDs(albums), fetcher.ids) {
+		t.Fatalf("Expected all albums to be fetched again, got %d requests and %d albums", fetcher.requests, len(albums))
+	}
+}
+
+type fakeSnapshotPlaylistEditor struct {
+	*DebugPlaylistEditor
+	requests int
+}
+
+func (fake *fakeSnapshotPlaylistEditor) GetPlaylistTracksOpt(ctx context.Context, playlistID spotify.ID, opt *spotify.Options, fields string) (*spotify.PlaylistTrackPage, error) {
+	fake.requests++
+	return fake.DebugPlaylistEditor.GetPlaylistTracksOpt(context.Background(), playlistID, opt, fields)
+}
+
+func TestLibraryCacheFetchesPlaylistTracksOfNewSnapshot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spotify-cli-library")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	editor := &fakeSnapshotPlaylistEditor{DebugPlaylistEditor: NewDebugPlaylistEditor()}
+	client := NewDebugClient().(DebugClient)
+	client.PlaylistEditor = editor
+	library := NewLibraryCache(cache.NewStore(dir))
+	playlist := spotify.SimplePlaylist{ID: "playlist", SnapshotID: "first"}
+
+	for i := 0; i < 2; i++ {
+		tracks, err := library.PlaylistTracks(context.Background(), client, playlist)
+		if err != nil {
+			t.Fatalf("Did not expect to fail, but it did with %v", err)
+		}
+		if len(tracks) != 10 {
+			t.Fatalf("Expected 10 tracks of the playlist, got %d", len(tracks))
+		}
+	}
+	if editor.requests != 1 {
+		t.Fatalf("Expected tracks of the same snapshot to be fetched once, got %d requests", editor.requests)
+	}
+	playlist.SnapshotID = "second"
+	if _, err := library.PlaylistTracks(context.Background(), client, playlist); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if editor.requests != 2 {
+		t.Fatalf("Expected tracks of the new snapshot to be fetched, got %d requests", editor.requests)
+	}
+}
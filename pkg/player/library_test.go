@@ -0,0 +1,114 @@
+package player
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/zmb3/spotify"
+)
+
+type fakeBatchLibraryEditor struct {
+	DebugLibraryEditor
+	batches []int
+}
+
+func (fake *fakeBatchLibraryEditor) UserHasTracks(ctx context.Context, trackIDs ...spotify.ID) ([]bool, error) {
+	fake.batches = append(fake.batches, len(trackIDs))
+	return fake.DebugLibraryEditor.UserHasTracks(context.Background(), trackIDs...)
+}
+
+func TestSavedTracksFetchesInBatches(t *testing.T) {
+	editor := &fakeBatchLibraryEditor{}
+	editor.AddTracksToLibrary(context.Background(), "track0", "track51")
+	client := NewDebugClient().(DebugClient)
+	client.LibraryEditor = editor
+	saved := &savedTracks{client: client}
+	for i := 0; i < 60; i++ {
+		saved.add(spotify.ID(fmt.Sprintf("track%d", i)))
+	}
+	saved.add("") // not a track
+
+	if err := saved.fetch(); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if len(editor.batches) != 2 || editor.batches[0] != 50 || editor.batches[1] != 10 {
+		t.Fatalf("Expected tracks to be checked in batches of 50 and 10, got %v", editor.batches)
+	}
+	for i, mark := range saved.marks {
+		expected := ""
+		if i == 0 || i == 51 {
+			expected = savedTrackMark
+		}
+		if mark.Text() != expected {
+			t.Fatalf("Expected mark of track %d to be %q, got %q", i, expected, mark.Text())
+		}
+	}
+}
+
+func TestSavedTracksSave(t *testing.T) {
+	client := NewDebugClient()
+	saved := &savedTracks{client: client}
+	saved.add("track")
+	saved.add("")
+
+	if err := saved.save(0, true); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if has, _ := client.UserHasTracks(context.Background(), "track"); !has[0] || saved.marks[0].Text() != savedTrackMark {
+		t.Fatalf("Expected track to be saved and marked")
+	}
+	if err := saved.save(0, false); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if has, _ := client.UserHasTracks(context.Background(), "track"); has[0] || saved.marks[0].Text() != "" {
+		t.Fatalf("Expected track to be removed and unmarked")
+	}
+	if err := saved.save(1, true); err != nil {
+		t.Fatalf("Expected item which is not a track to be skipped, but it failed with %v", err)
+	}
+}
+
+func TestSavedTracksFollowsArtists(t *testing.T) {
+	browser := &fakeFollowingBrowser{}
+	client := NewDebugClient().(DebugClient)
+	client.ArtistBrowser = browser
+	saved := &savedTracks{client: client}
+	saved.add("track")
+	saved.addArtist("artist1") // followed by DebugClient
+	saved.addArtist("other")
+
+	if err := saved.fetch(); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if saved.marks[1].Text() != followedArtistMark || saved.marks[2].Text() != "" {
+		t.Fatalf("Expected only followed artist to be marked, got %q and %q", saved.marks[1].Text(), saved.marks[2].Text())
+	}
+	if err := saved.save(2, true); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if err := saved.save(1, false); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if len(browser.followed) != 1 || browser.followed[0] != "other" || len(browser.unfollowed) != 1 || browser.unfollowed[0] != "artist1" {
+		t.Fatalf("Expected to follow other and unfollow artist1, followed %v, unfollowed %v", browser.followed, browser.unfollowed)
+	}
+	if saved.marks[1].Text() != "" || saved.marks[2].Text() != followedArtistMark {
+		t.Fatalf("Expected marks to follow the changes, got %q and %q", saved.marks[1].Text(), saved.marks[2].Text())
+	}
+	if ids := saved.trackIDs(); len(ids) != 1 {
+		t.Fatalf("Expected artists not to be added to playlists, got %v", ids)
+	}
+}
+
+func TestTrackID(t *testing.T) {
+	if id := trackID("spotify:track:abc"); id != "abc" {
+		t.Fatalf("Expected track ID abc, got %q", id)
+	}
+	if id := trackID("spotify:album:abc"); id != "" {
+		t.Fatalf("Expected no track ID for album, got %q", id)
+	}
+	if id := artistID("spotify:artist:abc"); id != "abc" {
+		t.Fatalf("Expected artist ID abc, got %q", id)
+	}
+}
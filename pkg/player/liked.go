@@ -0,0 +1,133 @@
+package player
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/marcusolsson/tui-go"
+	"github.com/zmb3/spotify"
+)
+
+// LikedSongs represents view of tracks saved in the user's Liked Songs. The
+// first page is listed right away, the others are fetched in the background,
+// so that the view can be used while a large collection is still loading.
+type LikedSongs struct {
+	Focusables []tui.Widget
+	Box        *tui.Box
+	ctx        context.Context
+	client     SpotifyClient
+	tracks     *searchResults
+	status     *tui.Label
+	// loads is increased with each load, so that pages fetched for the previous one are dropped.
+	loads int
+	// cancelLoad cancels requests for pages of the previous load.
+	cancelLoad context.CancelFunc
+	update     func(func())
+}
+
+var likedSongsPageSize = 50
+
+// NewLikedSongs creates view of Liked Songs, it is empty until loaded.
+func NewLikedSongs(ctx context.Context, client SpotifyClient) *LikedSongs {
+	tracks := newSearchResults(ctx, client, "Liked Songs")
+	status := tui.NewLabel("")
+
+	box := tui.NewVBox(tracks.getBox(), status)
+	box.SetSizePolicy(tui.Expanding, tui.Expanding)
+
+	return &LikedSongs{
+		Focusables: []tui.Widget{tracks.getTable()},
+		Box:        box,
+		ctx:        ctx,
+		client:     client,
+		tracks:     tracks,
+		status:     status,
+		update:     func(fn func()) { fn() },
+	}
+}
+
+// OnUpdate sets function running the given function in the UI goroutine, i.e. tui.UI.Update,
+// pages fetched in the background are listed with it. Without it they are listed right away.
+func (liked *LikedSongs) OnUpdate(fn func(func())) {
+	liked.update = fn
+}
+
+// OnAddToPlaylist sets function called with liked tracks which should be added to a playlist.
+func (liked *LikedSongs) OnAddToPlaylist(fn func([]spotify.ID)) {
+	liked.tracks.onAddToPlaylist(fn)
+}
+
+// Load lists the first page of Liked Songs, the other pages are fetched in the background.
+func (liked *LikedSongs) Load() error {
+	if liked.cancelLoad != nil {
+		liked.cancelLoad()
+	}
+	ctx, cancel := context.WithCancel(liked.ctx)
+	liked.cancelLoad = cancel
+	page, err := liked.fetch(ctx, 0)
+	if err != nil {
+		return err
+	}
+	liked.loads++
+	liked.tracks.resetSearchResults()
+	liked.appendPage(liked.loads, page)
+	if page.Next != "" && len(page.Tracks) > 0 {
+		go liked.loadRest(ctx, liked.loads, len(page.Tracks))
+	}
+	return nil
+}
+
+func (liked *LikedSongs) fetch(ctx context.Context, offset int) (*spotify.SavedTrackPage, error) {
+	page, err := liked.client.CurrentUsersTracksOpt(ctx, &spotify.Options{Limit: &likedSongsPageSize, Offset: &offset})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch liked songs: %v", err)
+	}
+	return page, nil
+}
+
+// loadRest fetches pages starting at the offset until the last one, or until Liked Songs are loaded again.
+func (liked *LikedSongs) loadRest(ctx context.Context, load, offset int) {
+	for {
+		page, err := liked.fetch(ctx, offset)
+		if ctx.Err() != nil {
+			return // loaded again or quitting
+		}
+		if err != nil {
+			log.Printf("Could not load liked songs with %s", err)
+			liked.update(func() {
+				if load == liked.loads {
+					liked.status.SetText(fmt.Sprintf("Loaded %d songs, could not load the rest: %v", len(liked.tracks.getData()), err))
+				}
+			})
+			return
+		}
+		current := true
+		liked.update(func() {
+			current = liked.appendPage(load, page)
+		})
+		offset += len(page.Tracks)
+		if !current || page.Next == "" || len(page.Tracks) == 0 {
+			return
+		}
+	}
+}
+
+// appendPage lists tracks of the page, unless it was fetched for the previous load,
+// which is told by the returned value.
+func (liked *LikedSongs) appendPage(load int, page *spotify.SavedTrackPage) bool {
+	if load != liked.loads {
+		return false
+	}
+	for _, track := range page.Tracks {
+		liked.tracks.appendSearchResult(URIName{Name: track.Name + " - " + artistsNames(track.Artists), URI: track.URI})
+		liked.tracks.saved.setMark(len(liked.tracks.saved.marks)-1, true)
+	}
+	loaded := len(liked.tracks.getData())
+	if page.Next == "" || len(page.Tracks) == 0 {
+		liked.status.SetText(fmt.Sprintf("%d songs", loaded))
+	} else {
+		liked.status.SetText(fmt.Sprintf("Loading %d of %d", loaded, page.Total))
+	}
+	return true
+}
@@ -0,0 +1,139 @@
+package player
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/zmb3/spotify"
+)
+
+type fakePagedLibraryEditor struct {
+	DebugLibraryEditor
+	total int
+}
+
+func (fake *fakePagedLibraryEditor) CurrentUsersTracksOpt(ctx context.Context, opt *spotify.Options) (*spotify.SavedTrackPage, error) {
+	page := &spotify.SavedTrackPage{}
+	page.Total = fake.total
+	for i := *opt.Offset; i < fake.total && i < *opt.Offset+*opt.Limit; i++ {
+		track := spotify.SavedTrack{}
+		track.ID = spotify.ID(fmt.Sprintf("track%d", i))
+		track.URI = spotify.URI("spotify:track:" + string(track.ID))
+		track.Name = fmt.Sprintf("Track %d", i)
+		page.Tracks = append(page.Tracks, track)
+	}
+	if *opt.Offset+len(page.Tracks) < fake.total {
+		page.Next = "next"
+	}
+	return page, nil
+}
+
+// updateInTest runs functions of the background updates once they are received
+// from the channel and called by the test, like the UI would.
+func updateInTest(updates chan func()) func(func()) {
+	return func(fn func()) {
+		done := make(chan struct{})
+		updates <- func() {
+			fn()
+			close(done)
+		}
+		<-done
+	}
+}
+
+func TestLikedSongsLoadsInBackground(t *testing.T) {
+	client := NewDebugClient().(DebugClient)
+	client.LibraryEditor = &fakePagedLibraryEditor{total: 120}
+	liked := NewLikedSongs(context.Background(), client)
+	updates := make(chan func())
+	liked.OnUpdate(updateInTest(updates))
+
+	if err := liked.Load(); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if len(liked.tracks.getData()) != 50 || liked.status.Text() != "Loading 50 of 120" {
+		t.Fatalf("Expected the first page to be listed right away, got %d tracks and status %q", len(liked.tracks.getData()), liked.status.Text())
+	}
+	(<-updates)()
+	if liked.status.Text() != "Loading 100 of 120" {
+		t.Fatalf("Expected the second page to be listed, got status %q", liked.status.Text())
+	}
+	(<-updates)()
+	if len(liked.tracks.getData()) != 120 || liked.status.Text() != "120 songs" {
+		t.Fatalf("Expected all tracks to be listed, got %d tracks and status %q", len(liked.tracks.getData()), liked.status.Text())
+	}
+	if liked.tracks.getData()[119] != "spotify:track:track119" || liked.tracks.saved.marks[119].Text() != savedTrackMark {
+		t.Fatalf("Expected tracks to be listed in order and marked as saved")
+	}
+}
+
+func TestLikedSongsDropsPagesOfPreviousLoad(t *testing.T) {
+	client := NewDebugClient().(DebugClient)
+	client.LibraryEditor = &fakePagedLibraryEditor{total: 120}
+	liked := NewLikedSongs(context.Background(), client)
+	updates := make(chan func())
+	liked.OnUpdate(updateInTest(updates))
+
+	if err := liked.Load(); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	stale := <-updates
+	if err := liked.Load(); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	stale()
+	if len(liked.tracks.getData()) != 50 {
+		t.Fatalf("Expected page of the previous load to be dropped, got %d tracks", len(liked.tracks.getData()))
+	}
+	(<-updates)()
+	(<-updates)()
+	if len(liked.tracks.getData()) != 120 {
+		t.Fatalf("Expected all tracks to be listed once, got %d tracks", len(liked.tracks.getData()))
+	}
+}
+
+// fakeHangingLibraryEditor gives the first page right away, requests for the other pages
+// hang until they are cancelled.
+type fakeHangingLibraryEditor struct {
+	fakePagedLibraryEditor
+	cancelled chan error
+}
+
+func (fake *fakeHangingLibraryEditor) CurrentUsersTracksOpt(ctx context.Context, opt *spotify.Options) (*spotify.SavedTrackPage, error) {
+	if *opt.Offset == 0 {
+		return fake.fakePagedLibraryEditor.CurrentUsersTracksOpt(ctx, opt)
+	}
+	<-ctx.Done()
+	fake.cancelled <- ctx.Err()
+	return nil, ctx.Err()
+}
+
+func TestLikedSongsCancelsLoadInBackground(t *testing.T) {
+	editor := &fakeHangingLibraryEditor{fakePagedLibraryEditor: fakePagedLibraryEditor{total: 120}, cancelled: make(chan error, 2)}
+	client := NewDebugClient().(DebugClient)
+	client.LibraryEditor = editor
+	ctx, cancel := context.WithCancel(context.Background())
+	liked := NewLikedSongs(ctx, client)
+	liked.OnUpdate(func(func()) { t.Errorf("Expected no pages to be listed after cancelling") })
+
+	if err := liked.Load(); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if err := liked.Load(); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	select {
+	case <-editor.cancelled:
+	case <-time.After(time.Second):
+		t.Fatalf("Expected request of the previous load to be cancelled when loading again")
+	}
+
+	cancel()
+	select {
+	case <-editor.cancelled:
+	case <-time.After(time.Second):
+		t.Fatalf("Expected request to be cancelled with the context of the view, i.e. when quitting")
+	}
+}
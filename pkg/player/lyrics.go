@@ -0,0 +1,111 @@
+package player
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/jedruniu/spotify-cli/pkg/lyrics"
+
+	"github.com/marcusolsson/tui-go"
+	"github.com/zmb3/spotify"
+)
+
+// commandLyrics is the provider of lyrics printed by the lyrics command.
+var commandLyrics lyrics.Provider = lyrics.NewLRCLIB("")
+
+// UseLyrics makes the lyrics command fetch lyrics from the provider.
+func UseLyrics(provider lyrics.Provider) {
+	commandLyrics = provider
+}
+
+// PlayingLyrics fetches lyrics of the currently playing track, which is returned along with them.
+func PlayingLyrics(ctx context.Context, client SpotifyClient, provider lyrics.Provider) (*spotify.FullTrack, string, error) {
+	playing, err := client.PlayerCurrentlyPlaying(ctx)
+	if err != nil {
+		return nil, "", commandError(err, "could not get currently playing item")
+	}
+	if playing.Item == nil {
+		return nil, "", exitErrorf(ExitNotFound, "no track is played, only tracks have lyrics")
+	}
+	track := playing.Item
+	text, err := provider.Lyrics(ctx, lyrics.Track{
+		Artist:   artistsNames(track.Artists),
+		Title:    track.Name,
+		Album:    track.Album.Name,
+		Duration: time.Duration(track.Duration) * time.Millisecond,
+	})
+	if err == lyrics.ErrNotFound {
+		return nil, "", exitErrorf(ExitNotFound, "lyrics of %s by %s were not found", track.Name, artistsNames(track.Artists))
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("could not fetch lyrics of %s: %v", track.Name, err)
+	}
+	return track, strings.TrimRight(text, "\n"), nil
+}
+
+// lyricsCommand prints lyrics of the currently playing track.
+func lyricsCommand(ctx context.Context, client SpotifyClient, args []string, out io.Writer) error {
+	if len(args) != 0 {
+		return usageErrorf("lyrics command takes no arguments, got %v", args)
+	}
+	_, text, err := PlayingLyrics(ctx, client, commandLyrics)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(out, text)
+	return err
+}
+
+// Lyrics represents view with lyrics of the currently playing track, a line in each row.
+type Lyrics struct {
+	Focusables []tui.Widget
+	Box        *tui.Box
+	ctx        context.Context
+	client     SpotifyClient
+	provider   lyrics.Provider
+	table      *countedTable
+	lines      []string
+}
+
+// NewLyrics creates view with lyrics fetched from the provider, it is empty until they are shown.
+func NewLyrics(ctx context.Context, client SpotifyClient, provider lyrics.Provider) *Lyrics {
+	table := newCountedTable()
+	table.SetColumnStretch(0, 1)
+
+	box := tui.NewVBox(table, tui.NewSpacer())
+	box.SetTitle("Lyrics")
+	box.SetBorder(true)
+	box.SetSizePolicy(tui.Expanding, tui.Expanding)
+
+	return &Lyrics{
+		Focusables: []tui.Widget{table},
+		Box:        box,
+		ctx:        ctx,
+		client:     client,
+		provider:   provider,
+		table:      table,
+	}
+}
+
+// ShowPlaying shows lyrics of the currently playing track, titled with the track.
+func (view *Lyrics) ShowPlaying() error {
+	track, text, err := PlayingLyrics(view.ctx, view.client, view.provider)
+	if err != nil {
+		return err
+	}
+	view.show(fmt.Sprintf("Lyrics of %s by %s", track.Name, artistsNames(track.Artists)), text)
+	return nil
+}
+
+func (view *Lyrics) show(title, text string) {
+	view.Box.SetTitle(title)
+	view.table.RemoveRows()
+	view.lines = strings.Split(text, "\n")
+	for _, line := range view.lines {
+		view.table.AppendRow(tui.NewLabel(line))
+	}
+	view.table.SetSelected(0)
+}
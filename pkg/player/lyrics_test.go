@@ -0,0 +1,52 @@
+package player
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/jedruniu/spotify-cli/pkg/lyrics"
+)
+
+// knownLyrics knows lyrics of tracks by their titles.
+type knownLyrics map[string]string
+
+func (known knownLyrics) Lyrics(ctx context.Context, track lyrics.Track) (string, error) {
+	text, ok := known[track.Title]
+	if !ok {
+		return "", lyrics.ErrNotFound
+	}
+	return text, nil
+}
+
+func TestLyricsCommand(t *testing.T) {
+	defer func(previous lyrics.Provider) { commandLyrics = previous }(commandLyrics)
+	UseLyrics(knownLyrics{"Idioteque": "Who's in a bunker?\nWho's in a bunker?\n"})
+
+	out := &bytes.Buffer{}
+	if err := RunCommand(context.Background(), &likingClient{}, "lyrics", nil, out); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if expected := "Who's in a bunker?\nWho's in a bunker?\n"; out.String() != expected {
+		t.Fatalf("Expected lyrics %q, got %q", expected, out.String())
+	}
+
+	UseLyrics(knownLyrics{})
+	if err := RunCommand(context.Background(), &likingClient{}, "lyrics", nil, out); ExitCode(err) != ExitNotFound {
+		t.Fatalf("Expected lyrics not to be found, got %v", err)
+	}
+	if err := RunCommand(context.Background(), &playbackClient{}, "lyrics", nil, out); ExitCode(err) != ExitNotFound {
+		t.Fatalf("Expected no lyrics when nothing is played, got %v", err)
+	}
+}
+
+func TestLyricsShowPlaying(t *testing.T) {
+	view := NewLyrics(context.Background(), &likingClient{}, knownLyrics{"Idioteque": "Who's in a bunker?\nWomen and children first"})
+	if err := view.ShowPlaying(); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if expected := []string{"Who's in a bunker?", "Women and children first"}; !reflect.DeepEqual(view.lines, expected) {
+		t.Fatalf("Expected lines %q, got %q", expected, view.lines)
+	}
+}
@@ -0,0 +1,154 @@
+package player
+
+import (
+	"image"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+// originMark is drawn at the top left corner of widgets which are clicked, the surface of the
+// terminal records where it is drawn instead of showing it, as tui-go does not tell where it
+// draws widgets. It is a private use character, which is never drawn otherwise.
+const originMark = '\U000FFFFD'
+
+// drawn keeps where widgets were drawn at the last repaint, it is only used in the ui goroutine,
+// in which widgets are drawn and clicks are handled.
+var drawn = &drawnWidgets{origins: map[tui.Widget]image.Point{}}
+
+type drawnWidgets struct {
+	origins map[tui.Widget]image.Point
+	// marking is the widget whose origin mark is being drawn.
+	marking tui.Widget
+}
+
+// markOrigin records where the widget is drawn with the painter, it has to be called by Draw
+// of the widget, before it draws anything else at its top left corner.
+func markOrigin(p *tui.Painter, w tui.Widget) {
+	drawn.marking = w
+	p.DrawRune(0, 0, originMark)
+	drawn.marking = nil
+}
+
+// bounds returns where the widget was drawn at the last repaint.
+func (d *drawnWidgets) bounds(w tui.Widget) (image.Rectangle, bool) {
+	origin, ok := d.origins[w]
+	if !ok {
+		return image.Rectangle{}, false
+	}
+	return image.Rectangle{Min: origin, Max: origin.Add(w.Size())}, true
+}
+
+// markingSurface records where origin marks are drawn, other cells are painted on the surface.
+type markingSurface struct {
+	tui.Surface
+}
+
+// Begin forgets where widgets were drawn, as they are all drawn again.
+func (s markingSurface) Begin() {
+	drawn.origins = map[tui.Widget]image.Point{}
+	s.Surface.Begin()
+}
+
+func (s markingSurface) SetCell(x, y int, ch rune, style tui.Style) {
+	if ch != originMark {
+		s.Surface.SetCell(x, y, ch, style)
+		return
+	}
+	if drawn.marking != nil {
+		drawn.origins[drawn.marking] = image.Point{x, y}
+	}
+}
+
+// clickableWidget records where the widget is drawn, so that the mouse can click it. Tables
+// record it themselves.
+type clickableWidget struct {
+	tui.Widget
+}
+
+// clickable wraps the button or entry for its box, while the widget itself is focused.
+func clickable(w tui.Widget) tui.Widget {
+	return &clickableWidget{Widget: w}
+}
+
+func (w *clickableWidget) Draw(p *tui.Painter) {
+	markOrigin(p, w.Widget)
+	w.Widget.Draw(p)
+}
+
+// Mouse focuses the widget clicked, selects the clicked row of a table and activates it
+// when double clicked, presses clicked buttons and moves the selection of a table with the
+// wheel. Widgets are found where they were drawn at the last repaint of the terminal.
+type Mouse struct {
+	widgets func() []tui.Widget
+	focus   func(tui.Widget)
+}
+
+// NewMouse creates mouse for the ui. Clicks are handled by widgets returned by widgets,
+// which are focused with focus.
+func NewMouse(widgets func() []tui.Widget, focus func(tui.Widget)) *Mouse {
+	return &Mouse{widgets: widgets, focus: focus}
+}
+
+// Handle handles the click or the scroll of the wheel.
+func (mouse *Mouse) Handle(ev MouseEvent) {
+	w, row, ok := mouse.widgetAt(ev.Pos)
+	if !ok {
+		return
+	}
+	table, _ := w.(rowsTable)
+	switch ev.Button {
+	case MouseClick:
+		mouse.focus(w)
+		if table == nil {
+			if _, button := w.(*tui.Button); button {
+				w.OnKeyEvent(tui.KeyEvent{Key: tui.KeyEnter})
+			}
+			return
+		}
+		if row < 0 {
+			return
+		}
+		if row != table.table().Selected() {
+			table.table().Select(row)
+		}
+		if ev.DoubleClick {
+			w.OnKeyEvent(tui.KeyEvent{Key: tui.KeyEnter})
+		}
+	case MouseWheelUp, MouseWheelDown:
+		if table == nil {
+			return
+		}
+		mouse.focus(w)
+		key := tui.KeyDown
+		if ev.Button == MouseWheelUp {
+			key = tui.KeyUp
+		}
+		w.OnKeyEvent(tui.KeyEvent{Key: key})
+	}
+}
+
+// widgetAt finds the widget drawn at the position, along with the row at the position
+// when the widget is a table, -1 when there is no row there.
+func (mouse *Mouse) widgetAt(pos image.Point) (tui.Widget, int, bool) {
+	for _, w := range mouse.widgets() {
+		table, isTable := w.(rowsTable)
+		drawnAs := w
+		if isTable {
+			drawnAs = table.table()
+		}
+		bounds, ok := drawn.bounds(drawnAs)
+		if !ok || !pos.In(bounds) {
+			continue
+		}
+		if !isTable {
+			return w, 0, true
+		}
+		// each row is a line
+		row := pos.Y - bounds.Min.Y
+		if row >= table.rows() {
+			row = -1
+		}
+		return w, row, true
+	}
+	return nil, 0, false
+}
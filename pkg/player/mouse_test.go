@@ -0,0 +1,109 @@
+package player
+
+import (
+	"image"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell"
+	"github.com/marcusolsson/tui-go"
+)
+
+func TestMouseSelectsClickedRows(t *testing.T) {
+	table := newCountedTable()
+	for i := 0; i < 5; i++ {
+		table.AppendRow(tui.NewLabel("row"))
+	}
+	activated := 0
+	table.OnItemActivated(func(*tui.Table) { activated++ })
+	pressed := 0
+	button := tui.NewButton("Play")
+	button.OnActivated(func(*tui.Button) { pressed++ })
+	// the table starts at the second line, the button at the eighth one
+	root := tui.NewVBox(tui.NewLabel("title"), table, tui.NewSpacer(), clickable(button))
+	tui.NewPainter(markingSurface{tui.NewTestSurface(20, 8)}, tui.NewTheme()).Repaint(root)
+
+	var focused tui.Widget
+	mouse := NewMouse(func() []tui.Widget {
+		return []tui.Widget{table, button}
+	}, func(w tui.Widget) {
+		if focused != nil {
+			focused.SetFocused(false)
+		}
+		focused = w
+		w.SetFocused(true)
+	})
+
+	mouse.Handle(MouseEvent{Pos: image.Point{2, 3}, Button: MouseClick})
+	if focused != table || table.Selected() != 2 || activated != 0 {
+		t.Fatalf("Expected the third row to be selected, got %d", table.Selected())
+	}
+	mouse.Handle(MouseEvent{Pos: image.Point{2, 3}, Button: MouseClick, DoubleClick: true})
+	if activated != 1 {
+		t.Fatalf("Expected the row to be activated on double click")
+	}
+	mouse.Handle(MouseEvent{Pos: image.Point{2, 2}, Button: MouseWheelDown})
+	mouse.Handle(MouseEvent{Pos: image.Point{2, 2}, Button: MouseWheelDown})
+	if table.Selected() != 4 {
+		t.Fatalf("Expected the wheel to move the selection, got %d", table.Selected())
+	}
+	// the spacer below the rows is not a row
+	mouse.Handle(MouseEvent{Pos: image.Point{2, 6}, Button: MouseClick})
+	if table.Selected() != 4 {
+		t.Fatalf("Expected clicking below the rows to keep the selection, got %d", table.Selected())
+	}
+
+	mouse.Handle(MouseEvent{Pos: image.Point{1, 7}, Button: MouseClick})
+	if focused != button || pressed != 1 {
+		t.Fatalf("Expected the button to be pressed")
+	}
+
+	// widgets which are not drawn are not clicked
+	tui.NewPainter(markingSurface{tui.NewTestSurface(20, 8)}, tui.NewTheme()).Repaint(tui.NewVBox(table))
+	mouse.Handle(MouseEvent{Pos: image.Point{1, 7}, Button: MouseClick})
+	if pressed != 1 {
+		t.Fatalf("Expected hidden button not to be pressed")
+	}
+}
+
+func TestMarkingSurfaceDoesNotPaintMarks(t *testing.T) {
+	surface := tui.NewTestSurface(10, 2)
+	button := tui.NewButton("Play")
+	tui.NewPainter(markingSurface{surface}, tui.NewTheme()).Repaint(tui.NewVBox(tui.NewLabel("title"), clickable(button)))
+
+	if bounds, ok := drawn.bounds(button); !ok || bounds != image.Rect(0, 1, 10, 2) {
+		t.Fatalf("Expected the button to be drawn at the second line, got %v", bounds)
+	}
+	if strings.ContainsRune(surface.String(), originMark) {
+		t.Fatalf("Expected marks not to be painted, got\n%s", surface.String())
+	}
+}
+
+func TestTerminalTellsDoubleClicks(t *testing.T) {
+	now := time.Now()
+	events := []MouseEvent{}
+	terminal := &Terminal{now: func() time.Time { return now }}
+	terminal.OnMouse(func(ev MouseEvent) { events = append(events, ev) })
+
+	click := func(x int) {
+		terminal.handleMouse(tcell.NewEventMouse(x, 1, tcell.Button1, tcell.ModNone))
+		terminal.handleMouse(tcell.NewEventMouse(x, 1, tcell.ButtonNone, tcell.ModNone))
+	}
+	click(1)
+	click(1)
+	click(2)
+	now = now.Add(time.Second)
+	click(2)
+	terminal.handleMouse(tcell.NewEventMouse(2, 1, tcell.WheelUp, tcell.ModNone))
+
+	if len(events) != 5 {
+		t.Fatalf("Expected 4 clicks and a scroll, got %v", events)
+	}
+	if events[0].DoubleClick || !events[1].DoubleClick || events[2].DoubleClick || events[3].DoubleClick {
+		t.Fatalf("Expected only the second click to be double, got %v", events)
+	}
+	if events[4].Button != MouseWheelUp || events[4].Pos != (image.Point{2, 1}) {
+		t.Fatalf("Expected scroll of the wheel, got %v", events[4])
+	}
+}
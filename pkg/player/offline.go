@@ -0,0 +1,135 @@
+package player
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sync"
+
+	"github.com/jedruniu/spotify-cli/pkg/cache"
+
+	"github.com/zmb3/spotify"
+	"golang.org/x/oauth2"
+)
+
+// offlinePaths are requests for the library, responses to them are kept so that the
+// library and playlists can be browsed when Spotify cannot be reached.
+var offlinePaths = regexp.MustCompile(`/(me|me/(albums|tracks|playlists|following|shows|audiobooks)|playlists/[^/]+(/tracks)?)$`)
+
+// offlineCacheEntry is followed by hash of the request URI.
+var offlineCacheEntry = "offline_"
+
+// offlineMessage is the error of requests which cannot be answered offline.
+var offlineMessage = "Not available offline, only the library can be browsed until spotify-cli is started online"
+
+// OfflineTransport keeps responses to requests for the library in the store. Once it goes
+// offline, the kept responses are given instead of sending requests, the other requests
+// fail with offlineMessage, so that nothing waits for Spotify which cannot be reached.
+type OfflineTransport struct {
+	// Base sends the requests, http.DefaultTransport is used when it is nil.
+	Base  http.RoundTripper
+	Store *cache.Store
+
+	mu      sync.Mutex
+	offline bool
+}
+
+// GoOffline stops sending requests, from now on only the kept responses are given.
+func (t *OfflineTransport) GoOffline() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.offline = true
+}
+
+// Offline tells whether requests are no longer sent.
+func (t *OfflineTransport) Offline() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.offline
+}
+
+// RoundTrip sends the request with the base transport and keeps the response to it,
+// or gives the kept response when offline.
+func (t *OfflineTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	kept := req.Method == http.MethodGet && offlinePaths.MatchString(req.URL.Path)
+	entry := offlineCacheEntry + offlineHash(req.URL)
+	if t.Offline() {
+		body := json.RawMessage{}
+		if kept {
+			if err := t.Store.Load(entry, &body); err != nil {
+				log.Printf("Could not load offline response with %s", err)
+			}
+		}
+		if len(body) == 0 {
+			return offlineResponse(req, http.StatusServiceUnavailable, offlineError()), nil
+		}
+		return offlineResponse(req, http.StatusOK, body), nil
+	}
+
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	if err != nil || !kept || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	// failing to keep the response only means it cannot be browsed offline
+	if err := t.Store.Save(entry, json.RawMessage(body)); err != nil {
+		log.Printf("Could not keep response for offline with %s", err)
+	}
+	return resp, nil
+}
+
+// offlineHash identifies the request by its path and query, so that each page is kept on its own.
+func offlineHash(u *url.URL) string {
+	hash := sha1.Sum([]byte(u.RequestURI()))
+	return hex.EncodeToString(hash[:])
+}
+
+func offlineError() []byte {
+	body, _ := json.Marshal(struct {
+		E spotify.Error `json:"error"`
+	}{spotify.Error{Message: offlineMessage, Status: http.StatusServiceUnavailable}})
+	return body
+}
+
+func offlineResponse(req *http.Request, status int, body []byte) *http.Response {
+	return &http.Response{
+		Status:        http.StatusText(status),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// Unreachable tells whether request failed because Spotify could not be reached, i.e. there
+// is no network, rather than because it was rejected.
+func Unreachable(err error) bool {
+	switch e := err.(type) {
+	case *url.Error:
+		_, rejected := e.Err.(*oauth2.RetrieveError)
+		return !rejected && e.Err != context.Canceled
+	case spotify.Error:
+		return e.Status >= http.StatusInternalServerError
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package player
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/jedruniu/spotify-cli/pkg/cache"
+
+	"github.com/zmb3/spotify"
+	"golang.org/x/oauth2"
+)
+
+func TestOfflineTransportGivesKeptResponses(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spotify-cli")
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	defer os.RemoveAll(dir)
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/me/playlists":
+			w.Write([]byte(`{"items": [{"id": "playlist", "name": "Running"}], "total": 1}`))
+		case "/me/player/devices":
+			w.Write([]byte(`{"devices": [{"id": "device", "name": "Laptop"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	transport := &OfflineTransport{Store: cache.NewStore(dir)}
+	client := NewClient(&http.Client{Transport: transport})
+	client.baseURL = server.URL + "/"
+
+	if _, err := client.CurrentUsersPlaylistsOpt(context.Background(), nil); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if _, err := client.PlayerDevices(context.Background()); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	transport.GoOffline()
+
+	playlists, err := client.CurrentUsersPlaylistsOpt(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if len(playlists.Playlists) != 1 || playlists.Playlists[0].Name != "Running" {
+		t.Fatalf("Expected kept playlists to be given, got %v", playlists.Playlists)
+	}
+	_, err = client.PlayerDevices(context.Background())
+	if err == nil || err.Error() != offlineMessage {
+		t.Fatalf("Expected requests which are not for the library to fail offline, got %v", err)
+	}
+	err = client.AddAlbumsToLibrary(context.Background(), "album")
+	if err == nil || err.Error() != offlineMessage {
+		t.Fatalf("Expected library not to be changed offline, got %v", err)
+	}
+	if requests != 2 {
+		t.Fatalf("Expected no requests to be sent offline, got %d requests", requests-2)
+	}
+}
+
+func TestUnreachable(t *testing.T) {
+	for _, test := range []struct {
+		err         error
+		unreachable bool
+	}{
+		{&url.Error{Op: "Get", URL: "https://api.spotify.com/v1/me", Err: &net.OpError{Op: "dial", Err: errors.New("network is unreachable")}}, true},
+		{&url.Error{Op: "Get", URL: "https://api.spotify.com/v1/me", Err: &oauth2.RetrieveError{}}, false},
+		{&url.Error{Op: "Get", URL: "https://api.spotify.com/v1/me", Err: context.Canceled}, false},
+		{spotify.Error{Status: http.StatusBadGateway}, true},
+		{spotify.Error{Status: http.StatusUnauthorized}, false},
+		{nil, false},
+	} {
+		if unreachable := Unreachable(test.err); unreachable != test.unreachable {
+			t.Fatalf("Expected %v to be unreachable %t, got %t", test.err, test.unreachable, unreachable)
+		}
+	}
+}
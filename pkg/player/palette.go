@@ -0,0 +1,109 @@
+package player
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jedruniu/spotify-cli/pkg/config"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+// CommandPalette represents input in which user can type commands
+// (or aliases of commands defined in config) to be run by the application.
+type CommandPalette struct {
+	Entry    *tui.Entry
+	Box      *tui.Box
+	aliases  config.Aliases
+	commands map[string]func(args []string) error
+}
+
+// NewCommandPalette creates command palette with playback commands
+// already registered, other commands can be added with Register.
+func NewCommandPalette(ctx context.Context, client SpotifyClient, aliases config.Aliases) *CommandPalette {
+	entry := tui.NewEntry()
+	entry.SetSizePolicy(tui.Expanding, tui.Minimum)
+
+	box := tui.NewHBox(clickable(entry))
+	box.SetTitle("Command")
+	box.SetBorder(true)
+
+	palette := &CommandPalette{
+		Entry:    entry,
+		Box:      box,
+		aliases:  aliases,
+		commands: map[string]func(args []string) error{},
+	}
+	registerPlaybackCommands(ctx, palette, client)
+
+	entry.OnSubmit(func(e *tui.Entry) {
+		err := palette.run(e.Text())
+		if err != nil {
+			notifyError(fmt.Errorf("could not run command %q: %v", e.Text(), err))
+		}
+		e.SetText("")
+	})
+	return palette
+}
+
+// Register makes command available under the given name.
+func (palette *CommandPalette) Register(name string, command func(args []string) error) {
+	palette.commands[name] = command
+}
+
+func (palette *CommandPalette) run(commandLine string) error {
+	args, err := config.SplitCommandLine(commandLine)
+	if err != nil {
+		return err
+	}
+	args, err = palette.aliases.Expand(args)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return nil
+	}
+	command, ok := palette.commands[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+	return command(args[1:])
+}
+
+func registerPlaybackCommands(ctx context.Context, palette *CommandPalette, client SpotifyClient) {
+	withoutArgs := func(action func(context.Context) error) func([]string) error {
+		return func(args []string) error {
+			if len(args) != 0 {
+				return fmt.Errorf("command does not take arguments, got %v", args)
+			}
+			return action(ctx)
+		}
+	}
+	palette.Register("play", withoutArgs(client.Play))
+	palette.Register("pause", withoutArgs(client.Pause))
+	palette.Register("next", withoutArgs(client.Next))
+	palette.Register("previous", withoutArgs(client.Previous))
+	palette.Register("open", func(args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("open command takes exactly one argument - Spotify URI or link, got %v", args)
+		}
+		uri, err := ParseURI(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		return playURI(ctx, client, uri)
+	})
+	palette.Register("device", func(args []string) error {
+		if len(args) == 0 {
+			return fmt.Errorf("device command takes name of the device, or a part of it")
+		}
+		// names often have spaces, they do not have to be quoted
+		device, err := transferPlaybackToDeviceNamed(ctx, client, strings.Join(args, " "))
+		if err != nil {
+			return err
+		}
+		notify("Playback transferred to %s", device.Name)
+		return nil
+	})
+}
@@ -0,0 +1,74 @@
+package player
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/jedruniu/spotify-cli/pkg/config"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+func TestCommandPaletteRunsCommandWithExpandedAlias(t *testing.T) {
+	palette := NewCommandPalette(context.Background(), NewDebugClient(), config.Aliases{"kitchen": `device "Kitchen speaker"`})
+	var givenArgs []string
+	palette.Register("device", func(args []string) error {
+		givenArgs = args
+		return nil
+	})
+
+	err := palette.run("kitchen")
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if !reflect.DeepEqual(givenArgs, []string{"Kitchen speaker"}) {
+		t.Fatalf("Expected command to be called with alias arguments, got %#v", givenArgs)
+	}
+}
+
+func TestCommandPaletteFailsOnUnknownCommand(t *testing.T) {
+	palette := NewCommandPalette(context.Background(), NewDebugClient(), config.Aliases{})
+	if err := palette.run("unknown"); err == nil {
+		t.Fatalf("Expected to fail, but it didn't")
+	}
+	if err := palette.run("next now"); err == nil {
+		t.Fatalf("Expected to fail when arguments are given to command without arguments, but it didn't")
+	}
+	if err := palette.run("   "); err != nil {
+		t.Fatalf("Did not expect to fail on empty command, but it did with %v", err)
+	}
+}
+
+func TestTransferPlaybackToDeviceNamed(t *testing.T) {
+	client := NewDebugClient()
+	device, err := transferPlaybackToDeviceNamed(context.Background(), client, "ipad")
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if device.ID == "" {
+		t.Fatalf("Expected the device to be returned")
+	}
+	if _, err := transferPlaybackToDeviceNamed(context.Background(), client, "Kitchen speaker"); err == nil {
+		t.Fatalf("Expected to fail for not existing device, but it didn't")
+	}
+}
+
+func TestCommandPaletteTellsWhatWasDone(t *testing.T) {
+	defer func(previous *StatusBar) { statusBar = previous }(statusBar)
+	status := NewStatusBar()
+	UseStatusBar(status)
+	palette := NewCommandPalette(context.Background(), NewDebugClient(), config.Aliases{})
+
+	palette.Entry.SetFocused(true)
+	palette.Entry.SetText("device ipad")
+	palette.Entry.OnKeyEvent(tui.KeyEvent{Key: tui.KeyEnter})
+	if text := status.message.Text(); text != "Playback transferred to iPad" {
+		t.Fatalf("Expected transfer to be told, got %q", text)
+	}
+	palette.Entry.SetText("unknown")
+	palette.Entry.OnKeyEvent(tui.KeyEvent{Key: tui.KeyEnter})
+	if text := status.message.Text(); text != `could not run command "unknown": unknown command "unknown"` || status.style != statusErrorStyle {
+		t.Fatalf("Expected error to be shown, got %q", text)
+	}
+}
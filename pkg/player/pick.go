@@ -0,0 +1,105 @@
+package player
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/zmb3/spotify"
+)
+
+// commandLibrary keeps the library listed by commands, so that only its changes are fetched.
+var commandLibrary *LibraryCache
+
+// UseLibraryCache makes commands listing the library, i.e. pick, use the cache.
+func UseLibraryCache(library *LibraryCache) {
+	commandLibrary = library
+}
+
+// pickLists list items of the library to pick from, as lines of their description and URI.
+var pickLists = map[string]func(context.Context, SpotifyClient) ([]pickLine, error){
+	"albums": func(ctx context.Context, client SpotifyClient) ([]pickLine, error) {
+		albums, err := commandLibrary.SavedAlbums(ctx, client)
+		lines := []pickLine{}
+		for _, album := range albums {
+			lines = append(lines, pickLine{artistsNames(album.Artists) + " – " + album.Name, album.URI})
+		}
+		return lines, err
+	},
+	"playlists": func(ctx context.Context, client SpotifyClient) ([]pickLine, error) {
+		playlists, err := fetchPlaylists(ctx, client)
+		lines := []pickLine{}
+		for _, playlist := range playlists {
+			lines = append(lines, pickLine{playlist.Name, playlist.URI})
+		}
+		return lines, err
+	},
+	"tracks": func(ctx context.Context, client SpotifyClient) ([]pickLine, error) {
+		tracks, err := commandLibrary.SavedTracks(ctx, client)
+		lines := []pickLine{}
+		for _, track := range tracks {
+			lines = append(lines, pickLine{artistsNames(track.Artists) + " – " + track.Name, track.URI})
+		}
+		return lines, err
+	},
+}
+
+type pickLine struct {
+	text string
+	uri  spotify.URI
+}
+
+// pickText keeps the description in its column, tabs and line breaks are replaced with spaces.
+var pickText = strings.NewReplacer("\t", " ", "\n", " ", "\r", " ")
+
+// pickCommand prints saved albums, playlists or Liked Songs as lines of their description and URI
+// separated by a tab, to be chosen from with rofi, dmenu or fzf and played with `play --uri -`.
+func pickCommand(ctx context.Context, client SpotifyClient, args []string, out io.Writer) error {
+	if len(args) != 1 || pickLists[args[0]] == nil {
+		return usageErrorf("pick command takes albums, playlists or tracks")
+	}
+	lines, err := pickLists[args[0]](ctx, client)
+	if err != nil {
+		return commandError(err, "could not list %s", args[0])
+	}
+	writer := bufio.NewWriter(out)
+	for _, line := range lines {
+		fmt.Fprintf(writer, "%s\t%s\n", pickText.Replace(line.text), line.uri)
+	}
+	return writer.Flush()
+}
+
+// ReadPickedURI replaces - given to --uri of the play command with the URI read from in, the
+// line picked from the output of pick or the URI alone. It is read by the application started
+// from the shell, as the command may be run by the daemon.
+func ReadPickedURI(name string, args []string, in io.Reader) ([]string, error) {
+	if name != "play" {
+		return args, nil
+	}
+	for i, arg := range args {
+		prefix := ""
+		switch {
+		case (arg == "--uri" || arg == "-uri") && i+1 < len(args) && args[i+1] == "-":
+			i++
+		case arg == "--uri=-" || arg == "-uri=-":
+			prefix = "--uri="
+		default:
+			continue
+		}
+		line, err := bufio.NewReader(in).ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, fmt.Errorf("could not read URI: %v", err)
+		}
+		// the picked line has description before the URI
+		uri := strings.TrimSpace(line[strings.LastIndex(line, "\t")+1:])
+		if uri == "" {
+			return nil, usageErrorf("nothing was picked")
+		}
+		replaced := append([]string{}, args...)
+		replaced[i] = prefix + uri
+		return replaced, nil
+	}
+	return args, nil
+}
@@ -0,0 +1,72 @@
+package player
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPickCommand(t *testing.T) {
+	out := &bytes.Buffer{}
+	if err := RunCommand(context.Background(), NewDebugClient(), "pick", []string{"playlists"}, out); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	expected := "Playlist Name 1\tspotify:playlist:playlist1\nPlaylist Name 2\tspotify:playlist:playlist2\nPlaylist Name 3\tspotify:playlist:playlist3\n"
+	if out.String() != expected {
+		t.Fatalf("Expected %q, got %q", expected, out.String())
+	}
+
+	out.Reset()
+	if err := RunCommand(context.Background(), NewDebugClient(), "pick", []string{"albums"}, out); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if line := strings.SplitN(out.String(), "\n", 2)[0]; line != "Artist Name 1 – Album Name 1\t" {
+		t.Fatalf("Expected artist and title of the first album, got %q", line)
+	}
+
+	if err := RunCommand(context.Background(), NewDebugClient(), "pick", []string{"shows"}, out); ExitCode(err) != ExitUsage {
+		t.Fatalf("Expected unknown list to be rejected, got %v", err)
+	}
+}
+
+func TestReadPickedURI(t *testing.T) {
+	for _, tc := range []struct {
+		args     []string
+		expected []string
+	}{
+		{[]string{"--uri", "-"}, []string{"--uri", "spotify:album:album1"}},
+		{[]string{"-uri=-"}, []string{"--uri=spotify:album:album1"}},
+		{[]string{"spotify:track:1"}, []string{"spotify:track:1"}},
+	} {
+		args, err := ReadPickedURI("play", tc.args, strings.NewReader("Radiohead – Kid A\tspotify:album:album1\n"))
+		if err != nil {
+			t.Fatalf("Did not expect to fail, but it did with %v", err)
+		}
+		if !reflect.DeepEqual(args, tc.expected) {
+			t.Fatalf("Expected arguments %v, got %v", tc.expected, args)
+		}
+	}
+
+	if _, err := ReadPickedURI("play", []string{"--uri", "-"}, strings.NewReader("")); ExitCode(err) != ExitUsage {
+		t.Fatalf("Expected nothing picked to be rejected, got %v", err)
+	}
+	if args, err := ReadPickedURI("queue", []string{"--uri", "-"}, strings.NewReader("")); err != nil || len(args) != 2 || args[1] != "-" {
+		t.Fatalf("Expected arguments of other commands to be left, got %v, %v", args, err)
+	}
+}
+
+func TestPlayCommandPlaysURIFlag(t *testing.T) {
+	client := &searchCommandClient{}
+	out := &bytes.Buffer{}
+	if err := RunCommand(context.Background(), client, "play", []string{"--uri", "spotify:album:album1"}, out); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if len(client.played) != 1 || *client.played[0].PlaybackContext != "spotify:album:album1" {
+		t.Fatalf("Expected the album to be played, got %v", client.played)
+	}
+	if err := RunCommand(context.Background(), client, "play", []string{"--uri", "spotify:album:album1", "spotify:track:1"}, out); ExitCode(err) != ExitUsage {
+		t.Fatalf("Expected both --uri and argument to be rejected, got %v", err)
+	}
+}
@@ -1,10 +1,14 @@
 package player
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/jedruniu/spotify-cli/pkg/ui"
 	"github.com/jedruniu/spotify-cli/pkg/web"
 
 	"github.com/marcusolsson/tui-go"
@@ -12,28 +16,88 @@ import (
 )
 
 type DevicesTable struct {
-	Table *tui.Table
-	box   *tui.Box
+	Table    ui.List
+	box      *tui.Box
+	ctx      context.Context
+	client   SpotifyClient
+	activeID spotify.ID
+	mu       sync.Mutex
+	devices  []spotify.PlayerDevice
 }
 
 type currentlyPlaying struct {
-	Box      tui.Widget
-	song     string
-	Devices  DevicesTable
-	Playback Playback
+	Box     tui.Widget
+	song    string
+	Devices *DevicesTable
+	// DevicesPane can hide the devices, see Panes
+	DevicesPane *Pane
+	Playback    Playback
+	NowPlaying  *NowPlaying
+}
+
+// NowPlaying is a label describing currently played item, which
+// can be concealed, e.g. not to give the answer away during the quiz.
+type NowPlaying struct {
+	Label     *tui.Label
+	mu        sync.Mutex
+	text      string
+	concealed bool
+}
+
+var concealedNowPlayingText = "???"
+
+// NewNowPlaying creates label describing currently played item.
+func NewNowPlaying() *NowPlaying {
+	label := tui.NewLabel("")
+	label.SetStyleName(nowPlayingStyle)
+	return &NowPlaying{Label: label}
+}
+
+// SetText changes description of currently played item, it is displayed
+// right away unless label is concealed.
+func (nowPlaying *NowPlaying) SetText(text string) {
+	nowPlaying.mu.Lock()
+	defer nowPlaying.mu.Unlock()
+	nowPlaying.text = text
+	if !nowPlaying.concealed {
+		nowPlaying.Label.SetText(text)
+	}
+}
+
+// Conceal hides or reveals description of currently played item.
+func (nowPlaying *NowPlaying) Conceal(concealed bool) {
+	nowPlaying.mu.Lock()
+	defer nowPlaying.mu.Unlock()
+	nowPlaying.concealed = concealed
+	if concealed {
+		nowPlaying.Label.SetText(concealedNowPlayingText)
+	} else {
+		nowPlaying.Label.SetText(nowPlaying.text)
+	}
 }
 
 type Playback struct {
-	Previous *tui.Button
-	Next     *tui.Button
-	Stop     *tui.Button
-	Play     *tui.Button
+	Previous ui.Button
+	Next     ui.Button
+	Stop     ui.Button
+	Play     ui.Button
 	Box      *tui.Box
 }
 
-// NewPlayback creates data structure representing current spotify playback.
-func NewPlayback(client SpotifyClient, playerStateChanges chan *web.WebPlaybackState, webPlayerID spotify.ID) currentlyPlaying {
-	currentlyPlayingLabel := tui.NewLabel("")
+// Buttons gives widgets of the buttons in the order they are laid out, i.e. to focus them.
+func (playback Playback) Buttons() []tui.Widget {
+	return []tui.Widget{
+		playback.Previous.Native().(tui.Widget),
+		playback.Play.Native().(tui.Widget),
+		playback.Stop.Native().(tui.Widget),
+		playback.Next.Native().(tui.Widget),
+	}
+}
+
+// NewPlayback creates data structure representing current spotify playback. Playback is transferred
+// to the device named defaultDevice, or to the web player when it is empty or there is no such device.
+func NewPlayback(ctx context.Context, client SpotifyClient, playerStateChanges chan *web.WebPlaybackState, webPlayerID spotify.ID, defaultDevice string) currentlyPlaying {
+	currentlyPlayingLabel := NewNowPlaying()
 	go func() {
 		for {
 			currentState := <-playerStateChanges
@@ -47,132 +111,254 @@ func NewPlayback(client SpotifyClient, playerStateChanges chan *web.WebPlaybackS
 		}
 	}()
 
-	updateCurrentlyPlayingLabel(client, currentlyPlayingLabel)
+	updateCurrentlyPlayingLabel(ctx, client, currentlyPlayingLabel)
 
-	// TODO handle error
-	_ = transferPlaybackToDevice(client, webPlayerID)
-	availableDevicesTable, err := createAvailableDevicesTable(client, webPlayerID)
+	activeID := webPlayerID
+	if defaultDevice != "" {
+		device, err := transferPlaybackToDeviceNamed(ctx, client, defaultDevice)
+		if err != nil {
+			log.Printf("Could not transfer playback to default device, falling back to web player with %s", err)
+		} else {
+			activeID = device.ID
+		}
+	}
+	if activeID == webPlayerID {
+		// TODO handle error
+		_ = transferPlaybackToDevice(ctx, client, webPlayerID)
+	}
+	availableDevicesTable, err := createAvailableDevicesTable(ctx, client, backend, activeID)
 	if err != nil {
-		log.Fatalf("err occured: %v", err)
+		log.Printf("Could not list devices with %s", err)
 	}
 
-	playbackButtons := createPlaybackButtons(client, currentlyPlayingLabel)
+	playbackButtons := createPlaybackButtons(ctx, client, backend, currentlyPlayingLabel)
 
-	currentlyPlayingBox := tui.NewHBox(currentlyPlayingLabel, availableDevicesTable.box, playbackButtons.Box)
+	devicesPane := NewPane(availableDevicesTable.box)
+	currentlyPlayingBox := tui.NewHBox(currentlyPlayingLabel.Label, devicesPane, playbackButtons.Box)
 	currentlyPlayingBox.SetBorder(true)
 	currentlyPlayingBox.SetTitle("Currently playing")
 	return currentlyPlaying{
-		Box:      currentlyPlayingBox,
-		Devices:  *availableDevicesTable,
-		Playback: playbackButtons,
+		Box:         currentlyPlayingBox,
+		Devices:     availableDevicesTable,
+		DevicesPane: devicesPane,
+		Playback:    playbackButtons,
+		NowPlaying:  currentlyPlayingLabel,
 	}
 }
 
-func updateCurrentlyPlayingLabel(client SpotifyClient, label *tui.Label) {
-	currentlyPlaying, err := client.PlayerCurrentlyPlaying()
+func updateCurrentlyPlayingLabel(ctx context.Context, client SpotifyClient, label *NowPlaying) {
+	currentlyPlaying, err := client.PlayerCurrentlyPlaying(ctx)
 	var currentSongName string
 	if err != nil {
 		log.Printf("could not fetch currently playing track - fallback to None, %s", err)
 		currentSongName = "None"
 	} else {
-		currentSongName = getTrackRepr(currentlyPlaying.Item)
+		currentSongName = getPlaybackItemRepr(currentlyPlaying)
 	}
 	label.SetText(currentSongName)
 }
 
-func createPlaybackButtons(client SpotifyClient, currentlyPlayingLabel *tui.Label) Playback {
-	playButton := tui.NewButton("[ ▷ Play]")
-	stopButton := tui.NewButton("[ ■ Stop]")
-	previousButton := tui.NewButton("[ |◄ Previous ]")
-	nextButton := tui.NewButton("[ ►| Next ]")
+func createPlaybackButtons(ctx context.Context, client SpotifyClient, backend ui.Backend, currentlyPlayingLabel *NowPlaying) Playback {
+	playButton := backend.NewButton("[ ▷ Play]")
+	stopButton := backend.NewButton("[ ■ Stop]")
+	previousButton := backend.NewButton("[ |◄ Previous ]")
+	nextButton := backend.NewButton("[ ►| Next ]")
 
-	playButton.OnActivated(func(btn *tui.Button) {
-		client.Play()
+	playButton.OnActivated(func() {
+		if err := client.Play(ctx); err != nil {
+			notifyError(fmt.Errorf("could not resume playback: %v", err))
+			return
+		}
 		time.Sleep(time.Millisecond * 500)
-		updateCurrentlyPlayingLabel(client, currentlyPlayingLabel)
+		updateCurrentlyPlayingLabel(ctx, client, currentlyPlayingLabel)
 	})
 
-	stopButton.OnActivated(func(*tui.Button) {
-		client.Pause()
+	stopButton.OnActivated(func() {
+		if err := client.Pause(ctx); err != nil {
+			notifyError(fmt.Errorf("could not pause playback: %v", err))
+		}
 	})
 
-	previousButton.OnActivated(func(*tui.Button) {
-		client.Previous()
+	previousButton.OnActivated(func() {
+		if err := client.Previous(ctx); err != nil {
+			notifyError(fmt.Errorf("could not play previous track: %v", err))
+			return
+		}
 		time.Sleep(time.Millisecond * 500)
-		updateCurrentlyPlayingLabel(client, currentlyPlayingLabel)
+		updateCurrentlyPlayingLabel(ctx, client, currentlyPlayingLabel)
 	})
 
-	nextButton.OnActivated(func(*tui.Button) {
-		client.Next()
+	nextButton.OnActivated(func() {
+		if err := client.Next(ctx); err != nil {
+			notifyError(fmt.Errorf("could not play next track: %v", err))
+			return
+		}
 		time.Sleep(time.Millisecond * 500)
-		updateCurrentlyPlayingLabel(client, currentlyPlayingLabel)
+		updateCurrentlyPlayingLabel(ctx, client, currentlyPlayingLabel)
 	})
 
-	buttons := tui.NewHBox(
-		tui.NewSpacer(),
-		tui.NewPadder(1, 0, previousButton),
-		tui.NewPadder(1, 0, playButton),
-		tui.NewPadder(1, 0, stopButton),
-		tui.NewPadder(1, 0, nextButton),
-	)
-	buttons.SetBorder(true)
-
-	return Playback{
+	playback := Playback{
 		Play:     playButton,
 		Stop:     stopButton,
 		Previous: previousButton,
 		Next:     nextButton,
-		Box:      buttons,
 	}
+	playback.Box = tui.NewHBox(tui.NewSpacer())
+	for _, button := range playback.Buttons() {
+		playback.Box.Append(tui.NewPadder(1, 0, clickable(button)))
+	}
+	playback.Box.SetBorder(true)
+	return playback
 }
 
-func createAvailableDevicesTable(client SpotifyClient, webPlayerID spotify.ID) (*DevicesTable, error) {
-	table := tui.NewTable(0, 0)
-	tableBox := tui.NewHBox(table)
+func createAvailableDevicesTable(ctx context.Context, client SpotifyClient, backend ui.Backend, activeID spotify.ID) (*DevicesTable, error) {
+	table := backend.NewList()
+	tableBox := tui.NewHBox(table.Native().(tui.Widget))
 	tableBox.SetTitle("Devices")
 	tableBox.SetBorder(true)
 
-	avalaibleDevices, err := client.PlayerDevices()
+	devices := &DevicesTable{box: tableBox, Table: table, ctx: ctx, client: client, activeID: activeID}
+	err := devices.Refresh()
+
+	table.OnItemActivated(func(selctedRow int) {
+		if selctedRow == 0 {
+			return // Selecting table header
+		}
+		devices.mu.Lock()
+		device := devices.devices[selctedRow-1]
+		devices.mu.Unlock()
+		if err := transferPlaybackToDevice(ctx, client, device.ID); err != nil {
+			notifyError(fmt.Errorf("could not transfer playback to %s: %v", device.Name, err))
+			return
+		}
+		notify("Playback transferred to %s", device.Name)
+	})
+
+	// table is given even when devices could not be listed, i.e. offline, so that they can be refreshed later
+	return devices, err
+}
+
+// Refresh fetches available devices again, i.e. to list devices which were turned on since.
+func (devices *DevicesTable) Refresh() error {
+	available, err := devices.client.PlayerDevices(devices.ctx)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	table.AppendRow(
-		tui.NewLabel("Name"),
-		tui.NewLabel("Type"),
-	)
-	for i, device := range avalaibleDevices {
-		table.AppendRow(
-			tui.NewLabel(device.Name),
-			tui.NewLabel(device.Type),
-		)
-		// we forced our web player to be the active one, but spotify backend
+	devices.mu.Lock()
+	defer devices.mu.Unlock()
+	devices.devices = available
+	selected := devices.Table.Selected()
+	devices.Table.RemoveRows()
+	devices.Table.AppendRow("Name", "Type")
+	for i, device := range available {
+		devices.Table.AppendRow(device.Name, device.Type)
+		// we forced the device to be the active one, but spotify backend
 		// has delays thus, instead of highlighting active device (which might be
-		// out of date), we highlight just our web player.
-		if device.ID == webPlayerID {
-			table.SetSelected(i + 1)
+		// out of date), we highlight just the device playback was transferred to.
+		if selected <= 0 && device.ID == devices.activeID {
+			selected = i + 1
 		}
 	}
+	if selected > len(available) {
+		selected = len(available)
+	}
+	devices.Table.SetSelected(selected)
+	return nil
+}
 
-	table.OnItemActivated(func(t *tui.Table) {
-		selctedRow := t.Selected()
-		if selctedRow == 0 {
-			return // Selecting table header
+func transferPlaybackToDevice(ctx context.Context, client SpotifyClient, id spotify.ID) error {
+	return client.TransferPlayback(ctx, id, true)
+}
+
+// transferPlaybackToDeviceNamed transfers playback to the device matching the given name or ID,
+// see matchDevice, and returns it.
+func transferPlaybackToDeviceNamed(ctx context.Context, client SpotifyClient, name string) (spotify.PlayerDevice, error) {
+	devices, err := client.PlayerDevices(ctx)
+	if err != nil {
+		return spotify.PlayerDevice{}, commandError(err, "could not fetch available devices")
+	}
+	device, err := matchDevice(devices, name)
+	if err != nil {
+		return spotify.PlayerDevice{}, err
+	}
+	return device, transferPlaybackToDevice(ctx, client, device.ID)
+}
+
+// matchDevice finds the device with the given ID or name, ignoring case. When there is none,
+// the name can be a part of the name of the device, i.e. "kitch" of "Kitchen Speaker", or its
+// letters in order, i.e. "kspk". It fails when several devices match as well.
+func matchDevice(devices []spotify.PlayerDevice, name string) (spotify.PlayerDevice, error) {
+	for _, device := range devices {
+		if strings.EqualFold(device.Name, name) || string(device.ID) == name {
+			return device, nil
 		}
-		transferPlaybackToDevice(client, avalaibleDevices[selctedRow-1].ID)
-	})
+	}
+	query := strings.ToLower(name)
+	for _, matches := range []func(string) bool{
+		func(deviceName string) bool { return strings.Contains(deviceName, query) },
+		func(deviceName string) bool { return containsInOrder(deviceName, query) },
+	} {
+		matched := []spotify.PlayerDevice{}
+		names := []string{}
+		for _, device := range devices {
+			if matches(strings.ToLower(device.Name)) {
+				matched = append(matched, device)
+				names = append(names, device.Name)
+			}
+		}
+		switch {
+		case len(matched) == 1:
+			return matched[0], nil
+		case len(matched) > 1:
+			return spotify.PlayerDevice{}, usageErrorf("%q matches devices %s, give more of the name", name, strings.Join(names, ", "))
+		}
+	}
+	return spotify.PlayerDevice{}, exitErrorf(ExitNotFound, "there is no device named %q", name)
+}
 
-	return &DevicesTable{box: tableBox, Table: table}, nil
+// containsInOrder tells whether all letters of the query are in the text, in the same order.
+func containsInOrder(text, query string) bool {
+	rest := []rune(query)
+	for _, r := range text {
+		if len(rest) == 0 {
+			break
+		}
+		if r == rest[0] {
+			rest = rest[1:]
+		}
+	}
+	return len(rest) == 0
 }
 
-func transferPlaybackToDevice(client SpotifyClient, id spotify.ID) error {
-	return client.TransferPlayback(id, true)
+func getPlaybackItemRepr(item *PlaybackItem) string {
+	switch {
+	case item.Item != nil:
+		return getTrackRepr(item.Item)
+	case item.Episode != nil:
+		return getEpisodeRepr(item.Episode)
+	default:
+		return "None"
+	}
 }
 
 func getTrackRepr(track *spotify.FullTrack) string {
+	artist := ""
+	if len(track.Artists) > 0 {
+		artist = track.Artists[0].Name
+	}
 	return fmt.Sprintf(
 		"%s\n%s\n%s",
 		track.Name,
 		track.Album.Name,
-		track.Artists[0].Name,
+		artist,
+	)
+}
+
+func getEpisodeRepr(episode *spotify.EpisodePage) string {
+	return fmt.Sprintf(
+		"%s\n%s\n%s",
+		episode.Name,
+		episode.Show.Name,
+		episode.Show.Publisher,
 	)
 }
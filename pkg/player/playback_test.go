@@ -1,8 +1,14 @@
 package player
 
 import (
+	"context"
+	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/jedruniu/spotify-cli/pkg/ui"
+
+	"github.com/marcusolsson/tui-go"
 	"github.com/zmb3/spotify"
 )
 
@@ -13,24 +19,20 @@ func TestGetTrackRepr(t *testing.T) {
 	}{
 		{
 			&spotify.FullTrack{
-				spotify.SimpleTrack{
+				SimpleTrack: spotify.SimpleTrack{
 					Name:    "Name",
 					Artists: []spotify.SimpleArtist{{Name: "art1"}, {Name: "art2"}},
 				},
-				spotify.SimpleAlbum{Name: "alb"},
-				nil,
-				0,
+				Album: spotify.SimpleAlbum{Name: "alb"},
 			}, "Name\nalb\nart1",
 		},
 		{
 			&spotify.FullTrack{
-				spotify.SimpleTrack{
+				SimpleTrack: spotify.SimpleTrack{
 					Name:    "Name",
 					Artists: []spotify.SimpleArtist{{Name: "art"}},
 				},
-				spotify.SimpleAlbum{Name: "alb"},
-				nil,
-				0,
+				Album: spotify.SimpleAlbum{Name: "alb"},
 			}, "Name\nalb\nart",
 		},
 	}
@@ -41,3 +43,184 @@ func TestGetTrackRepr(t *testing.T) {
 		}
 	}
 }
+
+func TestGetPlaybackItemRepr(t *testing.T) {
+	var tests = []struct {
+		item *PlaybackItem
+		repr string
+	}{
+		{
+			&PlaybackItem{CurrentlyPlaying: spotify.CurrentlyPlaying{Item: &spotify.FullTrack{
+				SimpleTrack: spotify.SimpleTrack{Name: "Name"},
+				Album:       spotify.SimpleAlbum{Name: "alb"},
+			}}}, "Name\nalb\n",
+		},
+		{
+			&PlaybackItem{Episode: &spotify.EpisodePage{
+				Name: "Episode",
+				Show: spotify.SimpleShow{Name: "Show", Publisher: "Publisher"},
+			}}, "Episode\nShow\nPublisher",
+		},
+		{
+			&PlaybackItem{}, "None",
+		},
+	}
+	for _, test := range tests {
+		got := getPlaybackItemRepr(test.item)
+		if got != test.repr {
+			t.Errorf("Got: %v, want: %v", got, test.repr)
+		}
+	}
+}
+
+// changingDevicesClient lists devices which can be changed, i.e. turned on.
+type changingDevicesClient struct {
+	DebugClient
+	devices []spotify.PlayerDevice
+}
+
+func (client *changingDevicesClient) PlayerDevices(ctx context.Context) ([]spotify.PlayerDevice, error) {
+	return client.devices, nil
+}
+
+func TestDevicesTableRefresh(t *testing.T) {
+	client := &changingDevicesClient{DebugClient: NewDebugClient().(DebugClient)}
+	client.devices = []spotify.PlayerDevice{{ID: "web", Name: "spotify-cli"}, {ID: "mac", Name: "Mac"}}
+	devices, err := createAvailableDevicesTable(context.Background(), client, backend, "mac")
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if devices.Table.Selected() != 2 {
+		t.Fatalf("Expected device playback was transferred to be selected, got row %d", devices.Table.Selected())
+	}
+
+	client.devices = append([]spotify.PlayerDevice{{ID: "kitchen", Name: "Kitchen"}}, client.devices...)
+	if err := devices.Refresh(); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if len(devices.devices) != 3 || devices.Table.Selected() != 2 {
+		t.Fatalf("Expected new device to be listed and selection to be kept, got %v and row %d", devices.devices, devices.Table.Selected())
+	}
+}
+
+// fakeBackend creates widgets which are driven by tests instead of the terminal.
+type fakeBackend struct{}
+
+type fakeLabel struct {
+	text string
+}
+
+type fakeButton struct {
+	text      string
+	activated func()
+}
+
+type fakeList struct {
+	rows      [][]string
+	selected  int
+	changed   func(int)
+	activated func(int)
+}
+
+func (fakeBackend) NewLabel(text string) ui.Label   { return &fakeLabel{text: text} }
+func (fakeBackend) NewButton(text string) ui.Button { return &fakeButton{text: text} }
+func (fakeBackend) NewList() ui.List                { return &fakeList{} }
+func (l *fakeLabel) Native() interface{}            { return tui.NewLabel(l.text) }
+func (l *fakeLabel) Text() string                   { return l.text }
+func (l *fakeLabel) SetText(text string)            { l.text = text }
+func (b *fakeButton) Native() interface{}           { return tui.NewButton(b.text) }
+func (b *fakeButton) OnActivated(fn func())         { b.activated = fn }
+func (l *fakeList) Native() interface{}             { return tui.NewSpacer() }
+func (l *fakeList) AppendRow(cells ...string)       { l.rows = append(l.rows, cells) }
+func (l *fakeList) RemoveRows()                     { l.rows = nil }
+func (l *fakeList) Selected() int                   { return l.selected }
+func (l *fakeList) SetSelected(row int)             { l.selected = row }
+func (l *fakeList) OnSelectionChanged(fn func(int)) { l.changed = fn }
+func (l *fakeList) OnItemActivated(fn func(int))    { l.activated = fn }
+func (l *fakeList) Select(row int) {
+	l.selected = row
+	if l.changed != nil {
+		l.changed(row)
+	}
+}
+
+// recordingClient records playback being paused and transferred.
+type recordingClient struct {
+	changingDevicesClient
+	paused      bool
+	transferred spotify.ID
+}
+
+func (client *recordingClient) Pause(ctx context.Context) error {
+	client.paused = true
+	return nil
+}
+
+func (client *recordingClient) TransferPlayback(ctx context.Context, id spotify.ID, play bool) error {
+	client.transferred = id
+	return nil
+}
+
+func TestPlaybackOnFakeBackend(t *testing.T) {
+	client := &recordingClient{changingDevicesClient: changingDevicesClient{DebugClient: NewDebugClient().(DebugClient)}}
+	client.devices = []spotify.PlayerDevice{{ID: "web", Name: "spotify-cli", Type: "Computer"}, {ID: "mac", Name: "Mac", Type: "Computer"}}
+
+	playback := createPlaybackButtons(context.Background(), client, fakeBackend{}, NewNowPlaying())
+	if len(playback.Buttons()) != 4 {
+		t.Fatalf("Expected 4 buttons, got %d", len(playback.Buttons()))
+	}
+	playback.Stop.(*fakeButton).activated()
+	if !client.paused {
+		t.Fatalf("Expected playback to be paused with the Stop button")
+	}
+
+	devices, err := createAvailableDevicesTable(context.Background(), client, fakeBackend{}, "mac")
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	list := devices.Table.(*fakeList)
+	expected := [][]string{{"Name", "Type"}, {"spotify-cli", "Computer"}, {"Mac", "Computer"}}
+	if !reflect.DeepEqual(list.rows, expected) || list.selected != 2 {
+		t.Fatalf("Expected devices %v with row 2 selected, got %v and row %d", expected, list.rows, list.selected)
+	}
+	list.activated(0)
+	if client.transferred != "" {
+		t.Fatalf("Expected header not to transfer playback, but it was transferred to %s", client.transferred)
+	}
+	list.activated(1)
+	if client.transferred != "web" {
+		t.Fatalf("Expected playback to be transferred to web, got %q", client.transferred)
+	}
+}
+
+func TestMatchDevice(t *testing.T) {
+	devices := []spotify.PlayerDevice{
+		{ID: "kitchen", Name: "Kitchen Speaker"},
+		{ID: "kitchen-tv", Name: "Kitchen TV"},
+		{ID: "laptop", Name: "Laptop"},
+	}
+	for name, expected := range map[string]spotify.ID{
+		"kitchen tv": "kitchen-tv",
+		"laptop":     "laptop",
+		"kitchen":    "kitchen",
+		"speak":      "kitchen",
+		"kspk":       "kitchen",
+		"LAP":        "laptop",
+	} {
+		device, err := matchDevice(devices, name)
+		if err != nil {
+			t.Fatalf("Did not expect to fail for %q, but it did with %v", name, err)
+		}
+		if device.ID != expected {
+			t.Fatalf("Expected %q to match %s, got %s", name, expected, device.ID)
+		}
+	}
+
+	_, err := matchDevice(devices, "kitch")
+	if ExitCode(err) != ExitUsage || !strings.Contains(err.Error(), "Kitchen Speaker, Kitchen TV") {
+		t.Fatalf("Expected both kitchen devices to be named, got %v", err)
+	}
+	if _, err := matchDevice(devices, "phone"); ExitCode(err) != ExitNotFound {
+		t.Fatalf("Expected no device to be found, got %v", err)
+	}
+}
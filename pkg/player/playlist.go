@@ -0,0 +1,466 @@
+package player
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/marcusolsson/tui-go"
+	"github.com/zmb3/spotify"
+)
+
+// Playlist represents view with tracks of a single playlist, which is
+// edited within PlaylistSession.
+type Playlist struct {
+	Focusables []tui.Widget
+	Box        *tui.Box
+	ctx        context.Context
+	client     SpotifyClient
+	session    *PlaylistSession
+	table      *countedTable
+	status     *tui.Label
+	// confirmation is asked before tracks are removed.
+	confirmation *Confirmation
+	// moving tells whether selected track is moved, instead of the selection, on key press.
+	moving bool
+	onEdit func(spotify.ID) error
+}
+
+var (
+	playlistRemoveKey   = 'd'
+	playlistMoveModeKey = 'm'
+	playlistMoveDownKey = 'J'
+	playlistMoveUpKey   = 'K'
+	playlistEditKey     = 'e'
+)
+
+// playlistTable is a table of playlist tracks which removes the selected
+// track on key press, once the removal is confirmed.
+// In move mode selected track is moved up and down within the playlist.
+type playlistTable struct {
+	*countedTable
+	playlist *Playlist
+}
+
+// OnKeyEvent edits the playlist when table is focused, other keys are handled by the table.
+func (t *playlistTable) OnKeyEvent(ev tui.KeyEvent) {
+	if !t.IsFocused() {
+		t.Table.OnKeyEvent(ev)
+		return
+	}
+	// -1 for the header
+	selected := t.Selected() - 1
+	if ev.Key == tui.KeyRune {
+		switch {
+		case ev.Rune == playlistRemoveKey:
+			t.playlist.askRemove(selected)
+			return
+		case ev.Rune == playlistEditKey && t.playlist.onEdit != nil && t.playlist.session != nil:
+			if err := t.playlist.onEdit(t.playlist.session.playlistID); err != nil {
+				t.playlist.status.SetText(fmt.Sprintf("Could not edit playlist: %v", err))
+			}
+			return
+		case ev.Rune == playlistMoveModeKey:
+			t.playlist.status.SetText(t.playlist.toggleMoving())
+			return
+		case ev.Rune == playlistMoveDownKey && t.playlist.moving:
+			t.playlist.status.SetText(t.playlist.move(selected, 1))
+			return
+		case ev.Rune == playlistMoveUpKey && t.playlist.moving:
+			t.playlist.status.SetText(t.playlist.move(selected, -1))
+			return
+		}
+	}
+	t.Table.OnKeyEvent(ev)
+}
+
+// NewPlaylist creates view of playlist tracks, it is empty until a playlist is opened.
+// Removal of tracks is confirmed with the given confirmation.
+func NewPlaylist(ctx context.Context, client SpotifyClient, confirmation *Confirmation) *Playlist {
+	table := newCountedTable()
+	table.SetColumnStretch(1, 4)
+	status := tui.NewLabel("")
+
+	playlist := &Playlist{
+		ctx:          ctx,
+		client:       client,
+		table:        table,
+		status:       status,
+		confirmation: confirmation,
+	}
+	box := tui.NewVBox(&playlistTable{countedTable: table, playlist: playlist}, tui.NewSpacer(), status)
+	box.SetTitle("Playlist")
+	box.SetBorder(true)
+	box.SetSizePolicy(tui.Expanding, tui.Expanding)
+
+	playlist.Focusables = []tui.Widget{table}
+	playlist.Box = box
+	return playlist
+}
+
+// OnEdit sets function called with ID of the opened playlist when its details
+// should be edited, returned error is shown to the user.
+func (playlist *Playlist) OnEdit(fn func(spotify.ID) error) {
+	playlist.onEdit = fn
+}
+
+// Open loads tracks of the playlist and displays them.
+func (playlist *Playlist) Open(playlistID spotify.ID, name string) error {
+	session, err := NewPlaylistSession(playlist.ctx, playlist.client, playlistID)
+	if err != nil {
+		return err
+	}
+	session.OnConflict(playlist.status.SetText)
+	playlist.session = session
+	playlist.moving = false
+	playlist.Box.SetTitle("Playlist - " + name)
+	playlist.status.SetText("")
+	playlist.render()
+	return nil
+}
+
+// OpenImported opens playlist created by the import, status reports lines for which no track was found.
+func (playlist *Playlist) OpenImported(imported *PlaylistImport) error {
+	err := playlist.Open(imported.Playlist.ID, imported.Playlist.Name)
+	if err != nil {
+		return err
+	}
+	playlist.status.SetText(imported.Summary())
+	return nil
+}
+
+func (playlist *Playlist) render() {
+	playlist.table.RemoveRows()
+	playlist.table.AppendRow(
+		tui.NewLabel("#"),
+		tui.NewLabel("Title"),
+		tui.NewLabel("Artist"),
+	)
+	for i, track := range playlist.session.Tracks {
+		playlist.table.AppendRow(
+			tui.NewLabel(fmt.Sprintf("%d", i+1)),
+			tui.NewLabel(trimWithCommasIfTooLong(track.Track.Name, uiColumnWidth)),
+			tui.NewLabel(trimWithCommasIfTooLong(artistsNames(track.Track.Artists), uiColumnWidth)),
+		)
+	}
+	if len(playlist.session.Tracks) == 0 {
+		playlist.status.SetText("Playlist is empty")
+	}
+}
+
+// askRemove asks to confirm removal of the track at the given position,
+// the track is removed once the removal is confirmed.
+func (playlist *Playlist) askRemove(position int) {
+	if playlist.session == nil || position < 0 || position >= len(playlist.session.Tracks) {
+		return
+	}
+	track := playlist.session.Tracks[position].Track
+	playlist.confirmation.Ask(fmt.Sprintf("Remove %s from the playlist?", track.Name), func() {
+		playlist.status.SetText(playlist.remove(position))
+	})
+}
+
+// remove removes the track at the given position, returned text describes the outcome.
+func (playlist *Playlist) remove(position int) string {
+	if position < 0 || position >= len(playlist.session.Tracks) {
+		return ""
+	}
+	name := playlist.session.Tracks[position].Track.Name
+	status, err := playlist.edit("Removed "+name, func() error {
+		return playlist.session.Remove(position)
+	})
+	if err != nil {
+		return fmt.Sprintf("Could not remove %s: %v", name, err)
+	}
+	if len(playlist.session.Tracks) == 0 {
+		return "Playlist is empty"
+	}
+	playlist.selectTrack(position)
+	return status
+}
+
+// toggleMoving switches between moving the selection and moving the selected track,
+// returned text describes the current mode.
+func (playlist *Playlist) toggleMoving() string {
+	playlist.moving = !playlist.moving
+	if playlist.moving {
+		return fmt.Sprintf("Move mode: %c - move track down, %c - move track up, %c - leave move mode",
+			playlistMoveDownKey, playlistMoveUpKey, playlistMoveModeKey)
+	}
+	return "Left move mode"
+}
+
+// move moves track at the given position by offset positions, the moved
+// track stays selected. Returned text describes the outcome.
+func (playlist *Playlist) move(position, offset int) string {
+	if playlist.session == nil || position < 0 || position >= len(playlist.session.Tracks) {
+		return ""
+	}
+	target := position + offset
+	if target < 0 || target >= len(playlist.session.Tracks) {
+		return ""
+	}
+	name := playlist.session.Tracks[position].Track.Name
+	// Spotify inserts the track before the given position of the playlist, which still holds the track.
+	insertBefore := target
+	if offset > 0 {
+		insertBefore = target + 1
+	}
+	status, err := playlist.edit(fmt.Sprintf("Moved %s to position %d", name, target+1), func() error {
+		return playlist.session.Move(position, insertBefore)
+	})
+	if err != nil {
+		return fmt.Sprintf("Could not move %s: %v", name, err)
+	}
+	playlist.selectTrack(target)
+	return status
+}
+
+// edit makes the change and renders the playlist again, returned text is the
+// given description, or the conflict which happened while making the change.
+func (playlist *Playlist) edit(description string, change func() error) (string, error) {
+	status := description
+	playlist.session.OnConflict(func(message string) { status = message })
+	defer playlist.session.OnConflict(playlist.status.SetText)
+	err := change()
+	if err != nil {
+		return "", err
+	}
+	playlist.render()
+	return status, nil
+}
+
+// selectTrack selects the track at the given position, or the last one when there are fewer tracks.
+func (playlist *Playlist) selectTrack(position int) {
+	if position >= len(playlist.session.Tracks) {
+		position = len(playlist.session.Tracks) - 1
+	}
+	playlist.table.SetSelected(position + 1)
+}
+
+// PlaylistForm represents view in which name, description and visibility
+// of a new playlist are given, or of an edited playlist owned by the user.
+// Collaborative playlists, which others can edit as well, are always private.
+type PlaylistForm struct {
+	Focusables    []tui.Widget
+	Box           *tui.Box
+	Name          *tui.Entry
+	ctx           context.Context
+	client        SpotifyClient
+	description   *tui.Entry
+	public        bool
+	collaborative bool
+	visibility    *tui.Label
+	status        *tui.Label
+	// edited is the playlist being edited, nil when a new playlist is created.
+	edited    *spotify.FullPlaylist
+	onCreated func(*spotify.FullPlaylist)
+	onEdited  func(*spotify.FullPlaylist)
+}
+
+var (
+	playlistFormCreateHelp = "Type the name and press Enter to create the playlist"
+	playlistFormEditHelp   = "Press Enter to save changes of the playlist"
+)
+
+// NewPlaylistForm creates form creating playlists of the current user.
+func NewPlaylistForm(ctx context.Context, client SpotifyClient) *PlaylistForm {
+	name := tui.NewEntry()
+	name.SetSizePolicy(tui.Expanding, tui.Minimum)
+	description := tui.NewEntry()
+	description.SetSizePolicy(tui.Expanding, tui.Minimum)
+	visibility := tui.NewLabel("")
+	toggle := tui.NewButton("[ Public/Private ]")
+	collaborativeToggle := tui.NewButton("[ Collaborative ]")
+	status := tui.NewLabel(playlistFormCreateHelp)
+
+	form := &PlaylistForm{
+		Name:        name,
+		ctx:         ctx,
+		client:      client,
+		description: description,
+		visibility:  visibility,
+		status:      status,
+	}
+	form.setPublic(false)
+	toggle.OnActivated(func(*tui.Button) {
+		form.setPublic(!form.public)
+	})
+	collaborativeToggle.OnActivated(func(*tui.Button) {
+		form.setCollaborative(!form.collaborative)
+	})
+	name.OnSubmit(func(*tui.Entry) { form.submit() })
+	description.OnSubmit(func(*tui.Entry) { form.submit() })
+
+	nameBox := tui.NewHBox(tui.NewLabel("Name: "), clickable(name))
+	descriptionBox := tui.NewHBox(tui.NewLabel("Description: "), clickable(description))
+	visibilityBox := tui.NewHBox(visibility, tui.NewPadder(1, 0, clickable(toggle)), clickable(collaborativeToggle), tui.NewSpacer())
+	box := tui.NewVBox(nameBox, descriptionBox, visibilityBox, status, tui.NewSpacer())
+	box.SetTitle("New playlist")
+	box.SetBorder(true)
+	box.SetSizePolicy(tui.Expanding, tui.Expanding)
+
+	form.Focusables = []tui.Widget{name, description, toggle, collaborativeToggle}
+	form.Box = box
+	return form
+}
+
+// OnCreated sets function called with each created playlist.
+func (form *PlaylistForm) OnCreated(fn func(*spotify.FullPlaylist)) {
+	form.onCreated = fn
+}
+
+// OnEdited sets function called with each playlist which changes were saved.
+func (form *PlaylistForm) OnEdited(fn func(*spotify.FullPlaylist)) {
+	form.onEdited = fn
+}
+
+// New clears the form, so that it creates a new playlist with the given name.
+func (form *PlaylistForm) New(name string) {
+	form.edited = nil
+	form.Name.SetText(name)
+	form.description.SetText("")
+	form.setPublic(false)
+	form.setCollaborative(false)
+	form.Box.SetTitle("New playlist")
+	form.status.SetText(playlistFormCreateHelp)
+}
+
+// Edit fills the form with details of the playlist, which are changed on submit.
+// Only playlists owned by the current user can be edited.
+func (form *PlaylistForm) Edit(playlistID spotify.ID) error {
+	playlist, err := form.client.GetPlaylistOpt(form.ctx, playlistID, "id,name,description,public,collaborative,owner(id)")
+	if err != nil {
+		return fmt.Errorf("could not fetch playlist details: %v", err)
+	}
+	user, err := form.client.CurrentUser(form.ctx)
+	if err != nil {
+		return fmt.Errorf("could not fetch current user: %v", err)
+	}
+	if playlist.Owner.ID != user.ID {
+		return fmt.Errorf("only playlists you own can be edited")
+	}
+	form.edited = playlist
+	form.Name.SetText(playlist.Name)
+	form.description.SetText(playlist.Description)
+	form.setPublic(playlist.IsPublic)
+	form.setCollaborative(playlist.Collaborative)
+	form.Box.SetTitle("Edit playlist - " + playlist.Name)
+	form.status.SetText(playlistFormEditHelp)
+	return nil
+}
+
+// setPublic makes the playlist public or private, public playlist is not collaborative.
+func (form *PlaylistForm) setPublic(public bool) {
+	form.public = public
+	if public {
+		form.collaborative = false
+	}
+	form.showVisibility()
+}
+
+// setCollaborative makes the playlist collaborative, which makes it private as well.
+func (form *PlaylistForm) setCollaborative(collaborative bool) {
+	form.collaborative = collaborative
+	if collaborative {
+		form.public = false
+	}
+	form.showVisibility()
+}
+
+func (form *PlaylistForm) showVisibility() {
+	switch {
+	case form.collaborative:
+		form.visibility.SetText("Visibility: private, collaborative")
+	case form.public:
+		form.visibility.SetText("Visibility: public")
+	default:
+		form.visibility.SetText("Visibility: private")
+	}
+}
+
+// submit creates the playlist or saves changes of the edited one.
+func (form *PlaylistForm) submit() {
+	if form.edited != nil {
+		playlist, err := form.save(form.Name.Text())
+		if err != nil {
+			form.status.SetText(fmt.Sprintf("Could not save playlist: %v", err))
+			return
+		}
+		form.status.SetText("Saved " + playlist.Name)
+		if form.onEdited != nil {
+			form.onEdited(playlist)
+		}
+		return
+	}
+	playlist, err := form.create(form.Name.Text())
+	if err != nil {
+		form.status.SetText(fmt.Sprintf("Could not create playlist: %v", err))
+		return
+	}
+	form.Name.SetText("")
+	form.description.SetText("")
+	form.status.SetText("Created " + playlist.Name)
+	if form.onCreated != nil {
+		form.onCreated(playlist)
+	}
+}
+
+func (form *PlaylistForm) create(name string) (*spotify.FullPlaylist, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("playlist name is required")
+	}
+	user, err := form.client.CurrentUser(form.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch current user: %v", err)
+	}
+	playlist, err := form.client.CreatePlaylistForUser(form.ctx, user.ID, name, strings.TrimSpace(form.description.Text()), form.public)
+	if err != nil {
+		return nil, fmt.Errorf("could not create playlist %s: %v", name, err)
+	}
+	if form.collaborative {
+		if err := form.client.ChangePlaylistCollaborative(form.ctx, playlist.ID, true); err != nil {
+			return nil, fmt.Errorf("could not make playlist %s collaborative: %v", name, err)
+		}
+		playlist.Collaborative = true
+	}
+	return playlist, nil
+}
+
+// save changes name, description and visibility of the edited playlist. Playlist stops
+// being collaborative before it is made public, and becomes collaborative once it is private.
+func (form *PlaylistForm) save(name string) (*spotify.FullPlaylist, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("playlist name is required")
+	}
+	changeCollaborative := func() error {
+		if form.collaborative == form.edited.Collaborative {
+			return nil
+		}
+		err := form.client.ChangePlaylistCollaborative(form.ctx, form.edited.ID, form.collaborative)
+		if err != nil {
+			return fmt.Errorf("could not change collaborative state of playlist %s: %v", form.edited.Name, err)
+		}
+		form.edited.Collaborative = form.collaborative
+		return nil
+	}
+	if !form.collaborative {
+		if err := changeCollaborative(); err != nil {
+			return nil, err
+		}
+	}
+	description := strings.TrimSpace(form.description.Text())
+	err := form.client.ChangePlaylistNameAccessAndDescription(form.ctx, form.edited.ID, name, description, form.public)
+	if err != nil {
+		return nil, fmt.Errorf("could not change details of playlist %s: %v", form.edited.Name, err)
+	}
+	form.edited.Name = name
+	form.edited.Description = description
+	form.edited.IsPublic = form.public
+	if err := changeCollaborative(); err != nil {
+		return nil, err
+	}
+	return form.edited, nil
+}
@@ -0,0 +1,264 @@
+package player
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/zmb3/spotify"
+)
+
+// PlaylistSession keeps local copy of the playlist and sends every change
+// along with the snapshot ID of that copy. When the playlist was changed in
+// the meantime (i.e. on another device), the copy is re-fetched, pending
+// change is rebased onto it and the user is informed about the conflict.
+type PlaylistSession struct {
+	ctx        context.Context
+	client     SpotifyClient
+	playlistID spotify.ID
+	snapshotID string
+	Tracks     []spotify.PlaylistTrack
+	onConflict func(string)
+}
+
+var (
+	playlistTracksPageSize = 100
+	// playlistAddBatchSize is the limit of tracks added at once by Spotify.
+	playlistAddBatchSize = 100
+)
+
+// NewPlaylistSession creates session for editing the playlist, its tracks are loaded right away.
+func NewPlaylistSession(ctx context.Context, client SpotifyClient, playlistID spotify.ID) (*PlaylistSession, error) {
+	session := &PlaylistSession{
+		ctx:        ctx,
+		client:     client,
+		playlistID: playlistID,
+		onConflict: func(message string) { log.Print(message) },
+	}
+	err := session.Reload()
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// OnConflict sets function informing the user about changes rebased
+// because the playlist was edited concurrently.
+func (session *PlaylistSession) OnConflict(fn func(string)) {
+	session.onConflict = fn
+}
+
+// Reload fetches snapshot ID and all tracks of the playlist.
+func (session *PlaylistSession) Reload() error {
+	snapshotID, err := session.currentSnapshotID()
+	if err != nil {
+		return err
+	}
+	tracks, err := fetchPlaylistTracks(session.ctx, session.client, session.playlistID)
+	if err != nil {
+		return err
+	}
+	session.snapshotID = snapshotID
+	session.Tracks = tracks
+	return nil
+}
+
+// fetchPlaylistTracks fetches all tracks of the playlist.
+func fetchPlaylistTracks(ctx context.Context, client PlaylistEditor, playlistID spotify.ID) ([]spotify.PlaylistTrack, error) {
+	tracks := []spotify.PlaylistTrack{}
+	for {
+		offset := len(tracks)
+		page, err := client.GetPlaylistTracksOpt(ctx, playlistID, &spotify.Options{Limit: &playlistTracksPageSize, Offset: &offset}, "")
+		if err != nil {
+			return nil, fmt.Errorf("could not fetch playlist tracks: %v", err)
+		}
+		tracks = append(tracks, page.Tracks...)
+		if page.Next == "" || len(page.Tracks) == 0 {
+			return tracks, nil
+		}
+	}
+}
+
+// Add appends tracks to the playlist.
+func (session *PlaylistSession) Add(trackIDs ...spotify.ID) error {
+	_, err := session.checkConflict()
+	if err != nil {
+		return err
+	}
+	snapshotID, err := addTracksToPlaylist(session.ctx, session.client, session.playlistID, trackIDs)
+	if err != nil {
+		return err
+	}
+	return session.applied(snapshotID)
+}
+
+// addTracksToPlaylist appends tracks in batches accepted by Spotify,
+// snapshot ID after the last batch is returned.
+func addTracksToPlaylist(ctx context.Context, client PlaylistEditor, playlistID spotify.ID, trackIDs []spotify.ID) (string, error) {
+	snapshotID := ""
+	for start := 0; start < len(trackIDs); start += playlistAddBatchSize {
+		end := start + playlistAddBatchSize
+		if end > len(trackIDs) {
+			end = len(trackIDs)
+		}
+		var err error
+		snapshotID, err = client.AddTracksToPlaylist(ctx, playlistID, trackIDs[start:end]...)
+		if err != nil {
+			return "", fmt.Errorf("could not add tracks to playlist: %v", err)
+		}
+	}
+	return snapshotID, nil
+}
+
+// Remove removes tracks at the given positions of the local copy.
+func (session *PlaylistSession) Remove(positions ...int) error {
+	ids, err := session.trackIDsAt(positions)
+	if err != nil {
+		return err
+	}
+	conflict, err := session.checkConflict()
+	if err != nil {
+		return err
+	}
+	if conflict {
+		positions = rebasePositions(ids, positions, session.Tracks)
+		if len(positions) == 0 {
+			session.onConflict("Playlist was changed on another device, tracks to remove are already gone")
+			return nil
+		}
+		session.onConflict("Playlist was changed on another device, removing tracks from its current version")
+	}
+
+	// Positions of the same track have to be given within a single entry.
+	indexByURI := map[spotify.URI]int{}
+	toRemove := []spotify.TrackToRemove{}
+	for _, position := range positions {
+		uri := session.Tracks[position].Track.URI
+		if i, ok := indexByURI[uri]; ok {
+			toRemove[i].Positions = append(toRemove[i].Positions, position)
+			continue
+		}
+		indexByURI[uri] = len(toRemove)
+		toRemove = append(toRemove, spotify.TrackToRemove{URI: string(uri), Positions: []int{position}})
+	}
+	snapshotID, err := session.client.RemoveTracksFromPlaylistOpt(session.ctx, session.playlistID, toRemove, session.snapshotID)
+	if err != nil {
+		return fmt.Errorf("could not remove tracks from playlist: %v", err)
+	}
+	return session.applied(snapshotID)
+}
+
+// Move moves track at the given position of the local copy, so that it is placed before insertBefore.
+func (session *PlaylistSession) Move(position, insertBefore int) error {
+	ids, err := session.trackIDsAt([]int{position})
+	if err != nil {
+		return err
+	}
+	conflict, err := session.checkConflict()
+	if err != nil {
+		return err
+	}
+	if conflict {
+		rebased := rebasePositions(ids, []int{position}, session.Tracks)
+		if len(rebased) == 0 {
+			session.onConflict("Playlist was changed on another device, track to move is gone")
+			return nil
+		}
+		insertBefore += rebased[0] - position
+		if insertBefore < 0 {
+			insertBefore = 0
+		}
+		if insertBefore > len(session.Tracks) {
+			insertBefore = len(session.Tracks)
+		}
+		position = rebased[0]
+		session.onConflict("Playlist was changed on another device, moving track within its current version")
+	}
+
+	snapshotID, err := session.client.ReorderPlaylistTracks(session.ctx, session.playlistID, spotify.PlaylistReorderOptions{
+		RangeStart:   position,
+		RangeLength:  1,
+		InsertBefore: insertBefore,
+		SnapshotID:   session.snapshotID,
+	})
+	if err != nil {
+		return fmt.Errorf("could not reorder playlist tracks: %v", err)
+	}
+	return session.applied(snapshotID)
+}
+
+// checkConflict tells whether playlist was changed since the local copy was
+// fetched, in such case the copy is reloaded.
+func (session *PlaylistSession) checkConflict() (bool, error) {
+	snapshotID, err := session.currentSnapshotID()
+	if err != nil {
+		return false, err
+	}
+	if snapshotID == session.snapshotID {
+		return false, nil
+	}
+	return true, session.Reload()
+}
+
+// applied refreshes local copy after the change identified by snapshotID was made.
+func (session *PlaylistSession) applied(snapshotID string) error {
+	err := session.Reload()
+	if err != nil {
+		return err
+	}
+	if snapshotID != "" && snapshotID != session.snapshotID {
+		// Yet another change was made right after ours.
+		session.onConflict("Playlist was changed on another device right after the change")
+	}
+	return nil
+}
+
+func (session *PlaylistSession) currentSnapshotID() (string, error) {
+	playlist, err := session.client.GetPlaylistOpt(session.ctx, session.playlistID, "snapshot_id")
+	if err != nil {
+		return "", fmt.Errorf("could not fetch playlist snapshot: %v", err)
+	}
+	return playlist.SnapshotID, nil
+}
+
+func (session *PlaylistSession) trackIDsAt(positions []int) ([]spotify.ID, error) {
+	ids := make([]spotify.ID, 0, len(positions))
+	for _, position := range positions {
+		if position < 0 || position >= len(session.Tracks) {
+			return nil, fmt.Errorf("there is no track at position %d", position)
+		}
+		ids = append(ids, session.Tracks[position].Track.ID)
+	}
+	return ids, nil
+}
+
+// rebasePositions finds where tracks, which were at old positions, are placed in
+// the current tracks. Track which appears more than once is matched with its
+// occurrence closest to the old position, tracks which are gone are skipped.
+func rebasePositions(ids []spotify.ID, oldPositions []int, tracks []spotify.PlaylistTrack) []int {
+	taken := map[int]bool{}
+	rebased := []int{}
+	for i, id := range ids {
+		best := -1
+		for position, track := range tracks {
+			if track.Track.ID != id || taken[position] {
+				continue
+			}
+			if best == -1 || abs(position-oldPositions[i]) < abs(best-oldPositions[i]) {
+				best = position
+			}
+		}
+		if best != -1 {
+			taken[best] = true
+			rebased = append(rebased, best)
+		}
+	}
+	return rebased
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
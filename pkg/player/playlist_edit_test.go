@@ -0,0 +1,96 @@
+package player
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/zmb3/spotify"
+)
+
+func newTestPlaylistSession(t *testing.T) (*PlaylistSession, *DebugPlaylistEditor, *[]string) {
+	editor := NewDebugPlaylistEditor()
+	client := DebugClient{PlaylistEditor: editor}
+	session, err := NewPlaylistSession(context.Background(), client, "playlist")
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	conflicts := &[]string{}
+	session.OnConflict(func(message string) {
+		*conflicts = append(*conflicts, message)
+	})
+	return session, editor, conflicts
+}
+
+func trackIDs(tracks []spotify.PlaylistTrack) []spotify.ID {
+	ids := []spotify.ID{}
+	for _, track := range tracks {
+		ids = append(ids, track.Track.ID)
+	}
+	return ids
+}
+
+func TestPlaylistSessionRemoveWithoutConflict(t *testing.T) {
+	session, _, conflicts := newTestPlaylistSession(t)
+	if err := session.Remove(0, 2); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if len(session.Tracks) != 8 || session.Tracks[0].Track.ID != "playlisttrack2" {
+		t.Fatalf("Expected first and third tracks to be removed, got %v", trackIDs(session.Tracks))
+	}
+	if len(*conflicts) != 0 {
+		t.Fatalf("Did not expect conflicts, got %v", *conflicts)
+	}
+}
+
+func TestPlaylistSessionRebasesRemoveOnConflict(t *testing.T) {
+	session, editor, conflicts := newTestPlaylistSession(t)
+	// another device moves the first track to the end
+	editor.ReorderPlaylistTracks(context.Background(), "playlist", spotify.PlaylistReorderOptions{RangeStart: 0, InsertBefore: 10})
+
+	if err := session.Remove(1); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	for _, track := range session.Tracks {
+		if track.Track.ID == "playlisttrack2" {
+			t.Fatalf("Expected the second track to be removed from the current version, got %v", trackIDs(session.Tracks))
+		}
+	}
+	if len(session.Tracks) != 9 || session.Tracks[8].Track.ID != "playlisttrack1" {
+		t.Fatalf("Expected change from another device to be kept, got %v", trackIDs(session.Tracks))
+	}
+	if len(*conflicts) != 1 {
+		t.Fatalf("Expected user to be informed about the conflict, got %v", *conflicts)
+	}
+}
+
+func TestPlaylistSessionRebasesMoveOnConflict(t *testing.T) {
+	session, editor, conflicts := newTestPlaylistSession(t)
+	// another device adds a track at the end and removes the first one
+	editor.AddTracksToPlaylist(context.Background(), "playlist", "new")
+	snapshot, _ := editor.GetPlaylistOpt(context.Background(), "playlist", "")
+	editor.RemoveTracksFromPlaylistOpt(context.Background(), "playlist", []spotify.TrackToRemove{{URI: "spotify:track:playlisttrack1", Positions: []int{0}}}, snapshot.SnapshotID)
+
+	// move the third track before the second one
+	if err := session.Move(2, 1); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	expected := []spotify.ID{"playlisttrack3", "playlisttrack2", "playlisttrack4"}
+	if !reflect.DeepEqual(trackIDs(session.Tracks)[:3], expected) {
+		t.Fatalf("Expected tracks to start with %v, got %v", expected, trackIDs(session.Tracks))
+	}
+	if len(*conflicts) != 1 {
+		t.Fatalf("Expected user to be informed about the conflict, got %v", *conflicts)
+	}
+}
+
+func TestRebasePositionsPrefersClosestDuplicate(t *testing.T) {
+	tracks := []spotify.PlaylistTrack{}
+	for _, id := range []spotify.ID{"a", "b", "a", "c", "a"} {
+		tracks = append(tracks, debugPlaylistTrack(id))
+	}
+	rebased := rebasePositions([]spotify.ID{"a", "gone"}, []int{3, 0}, tracks)
+	if !reflect.DeepEqual(rebased, []int{2}) {
+		t.Fatalf("Expected to match the closest occurrence and skip the missing track, got %v", rebased)
+	}
+}
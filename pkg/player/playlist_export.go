@@ -0,0 +1,116 @@
+package player
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/zmb3/spotify"
+)
+
+// playlistExportFormats encode an exported playlist, by the name given with --format.
+var playlistExportFormats = map[string]func(*ExportedPlaylist) ([]byte, error){
+	"json": (*ExportedPlaylist).JSON,
+	"csv":  (*ExportedPlaylist).CSV,
+	"m3u":  (*ExportedPlaylist).M3U,
+}
+
+// spotifyID matches base-62 IDs of Spotify items.
+var spotifyID = regexp.MustCompile(`^[A-Za-z0-9]{22}$`)
+
+// playlistCSVHeader names columns of the CSV export of a playlist, each row is its track.
+var playlistCSVHeader = []string{"uri", "name", "artists", "album", "duration_ms", "added_at"}
+
+// findPlaylist finds playlist of the user by its name, ID, URI or link. Playlists which the user
+// does not follow can be found by ID, URI or link as well.
+func findPlaylist(ctx context.Context, client SpotifyClient, value string) (spotify.SimplePlaylist, error) {
+	id := spotify.ID("")
+	if spotifyID.MatchString(value) {
+		id = spotify.ID(value)
+	} else if uri, err := ParseURI(ctx, value); err == nil {
+		if id = uriID(uri, "playlist"); id == "" {
+			return spotify.SimplePlaylist{}, usageErrorf("%s is not a playlist", value)
+		}
+	}
+	playlists, err := fetchPlaylists(ctx, client)
+	if err != nil {
+		return spotify.SimplePlaylist{}, commandError(err, "could not find playlist")
+	}
+	named := []spotify.SimplePlaylist{}
+	for _, playlist := range playlists {
+		if playlist.ID == id {
+			return playlist, nil
+		}
+		if strings.EqualFold(playlist.Name, value) {
+			named = append(named, playlist)
+		}
+	}
+	switch {
+	case len(named) == 1:
+		return named[0], nil
+	case len(named) > 1:
+		return spotify.SimplePlaylist{}, usageErrorf("there are %d playlists named %q, give ID of the one to export", len(named), value)
+	case id == "":
+		return spotify.SimplePlaylist{}, exitErrorf(ExitNotFound, "there is no playlist named %q", value)
+	}
+	// i.e. playlists shared by others, which are not followed
+	playlist, err := client.GetPlaylistOpt(ctx, id, "")
+	if err != nil {
+		if ExitCode(err) == ExitNotFound {
+			return spotify.SimplePlaylist{}, exitErrorf(ExitNotFound, "there is no playlist %s", value)
+		}
+		return spotify.SimplePlaylist{}, commandError(err, "could not find playlist")
+	}
+	return playlist.SimplePlaylist, nil
+}
+
+// ExportPlaylist fetches all tracks of the playlist, page by page.
+func ExportPlaylist(ctx context.Context, client SpotifyClient, playlist spotify.SimplePlaylist) (*ExportedPlaylist, error) {
+	tracks, err := fetchPlaylistTracks(ctx, client, playlist.ID)
+	if err != nil {
+		return nil, commandError(err, "could not export playlist %s", playlist.Name)
+	}
+	exported := &ExportedPlaylist{URI: playlist.URI, Name: playlist.Name, Owner: playlist.Owner.DisplayName, Tracks: []ExportedTrack{}}
+	for _, track := range tracks {
+		exported.Tracks = append(exported.Tracks, exportedTrack(track.Track, track.AddedAt))
+	}
+	return exported, nil
+}
+
+// JSON encodes the playlist as indented JSON.
+func (playlist *ExportedPlaylist) JSON() ([]byte, error) {
+	data, err := json.MarshalIndent(playlist, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("could not encode playlist export: %v", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// CSV encodes tracks of the playlist as CSV with playlistCSVHeader columns.
+func (playlist *ExportedPlaylist) CSV() ([]byte, error) {
+	rows := [][]string{playlistCSVHeader}
+	for _, track := range playlist.Tracks {
+		rows = append(rows, []string{string(track.URI), track.Name, track.Artists, track.Album, strconv.Itoa(track.Duration), track.AddedAt})
+	}
+	buf := &bytes.Buffer{}
+	if err := csv.NewWriter(buf).WriteAll(rows); err != nil {
+		return nil, fmt.Errorf("could not encode playlist export: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// M3U encodes the playlist as extended M3U, with Spotify URIs of tracks as their locations,
+// which players able to play them, i.e. mopidy, understand.
+func (playlist *ExportedPlaylist) M3U() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "#EXTM3U\n#PLAYLIST:%s\n", playlist.Name)
+	for _, track := range playlist.Tracks {
+		fmt.Fprintf(buf, "#EXTINF:%d,%s - %s\n%s\n", track.Duration/1000, track.Artists, track.Name, track.URI)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,82 @@
+package player
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPlaylistExportCommand(t *testing.T) {
+	defer func(previous int) { playlistTracksPageSize = previous }(playlistTracksPageSize)
+	playlistTracksPageSize = 3
+
+	out := &bytes.Buffer{}
+	if err := RunCommand(context.Background(), NewDebugClient(), "playlist", []string{"export", "playlist", "name", "2"}, out); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	exported := ExportedPlaylist{}
+	if err := json.Unmarshal(out.Bytes(), &exported); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if exported.Name != "Playlist Name 2" || len(exported.Tracks) != 10 || exported.Tracks[9].Name != "Track playlist2track10" {
+		t.Fatalf("Expected all 10 tracks of Playlist Name 2, got %+v", exported)
+	}
+
+	out.Reset()
+	if err := RunCommand(context.Background(), NewDebugClient(), "playlist", []string{"export", "--format", "m3u", "spotify:playlist:playlist1"}, out); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	lines := strings.Split(out.String(), "\n")
+	if expected := []string{"#EXTM3U", "#PLAYLIST:Playlist Name 1", "#EXTINF:0, - Track playlist1track1", "spotify:track:playlist1track1"}; strings.Join(lines[:4], "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("Expected M3U starting with %q, got %q", expected, lines[:4])
+	}
+}
+
+func TestPlaylistExportCommandToFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spotify-cli-playlist-export")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "playlist.csv")
+
+	out := &bytes.Buffer{}
+	// flags can follow the name
+	if err := RunCommand(context.Background(), NewDebugClient(), "playlist", []string{"export", "Playlist Name 3", "--format", "csv", "--output", path}, out); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if expected := "Exported 10 tracks of Playlist Name 3 to " + path + "\n"; out.String() != expected {
+		t.Fatalf("Expected %q, got %q", expected, out.String())
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	rows := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(rows) != 11 || rows[0] != "uri,name,artists,album,duration_ms,added_at" || rows[1] != "spotify:track:playlist3track1,Track playlist3track1,,,0," {
+		t.Fatalf("Expected header and 10 tracks, got %q", rows)
+	}
+}
+
+func TestPlaylistExportCommandFailures(t *testing.T) {
+	for _, tc := range []struct {
+		args     []string
+		expected int
+	}{
+		{[]string{"import"}, ExitUsage},
+		{[]string{"export"}, ExitUsage},
+		{[]string{"export", "--format", "xspf", "Playlist Name 1"}, ExitUsage},
+		{[]string{"export", "spotify:album:album1"}, ExitUsage},
+		{[]string{"export", "Road trip"}, ExitNotFound},
+	} {
+		err := RunCommand(context.Background(), NewDebugClient(), "playlist", tc.args, &bytes.Buffer{})
+		if code := ExitCode(err); code != tc.expected {
+			t.Errorf("Expected %v to exit with %d, got %d with %v", tc.args, tc.expected, code, err)
+		}
+	}
+}
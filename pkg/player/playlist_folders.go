@@ -0,0 +1,234 @@
+package player
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jedruniu/spotify-cli/pkg/config"
+
+	"github.com/marcusolsson/tui-go"
+	"github.com/zmb3/spotify"
+)
+
+// PlaylistFolders represents view listing the user's playlists as a tree of
+// folders defined in the config, followed by playlists which are in none of them.
+// Pressing Enter on a folder collapses or expands it, on a playlist opens it.
+type PlaylistFolders struct {
+	Focusables []tui.Widget
+	Box        *tui.Box
+	ctx        context.Context
+	client     SpotifyClient
+	table      *countedTable
+	status     *tui.Label
+	folders    []config.PlaylistFolder
+	playlists  []spotify.SimplePlaylist
+	// rows are folders and playlists as listed in the table, row by row.
+	rows      []playlistTreeRow
+	onOpen    func(spotify.SimplePlaylist)
+	onChanged func([]config.PlaylistFolder)
+}
+
+// playlistTreeRow is a folder header when playlist is nil, or a playlist
+// in the folder, folder is -1 for playlists which are in none of them.
+type playlistTreeRow struct {
+	folder   int
+	playlist *spotify.SimplePlaylist
+}
+
+var (
+	playlistFolderExpanded  = "▾"
+	playlistFolderCollapsed = "▸"
+)
+
+// NewPlaylistFolders creates view of playlists grouped in the given folders, it is empty until refreshed.
+func NewPlaylistFolders(ctx context.Context, client SpotifyClient, folders []config.PlaylistFolder) *PlaylistFolders {
+	table := newCountedTable()
+	status := tui.NewLabel("Press Enter to open the playlist, or to collapse and expand the folder")
+
+	tree := &PlaylistFolders{
+		ctx:     ctx,
+		client:  client,
+		table:   table,
+		status:  status,
+		folders: append([]config.PlaylistFolder{}, folders...),
+	}
+	table.OnItemActivated(func(t *tui.Table) {
+		tree.activate(t.Selected())
+	})
+
+	box := tui.NewVBox(table, tui.NewSpacer(), status)
+	box.SetTitle("Playlists")
+	box.SetBorder(true)
+	box.SetSizePolicy(tui.Expanding, tui.Expanding)
+
+	tree.Focusables = []tui.Widget{table}
+	tree.Box = box
+	return tree
+}
+
+// OnOpen sets function called with the playlist chosen to be opened.
+func (tree *PlaylistFolders) OnOpen(fn func(spotify.SimplePlaylist)) {
+	tree.onOpen = fn
+}
+
+// OnChanged sets function called with folders each time they are changed,
+// i.e. collapsed, so that they can be saved to the config.
+func (tree *PlaylistFolders) OnChanged(fn func([]config.PlaylistFolder)) {
+	tree.onChanged = fn
+}
+
+// Refresh fetches all the playlists of the user and lists them in folders.
+func (tree *PlaylistFolders) Refresh() error {
+	playlists, err := fetchPlaylists(tree.ctx, tree.client)
+	if err != nil {
+		return err
+	}
+	tree.playlists = playlists
+	tree.render()
+	return nil
+}
+
+// inFolder tells whether the playlist is listed in the folder by its ID or name.
+func inFolder(folder config.PlaylistFolder, playlist spotify.SimplePlaylist) bool {
+	for _, entry := range folder.Playlists {
+		if entry == string(playlist.ID) || entry == playlist.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// layout lists playlists of each folder in the order they are given in the config,
+// unless the folder is collapsed, and then playlists which are in none of the folders.
+func (tree *PlaylistFolders) layout() []playlistTreeRow {
+	rows := []playlistTreeRow{}
+	grouped := map[spotify.ID]bool{}
+	for i, folder := range tree.folders {
+		rows = append(rows, playlistTreeRow{folder: i})
+		for _, entry := range folder.Playlists {
+			for j := range tree.playlists {
+				playlist := &tree.playlists[j]
+				if entry != string(playlist.ID) && entry != playlist.Name {
+					continue
+				}
+				grouped[playlist.ID] = true
+				if !folder.Collapsed {
+					rows = append(rows, playlistTreeRow{folder: i, playlist: playlist})
+				}
+				break
+			}
+		}
+	}
+	for i := range tree.playlists {
+		if !grouped[tree.playlists[i].ID] {
+			rows = append(rows, playlistTreeRow{folder: -1, playlist: &tree.playlists[i]})
+		}
+	}
+	return rows
+}
+
+func (tree *PlaylistFolders) render() {
+	selected := tree.table.Selected()
+	tree.rows = tree.layout()
+	tree.table.RemoveRows()
+	for _, row := range tree.rows {
+		var text string
+		switch {
+		case row.playlist == nil:
+			folder := tree.folders[row.folder]
+			marker := playlistFolderExpanded
+			if folder.Collapsed {
+				marker = playlistFolderCollapsed
+			}
+			count := 0
+			for _, playlist := range tree.playlists {
+				if inFolder(folder, playlist) {
+					count++
+				}
+			}
+			text = fmt.Sprintf("%s %s (%d)", marker, folder.Name, count)
+		case row.folder >= 0:
+			text = "    " + row.playlist.Name
+		default:
+			text = row.playlist.Name
+		}
+		tree.table.AppendRow(tui.NewLabel(trimWithCommasIfTooLong(text, 2*uiColumnWidth)))
+	}
+	if selected >= len(tree.rows) {
+		selected = len(tree.rows) - 1
+	}
+	if selected < 0 && len(tree.rows) > 0 {
+		selected = 0
+	}
+	tree.table.SetSelected(selected)
+}
+
+// activate collapses or expands the folder at the given row, or opens the playlist.
+func (tree *PlaylistFolders) activate(row int) {
+	if row < 0 || row >= len(tree.rows) {
+		return
+	}
+	if playlist := tree.rows[row].playlist; playlist != nil {
+		if tree.onOpen != nil {
+			tree.onOpen(*playlist)
+		}
+		return
+	}
+	folder := &tree.folders[tree.rows[row].folder]
+	folder.Collapsed = !folder.Collapsed
+	tree.render()
+	tree.changed()
+}
+
+// MoveSelected moves the selected playlist to the folder with the given name, which
+// is created when there is none. With empty name playlist is taken out of its folder.
+func (tree *PlaylistFolders) MoveSelected(name string) error {
+	row := tree.table.Selected()
+	if row < 0 || row >= len(tree.rows) || tree.rows[row].playlist == nil {
+		return fmt.Errorf("select a playlist in the playlists view first")
+	}
+	playlist := *tree.rows[row].playlist
+	name = strings.TrimSpace(name)
+	target := -1
+	for i := range tree.folders {
+		folder := &tree.folders[i]
+		entries := []string{}
+		for _, entry := range folder.Playlists {
+			if entry != string(playlist.ID) && entry != playlist.Name {
+				entries = append(entries, entry)
+			}
+		}
+		folder.Playlists = entries
+		if folder.Name == name {
+			target = i
+		}
+	}
+	if name != "" {
+		if target < 0 {
+			tree.folders = append(tree.folders, config.PlaylistFolder{Name: name})
+			target = len(tree.folders) - 1
+		}
+		tree.folders[target].Playlists = append(tree.folders[target].Playlists, string(playlist.ID))
+		tree.folders[target].Collapsed = false
+	}
+	tree.render()
+	for i, row := range tree.rows {
+		if row.playlist != nil && row.playlist.ID == playlist.ID {
+			tree.table.SetSelected(i)
+		}
+	}
+	if name == "" {
+		tree.status.SetText(fmt.Sprintf("Took %s out of its folder", playlist.Name))
+	} else {
+		tree.status.SetText(fmt.Sprintf("Moved %s to %s", playlist.Name, name))
+	}
+	tree.changed()
+	return nil
+}
+
+func (tree *PlaylistFolders) changed() {
+	if tree.onChanged != nil {
+		tree.onChanged(append([]config.PlaylistFolder{}, tree.folders...))
+	}
+}
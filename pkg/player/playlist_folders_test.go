@@ -0,0 +1,99 @@
+package player
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/jedruniu/spotify-cli/pkg/config"
+
+	"github.com/zmb3/spotify"
+)
+
+func playlistTreeTexts(tree *PlaylistFolders) []string {
+	texts := []string{}
+	for _, row := range tree.rows {
+		switch {
+		case row.playlist == nil:
+			texts = append(texts, "folder "+tree.folders[row.folder].Name)
+		default:
+			texts = append(texts, string(row.playlist.ID))
+		}
+	}
+	return texts
+}
+
+func TestPlaylistFoldersListsTree(t *testing.T) {
+	tree := NewPlaylistFolders(context.Background(), NewDebugClient(), []config.PlaylistFolder{
+		{Name: "Running", Playlists: []string{"playlist3", "Playlist Name 1", "missing"}},
+		{Name: "Empty"},
+	})
+	opened := []spotify.ID{}
+	tree.OnOpen(func(playlist spotify.SimplePlaylist) {
+		opened = append(opened, playlist.ID)
+	})
+	var saved []config.PlaylistFolder
+	tree.OnChanged(func(folders []config.PlaylistFolder) {
+		saved = folders
+	})
+	if err := tree.Refresh(); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	expected := []string{"folder Running", "playlist3", "playlist1", "folder Empty", "playlist2"}
+	if texts := playlistTreeTexts(tree); !reflect.DeepEqual(texts, expected) {
+		t.Fatalf("Expected tree %v, got %v", expected, texts)
+	}
+
+	tree.activate(1)
+	if !reflect.DeepEqual(opened, []spotify.ID{"playlist3"}) {
+		t.Fatalf("Expected playlist3 to be opened, got %v", opened)
+	}
+	tree.activate(0)
+	expected = []string{"folder Running", "folder Empty", "playlist2"}
+	if texts := playlistTreeTexts(tree); !reflect.DeepEqual(texts, expected) {
+		t.Fatalf("Expected folder to be collapsed to %v, got %v", expected, texts)
+	}
+	if len(saved) != 2 || !saved[0].Collapsed {
+		t.Fatalf("Expected collapsed folder to be saved, got %v", saved)
+	}
+}
+
+func TestPlaylistFoldersMoveSelected(t *testing.T) {
+	tree := NewPlaylistFolders(context.Background(), NewDebugClient(), []config.PlaylistFolder{
+		{Name: "Running", Playlists: []string{"playlist3"}},
+	})
+	var saved []config.PlaylistFolder
+	tree.OnChanged(func(folders []config.PlaylistFolder) {
+		saved = folders
+	})
+	if err := tree.Refresh(); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	tree.table.SetSelected(0)
+	if err := tree.MoveSelected("Running"); err == nil {
+		t.Fatalf("Expected to fail moving a folder, but it didn't")
+	}
+
+	tree.table.SetSelected(1)
+	if err := tree.MoveSelected("Chill"); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	expected := []string{"folder Running", "folder Chill", "playlist3", "playlist1", "playlist2"}
+	if texts := playlistTreeTexts(tree); !reflect.DeepEqual(texts, expected) {
+		t.Fatalf("Expected tree %v, got %v", expected, texts)
+	}
+	if tree.table.Selected() != 2 {
+		t.Fatalf("Expected moved playlist to stay selected, got row %d", tree.table.Selected())
+	}
+	if len(saved) != 2 || !reflect.DeepEqual(saved[1].Playlists, []string{"playlist3"}) {
+		t.Fatalf("Expected new folder to be saved, got %v", saved)
+	}
+
+	if err := tree.MoveSelected(""); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	expected = []string{"folder Running", "folder Chill", "playlist1", "playlist2", "playlist3"}
+	if texts := playlistTreeTexts(tree); !reflect.DeepEqual(texts, expected) {
+		t.Fatalf("Expected playlist to be taken out of the folder, got %v", texts)
+	}
+}
@@ -0,0 +1,112 @@
+package player
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/zmb3/spotify"
+)
+
+// PlaylistImport is the outcome of importing a playlist from a file.
+type PlaylistImport struct {
+	Playlist *spotify.FullPlaylist
+	Added    int
+	// Unmatched are lines for which no track was found, along with their numbers.
+	Unmatched []string
+}
+
+// ImportPlaylistFromFile creates private playlist with tracks listed in the file,
+// see ImportPlaylist. Without the name, playlist is named after the file.
+func ImportPlaylistFromFile(ctx context.Context, client SpotifyClient, path, name string) (*PlaylistImport, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open playlist file: %v", err)
+	}
+	defer f.Close()
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return ImportPlaylist(ctx, client, name, f)
+}
+
+// ImportPlaylist creates private playlist with tracks listed line by line, either
+// as Spotify URIs or links, or as "Artist - Title" which are searched for. Empty
+// lines and lines starting with # are skipped. The playlist is not created when
+// none of the tracks is found.
+func ImportPlaylist(ctx context.Context, client SpotifyClient, name string, lines io.Reader) (*PlaylistImport, error) {
+	imported := &PlaylistImport{}
+	trackIDs := []spotify.ID{}
+	scanner := bufio.NewScanner(lines)
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		id, err := resolveTrack(ctx, client, line)
+		if err != nil {
+			return nil, fmt.Errorf("could not resolve line %d: %v", number, err)
+		}
+		if id == "" {
+			imported.Unmatched = append(imported.Unmatched, fmt.Sprintf("line %d: %s", number, line))
+			continue
+		}
+		trackIDs = append(trackIDs, id)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read playlist file: %v", err)
+	}
+	if len(trackIDs) == 0 {
+		return nil, fmt.Errorf("none of the tracks was found, unmatched: %s", strings.Join(imported.Unmatched, "; "))
+	}
+
+	user, err := client.CurrentUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch current user: %v", err)
+	}
+	playlist, err := client.CreatePlaylistForUser(ctx, user.ID, name, "Imported with spotify-cli", false)
+	if err != nil {
+		return nil, fmt.Errorf("could not create playlist %s: %v", name, err)
+	}
+	imported.Playlist = playlist
+	_, err = addTracksToPlaylist(ctx, client, playlist.ID, trackIDs)
+	if err != nil {
+		return nil, err
+	}
+	imported.Added = len(trackIDs)
+	return imported, nil
+}
+
+// resolveTrack returns ID of the track given by URI or link, or the first one found
+// by "Artist - Title" or any other text. Empty ID is returned when nothing was found.
+func resolveTrack(ctx context.Context, client SpotifyClient, line string) (spotify.ID, error) {
+	if uri, err := ParseURI(ctx, line); err == nil && trackID(uri) != "" {
+		return trackID(uri), nil
+	}
+	query := line
+	if parts := strings.SplitN(line, " - ", 2); len(parts) == 2 {
+		unquote := strings.NewReplacer(`"`, "")
+		query = fmt.Sprintf(`artist:"%s" track:"%s"`, unquote.Replace(strings.TrimSpace(parts[0])), unquote.Replace(strings.TrimSpace(parts[1])))
+	}
+	result, err := client.Search(ctx, query, spotify.SearchTypeTrack)
+	if err != nil {
+		return "", fmt.Errorf("could not search for %s: %v", line, err)
+	}
+	if result == nil || result.Tracks == nil || len(result.Tracks.Tracks) == 0 {
+		return "", nil
+	}
+	return result.Tracks.Tracks[0].ID, nil
+}
+
+// Summary describes the outcome of the import.
+func (imported *PlaylistImport) Summary() string {
+	summary := fmt.Sprintf("Imported %d tracks to %s", imported.Added, imported.Playlist.Name)
+	if len(imported.Unmatched) > 0 {
+		summary += fmt.Sprintf(", %d lines unmatched: %s", len(imported.Unmatched), strings.Join(imported.Unmatched, "; "))
+	}
+	return summary
+}
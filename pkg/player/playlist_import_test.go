@@ -0,0 +1,61 @@
+package player
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zmb3/spotify"
+)
+
+type fakeImportSearcher struct {
+	queries []string
+}
+
+func (fake *fakeImportSearcher) Search(ctx context.Context, query string, t spotify.SearchType) (*spotify.SearchResult, error) {
+	fake.queries = append(fake.queries, query)
+	result := &spotify.SearchResult{Tracks: &spotify.FullTrackPage{}}
+	if strings.Contains(query, "Queen") {
+		track := spotify.FullTrack{}
+		track.ID = "found"
+		result.Tracks.Tracks = append(result.Tracks.Tracks, track)
+	}
+	return result, nil
+}
+
+func TestImportPlaylist(t *testing.T) {
+	searcher := &fakeImportSearcher{}
+	client := NewDebugClient().(DebugClient)
+	client.Searcher = searcher
+	lines := strings.Join([]string{
+		"# exported from another service",
+		"spotify:track:uri",
+		"https://open.spotify.com/track/link?si=abc",
+		"",
+		`Queen - Don't Stop Me "Now"`,
+		"Nobody - Nothing",
+	}, "\n")
+
+	imported, err := ImportPlaylist(context.Background(), client, "Imported", strings.NewReader(lines))
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if !reflect.DeepEqual(searcher.queries, []string{`artist:"Queen" track:"Don't Stop Me Now"`, `artist:"Nobody" track:"Nothing"`}) {
+		t.Fatalf("Unexpected search queries: %v", searcher.queries)
+	}
+	if imported.Added != 3 || !reflect.DeepEqual(imported.Unmatched, []string{"line 6: Nobody - Nothing"}) {
+		t.Fatalf("Expected 3 tracks to be added and one line unmatched, got %s", imported.Summary())
+	}
+	tracks, err := fetchPlaylistTracks(context.Background(), client, imported.Playlist.ID)
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if ids := trackIDs(tracks); !reflect.DeepEqual(ids, []spotify.ID{"uri", "link", "found"}) {
+		t.Fatalf("Expected matched tracks in the playlist, got %v", ids)
+	}
+
+	if _, err := ImportPlaylist(context.Background(), client, "Empty", strings.NewReader("Nobody - Nothing")); err == nil {
+		t.Fatalf("Expected to fail when none of the tracks is found")
+	}
+}
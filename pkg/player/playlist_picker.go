@@ -0,0 +1,162 @@
+package player
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/marcusolsson/tui-go"
+	"github.com/zmb3/spotify"
+)
+
+// PlaylistPicker represents view in which one of the user's playlists is
+// chosen, picked tracks are appended to it. Playlists are narrowed down
+// to the ones with names containing text typed in the filter.
+type PlaylistPicker struct {
+	Focusables []tui.Widget
+	Box        *tui.Box
+	ctx        context.Context
+	client     SpotifyClient
+	filter     *tui.Entry
+	table      *countedTable
+	status     *tui.Label
+	playlists  []spotify.SimplePlaylist
+	shown      []spotify.SimplePlaylist
+	trackIDs   []spotify.ID
+}
+
+var (
+	playlistPickerPageSize = 50
+	// playlistCollaborativeMark marks playlists which others can edit as well.
+	playlistCollaborativeMark = "⇄"
+)
+
+// NewPlaylistPicker creates view choosing playlist to add tracks to, it is empty until tracks are picked.
+func NewPlaylistPicker(ctx context.Context, client SpotifyClient) *PlaylistPicker {
+	filter := tui.NewEntry()
+	filter.SetSizePolicy(tui.Expanding, tui.Minimum)
+	table := newCountedTable()
+	table.SetColumnStretch(1, 1)
+	status := tui.NewLabel("")
+
+	picker := &PlaylistPicker{
+		ctx:    ctx,
+		client: client,
+		filter: filter,
+		table:  table,
+		status: status,
+	}
+	filter.OnChanged(func(e *tui.Entry) {
+		picker.render(e.Text())
+	})
+	filter.OnSubmit(func(*tui.Entry) {
+		picker.status.SetText(picker.add(0))
+	})
+	table.OnItemActivated(func(t *tui.Table) {
+		picker.status.SetText(picker.add(t.Selected()))
+	})
+
+	filterBox := tui.NewHBox(tui.NewLabel("Filter: "), clickable(filter))
+	box := tui.NewVBox(filterBox, table, tui.NewSpacer(), status)
+	box.SetTitle("Add to playlist")
+	box.SetBorder(true)
+	box.SetSizePolicy(tui.Expanding, tui.Expanding)
+
+	picker.Focusables = []tui.Widget{filter, table}
+	picker.Box = box
+	return picker
+}
+
+// Pick fetches playlists which the user can edit and waits for one of them
+// to be chosen, the given tracks are added to it then.
+func (picker *PlaylistPicker) Pick(trackIDs []spotify.ID) error {
+	playlists, err := editablePlaylists(picker.ctx, picker.client)
+	if err != nil {
+		return err
+	}
+	picker.playlists = playlists
+	picker.trackIDs = trackIDs
+	picker.filter.SetText("")
+	picker.render("")
+	if len(trackIDs) == 1 {
+		picker.status.SetText("Choose playlist to add the track to")
+	} else {
+		picker.status.SetText(fmt.Sprintf("Choose playlist to add %d tracks to", len(trackIDs)))
+	}
+	return nil
+}
+
+func (picker *PlaylistPicker) render(filter string) {
+	filter = strings.ToLower(strings.TrimSpace(filter))
+	picker.shown = picker.shown[:0]
+	picker.table.RemoveRows()
+	for _, playlist := range picker.playlists {
+		if !strings.Contains(strings.ToLower(playlist.Name), filter) {
+			continue
+		}
+		picker.shown = append(picker.shown, playlist)
+		mark := ""
+		if playlist.Collaborative {
+			mark = playlistCollaborativeMark
+		}
+		picker.table.AppendRow(tui.NewLabel(mark), tui.NewLabel(trimWithCommasIfTooLong(playlist.Name, 2*uiColumnWidth)))
+	}
+	if len(picker.shown) > 0 {
+		picker.table.SetSelected(0)
+	}
+}
+
+// add appends picked tracks to the shown playlist at the given row,
+// returned status informs the user about the outcome.
+func (picker *PlaylistPicker) add(row int) string {
+	if len(picker.trackIDs) == 0 {
+		return "There are no tracks to add"
+	}
+	if row < 0 || row >= len(picker.shown) {
+		return "There is no playlist matching the filter"
+	}
+	playlist := picker.shown[row]
+	_, err := addTracksToPlaylist(picker.ctx, picker.client, playlist.ID, picker.trackIDs)
+	if err != nil {
+		return fmt.Sprintf("Could not add tracks to %s: %v", playlist.Name, err)
+	}
+	status := fmt.Sprintf("Added %d tracks to %s", len(picker.trackIDs), playlist.Name)
+	picker.trackIDs = nil
+	return status
+}
+
+// editablePlaylists fetches all the playlists of the current user, skipping
+// ones owned by others which are not collaborative.
+func editablePlaylists(ctx context.Context, client SpotifyClient) ([]spotify.SimplePlaylist, error) {
+	user, err := client.CurrentUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch current user: %v", err)
+	}
+	all, err := fetchPlaylists(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	playlists := []spotify.SimplePlaylist{}
+	for _, playlist := range all {
+		if playlist.Owner.ID == user.ID || playlist.Collaborative {
+			playlists = append(playlists, playlist)
+		}
+	}
+	return playlists, nil
+}
+
+// fetchPlaylists fetches all the playlists of the current user, including followed ones.
+func fetchPlaylists(ctx context.Context, client SpotifyClient) ([]spotify.SimplePlaylist, error) {
+	playlists := []spotify.SimplePlaylist{}
+	for {
+		offset := len(playlists)
+		page, err := client.CurrentUsersPlaylistsOpt(ctx, &spotify.Options{Limit: &playlistPickerPageSize, Offset: &offset})
+		if err != nil {
+			return nil, fmt.Errorf("could not fetch playlists: %v", err)
+		}
+		playlists = append(playlists, page.Playlists...)
+		if page.Next == "" || len(page.Playlists) == 0 {
+			return playlists, nil
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package player
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/zmb3/spotify"
+)
+
+type fakeBatchingPlaylistEditor struct {
+	*DebugPlaylistEditor
+	batches []int
+}
+
+func (fake *fakeBatchingPlaylistEditor) AddTracksToPlaylist(ctx context.Context, playlistID spotify.ID, trackIDs ...spotify.ID) (string, error) {
+	fake.batches = append(fake.batches, len(trackIDs))
+	return fake.DebugPlaylistEditor.AddTracksToPlaylist(context.Background(), playlistID, trackIDs...)
+}
+
+func TestPlaylistPickerFiltersPlaylists(t *testing.T) {
+	picker := NewPlaylistPicker(context.Background(), NewDebugClient())
+	if err := picker.Pick([]spotify.ID{"track"}); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if len(picker.shown) != 3 {
+		t.Fatalf("Expected all 3 playlists of the user to be shown, got %d", len(picker.shown))
+	}
+	picker.filter.SetText("name 2")
+	picker.render(picker.filter.Text())
+	if len(picker.shown) != 1 || picker.shown[0].ID != "playlist2" {
+		t.Fatalf("Expected only playlist2 to match the filter, got %v", picker.shown)
+	}
+	picker.render("not existing")
+	if status := picker.add(0); status != "There is no playlist matching the filter" {
+		t.Fatalf("Unexpected status when no playlist matches: %q", status)
+	}
+}
+
+func TestPlaylistPickerAddsTracksInBatches(t *testing.T) {
+	editor := &fakeBatchingPlaylistEditor{DebugPlaylistEditor: NewDebugPlaylistEditor()}
+	client := NewDebugClient().(DebugClient)
+	client.PlaylistEditor = editor
+	picker := NewPlaylistPicker(context.Background(), client)
+
+	ids := []spotify.ID{}
+	for i := 0; i < 250; i++ {
+		ids = append(ids, spotify.ID(fmt.Sprintf("track%d", i)))
+	}
+	if err := picker.Pick(ids); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if status := picker.add(1); status != "Added 250 tracks to Playlist Name 2" {
+		t.Fatalf("Unexpected status after adding: %q", status)
+	}
+	if fmt.Sprint(editor.batches) != "[100 100 50]" {
+		t.Fatalf("Expected tracks to be added in batches of 100, got %v", editor.batches)
+	}
+	// debug playlists initially have 10 tracks
+	if tracks := editor.playlist("playlist2").tracks; len(tracks) != 260 || tracks[259].Track.ID != "track249" {
+		t.Fatalf("Expected tracks to be appended in order, got %d tracks", len(tracks))
+	}
+	if status := picker.add(1); status != "There are no tracks to add" {
+		t.Fatalf("Expected tracks to be added only once, got status %q", status)
+	}
+}
+
+type fakeCollaborativePlaylistFetcher struct {
+	DebugPlaylistFetcher
+}
+
+func (fake fakeCollaborativePlaylistFetcher) CurrentUsersPlaylistsOpt(ctx context.Context, opt *spotify.Options) (*spotify.SimplePlaylistPage, error) {
+	page, err := fake.DebugPlaylistFetcher.CurrentUsersPlaylistsOpt(context.Background(), opt)
+	page.Playlists[1].Collaborative = true
+	return page, err
+}
+
+func TestPlaylistPickerMarksCollaborativePlaylists(t *testing.T) {
+	client := NewDebugClient().(DebugClient)
+	client.PlaylistFetcher = fakeCollaborativePlaylistFetcher{}
+	picker := NewPlaylistPicker(context.Background(), client)
+	if err := picker.Pick([]spotify.ID{"track"}); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	for i, playlist := range picker.shown {
+		if playlist.Collaborative != (i == 1) {
+			t.Fatalf("Expected only the second playlist to be collaborative, got %v", picker.shown)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package player
+
+import (
+	"context"
+	"testing"
+
+	"github.com/zmb3/spotify"
+)
+
+func TestPlaylistFormCreatesPlaylist(t *testing.T) {
+	form := NewPlaylistForm(context.Background(), NewDebugClient())
+	if _, err := form.create("  "); err == nil {
+		t.Fatalf("Expected to fail without playlist name, but it didn't")
+	}
+
+	form.setPublic(true)
+	playlist, err := form.create("Road trip")
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if playlist.Name != "Road trip" || !playlist.IsPublic {
+		t.Fatalf("Expected public playlist named Road trip, got %q public: %v", playlist.Name, playlist.IsPublic)
+	}
+	if form.visibility.Text() != "Visibility: public" {
+		t.Fatalf("Expected visibility to be shown, got %q", form.visibility.Text())
+	}
+}
+
+func TestPlaylistOpen(t *testing.T) {
+	client := NewDebugClient()
+	playlist := NewPlaylist(context.Background(), client, NewConfirmation())
+	created, err := client.CreatePlaylistForUser(context.Background(), debugUserID, "New", "", false)
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+
+	if err := playlist.Open(created.ID, created.Name); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if len(playlist.session.Tracks) != 0 || playlist.status.Text() != "Playlist is empty" {
+		t.Fatalf("Expected created playlist to be empty, got %d tracks", len(playlist.session.Tracks))
+	}
+
+	if err := playlist.Open(spotify.ID("existing"), "Existing"); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	// DebugPlaylistEditor playlists have 10 tracks
+	if len(playlist.session.Tracks) != 10 || playlist.status.Text() != "" {
+		t.Fatalf("Expected 10 tracks of existing playlist, got %d", len(playlist.session.Tracks))
+	}
+}
+
+func TestPlaylistRemoveTrackAfterConfirmation(t *testing.T) {
+	confirmation := NewConfirmation()
+	playlist := NewPlaylist(context.Background(), NewDebugClient(), confirmation)
+	if err := playlist.Open(spotify.ID("existing"), "Existing"); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	second := playlist.session.Tracks[1].Track
+
+	playlist.askRemove(1)
+	if !confirmation.Pending() {
+		t.Fatalf("Expected to be asked for confirmation")
+	}
+	confirmation.answer(false)
+	if len(playlist.session.Tracks) != 10 {
+		t.Fatalf("Expected removal to be cancelled, got %d tracks", len(playlist.session.Tracks))
+	}
+
+	playlist.askRemove(1)
+	confirmation.answer(true)
+	if status := playlist.status.Text(); status != "Removed "+second.Name {
+		t.Fatalf("Unexpected status after removal: %q", status)
+	}
+	if len(playlist.session.Tracks) != 9 || playlist.session.Tracks[1].Track.ID == second.ID {
+		t.Fatalf("Expected track %s to be removed, got %d tracks", second.ID, len(playlist.session.Tracks))
+	}
+	playlist.askRemove(9)
+	if confirmation.Pending() {
+		t.Fatalf("Expected not to ask about position out of range")
+	}
+}
+
+func TestPlaylistMoveTrack(t *testing.T) {
+	playlist := NewPlaylist(context.Background(), NewDebugClient(), NewConfirmation())
+	if err := playlist.Open(spotify.ID("existing"), "Existing"); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	first := playlist.session.Tracks[0].Track.ID
+	second := playlist.session.Tracks[1].Track.ID
+
+	if status := playlist.move(0, 1); status != "Moved "+playlist.session.Tracks[1].Track.Name+" to position 2" {
+		t.Fatalf("Unexpected status after moving down: %q", status)
+	}
+	if playlist.session.Tracks[0].Track.ID != second || playlist.session.Tracks[1].Track.ID != first {
+		t.Fatalf("Expected first track to be moved down, got %v", trackIDs(playlist.session.Tracks))
+	}
+	if playlist.table.Selected() != 2 {
+		t.Fatalf("Expected moved track to stay selected, selected row %d", playlist.table.Selected())
+	}
+
+	playlist.move(1, -1)
+	if playlist.session.Tracks[0].Track.ID != first || playlist.session.Tracks[1].Track.ID != second {
+		t.Fatalf("Expected track to be moved back up, got %v", trackIDs(playlist.session.Tracks))
+	}
+	if status := playlist.move(0, -1); status != "" {
+		t.Fatalf("Expected first track not to move up, got status %q", status)
+	}
+	if status := playlist.move(9, 1); status != "" {
+		t.Fatalf("Expected last track not to move down, got status %q", status)
+	}
+}
+
+type fakeForeignPlaylistEditor struct {
+	*DebugPlaylistEditor
+}
+
+func (fake fakeForeignPlaylistEditor) GetPlaylistOpt(ctx context.Context, playlistID spotify.ID, fields string) (*spotify.FullPlaylist, error) {
+	playlist, err := fake.DebugPlaylistEditor.GetPlaylistOpt(context.Background(), playlistID, fields)
+	playlist.Owner.ID = "someone"
+	return playlist, err
+}
+
+func TestPlaylistFormEditsPlaylist(t *testing.T) {
+	client := NewDebugClient()
+	form := NewPlaylistForm(context.Background(), client)
+	if err := form.Edit("existing"); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if form.Name.Text() != "Playlist existing" || form.visibility.Text() != "Visibility: private" {
+		t.Fatalf("Expected form to be filled with playlist details, got name %q", form.Name.Text())
+	}
+
+	form.Name.SetText("Renamed")
+	form.description.SetText("For the road")
+	form.setPublic(true)
+	form.submit()
+	edited, _ := client.GetPlaylistOpt(context.Background(), "existing", "")
+	if edited.Name != "Renamed" || edited.Description != "For the road" || !edited.IsPublic {
+		t.Fatalf("Expected playlist details to be changed, got %q %q public: %v", edited.Name, edited.Description, edited.IsPublic)
+	}
+	if form.status.Text() != "Saved Renamed" {
+		t.Fatalf("Unexpected status after saving: %q", form.status.Text())
+	}
+
+	form.New("")
+	if form.edited != nil || form.description.Text() != "" {
+		t.Fatalf("Expected form to be cleared for a new playlist")
+	}
+
+	foreign := NewDebugClient().(DebugClient)
+	foreign.PlaylistEditor = fakeForeignPlaylistEditor{NewDebugPlaylistEditor()}
+	if err := NewPlaylistForm(context.Background(), foreign).Edit("existing"); err == nil {
+		t.Fatalf("Expected to fail editing playlist of someone else, but it didn't")
+	}
+}
+
+func TestPlaylistFormChangesCollaborative(t *testing.T) {
+	client := NewDebugClient()
+	form := NewPlaylistForm(context.Background(), client)
+	if err := form.Edit("existing"); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	form.setPublic(true)
+	form.setCollaborative(true)
+	if form.public || form.visibility.Text() != "Visibility: private, collaborative" {
+		t.Fatalf("Expected collaborative playlist to be private, got %q", form.visibility.Text())
+	}
+	form.submit()
+	edited, _ := client.GetPlaylistOpt(context.Background(), "existing", "")
+	if !edited.Collaborative || edited.IsPublic {
+		t.Fatalf("Expected playlist to become collaborative and private, got collaborative: %v public: %v", edited.Collaborative, edited.IsPublic)
+	}
+
+	if err := form.Edit("existing"); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if !form.collaborative {
+		t.Fatalf("Expected form to be filled with collaborative state")
+	}
+	form.setPublic(true)
+	form.submit()
+	edited, _ = client.GetPlaylistOpt(context.Background(), "existing", "")
+	if edited.Collaborative || !edited.IsPublic {
+		t.Fatalf("Expected playlist to become public, got collaborative: %v public: %v", edited.Collaborative, edited.IsPublic)
+	}
+}
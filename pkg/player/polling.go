@@ -0,0 +1,160 @@
+package player
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/zmb3/spotify"
+)
+
+// PollingClient is a SpotifyClient which polls what is played and the devices in the background,
+// so that they are given right away instead of being requested each time, i.e. by the daemon
+// answering status commands of status bars. The rest of requests are sent as they are.
+type PollingClient struct {
+	SpotifyClient
+
+	mu      sync.Mutex
+	playing *PlaybackItem
+	devices []spotify.PlayerDevice
+	polled  time.Time
+	now     func() time.Time
+
+	// played is URI of the item which was played last, it is kept when nothing is played
+	// and when polled state is forgotten
+	played        spotify.URI
+	polledBefore  bool
+	onTrackChange func(*PlaybackItem)
+}
+
+// NewPollingClient wraps the client, nothing is polled until Poll is called.
+func NewPollingClient(client SpotifyClient) *PollingClient {
+	return &PollingClient{SpotifyClient: client, now: time.Now}
+}
+
+// Poll requests what is played and the devices every interval, until the context is done.
+func (c *PollingClient) Poll(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := c.poll(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Could not poll player state with %s", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *PollingClient) poll(ctx context.Context) error {
+	playing, err := c.SpotifyClient.PlayerCurrentlyPlaying(ctx)
+	if err != nil {
+		return err
+	}
+	devices, err := c.SpotifyClient.PlayerDevices(ctx)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	played := playbackItemURI(playing)
+	changed := c.polledBefore && played != "" && played != c.played
+	c.playing, c.devices, c.polled = playing, devices, c.now()
+	c.polledBefore = true
+	if played != "" {
+		c.played = played
+	}
+	onTrackChange := c.onTrackChange
+	c.mu.Unlock()
+	if changed && onTrackChange != nil {
+		onTrackChange(playing)
+	}
+	return nil
+}
+
+// OnTrackChange sets function called with the item once polling finds that another one started
+// playing. It is not called for the item which is played when polled for the first time.
+func (c *PollingClient) OnTrackChange(fn func(*PlaybackItem)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onTrackChange = fn
+}
+
+// Forget drops what was polled, so that it is requested again until the next poll, i.e. once
+// playback was changed.
+func (c *PollingClient) Forget() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.playing, c.devices = nil, nil
+}
+
+// PlayerCurrentlyPlaying gives what was played when polled, with progress moved on by the time
+// which has passed since, or requests it when nothing was polled.
+func (c *PollingClient) PlayerCurrentlyPlaying(ctx context.Context) (*PlaybackItem, error) {
+	c.mu.Lock()
+	if c.playing == nil {
+		c.mu.Unlock()
+		return c.SpotifyClient.PlayerCurrentlyPlaying(ctx)
+	}
+	playing := *c.playing
+	if playing.Playing {
+		playing.Progress += int(c.now().Sub(c.polled) / time.Millisecond)
+		if duration := playbackItemDuration(&playing); playing.Progress > duration {
+			playing.Progress = duration
+		}
+	}
+	c.mu.Unlock()
+	return &playing, nil
+}
+
+// PlayerDevices gives devices which were there when polled, or requests them when nothing was polled.
+func (c *PollingClient) PlayerDevices(ctx context.Context) ([]spotify.PlayerDevice, error) {
+	c.mu.Lock()
+	devices := c.devices
+	c.mu.Unlock()
+	if devices == nil {
+		return c.SpotifyClient.PlayerDevices(ctx)
+	}
+	return devices, nil
+}
+
+func playbackItemURI(item *PlaybackItem) spotify.URI {
+	switch {
+	case item.Item != nil:
+		return item.Item.URI
+	case item.Episode != nil:
+		return item.Episode.URI
+	default:
+		return ""
+	}
+}
+
+// HookEnv describes the item in environment variables of hooks, episodes are described
+// by their show in place of the album and its publisher in place of the artist.
+func HookEnv(item *PlaybackItem) map[string]string {
+	env := map[string]string{"SPOTIFY_URI": string(playbackItemURI(item))}
+	switch {
+	case item.Item != nil:
+		env["SPOTIFY_TITLE"] = item.Item.Name
+		env["SPOTIFY_ARTIST"] = artistsNames(item.Item.Artists)
+		env["SPOTIFY_ALBUM"] = item.Item.Album.Name
+	case item.Episode != nil:
+		env["SPOTIFY_TITLE"] = item.Episode.Name
+		env["SPOTIFY_ARTIST"] = item.Episode.Show.Publisher
+		env["SPOTIFY_ALBUM"] = item.Episode.Show.Name
+	}
+	return env
+}
+
+func playbackItemDuration(item *PlaybackItem) int {
+	switch {
+	case item.Item != nil:
+		return item.Item.Duration
+	case item.Episode != nil:
+		return item.Episode.Duration_ms
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,85 @@
+package player
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/zmb3/spotify"
+)
+
+// countingClient plays a track of 5 minutes from its first minute, counting requests.
+type countingClient struct {
+	DebugClient
+	requests int
+}
+
+func (client *countingClient) PlayerCurrentlyPlaying(ctx context.Context) (*PlaybackItem, error) {
+	client.requests++
+	return &PlaybackItem{CurrentlyPlaying: spotify.CurrentlyPlaying{
+		Playing:  true,
+		Progress: 60000,
+		Item:     &spotify.FullTrack{SimpleTrack: spotify.SimpleTrack{Duration: 300000}},
+	}}, nil
+}
+
+func TestPollingClientGivesPolledState(t *testing.T) {
+	client := &countingClient{}
+	polling := NewPollingClient(client)
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	polling.now = func() time.Time { return now }
+	if err := polling.poll(context.Background()); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+
+	now = now.Add(30 * time.Second)
+	playing, err := polling.PlayerCurrentlyPlaying(context.Background())
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if playing.Progress != 90000 || client.requests != 1 {
+		t.Fatalf("Expected polled progress moved on by 30 seconds without requests, got %d after %d requests", playing.Progress, client.requests)
+	}
+	now = now.Add(10 * time.Minute)
+	if playing, _ := polling.PlayerCurrentlyPlaying(context.Background()); playing.Progress != 300000 {
+		t.Fatalf("Expected progress not to pass duration of the track, got %d", playing.Progress)
+	}
+
+	polling.Forget()
+	if _, err := polling.PlayerCurrentlyPlaying(context.Background()); err != nil || client.requests != 2 {
+		t.Fatalf("Expected state to be requested once forgotten, got %d requests, %v", client.requests, err)
+	}
+}
+
+// changingTrackClient plays the tracks in turn, one on each request, nothing when the URI is empty.
+type changingTrackClient struct {
+	DebugClient
+	tracks []spotify.URI
+}
+
+func (client *changingTrackClient) PlayerCurrentlyPlaying(ctx context.Context) (*PlaybackItem, error) {
+	uri := client.tracks[0]
+	client.tracks = client.tracks[1:]
+	if uri == "" {
+		return &PlaybackItem{}, nil
+	}
+	return &PlaybackItem{CurrentlyPlaying: spotify.CurrentlyPlaying{Item: &spotify.FullTrack{SimpleTrack: spotify.SimpleTrack{URI: uri, Name: string(uri)}}}}, nil
+}
+
+func TestPollingClientTellsWhenTrackChanges(t *testing.T) {
+	client := &changingTrackClient{tracks: []spotify.URI{"spotify:track:1", "spotify:track:1", "spotify:track:2", "", "spotify:track:2", "spotify:track:3"}}
+	polling := NewPollingClient(client)
+	changes := []string{}
+	polling.OnTrackChange(func(item *PlaybackItem) {
+		changes = append(changes, HookEnv(item)["SPOTIFY_URI"])
+	})
+	for range client.tracks {
+		if err := polling.poll(context.Background()); err != nil {
+			t.Fatalf("Did not expect to fail, but it did with %v", err)
+		}
+	}
+	if expected := []string{"spotify:track:2", "spotify:track:3"}; !reflect.DeepEqual(changes, expected) {
+		t.Fatalf("Expected changes to %v, got %v", expected, changes)
+	}
+}
@@ -0,0 +1,93 @@
+package player
+
+import (
+	"fmt"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+// ProfileSwitcher represents view listing account profiles, each with its own
+// token and cache. Pressing Enter on a profile switches to its account.
+type ProfileSwitcher struct {
+	Focusables []tui.Widget
+	Box        *tui.Box
+	table      *countedTable
+	status     *tui.Label
+	current    string
+	profiles   []string
+	onSwitch   func(string)
+}
+
+// profileCurrentMark marks the profile which is in use.
+var profileCurrentMark = "●"
+
+// NewProfileSwitcher creates view of profiles, the current one is marked. It is empty until refreshed.
+func NewProfileSwitcher(current string) *ProfileSwitcher {
+	table := newCountedTable()
+	table.SetColumnStretch(1, 1)
+	status := tui.NewLabel("Press Enter to switch to the account of the profile, new profiles are added with profile <name>")
+
+	switcher := &ProfileSwitcher{
+		table:   table,
+		status:  status,
+		current: current,
+	}
+	table.OnItemActivated(func(t *tui.Table) {
+		switcher.status.SetText(switcher.choose(t.Selected()))
+	})
+
+	box := tui.NewVBox(table, tui.NewSpacer(), status)
+	box.SetTitle("Profiles")
+	box.SetBorder(true)
+	box.SetSizePolicy(tui.Expanding, tui.Expanding)
+
+	switcher.Focusables = []tui.Widget{table}
+	switcher.Box = box
+	return switcher
+}
+
+// OnSwitch sets function called with the profile chosen to switch to.
+func (switcher *ProfileSwitcher) OnSwitch(fn func(string)) {
+	switcher.onSwitch = fn
+}
+
+// Refresh lists the given profiles, the current one is listed even if it is not among them.
+func (switcher *ProfileSwitcher) Refresh(profiles []string) {
+	switcher.profiles = []string{}
+	listed := false
+	for _, profile := range profiles {
+		switcher.profiles = append(switcher.profiles, profile)
+		listed = listed || profile == switcher.current
+	}
+	if !listed {
+		switcher.profiles = append(switcher.profiles, switcher.current)
+	}
+	switcher.table.RemoveRows()
+	for i, profile := range switcher.profiles {
+		mark := ""
+		if profile == switcher.current {
+			mark = profileCurrentMark
+			switcher.table.SetSelected(i)
+		}
+		switcher.table.AppendRow(tui.NewLabel(mark), tui.NewLabel(profile))
+	}
+}
+
+// Switch switches to the account of the given profile, returned text describes the outcome.
+func (switcher *ProfileSwitcher) Switch(profile string) string {
+	if profile == switcher.current {
+		return fmt.Sprintf("Profile %s is already in use", profile)
+	}
+	if switcher.onSwitch != nil {
+		switcher.onSwitch(profile)
+	}
+	return fmt.Sprintf("Switching to profile %s", profile)
+}
+
+// choose switches to the profile at the given row.
+func (switcher *ProfileSwitcher) choose(row int) string {
+	if row < 0 || row >= len(switcher.profiles) {
+		return ""
+	}
+	return switcher.Switch(switcher.profiles[row])
+}
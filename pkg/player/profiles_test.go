@@ -0,0 +1,32 @@
+package player
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProfileSwitcher(t *testing.T) {
+	switcher := NewProfileSwitcher("work")
+	switched := []string{}
+	switcher.OnSwitch(func(profile string) {
+		switched = append(switched, profile)
+	})
+
+	switcher.Refresh([]string{"default", "family"})
+	if expected := []string{"default", "family", "work"}; !reflect.DeepEqual(switcher.profiles, expected) {
+		t.Fatalf("Expected current profile to be listed along with the others %v, got %v", expected, switcher.profiles)
+	}
+	if switcher.table.Selected() != 2 {
+		t.Fatalf("Expected current profile to be selected, got row %d", switcher.table.Selected())
+	}
+
+	if status := switcher.choose(2); status != "Profile work is already in use" || len(switched) != 0 {
+		t.Fatalf("Expected not to switch to the current profile, got %q and %v", status, switched)
+	}
+	if status := switcher.choose(1); status != "Switching to profile family" {
+		t.Fatalf("Unexpected status after choosing profile: %q", status)
+	}
+	if !reflect.DeepEqual(switched, []string{"family"}) {
+		t.Fatalf("Expected to switch to family profile, got %v", switched)
+	}
+}
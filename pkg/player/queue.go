@@ -0,0 +1,57 @@
+package player
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/marcusolsson/tui-go"
+	"github.com/zmb3/spotify"
+)
+
+// QueueList lists tracks which are played next, either in the queue pane or in the Queue tab,
+// it is empty until refreshed.
+type QueueList struct {
+	Focusables []tui.Widget
+	Box        *tui.Box
+	ctx        context.Context
+	client     SpotifyClient
+	table      *countedTable
+	tracks     []spotify.FullTrack
+}
+
+// NewQueueList creates empty list of tracks which are played next.
+func NewQueueList(ctx context.Context, client SpotifyClient) *QueueList {
+	table := newCountedTable()
+	table.SetColumnStretch(0, 1)
+
+	box := tui.NewVBox(table, tui.NewSpacer())
+	box.SetTitle("Queue")
+	box.SetBorder(true)
+	box.SetSizePolicy(tui.Preferred, tui.Expanding)
+
+	return &QueueList{
+		Focusables: []tui.Widget{table},
+		Box:        box,
+		ctx:        ctx,
+		client:     client,
+		table:      table,
+	}
+}
+
+// Refresh lists tracks which are played next again, i.e. once another track plays.
+func (queue *QueueList) Refresh() error {
+	playing, err := queue.client.PlayerQueue(queue.ctx)
+	if err != nil {
+		return fmt.Errorf("could not get queue: %v", err)
+	}
+	queue.tracks = playing.Queue
+	queue.table.RemoveRows()
+	for _, track := range queue.tracks {
+		queue.table.AppendRow(tui.NewLabel(trimWithCommasIfTooLong(fmt.Sprintf("%s - %s", artistsNames(track.Artists), track.Name), 2*uiColumnWidth)))
+	}
+	if len(queue.tracks) == 0 {
+		queue.table.AppendRow(tui.NewLabel("Nothing is queued"))
+	}
+	queue.table.SetSelected(0)
+	return nil
+}
@@ -0,0 +1,16 @@
+package player
+
+import (
+	"context"
+	"testing"
+)
+
+func TestQueueListsQueuedTracks(t *testing.T) {
+	queue := NewQueueList(context.Background(), NewDebugClient())
+	if err := queue.Refresh(); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if len(queue.tracks) != 3 || queue.table.Selected() != 0 {
+		t.Fatalf("Expected 3 queued tracks with the first selected, got %d with %d selected", len(queue.tracks), queue.table.Selected())
+	}
+}
@@ -0,0 +1,255 @@
+package player
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/marcusolsson/tui-go"
+	"github.com/zmb3/spotify"
+)
+
+// Quiz represents blindtest view - random tracks of the chosen playlist are
+// played with now playing info concealed and user guesses their title and artist.
+type Quiz struct {
+	Focusables []tui.Widget
+	Box        *tui.Box
+}
+
+// Concealer hides or reveals information about currently played item.
+type Concealer interface {
+	Conceal(bool)
+}
+
+var (
+	quizPlaylistsPageSize = 50
+	// quizMatchThreshold is the minimal similarity of a guess and an answer
+	// for the guess to be scored.
+	quizMatchThreshold = 0.8
+	// quizDecorations matches parts of titles which are not expected to be guessed,
+	// like "(feat. Someone)", "[Live]" or "- Remastered 2011".
+	quizDecorations = regexp.MustCompile(`\(.*?\)|\[.*?\]|\s-\s.*$`)
+)
+
+type quiz struct {
+	ctx       context.Context
+	client    Player
+	concealer Concealer
+	tracks    []spotify.FullTrack
+	round     int
+	score     int
+	revealed  bool
+}
+
+// NewQuiz creates quiz view with playlists of the current user to choose from.
+func NewQuiz(ctx context.Context, client SpotifyClient, concealer Concealer) (*Quiz, error) {
+	page, err := client.CurrentUsersPlaylistsOpt(ctx, &spotify.Options{Limit: &quizPlaylistsPageSize})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch playlists: %v", err)
+	}
+	playlists := page.Playlists
+
+	playlistsTable := newCountedTable()
+	for _, playlist := range playlists {
+		playlistsTable.AppendRow(tui.NewLabel(trimWithCommasIfTooLong(playlist.Name, uiColumnWidth)))
+	}
+	var playlistsBox *tui.Box
+	playlistsTypeAhead := newTypeAheadTable(playlistsTable, jumpToPlaylist(playlistsTable, playlists), func(prefix string) {
+		playlistsBox.SetTitle(typeAheadTitle("Quiz playlists", prefix))
+	})
+	playlistsBox = tui.NewVBox(playlistsTypeAhead, tui.NewSpacer())
+	playlistsBox.SetTitle("Quiz playlists")
+	playlistsBox.SetBorder(true)
+
+	status := tui.NewLabel("Choose playlist to start the quiz")
+	status.SetWordWrap(true)
+	statusBox := tui.NewVBox(status, tui.NewSpacer())
+	statusBox.SetTitle("Quiz")
+	statusBox.SetBorder(true)
+	statusBox.SetSizePolicy(tui.Expanding, tui.Expanding)
+
+	guessInput := tui.NewEntry()
+	guessInput.SetSizePolicy(tui.Expanding, tui.Minimum)
+	guessBox := tui.NewHBox(clickable(guessInput))
+	guessBox.SetTitle("Guess title or artist, empty guess reveals the answer")
+	guessBox.SetBorder(true)
+
+	q := &quiz{ctx: ctx, client: client, concealer: concealer}
+	playlistsTable.OnItemActivated(func(t *tui.Table) {
+		playlist := playlists[t.Selected()]
+		page, err := client.GetPlaylistTracks(ctx, playlist.ID)
+		if err != nil {
+			log.Printf("Could not fetch tracks of %s with %s", playlist.Name, err)
+			return
+		}
+		tracks := make([]spotify.FullTrack, 0, len(page.Tracks))
+		for _, playlistTrack := range page.Tracks {
+			tracks = append(tracks, playlistTrack.Track)
+		}
+		status.SetText(q.start(tracks))
+	})
+	guessInput.OnSubmit(func(e *tui.Entry) {
+		status.SetText(q.submit(e.Text()))
+		e.SetText("")
+	})
+
+	return &Quiz{
+		Focusables: []tui.Widget{playlistsTable, guessInput},
+		Box:        tui.NewHBox(playlistsBox, tui.NewVBox(statusBox, guessBox)),
+	}, nil
+}
+
+// start shuffles tracks and plays the first of them, returned text
+// describes state of the quiz.
+func (q *quiz) start(tracks []spotify.FullTrack) string {
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	r.Shuffle(len(tracks), func(i, j int) {
+		tracks[i], tracks[j] = tracks[j], tracks[i]
+	})
+	q.tracks = tracks
+	q.round = -1
+	q.score = 0
+	return q.nextRound()
+}
+
+func (q *quiz) nextRound() string {
+	q.round++
+	if q.round >= len(q.tracks) {
+		q.concealer.Conceal(false)
+		return fmt.Sprintf("Quiz is over, final score: %d/%d", q.score, 2*len(q.tracks))
+	}
+	q.revealed = false
+	q.concealer.Conceal(true)
+	track := q.tracks[q.round]
+	err := q.client.PlayOpt(q.ctx, &spotify.PlayOptions{URIs: []spotify.URI{track.URI}})
+	if err != nil {
+		notifyError(fmt.Errorf("could not play quiz track: %v", err))
+	}
+	return fmt.Sprintf("Round %d/%d, score: %d\nWhat is playing?", q.round+1, len(q.tracks), q.score)
+}
+
+// submit scores the guess and reveals the answer, once the answer
+// is revealed following submit starts the next round.
+func (q *quiz) submit(guess string) string {
+	if q.round < 0 || q.round >= len(q.tracks) {
+		return "Choose playlist to start the quiz"
+	}
+	if q.revealed {
+		return q.nextRound()
+	}
+	track := q.tracks[q.round]
+	points := scoreGuess(guess, track)
+	q.score += points
+	q.revealed = true
+	q.concealer.Conceal(false)
+	return fmt.Sprintf(
+		"+%d points, score: %d\nIt was %s by %s\nSubmit to continue",
+		points,
+		q.score,
+		track.Name,
+		artistsNames(track.Artists),
+	)
+}
+
+// scoreGuess gives a point for guessing the title and a point for
+// guessing any of the artists. Guess may contain both of them.
+func scoreGuess(guess string, track spotify.FullTrack) int {
+	if strings.TrimSpace(guess) == "" {
+		return 0
+	}
+	points := 0
+	if isFuzzyMatch(guess, track.Name) {
+		points++
+	}
+	for _, artist := range track.Artists {
+		if isFuzzyMatch(guess, artist.Name) {
+			points++
+			break
+		}
+	}
+	return points
+}
+
+// isFuzzyMatch checks if the guess, or any of its word sequences as long
+// as the answer, is similar enough to the answer.
+func isFuzzyMatch(guess, answer string) bool {
+	guessWords := strings.Fields(normalizeAnswer(guess))
+	answerWords := strings.Fields(normalizeAnswer(answer))
+	if len(guessWords) == 0 || len(answerWords) == 0 {
+		return false
+	}
+	if len(guessWords) <= len(answerWords) {
+		return similarity(strings.Join(guessWords, " "), strings.Join(answerWords, " ")) >= quizMatchThreshold
+	}
+	for i := 0; i+len(answerWords) <= len(guessWords); i++ {
+		candidate := strings.Join(guessWords[i:i+len(answerWords)], " ")
+		if similarity(candidate, strings.Join(answerWords, " ")) >= quizMatchThreshold {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeAnswer lowercases text and removes decorations and punctuation from it.
+func normalizeAnswer(text string) string {
+	text = quizDecorations.ReplaceAllString(text, "")
+	return strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			return unicode.ToLower(r)
+		case unicode.IsSpace(r):
+			return ' '
+		default:
+			return -1
+		}
+	}, text)
+}
+
+// similarity returns value between 0 and 1 based on the edit distance of a and b,
+// 1 meaning they are equal.
+func similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+func levenshtein(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
@@ -0,0 +1,84 @@
+package player
+
+import (
+	"context"
+	"testing"
+
+	"github.com/zmb3/spotify"
+)
+
+type FakeConcealer struct {
+	concealed bool
+}
+
+func (c *FakeConcealer) Conceal(concealed bool) {
+	c.concealed = concealed
+}
+
+func TestNewQuiz(t *testing.T) {
+	quiz, err := NewQuiz(context.Background(), NewDebugClient(), &FakeConcealer{})
+	if err != nil {
+		t.Fatalf("Unexpected error occured: %s", err)
+	}
+	if len(quiz.Focusables) != 2 {
+		t.Fatalf("Expected to have 2 focusables elements, got %d", len(quiz.Focusables))
+	}
+}
+
+func TestIsFuzzyMatch(t *testing.T) {
+	var tests = []struct {
+		guess   string
+		answer  string
+		matches bool
+	}{
+		{"bohemian rhapsody", "Bohemian Rhapsody - Remastered 2011", true},
+		{"bohemain rapsody", "Bohemian Rhapsody", true},
+		{"Bohemian Rhapsody by Queen", "Queen", true},
+		{"Bohemian Rhapsody by Queen", "Bohemian Rhapsody", true},
+		{"dont stop me now", "Don't Stop Me Now", true},
+		{"Despacito", "Despacito (feat. Justin Bieber)", true},
+		{"bohemian", "Bohemian Rhapsody", false},
+		{"killer queen", "Bohemian Rhapsody", false},
+		{"", "Queen", false},
+	}
+	for _, test := range tests {
+		got := isFuzzyMatch(test.guess, test.answer)
+		if got != test.matches {
+			t.Errorf("Matching %q with %q, got: %v, want: %v", test.guess, test.answer, got, test.matches)
+		}
+	}
+}
+
+func TestQuizRounds(t *testing.T) {
+	concealer := &FakeConcealer{}
+	q := &quiz{client: NewDebugClient(), concealer: concealer}
+	tracks := []spotify.FullTrack{
+		{SimpleTrack: spotify.SimpleTrack{Name: "Bohemian Rhapsody", Artists: []spotify.SimpleArtist{{Name: "Queen"}}}},
+		{SimpleTrack: spotify.SimpleTrack{Name: "Bohemian Rhapsody", Artists: []spotify.SimpleArtist{{Name: "Queen"}}}},
+	}
+	q.start(tracks)
+	if !concealer.concealed {
+		t.Fatalf("Expected now playing to be concealed during the round")
+	}
+
+	q.submit("bohemian rhapsody queen")
+	if q.score != 2 {
+		t.Fatalf("Expected to score both title and artist, got score %d", q.score)
+	}
+	if concealer.concealed {
+		t.Fatalf("Expected now playing to be revealed after guess")
+	}
+
+	q.submit("")
+	if q.round != 1 || !concealer.concealed {
+		t.Fatalf("Expected submit after reveal to start next concealed round, got round %d", q.round)
+	}
+	q.submit("")
+	if q.score != 2 {
+		t.Fatalf("Expected empty guess not to be scored, got score %d", q.score)
+	}
+	q.submit("")
+	if q.round != 2 || concealer.concealed {
+		t.Fatalf("Expected quiz to be over and now playing revealed, got round %d", q.round)
+	}
+}
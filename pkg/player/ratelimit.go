@@ -0,0 +1,131 @@
+package player
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var (
+	// rateLimitTimer is replaced in tests, so that they do not wait for the rate limit to pass.
+	rateLimitTimer = time.NewTimer
+	// defaultRetryAfter is waited for when Spotify does not say how long to wait.
+	defaultRetryAfter = time.Second
+	// maxRateLimitRetries is the number of times request is sent again before it fails with 429.
+	maxRateLimitRetries = 10
+)
+
+// RateLimitTransport sends requests rejected with 429 Too Many Requests again, once the time
+// given by Retry-After passes. Until then other requests are held back as well, so that
+// heavy library syncs slow down instead of failing at random.
+type RateLimitTransport struct {
+	// Base sends the requests, http.DefaultTransport is used when it is nil.
+	Base http.RoundTripper
+
+	mu      sync.Mutex
+	until   time.Time
+	limited bool
+	onLimit func(retryIn time.Duration)
+}
+
+// OnRateLimited sets function called with the time left until requests are sent again when
+// they are rate limited, and with 0 once they go through again.
+func (t *RateLimitTransport) OnRateLimited(f func(retryIn time.Duration)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.onLimit = f
+}
+
+// RoundTrip sends the request with the base transport, waiting for the rate limit to pass
+// unless the request is cancelled.
+func (t *RateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	for retries := 0; ; retries++ {
+		if err := t.wait(req.Context()); err != nil {
+			if req.Body != nil {
+				req.Body.Close()
+			}
+			return nil, err
+		}
+		resp, err := base.RoundTrip(req)
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+			if err == nil {
+				t.passed()
+			}
+			return resp, err
+		}
+		// request can be sent again only when its body can be read again
+		if retries == maxRateLimitRetries || (req.Body != nil && req.GetBody == nil) {
+			return resp, nil
+		}
+		resp.Body.Close()
+		t.limit(retryAfter(resp))
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			retried := *req
+			retried.Body = body
+			req = &retried
+		}
+	}
+}
+
+// wait waits until requests are not rate limited, or until the context is done.
+func (t *RateLimitTransport) wait(ctx context.Context) error {
+	t.mu.Lock()
+	wait := time.Until(t.until)
+	t.mu.Unlock()
+	if wait <= 0 {
+		return nil
+	}
+	timer := rateLimitTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// limit holds requests back for the given time.
+func (t *RateLimitTransport) limit(retryIn time.Duration) {
+	t.mu.Lock()
+	if until := time.Now().Add(retryIn); until.After(t.until) {
+		t.until = until
+	}
+	t.limited = true
+	onLimit := t.onLimit
+	t.mu.Unlock()
+	if onLimit != nil {
+		onLimit(retryIn)
+	}
+}
+
+// passed reports that requests go through again, once they were rate limited.
+func (t *RateLimitTransport) passed() {
+	t.mu.Lock()
+	limited := t.limited
+	t.limited = false
+	onLimit := t.onLimit
+	t.mu.Unlock()
+	if limited && onLimit != nil {
+		onLimit(0)
+	}
+}
+
+// retryAfter returns how long Spotify asked to wait, Retry-After is given in seconds.
+func retryAfter(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return defaultRetryAfter
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,101 @@
+package player
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRateLimitTransportRetriesAfterWaiting(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests <= 2 {
+			w.Header().Set("Retry-After", "3")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	slept := []time.Duration{}
+	defer func(previous func(time.Duration) *time.Timer) { rateLimitTimer = previous }(rateLimitTimer)
+	rateLimitTimer = func(d time.Duration) *time.Timer {
+		slept = append(slept, d.Round(time.Second))
+		return time.NewTimer(0)
+	}
+	transport := &RateLimitTransport{}
+	reported := []time.Duration{}
+	transport.OnRateLimited(func(retryIn time.Duration) {
+		reported = append(reported, retryIn)
+	})
+	client := NewClient(&http.Client{Transport: transport})
+	client.baseURL = server.URL + "/"
+
+	if err := client.AddAlbumsToLibrary(context.Background(), "album"); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if requests != 3 {
+		t.Fatalf("Expected request to be sent again until it went through, sent %d times", requests)
+	}
+	if expected := []time.Duration{3 * time.Second, 3 * time.Second}; !reflect.DeepEqual(slept, expected) {
+		t.Fatalf("Expected to wait %v, waited %v", expected, slept)
+	}
+	if expected := []time.Duration{3 * time.Second, 3 * time.Second, 0}; !reflect.DeepEqual(reported, expected) {
+		t.Fatalf("Expected rate limit to be reported as %v, got %v", expected, reported)
+	}
+}
+
+func TestRateLimitTransportGivesUp(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+	defer func(previous func(time.Duration) *time.Timer) { rateLimitTimer = previous }(rateLimitTimer)
+	rateLimitTimer = func(time.Duration) *time.Timer { return time.NewTimer(0) }
+	client := NewClient(&http.Client{Transport: &RateLimitTransport{}})
+	client.baseURL = server.URL + "/"
+
+	if err := client.AddAlbumsToLibrary(context.Background(), "album"); err == nil {
+		t.Fatalf("Expected to fail once rate limit did not pass, but it didn't")
+	}
+	if requests != maxRateLimitRetries+1 {
+		t.Fatalf("Expected request to be sent %d times, sent %d times", maxRateLimitRetries+1, requests)
+	}
+}
+
+func TestRateLimitTransportStopsWaitingOnceCancelled(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	transport := &RateLimitTransport{}
+	// cancelled i.e. by quitting, once the request is rate limited
+	transport.OnRateLimited(func(time.Duration) { cancel() })
+	client := NewClient(&http.Client{Transport: transport})
+	client.baseURL = server.URL + "/"
+
+	done := make(chan error)
+	go func() { done <- client.AddAlbumsToLibrary(ctx, "album") }()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatalf("Expected to fail once cancelled, but it didn't")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected to stop waiting for the rate limit once cancelled")
+	}
+	if requests != 1 {
+		t.Fatalf("Expected request not to be sent again once cancelled, sent %d times", requests)
+	}
+}
@@ -0,0 +1,132 @@
+package player
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/marcusolsson/tui-go"
+	"github.com/zmb3/spotify"
+)
+
+// RecentlyAdded represents view listing albums saved to the library in the
+// last days, most recent first, i.e. to find albums just saved on the phone.
+// Pressing Enter plays the selected album.
+type RecentlyAdded struct {
+	Focusables []tui.Widget
+	Box        *tui.Box
+	ctx        context.Context
+	client     SpotifyClient
+	albumList  *AlbumList
+	table      *countedTable
+	status     *tui.Label
+	location   *time.Location
+	now        func() time.Time
+	// albums are recently added albums as listed in the table, row by row.
+	albums []albumDescription
+}
+
+// recentlyAddedDays is how many days back albums are listed, counting today.
+var recentlyAddedDays = 30
+
+// NewRecentlyAdded creates view of albums recently saved to the library listed in the given album
+// list, days are counted in the given location. It is empty until refreshed.
+func NewRecentlyAdded(ctx context.Context, client SpotifyClient, albumList *AlbumList, location *time.Location) *RecentlyAdded {
+	if location == nil {
+		location = time.Local
+	}
+	table := newCountedTable()
+	table.SetColumnStretch(0, 2)
+	table.SetColumnStretch(1, 2)
+	table.SetColumnStretch(2, 1)
+	status := tui.NewLabel("")
+
+	recent := &RecentlyAdded{
+		ctx:       ctx,
+		client:    client,
+		albumList: albumList,
+		table:     table,
+		status:    status,
+		location:  location,
+		now:       time.Now,
+	}
+	table.OnItemActivated(func(t *tui.Table) {
+		recent.play(t.Selected())
+	})
+
+	box := tui.NewVBox(table, tui.NewSpacer(), status)
+	box.SetTitle("Recently added")
+	box.SetBorder(true)
+	box.SetSizePolicy(tui.Expanding, tui.Expanding)
+
+	recent.Focusables = []tui.Widget{table}
+	recent.Box = box
+	return recent
+}
+
+// Refresh lists albums added to the library in the last recentlyAddedDays days.
+func (recent *RecentlyAdded) Refresh() {
+	now := recent.now().In(recent.location)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, recent.location)
+	since := today.AddDate(0, 0, 1-recentlyAddedDays)
+
+	recent.albums = []albumDescription{}
+	for _, album := range recent.albumList.albums() {
+		addedAt, err := time.Parse(time.RFC3339, album.addedAt)
+		if err != nil || addedAt.Before(since) {
+			continue
+		}
+		recent.albums = append(recent.albums, album)
+	}
+	sort.SliceStable(recent.albums, func(i, j int) bool {
+		return recent.albums[i].addedAt > recent.albums[j].addedAt
+	})
+
+	recent.table.RemoveRows()
+	for _, album := range recent.albums {
+		addedAt, _ := time.Parse(time.RFC3339, album.addedAt)
+		recent.table.AppendRow(
+			tui.NewLabel(trimWithCommasIfTooLong(album.title, 2*uiColumnWidth)),
+			tui.NewLabel(trimWithCommasIfTooLong(album.artist, 2*uiColumnWidth)),
+			tui.NewLabel(addedDaysAgo(addedAt.In(recent.location), today)),
+		)
+	}
+	if len(recent.albums) == 0 {
+		recent.status.SetText(fmt.Sprintf("No albums were added in the last %d days", recentlyAddedDays))
+		return
+	}
+	recent.table.SetSelected(0)
+	recent.status.SetText(fmt.Sprintf("%d albums added in the last %d days, press Enter to play the album", len(recent.albums), recentlyAddedDays))
+}
+
+// addedDaysAgo describes how long before today, which is midnight, the album was added.
+func addedDaysAgo(addedAt, today time.Time) string {
+	days := 0
+	for day := today; addedAt.Before(day); day = day.AddDate(0, 0, -1) {
+		days++
+	}
+	switch days {
+	case 0:
+		return "today"
+	case 1:
+		return "yesterday"
+	default:
+		return fmt.Sprintf("%d days ago", days)
+	}
+}
+
+// play plays the album at the given row.
+func (recent *RecentlyAdded) play(row int) {
+	if row < 0 || row >= len(recent.albums) {
+		return
+	}
+	album := recent.albums[row]
+	if err := recent.client.PlayOpt(recent.ctx, &spotify.PlayOptions{PlaybackContext: &album.uri}); err != nil {
+		log.Printf("Could not play album with %s", err)
+		recent.status.SetText(fmt.Sprintf("Could not play %s: %v", album.title, err))
+		return
+	}
+	recent.status.SetText(fmt.Sprintf("Playing %s - %s", album.artist, album.title))
+}
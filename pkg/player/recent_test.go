@@ -0,0 +1,56 @@
+package player
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/zmb3/spotify"
+)
+
+func TestRecentlyAddedListsAlbumsOfLastDays(t *testing.T) {
+	albumList := newEmptyAlbumList(context.Background(), NewDebugClient())
+	albumList.albumsDescriptions = []albumDescription{
+		{artist: "Queen", title: "Jazz", id: "jazz", addedAt: "2020-03-09T23:30:00Z"},
+		{artist: "Pink Floyd", title: "Animals", id: "animals", addedAt: "2020-03-10T08:00:00Z"},
+		{artist: "ABBA", title: "Voulez-Vous", id: "voulez", addedAt: "2020-01-01T12:00:00Z"},
+		{artist: "Queen", title: "Innuendo", id: "innuendo", addedAt: "2020-02-20T12:00:00Z"},
+	}
+	// pinned albums are listed first in the sidebar, but not here
+	albumList.SetPinned([]string{"innuendo"})
+	location := time.FixedZone("UTC+1", 60*60)
+	client := NewDebugClient().(DebugClient)
+	player := &FakePlayer{}
+	client.Player = player
+	recent := NewRecentlyAdded(context.Background(), client, albumList, location)
+	recent.now = func() time.Time { return time.Date(2020, 3, 10, 9, 0, 0, 0, location) }
+
+	recent.Refresh()
+	if ids := albumIDs(recent.albums); !reflect.DeepEqual(ids, []spotify.ID{"animals", "jazz", "innuendo"}) {
+		t.Fatalf("Expected albums added in the last days to be listed, most recent first, got %v", ids)
+	}
+	today := time.Date(2020, 3, 10, 0, 0, 0, 0, location)
+	cases := []struct {
+		addedAt  string
+		expected string
+	}{
+		{"2020-03-09T23:30:00Z", "today"},
+		{"2020-03-09T22:30:00Z", "yesterday"},
+		{"2020-02-20T12:00:00Z", "19 days ago"},
+	}
+	for _, c := range cases {
+		addedAt, _ := time.Parse(time.RFC3339, c.addedAt)
+		if added := addedDaysAgo(addedAt.In(location), today); added != c.expected {
+			t.Fatalf("Expected album added at %s to be added %q, got %q", c.addedAt, c.expected, added)
+		}
+	}
+
+	recent.play(1)
+	if player.playOptCalls != 1 {
+		t.Fatalf("Expected the album to be played, got %d PlayOpt calls", player.playOptCalls)
+	}
+	if status := recent.status.Text(); status != "Playing Queen - Jazz" {
+		t.Fatalf("Unexpected status after playing the album: %q", status)
+	}
+}
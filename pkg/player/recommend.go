@@ -0,0 +1,162 @@
+package player
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/marcusolsson/tui-go"
+	"github.com/zmb3/spotify"
+)
+
+// Recommender decides what to play next after the seed track.
+type Recommender interface {
+	Recommend(seed spotify.FullTrack) ([]spotify.SimpleTrack, error)
+}
+
+var (
+	recommendationsLimit = 20
+	// DefaultRecommender is the name of recommender used when none is configured.
+	DefaultRecommender = "spotify"
+	recommenders       = map[string]func(context.Context, SpotifyClient) Recommender{
+		"spotify": func(ctx context.Context, client SpotifyClient) Recommender {
+			return &spotifyRecommender{ctx: ctx, client: client}
+		},
+		"history": func(ctx context.Context, client SpotifyClient) Recommender {
+			return &historyRecommender{ctx: ctx, client: client}
+		},
+	}
+)
+
+// NewRecommender creates recommender registered under given name,
+// DefaultRecommender is used when name is empty.
+func NewRecommender(ctx context.Context, name string, client SpotifyClient) (Recommender, error) {
+	if name == "" {
+		name = DefaultRecommender
+	}
+	newRecommender, ok := recommenders[name]
+	if !ok {
+		return nil, fmt.Errorf("there is no recommendation provider named %q", name)
+	}
+	return newRecommender(ctx, client), nil
+}
+
+// spotifyRecommender uses Spotify recommendations endpoint seeded
+// with the track and its first artist.
+type spotifyRecommender struct {
+	ctx    context.Context
+	client SpotifyClient
+}
+
+func (r *spotifyRecommender) Recommend(seed spotify.FullTrack) ([]spotify.SimpleTrack, error) {
+	seeds := spotify.Seeds{Tracks: []spotify.ID{seed.ID}}
+	if len(seed.Artists) > 0 {
+		seeds.Artists = []spotify.ID{seed.Artists[0].ID}
+	}
+	recommendations, err := r.client.GetRecommendations(r.ctx, seeds, nil, &spotify.Options{Limit: &recommendationsLimit})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch recommendations: %v", err)
+	}
+	return recommendations.Tracks, nil
+}
+
+// historyRecommender recommends recently played tracks, the ones
+// by artists of the seed track go first.
+type historyRecommender struct {
+	ctx    context.Context
+	client SpotifyClient
+}
+
+func (r *historyRecommender) Recommend(seed spotify.FullTrack) ([]spotify.SimpleTrack, error) {
+	played, err := r.client.PlayerRecentlyPlayedOpt(r.ctx, &spotify.RecentlyPlayedOptions{Limit: 50})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch recently played tracks: %v", err)
+	}
+	seedArtists := map[spotify.ID]bool{}
+	for _, artist := range seed.Artists {
+		seedArtists[artist.ID] = true
+	}
+
+	seen := map[spotify.ID]bool{seed.ID: true}
+	sameArtists := []spotify.SimpleTrack{}
+	others := []spotify.SimpleTrack{}
+	for _, item := range played {
+		if seen[item.Track.ID] {
+			continue
+		}
+		seen[item.Track.ID] = true
+		if hasAnyArtist(item.Track, seedArtists) {
+			sameArtists = append(sameArtists, item.Track)
+		} else {
+			others = append(others, item.Track)
+		}
+	}
+	tracks := append(sameArtists, others...)
+	if len(tracks) > recommendationsLimit {
+		tracks = tracks[:recommendationsLimit]
+	}
+	return tracks, nil
+}
+
+func hasAnyArtist(track spotify.SimpleTrack, artists map[spotify.ID]bool) bool {
+	for _, artist := range track.Artists {
+		if artists[artist.ID] {
+			return true
+		}
+	}
+	return false
+}
+
+// Recommendations represents view with tracks recommended
+// to play after the currently playing one.
+type Recommendations struct {
+	Focusables  []tui.Widget
+	Box         *tui.Box
+	ctx         context.Context
+	client      SpotifyClient
+	recommender Recommender
+	results     appendReseter
+	tracks      searchResultsInterface
+}
+
+// NewRecommendations creates view with tracks recommended by the given recommender,
+// it is empty until refreshed.
+func NewRecommendations(ctx context.Context, client SpotifyClient, recommender Recommender) *Recommendations {
+	results := NewSearchResults(ctx, client, "Recommended next")
+	box := tui.NewVBox(results.getBox())
+	box.SetSizePolicy(tui.Expanding, tui.Expanding)
+	return &Recommendations{
+		Focusables:  []tui.Widget{results.getTable()},
+		Box:         box,
+		ctx:         ctx,
+		client:      client,
+		recommender: recommender,
+		results:     results,
+		tracks:      results,
+	}
+}
+
+// OnAddToPlaylist sets function called with recommended tracks which should be added to a playlist.
+func (r *Recommendations) OnAddToPlaylist(fn func([]spotify.ID)) {
+	r.tracks.onAddToPlaylist(fn)
+}
+
+// Refresh replaces recommendations with ones for currently playing track.
+func (r *Recommendations) Refresh() error {
+	playing, err := r.client.PlayerCurrentlyPlaying(r.ctx)
+	if err != nil {
+		return fmt.Errorf("could not fetch currently playing track: %v", err)
+	}
+	if playing.Item == nil {
+		return fmt.Errorf("recommendations are available only when a track is playing")
+	}
+	tracks, err := r.recommender.Recommend(*playing.Item)
+	if err != nil {
+		return err
+	}
+	r.results.resetSearchResults()
+	for _, track := range tracks {
+		r.results.appendSearchResult(URIName{Name: track.Name, URI: track.URI})
+	}
+	r.results.markSavedTracks()
+	return nil
+}
@@ -0,0 +1,52 @@
+package player
+
+import (
+	"context"
+	"testing"
+
+	"github.com/zmb3/spotify"
+)
+
+func TestNewRecommender(t *testing.T) {
+	for _, name := range []string{"", "spotify", "history"} {
+		if _, err := NewRecommender(context.Background(), name, NewDebugClient()); err != nil {
+			t.Errorf("Did not expect to fail for %q, but it did with %v", name, err)
+		}
+	}
+	if _, err := NewRecommender(context.Background(), "unknown", NewDebugClient()); err == nil {
+		t.Fatalf("Expected to fail for unknown recommender, but it didn't")
+	}
+}
+
+func TestHistoryRecommenderPrefersSameArtists(t *testing.T) {
+	recommender := &historyRecommender{client: NewDebugClient()}
+	seed := spotify.FullTrack{SimpleTrack: spotify.SimpleTrack{
+		ID:      "played1",
+		Artists: []spotify.SimpleArtist{{ID: "artist0"}},
+	}}
+	tracks, err := recommender.Recommend(seed)
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	// DebugClient played 5 distinct tracks, the seed is skipped
+	if len(tracks) != 4 {
+		t.Fatalf("Expected 4 recommendations, got %d", len(tracks))
+	}
+	if tracks[0].Artists[0].ID != "artist0" {
+		t.Fatalf("Expected track by seed artist to go first, got %v", tracks[0].Artists)
+	}
+}
+
+func TestRecommendationsRefresh(t *testing.T) {
+	client := NewDebugClient()
+	recommendations := NewRecommendations(context.Background(), client, &spotifyRecommender{client: client})
+	results := &FakeSearchResult{}
+	recommendations.results = results
+	if err := recommendations.Refresh(); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	// DebugClient recommends 10 tracks
+	if results.resetCalls != 1 || results.appendCalls != 10 {
+		t.Fatalf("Expected to reset once and append 10 times, got %d resets and %d appends", results.resetCalls, results.appendCalls)
+	}
+}
@@ -0,0 +1,35 @@
+package player
+
+import (
+	"fmt"
+	"log"
+	"runtime/debug"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+// Recover shows a panic as an error in the status bar instead of quitting the whole
+// application, i.e. when a view could not be rendered. It has to be deferred.
+func Recover(action string) {
+	if r := recover(); r != nil {
+		log.Printf("Recovered from panic while %s: %v\n%s", action, r, debug.Stack())
+		notifyError(fmt.Errorf("%s failed unexpectedly: %v", action, r))
+	}
+}
+
+// recoveringWidget handles keys with the wrapped widget, recovering from its panics.
+type recoveringWidget struct {
+	tui.Widget
+}
+
+// OnKeyEvent passes the key to the wrapped widget.
+func (w *recoveringWidget) OnKeyEvent(ev tui.KeyEvent) {
+	defer Recover("handling " + ev.Name())
+	w.Widget.OnKeyEvent(ev)
+}
+
+// Recovering wraps the root widget of the ui, so that keys handled by the widgets it
+// contains do not quit the application when they panic, see Recover.
+func Recovering(root tui.Widget) tui.Widget {
+	return &recoveringWidget{Widget: root}
+}
@@ -0,0 +1,45 @@
+package player
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+// panickingWidget panics on each key, like a view which could not be rendered.
+type panickingWidget struct {
+	tui.Label
+}
+
+func (w *panickingWidget) OnKeyEvent(ev tui.KeyEvent) {
+	var rows []string
+	_ = rows[ev.Rune]
+}
+
+func TestRecoveringShowsPanic(t *testing.T) {
+	defer func(previous *StatusBar) { statusBar = previous }(statusBar)
+	status := NewStatusBar()
+	UseStatusBar(status)
+
+	Recovering(&panickingWidget{}).OnKeyEvent(tui.KeyEvent{Key: tui.KeyRune, Rune: 'j'})
+	if text := status.message.Text(); !strings.HasPrefix(text, "handling j failed unexpectedly: runtime error: index out of range") {
+		t.Fatalf("Expected panic to be shown, got %q", text)
+	}
+}
+
+func TestFocusChainRecovers(t *testing.T) {
+	defer func(previous *StatusBar) { statusBar = previous }(statusBar)
+	UseStatusBar(NewStatusBar())
+	first, second := &panickingWidget{}, tui.NewLabel("")
+	chain := &FocusChain{}
+	chain.Set(first, second)
+	focused := chain.FocusDefault()
+	focused.OnKeyEvent(tui.KeyEvent{Key: tui.KeyRune, Rune: 'j'})
+
+	// the focused widget is found once the chain is set again, i.e. when another view is shown
+	chain.Set(first, second)
+	if next := chain.FocusNext(focused); next == nil {
+		t.Fatalf("Expected focused widget to stay in the chain")
+	}
+}
@@ -0,0 +1,451 @@
+package player
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/zmb3/spotify"
+)
+
+// RefreshingClient is a SpotifyClient which refreshes the token and sends the request again
+// when Spotify rejects it with 401 Unauthorized, i.e. when the token was revoked before it
+// expired during a long session, so that the interface does not break until restart.
+type RefreshingClient struct {
+	client  SpotifyClient
+	refresh func() error
+}
+
+// NewRefreshingClient wraps the client, refresh is expected to refresh the token authorizing its requests.
+func NewRefreshingClient(client SpotifyClient, refresh func() error) *RefreshingClient {
+	return &RefreshingClient{client: client, refresh: refresh}
+}
+
+// retry sends the request again once the token is refreshed, when it was unauthorized.
+func (c *RefreshingClient) retry(ctx context.Context, request func() error) error {
+	err := request()
+	if !isUnauthorized(err) || ctx.Err() != nil {
+		return err
+	}
+	if refreshErr := c.refresh(); refreshErr != nil {
+		log.Printf("Could not refresh rejected token with %s", refreshErr)
+		return err
+	}
+	return request()
+}
+
+func isUnauthorized(err error) bool {
+	e, ok := err.(spotify.Error)
+	return ok && e.Status == http.StatusUnauthorized
+}
+
+func (c *RefreshingClient) Pause(ctx context.Context) error {
+	return c.retry(ctx, func() error {
+		return c.client.Pause(ctx)
+	})
+}
+
+func (c *RefreshingClient) Previous(ctx context.Context) error {
+	return c.retry(ctx, func() error {
+		return c.client.Previous(ctx)
+	})
+}
+
+func (c *RefreshingClient) Next(ctx context.Context) error {
+	return c.retry(ctx, func() error {
+		return c.client.Next(ctx)
+	})
+}
+
+func (c *RefreshingClient) Volume(ctx context.Context, percent int) error {
+	return c.retry(ctx, func() error {
+		return c.client.Volume(ctx, percent)
+	})
+}
+
+func (c *RefreshingClient) Seek(ctx context.Context, position int) error {
+	return c.retry(ctx, func() error {
+		return c.client.Seek(ctx, position)
+	})
+}
+
+func (c *RefreshingClient) Shuffle(ctx context.Context, shuffle bool) error {
+	return c.retry(ctx, func() error {
+		return c.client.Shuffle(ctx, shuffle)
+	})
+}
+
+func (c *RefreshingClient) Repeat(ctx context.Context, state string) error {
+	return c.retry(ctx, func() error {
+		return c.client.Repeat(ctx, state)
+	})
+}
+
+func (c *RefreshingClient) PlayerState(ctx context.Context) (*spotify.PlayerState, error) {
+	var result *spotify.PlayerState
+	err := c.retry(ctx, func() (err error) {
+		result, err = c.client.PlayerState(ctx)
+		return err
+	})
+	return result, err
+}
+
+func (c *RefreshingClient) QueueSong(ctx context.Context, trackID spotify.ID) error {
+	return c.retry(ctx, func() error {
+		return c.client.QueueSong(ctx, trackID)
+	})
+}
+
+func (c *RefreshingClient) PlayerQueue(ctx context.Context) (*Queue, error) {
+	var result *Queue
+	err := c.retry(ctx, func() (err error) {
+		result, err = c.client.PlayerQueue(ctx)
+		return err
+	})
+	return result, err
+}
+
+func (c *RefreshingClient) PlayerCurrentlyPlaying(ctx context.Context) (*PlaybackItem, error) {
+	var result *PlaybackItem
+	err := c.retry(ctx, func() (err error) {
+		result, err = c.client.PlayerCurrentlyPlaying(ctx)
+		return err
+	})
+	return result, err
+}
+
+func (c *RefreshingClient) PlayerDevices(ctx context.Context) ([]spotify.PlayerDevice, error) {
+	var result []spotify.PlayerDevice
+	err := c.retry(ctx, func() (err error) {
+		result, err = c.client.PlayerDevices(ctx)
+		return err
+	})
+	return result, err
+}
+
+func (c *RefreshingClient) TransferPlayback(ctx context.Context, deviceID spotify.ID, play bool) error {
+	return c.retry(ctx, func() error {
+		return c.client.TransferPlayback(ctx, deviceID, play)
+	})
+}
+
+func (c *RefreshingClient) CurrentUser(ctx context.Context) (*spotify.PrivateUser, error) {
+	var result *spotify.PrivateUser
+	err := c.retry(ctx, func() (err error) {
+		result, err = c.client.CurrentUser(ctx)
+		return err
+	})
+	return result, err
+}
+
+func (c *RefreshingClient) Play(ctx context.Context) error {
+	return c.retry(ctx, func() error {
+		return c.client.Play(ctx)
+	})
+}
+
+func (c *RefreshingClient) PlayOpt(ctx context.Context, opt *spotify.PlayOptions) error {
+	return c.retry(ctx, func() error {
+		return c.client.PlayOpt(ctx, opt)
+	})
+}
+
+func (c *RefreshingClient) Search(ctx context.Context, query string, searchType spotify.SearchType) (*spotify.SearchResult, error) {
+	var result *spotify.SearchResult
+	err := c.retry(ctx, func() (err error) {
+		result, err = c.client.Search(ctx, query, searchType)
+		return err
+	})
+	return result, err
+}
+
+func (c *RefreshingClient) CurrentUsersAlbumsOpt(ctx context.Context, opt *spotify.Options) (*spotify.SavedAlbumPage, error) {
+	var result *spotify.SavedAlbumPage
+	err := c.retry(ctx, func() (err error) {
+		result, err = c.client.CurrentUsersAlbumsOpt(ctx, opt)
+		return err
+	})
+	return result, err
+}
+
+func (c *RefreshingClient) CurrentUsersFollowedArtistsOpt(ctx context.Context, limit int, after string) (*spotify.FullArtistCursorPage, error) {
+	var result *spotify.FullArtistCursorPage
+	err := c.retry(ctx, func() (err error) {
+		result, err = c.client.CurrentUsersFollowedArtistsOpt(ctx, limit, after)
+		return err
+	})
+	return result, err
+}
+
+func (c *RefreshingClient) GetArtistAlbums(ctx context.Context, artistID spotify.ID) (*spotify.SimpleAlbumPage, error) {
+	var result *spotify.SimpleAlbumPage
+	err := c.retry(ctx, func() (err error) {
+		result, err = c.client.GetArtistAlbums(ctx, artistID)
+		return err
+	})
+	return result, err
+}
+
+func (c *RefreshingClient) GetArtistsTopTracks(ctx context.Context, artistID spotify.ID, country string) ([]spotify.FullTrack, error) {
+	var result []spotify.FullTrack
+	err := c.retry(ctx, func() (err error) {
+		result, err = c.client.GetArtistsTopTracks(ctx, artistID, country)
+		return err
+	})
+	return result, err
+}
+
+func (c *RefreshingClient) GetRelatedArtists(ctx context.Context, artistID spotify.ID) ([]spotify.FullArtist, error) {
+	var result []spotify.FullArtist
+	err := c.retry(ctx, func() (err error) {
+		result, err = c.client.GetRelatedArtists(ctx, artistID)
+		return err
+	})
+	return result, err
+}
+
+func (c *RefreshingClient) FollowArtist(ctx context.Context, artistIDs ...spotify.ID) error {
+	return c.retry(ctx, func() error {
+		return c.client.FollowArtist(ctx, artistIDs...)
+	})
+}
+
+func (c *RefreshingClient) UnfollowArtist(ctx context.Context, artistIDs ...spotify.ID) error {
+	return c.retry(ctx, func() error {
+		return c.client.UnfollowArtist(ctx, artistIDs...)
+	})
+}
+
+func (c *RefreshingClient) CurrentUserFollows(ctx context.Context, t string, ids ...spotify.ID) ([]bool, error) {
+	var result []bool
+	err := c.retry(ctx, func() (err error) {
+		result, err = c.client.CurrentUserFollows(ctx, t, ids...)
+		return err
+	})
+	return result, err
+}
+
+func (c *RefreshingClient) CurrentUsersPlaylistsOpt(ctx context.Context, opt *spotify.Options) (*spotify.SimplePlaylistPage, error) {
+	var result *spotify.SimplePlaylistPage
+	err := c.retry(ctx, func() (err error) {
+		result, err = c.client.CurrentUsersPlaylistsOpt(ctx, opt)
+		return err
+	})
+	return result, err
+}
+
+func (c *RefreshingClient) GetCategoryPlaylistsOpt(ctx context.Context, catID string, opt *spotify.Options) (*spotify.SimplePlaylistPage, error) {
+	var result *spotify.SimplePlaylistPage
+	err := c.retry(ctx, func() (err error) {
+		result, err = c.client.GetCategoryPlaylistsOpt(ctx, catID, opt)
+		return err
+	})
+	return result, err
+}
+
+func (c *RefreshingClient) GetPlaylistTracks(ctx context.Context, playlistID spotify.ID) (*spotify.PlaylistTrackPage, error) {
+	var result *spotify.PlaylistTrackPage
+	err := c.retry(ctx, func() (err error) {
+		result, err = c.client.GetPlaylistTracks(ctx, playlistID)
+		return err
+	})
+	return result, err
+}
+
+func (c *RefreshingClient) CurrentUsersShowsOpt(ctx context.Context, opt *spotify.Options) (*spotify.SavedShowPage, error) {
+	var result *spotify.SavedShowPage
+	err := c.retry(ctx, func() (err error) {
+		result, err = c.client.CurrentUsersShowsOpt(ctx, opt)
+		return err
+	})
+	return result, err
+}
+
+func (c *RefreshingClient) GetShowEpisodesOpt(ctx context.Context, opt *spotify.Options, id string) (*spotify.SimpleEpisodePage, error) {
+	var result *spotify.SimpleEpisodePage
+	err := c.retry(ctx, func() (err error) {
+		result, err = c.client.GetShowEpisodesOpt(ctx, opt, id)
+		return err
+	})
+	return result, err
+}
+
+func (c *RefreshingClient) CurrentUsersAudiobooksOpt(ctx context.Context, opt *spotify.Options) (*AudiobookPage, error) {
+	var result *AudiobookPage
+	err := c.retry(ctx, func() (err error) {
+		result, err = c.client.CurrentUsersAudiobooksOpt(ctx, opt)
+		return err
+	})
+	return result, err
+}
+
+func (c *RefreshingClient) GetAudiobookChaptersOpt(ctx context.Context, opt *spotify.Options, id spotify.ID) (*ChapterPage, error) {
+	var result *ChapterPage
+	err := c.retry(ctx, func() (err error) {
+		result, err = c.client.GetAudiobookChaptersOpt(ctx, opt, id)
+		return err
+	})
+	return result, err
+}
+
+func (c *RefreshingClient) GetAlbumCredits(ctx context.Context, albumID spotify.ID) (*AlbumCredits, error) {
+	var result *AlbumCredits
+	err := c.retry(ctx, func() (err error) {
+		result, err = c.client.GetAlbumCredits(ctx, albumID)
+		return err
+	})
+	return result, err
+}
+
+func (c *RefreshingClient) CurrentUsersTopTracksOpt(ctx context.Context, opt *spotify.Options) (*spotify.FullTrackPage, error) {
+	var result *spotify.FullTrackPage
+	err := c.retry(ctx, func() (err error) {
+		result, err = c.client.CurrentUsersTopTracksOpt(ctx, opt)
+		return err
+	})
+	return result, err
+}
+
+func (c *RefreshingClient) CurrentUsersTopArtistsOpt(ctx context.Context, opt *spotify.Options) (*spotify.FullArtistPage, error) {
+	var result *spotify.FullArtistPage
+	err := c.retry(ctx, func() (err error) {
+		result, err = c.client.CurrentUsersTopArtistsOpt(ctx, opt)
+		return err
+	})
+	return result, err
+}
+
+func (c *RefreshingClient) GetRecommendations(ctx context.Context, seeds spotify.Seeds, trackAttributes *spotify.TrackAttributes, opt *spotify.Options) (*spotify.Recommendations, error) {
+	var result *spotify.Recommendations
+	err := c.retry(ctx, func() (err error) {
+		result, err = c.client.GetRecommendations(ctx, seeds, trackAttributes, opt)
+		return err
+	})
+	return result, err
+}
+
+func (c *RefreshingClient) PlayerRecentlyPlayedOpt(ctx context.Context, opt *spotify.RecentlyPlayedOptions) ([]spotify.RecentlyPlayedItem, error) {
+	var result []spotify.RecentlyPlayedItem
+	err := c.retry(ctx, func() (err error) {
+		result, err = c.client.PlayerRecentlyPlayedOpt(ctx, opt)
+		return err
+	})
+	return result, err
+}
+
+func (c *RefreshingClient) GetPlaylistOpt(ctx context.Context, playlistID spotify.ID, fields string) (*spotify.FullPlaylist, error) {
+	var result *spotify.FullPlaylist
+	err := c.retry(ctx, func() (err error) {
+		result, err = c.client.GetPlaylistOpt(ctx, playlistID, fields)
+		return err
+	})
+	return result, err
+}
+
+func (c *RefreshingClient) GetPlaylistTracksOpt(ctx context.Context, playlistID spotify.ID, opt *spotify.Options, fields string) (*spotify.PlaylistTrackPage, error) {
+	var result *spotify.PlaylistTrackPage
+	err := c.retry(ctx, func() (err error) {
+		result, err = c.client.GetPlaylistTracksOpt(ctx, playlistID, opt, fields)
+		return err
+	})
+	return result, err
+}
+
+func (c *RefreshingClient) AddTracksToPlaylist(ctx context.Context, playlistID spotify.ID, trackIDs ...spotify.ID) (string, error) {
+	var result string
+	err := c.retry(ctx, func() (err error) {
+		result, err = c.client.AddTracksToPlaylist(ctx, playlistID, trackIDs...)
+		return err
+	})
+	return result, err
+}
+
+func (c *RefreshingClient) RemoveTracksFromPlaylistOpt(ctx context.Context, playlistID spotify.ID, tracks []spotify.TrackToRemove, snapshotID string) (string, error) {
+	var result string
+	err := c.retry(ctx, func() (err error) {
+		result, err = c.client.RemoveTracksFromPlaylistOpt(ctx, playlistID, tracks, snapshotID)
+		return err
+	})
+	return result, err
+}
+
+func (c *RefreshingClient) ReorderPlaylistTracks(ctx context.Context, playlistID spotify.ID, opt spotify.PlaylistReorderOptions) (string, error) {
+	var result string
+	err := c.retry(ctx, func() (err error) {
+		result, err = c.client.ReorderPlaylistTracks(ctx, playlistID, opt)
+		return err
+	})
+	return result, err
+}
+
+func (c *RefreshingClient) CreatePlaylistForUser(ctx context.Context, userID string, playlistName string, description string, public bool) (*spotify.FullPlaylist, error) {
+	var result *spotify.FullPlaylist
+	err := c.retry(ctx, func() (err error) {
+		result, err = c.client.CreatePlaylistForUser(ctx, userID, playlistName, description, public)
+		return err
+	})
+	return result, err
+}
+
+func (c *RefreshingClient) ChangePlaylistNameAccessAndDescription(ctx context.Context, playlistID spotify.ID, newName string, newDescription string, public bool) error {
+	return c.retry(ctx, func() error {
+		return c.client.ChangePlaylistNameAccessAndDescription(ctx, playlistID, newName, newDescription, public)
+	})
+}
+
+func (c *RefreshingClient) ChangePlaylistCollaborative(ctx context.Context, playlistID spotify.ID, collaborative bool) error {
+	return c.retry(ctx, func() error {
+		return c.client.ChangePlaylistCollaborative(ctx, playlistID, collaborative)
+	})
+}
+
+func (c *RefreshingClient) AddAlbumsToLibrary(ctx context.Context, albumIDs ...spotify.ID) error {
+	return c.retry(ctx, func() error {
+		return c.client.AddAlbumsToLibrary(ctx, albumIDs...)
+	})
+}
+
+func (c *RefreshingClient) RemoveAlbumsFromLibrary(ctx context.Context, albumIDs ...spotify.ID) error {
+	return c.retry(ctx, func() error {
+		return c.client.RemoveAlbumsFromLibrary(ctx, albumIDs...)
+	})
+}
+
+func (c *RefreshingClient) AddTracksToLibrary(ctx context.Context, trackIDs ...spotify.ID) error {
+	return c.retry(ctx, func() error {
+		return c.client.AddTracksToLibrary(ctx, trackIDs...)
+	})
+}
+
+func (c *RefreshingClient) RemoveTracksFromLibrary(ctx context.Context, trackIDs ...spotify.ID) error {
+	return c.retry(ctx, func() error {
+		return c.client.RemoveTracksFromLibrary(ctx, trackIDs...)
+	})
+}
+
+func (c *RefreshingClient) UserHasTracks(ctx context.Context, trackIDs ...spotify.ID) ([]bool, error) {
+	var result []bool
+	err := c.retry(ctx, func() (err error) {
+		result, err = c.client.UserHasTracks(ctx, trackIDs...)
+		return err
+	})
+	return result, err
+}
+
+func (c *RefreshingClient) CurrentUsersTracksOpt(ctx context.Context, opt *spotify.Options) (*spotify.SavedTrackPage, error) {
+	var result *spotify.SavedTrackPage
+	err := c.retry(ctx, func() (err error) {
+		result, err = c.client.CurrentUsersTracksOpt(ctx, opt)
+		return err
+	})
+	return result, err
+}
+
+func (c *RefreshingClient) GetAudioFeatures(ctx context.Context, ids ...spotify.ID) ([]*spotify.AudioFeatures, error) {
+	var result []*spotify.AudioFeatures
+	err := c.retry(ctx, func() (err error) {
+		result, err = c.client.GetAudioFeatures(ctx, ids...)
+		return err
+	})
+	return result, err
+}
@@ -0,0 +1,53 @@
+package player
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/zmb3/spotify"
+)
+
+// rejectingClient rejects requests with 401 Unauthorized until the token is refreshed.
+type rejectingClient struct {
+	DebugClient
+	refreshed bool
+	requests  int
+}
+
+func (fake *rejectingClient) Next(ctx context.Context) error {
+	fake.requests++
+	if !fake.refreshed {
+		return spotify.Error{Message: "The access token expired", Status: http.StatusUnauthorized}
+	}
+	return nil
+}
+
+func TestRefreshingClientRetriesOnceTokenIsRefreshed(t *testing.T) {
+	fake := &rejectingClient{DebugClient: NewDebugClient().(DebugClient)}
+	client := NewRefreshingClient(fake, func() error {
+		fake.refreshed = true
+		return nil
+	})
+	if err := client.Next(context.Background()); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if fake.requests != 2 {
+		t.Fatalf("Expected request to be sent again once token was refreshed, sent %d times", fake.requests)
+	}
+}
+
+func TestRefreshingClientFailsWhenTokenCannotBeRefreshed(t *testing.T) {
+	fake := &rejectingClient{DebugClient: NewDebugClient().(DebugClient)}
+	client := NewRefreshingClient(fake, func() error {
+		return errors.New("refresh token was revoked")
+	})
+	err := client.Next(context.Background())
+	if err == nil || err.Error() != "The access token expired" {
+		t.Fatalf("Expected to fail with the rejected request, got %v", err)
+	}
+	if fake.requests != 1 {
+		t.Fatalf("Expected request not to be sent again, sent %d times", fake.requests)
+	}
+}
@@ -0,0 +1,186 @@
+package player
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/marcusolsson/tui-go"
+	"github.com/zmb3/spotify"
+)
+
+// RelatedArtists represents sidebar with artists related to the
+// artist of currently playing track.
+type RelatedArtists struct {
+	Focusables []tui.Widget
+	Box        *tui.Box
+	list       *relatedArtistsList
+}
+
+var (
+	relatedArtistsFollowKey   = 'f'
+	relatedArtistsUnfollowKey = 'u'
+	relatedArtistsPlayKey     = 'p'
+)
+
+type relatedArtistsList struct {
+	ctx      context.Context
+	client   SpotifyClient
+	table    *relatedArtistsTable
+	status   *tui.Label
+	artistID spotify.ID
+	artists  []spotify.FullArtist
+	country  string
+}
+
+// relatedArtistsTable is a table of artists which additionally
+// follows, unfollows or plays the selected artist on key press.
+type relatedArtistsTable struct {
+	*countedTable
+	onFollow   func()
+	onUnfollow func()
+	onPlay     func()
+}
+
+// OnKeyEvent follows, unfollows or plays selected artist when table is focused,
+// other keys are handled by the table.
+func (t *relatedArtistsTable) OnKeyEvent(ev tui.KeyEvent) {
+	if t.IsFocused() && ev.Key == tui.KeyRune {
+		switch {
+		case ev.Rune == relatedArtistsFollowKey && t.onFollow != nil:
+			t.onFollow()
+			return
+		case ev.Rune == relatedArtistsUnfollowKey && t.onUnfollow != nil:
+			t.onUnfollow()
+			return
+		case ev.Rune == relatedArtistsPlayKey && t.onPlay != nil:
+			t.onPlay()
+			return
+		}
+	}
+	t.Table.OnKeyEvent(ev)
+}
+
+// NewRelatedArtists creates sidebar with artists related to the artist of currently
+// playing track, it is empty until refreshed.
+func NewRelatedArtists(ctx context.Context, client SpotifyClient) *RelatedArtists {
+	list := &relatedArtistsList{
+		ctx:    ctx,
+		client: client,
+		table:  &relatedArtistsTable{countedTable: newCountedTable()},
+		status: tui.NewLabel(fmt.Sprintf("%c - follow, %c - unfollow, %c - play top tracks", relatedArtistsFollowKey, relatedArtistsUnfollowKey, relatedArtistsPlayKey)),
+	}
+	list.table.onFollow = func() {
+		list.status.SetText(list.follow(list.table.Selected()))
+	}
+	list.table.onUnfollow = func() {
+		list.status.SetText(list.unfollow(list.table.Selected()))
+	}
+	list.table.onPlay = func() {
+		list.status.SetText(list.play(list.table.Selected()))
+	}
+	list.table.OnItemActivated(func(t *tui.Table) {
+		list.status.SetText(list.play(t.Selected()))
+	})
+	list.status.SetWordWrap(true)
+
+	box := tui.NewVBox(list.table, tui.NewSpacer(), list.status)
+	box.SetTitle("Related artists")
+	box.SetBorder(true)
+	box.SetSizePolicy(tui.Preferred, tui.Expanding)
+
+	return &RelatedArtists{
+		Focusables: []tui.Widget{list.table.countedTable},
+		Box:        box,
+		list:       list,
+	}
+}
+
+// Refresh lists artists related to the artist of currently playing track,
+// artists are fetched again only when the artist has changed.
+func (related *RelatedArtists) Refresh() error {
+	playing, err := related.list.client.PlayerCurrentlyPlaying(related.list.ctx)
+	if err != nil {
+		return fmt.Errorf("could not fetch currently playing track: %v", err)
+	}
+	if playing.Item == nil || len(playing.Item.Artists) == 0 {
+		return nil // Nothing to relate to, i.e. podcast episode is playing
+	}
+	return related.list.show(playing.Item.Artists[0].ID)
+}
+
+func (list *relatedArtistsList) show(artistID spotify.ID) error {
+	if artistID == list.artistID {
+		return nil
+	}
+	artists, err := list.client.GetRelatedArtists(list.ctx, artistID)
+	if err != nil {
+		return fmt.Errorf("could not fetch related artists: %v", err)
+	}
+	list.artistID = artistID
+	list.artists = artists
+	list.table.RemoveRows()
+	for _, artist := range artists {
+		list.table.AppendRow(tui.NewLabel(trimWithCommasIfTooLong(artist.Name, uiColumnWidth)))
+	}
+	return nil
+}
+
+// follow follows artist at the selected row, returned text describes the outcome.
+func (list *relatedArtistsList) follow(selectedRow int) string {
+	if selectedRow < 0 || selectedRow >= len(list.artists) {
+		return ""
+	}
+	artist := list.artists[selectedRow]
+	err := list.client.FollowArtist(list.ctx, artist.ID)
+	if err != nil {
+		log.Printf("Could not follow artist %s with %s", artist.Name, err)
+		return "Could not follow " + artist.Name
+	}
+	return "Following " + artist.Name
+}
+
+// unfollow unfollows artist at the selected row, returned text describes the outcome.
+func (list *relatedArtistsList) unfollow(selectedRow int) string {
+	if selectedRow < 0 || selectedRow >= len(list.artists) {
+		return ""
+	}
+	artist := list.artists[selectedRow]
+	err := list.client.UnfollowArtist(list.ctx, artist.ID)
+	if err != nil {
+		log.Printf("Could not unfollow artist %s with %s", artist.Name, err)
+		return "Could not unfollow " + artist.Name
+	}
+	return "Not following " + artist.Name + " anymore"
+}
+
+// play plays top tracks of artist at the selected row, returned text describes the outcome.
+func (list *relatedArtistsList) play(selectedRow int) string {
+	if selectedRow < 0 || selectedRow >= len(list.artists) {
+		return ""
+	}
+	artist := list.artists[selectedRow]
+	if list.country == "" {
+		user, err := list.client.CurrentUser(list.ctx)
+		if err != nil {
+			log.Printf("Could not fetch current user with %s", err)
+			return "Could not play " + artist.Name
+		}
+		list.country = user.Country
+	}
+	tracks, err := list.client.GetArtistsTopTracks(list.ctx, artist.ID, list.country)
+	if err != nil {
+		log.Printf("Could not fetch top tracks of artist %s with %s", artist.Name, err)
+		return "Could not play " + artist.Name
+	}
+	uris := make([]spotify.URI, 0, len(tracks))
+	for _, track := range tracks {
+		uris = append(uris, track.URI)
+	}
+	err = list.client.PlayOpt(list.ctx, &spotify.PlayOptions{URIs: uris})
+	if err != nil {
+		log.Printf("Could not play top tracks of artist %s with %s", artist.Name, err)
+		return "Could not play " + artist.Name
+	}
+	return "Playing top tracks of " + artist.Name
+}
@@ -0,0 +1,81 @@
+package player
+
+import (
+	"context"
+	"testing"
+
+	"github.com/zmb3/spotify"
+)
+
+type fakeFollowingBrowser struct {
+	DebugArtistBrowser
+	relatedCalls int
+	followed     []spotify.ID
+	unfollowed   []spotify.ID
+}
+
+func (fake *fakeFollowingBrowser) GetRelatedArtists(ctx context.Context, artistID spotify.ID) ([]spotify.FullArtist, error) {
+	fake.relatedCalls++
+	return fake.DebugArtistBrowser.GetRelatedArtists(context.Background(), artistID)
+}
+
+func (fake *fakeFollowingBrowser) FollowArtist(ctx context.Context, artistIDs ...spotify.ID) error {
+	fake.followed = append(fake.followed, artistIDs...)
+	return nil
+}
+
+func (fake *fakeFollowingBrowser) UnfollowArtist(ctx context.Context, artistIDs ...spotify.ID) error {
+	fake.unfollowed = append(fake.unfollowed, artistIDs...)
+	return nil
+}
+
+func TestRelatedArtistsRefresh(t *testing.T) {
+	browser := &fakeFollowingBrowser{}
+	client := NewDebugClient().(DebugClient)
+	client.ArtistBrowser = browser
+	related := NewRelatedArtists(context.Background(), client)
+
+	for i := 0; i < 2; i++ {
+		if err := related.Refresh(); err != nil {
+			t.Fatalf("Did not expect to fail, but it did with %v", err)
+		}
+	}
+	if len(related.list.artists) != debugRelatedArtistsCount {
+		t.Fatalf("Expected %d related artists, got %d", debugRelatedArtistsCount, len(related.list.artists))
+	}
+	// Artist did not change between refreshes
+	if browser.relatedCalls != 1 {
+		t.Fatalf("Expected related artists to be fetched once, but they were fetched %d times", browser.relatedCalls)
+	}
+}
+
+func TestRelatedArtistsFollowAndPlay(t *testing.T) {
+	browser := &fakeFollowingBrowser{}
+	client := NewDebugClient().(DebugClient)
+	client.ArtistBrowser = browser
+	related := NewRelatedArtists(context.Background(), client)
+	if err := related.Refresh(); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+
+	status := related.list.follow(1)
+	if len(browser.followed) != 1 || browser.followed[0] != related.list.artists[1].ID {
+		t.Fatalf("Expected to follow %s, followed %v", related.list.artists[1].ID, browser.followed)
+	}
+	if status != "Following "+related.list.artists[1].Name {
+		t.Fatalf("Unexpected status after following: %q", status)
+	}
+	status = related.list.unfollow(1)
+	if len(browser.unfollowed) != 1 || browser.unfollowed[0] != related.list.artists[1].ID {
+		t.Fatalf("Expected to unfollow %s, unfollowed %v", related.list.artists[1].ID, browser.unfollowed)
+	}
+	if status != "Not following "+related.list.artists[1].Name+" anymore" {
+		t.Fatalf("Unexpected status after unfollowing: %q", status)
+	}
+	if status := related.list.play(0); status != "Playing top tracks of "+related.list.artists[0].Name {
+		t.Fatalf("Unexpected status after playing: %q", status)
+	}
+	if status := related.list.follow(debugRelatedArtistsCount); status != "" {
+		t.Fatalf("Expected nothing to happen for row out of range, got status %q", status)
+	}
+}
@@ -0,0 +1,65 @@
+package player
+
+import (
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RequestLogTransport logs each request sent to Spotify Web API along with the status it was
+// answered with and how long it took, i.e. to see why a command run from cron is slow.
+type RequestLogTransport struct {
+	// Base sends the requests, http.DefaultTransport is used when it is nil.
+	Base http.RoundTripper
+	// Logger writes the logs, the standard logger is used when it is nil.
+	Logger *log.Logger
+	// Headers logs headers of requests and responses as well, except for credentials.
+	Headers bool
+}
+
+// RoundTrip sends the request with the base transport and logs it.
+func (t *RequestLogTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	logf := log.Printf
+	if t.Logger != nil {
+		logf = t.Logger.Printf
+	}
+	if t.Headers {
+		for _, line := range headerLines(req.Header) {
+			logf("> %s", line)
+		}
+	}
+	started := time.Now()
+	resp, err := base.RoundTrip(req)
+	took := time.Since(started).Round(time.Millisecond)
+	if err != nil {
+		logf("%s %s failed after %s with %s", req.Method, req.URL, took, err)
+		return resp, err
+	}
+	logf("%s %s %s in %s", req.Method, req.URL, resp.Status, took)
+	if t.Headers {
+		for _, line := range headerLines(resp.Header) {
+			logf("< %s", line)
+		}
+	}
+	return resp, nil
+}
+
+// headerLines gives headers sorted by name, with credentials left out.
+func headerLines(header http.Header) []string {
+	lines := make([]string, 0, len(header))
+	for name, values := range header {
+		value := strings.Join(values, ", ")
+		if name == "Authorization" || name == "Cookie" || name == "Set-Cookie" {
+			value = "[hidden]"
+		}
+		lines = append(lines, name+": "+value)
+	}
+	sort.Strings(lines)
+	return lines
+}
@@ -0,0 +1,42 @@
+package player
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequestLogTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	logs := &bytes.Buffer{}
+	transport := &RequestLogTransport{Logger: log.New(logs, "", 0)}
+	client := NewClient(&http.Client{Transport: transport})
+	client.baseURL = server.URL + "/"
+
+	if err := client.AddAlbumsToLibrary(context.Background(), "album"); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if !strings.HasPrefix(logs.String(), "PUT "+server.URL+"/me/albums?ids=album 204 No Content in ") {
+		t.Fatalf("Expected request to be logged with its status, got %q", logs.String())
+	}
+	if strings.Contains(logs.String(), "Set-Cookie") {
+		t.Fatalf("Expected headers not to be logged, got %q", logs.String())
+	}
+
+	logs.Reset()
+	transport.Headers = true
+	if err := client.AddAlbumsToLibrary(context.Background(), "album"); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if !strings.Contains(logs.String(), "< Set-Cookie: [hidden]\n") || strings.Contains(logs.String(), "secret") {
+		t.Fatalf("Expected headers to be logged without credentials, got %q", logs.String())
+	}
+}
@@ -0,0 +1,194 @@
+package player
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/zmb3/spotify"
+)
+
+// scopeRequirement is a feature of the application which needs permissions,
+// user is not asked for when logging in, to send requests matching method and path.
+type scopeRequirement struct {
+	Methods []string
+	Path    *regexp.Regexp
+	Feature string
+	Scopes  []string
+}
+
+var playlistModifyScopes = []string{spotify.ScopePlaylistModifyPublic, spotify.ScopePlaylistModifyPrivate}
+
+// scopeRequirements are matched against requests failing with 403 Forbidden, in order.
+var scopeRequirements = []scopeRequirement{
+	{
+		Methods: []string{http.MethodPut, http.MethodDelete},
+		Path:    regexp.MustCompile(`/me/(albums|tracks|shows|episodes|audiobooks)$`),
+		Feature: "Saving to the library",
+		Scopes:  []string{spotify.ScopeUserLibraryModify},
+	},
+	{
+		Methods: []string{http.MethodPut, http.MethodDelete},
+		Path:    regexp.MustCompile(`/(me/following|playlists/[^/]+/followers)$`),
+		Feature: "Following",
+		Scopes:  []string{spotify.ScopeUserFollowModify},
+	},
+	{
+		Methods: []string{http.MethodGet},
+		Path:    regexp.MustCompile(`/me/following(/contains)?$`),
+		Feature: "Followed artists",
+		Scopes:  []string{spotify.ScopeUserFollowRead},
+	},
+	{
+		Methods: []string{http.MethodPost, http.MethodPut, http.MethodDelete},
+		Path:    regexp.MustCompile(`/(playlists/[^/]+(/tracks)?|users/[^/]+/playlists)$`),
+		Feature: "Editing playlists",
+		Scopes:  playlistModifyScopes,
+	},
+	{
+		Methods: []string{http.MethodGet},
+		Path:    regexp.MustCompile(`/playlists/[^/]+(/tracks)?$`),
+		Feature: "Collaborative playlists",
+		Scopes:  []string{spotify.ScopePlaylistReadCollaborative},
+	},
+	{
+		Methods: []string{http.MethodGet},
+		Path:    regexp.MustCompile(`/me/top/(artists|tracks)$`),
+		Feature: "Top artists and tracks",
+		Scopes:  []string{spotify.ScopeUserTopRead},
+	},
+	{
+		Methods: []string{http.MethodGet},
+		Path:    regexp.MustCompile(`/me/player/recently-played$`),
+		Feature: "Recently played",
+		Scopes:  []string{spotify.ScopeUserReadRecentlyPlayed},
+	},
+}
+
+// OptionalScopes are permissions which can be granted once a feature needs them.
+func OptionalScopes() []string {
+	var scopes []string
+	seen := map[string]bool{}
+	for _, requirement := range scopeRequirements {
+		for _, scope := range requirement.Scopes {
+			if !seen[scope] {
+				seen[scope] = true
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	return scopes
+}
+
+// MissingScopeError is returned when the feature cannot be used, because user has not granted its scopes.
+type MissingScopeError struct {
+	Feature string
+	Scopes  []string
+}
+
+func (e MissingScopeError) Error() string {
+	return fmt.Sprintf("%s needs permission %s, use grant in the command palette to log in again with it",
+		e.Feature, strings.Join(e.Scopes, ", "))
+}
+
+// ScopeTransport recognizes requests failing with 403 Forbidden because of missing scopes, as
+// Spotify tells in the body of the response, it replaces cryptic error message of Spotify with
+// MissingScopeError and calls OnMissing, so that user can be asked to log in again granting
+// the scopes.
+type ScopeTransport struct {
+	// Base sends the requests, http.DefaultTransport is used when it is nil.
+	Base http.RoundTripper
+
+	mu        sync.Mutex
+	onMissing func(MissingScopeError)
+	missing   []MissingScopeError
+}
+
+// OnMissing sets function called with each feature which could not be used, features which
+// could not be used before it was set, i.e. while the interface was being created, are passed
+// to it right away.
+func (t *ScopeTransport) OnMissing(f func(MissingScopeError)) {
+	t.mu.Lock()
+	t.onMissing = f
+	missing := t.missing
+	t.missing = nil
+	t.mu.Unlock()
+	for _, e := range missing {
+		f(e)
+	}
+}
+
+// RoundTrip sends the request with the base transport.
+func (t *ScopeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusForbidden {
+		return resp, err
+	}
+	missing, ok := missingScope(req)
+	if !ok {
+		return resp, nil
+	}
+	// others are forbidden i.e. to edit playlists of other users, logging in again would not help
+	original, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(original))
+	if err != nil || !insufficientScope(original) {
+		return resp, nil
+	}
+	body, err := json.Marshal(struct {
+		E spotify.Error `json:"error"`
+	}{spotify.Error{Message: missing.Error(), Status: http.StatusForbidden}})
+	if err != nil {
+		return resp, nil
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	resp.Header.Del("Content-Length")
+
+	t.mu.Lock()
+	onMissing := t.onMissing
+	if onMissing == nil {
+		t.missing = append(t.missing, missing)
+	}
+	t.mu.Unlock()
+	if onMissing != nil {
+		onMissing(missing)
+	}
+	return resp, nil
+}
+
+// insufficientScope tells whether Spotify forbade the request in the body of the response,
+// because the token was not granted scopes it needs.
+func insufficientScope(body []byte) bool {
+	var e struct {
+		E spotify.Error `json:"error"`
+	}
+	if err := json.Unmarshal(body, &e); err != nil {
+		return false
+	}
+	return strings.Contains(strings.ToLower(e.E.Message), "insufficient client scope")
+}
+
+// missingScope finds the feature which needs scopes to send the request.
+func missingScope(req *http.Request) (MissingScopeError, bool) {
+	for _, requirement := range scopeRequirements {
+		if !requirement.Path.MatchString(req.URL.Path) {
+			continue
+		}
+		for _, method := range requirement.Methods {
+			if method == req.Method {
+				return MissingScopeError{Feature: requirement.Feature, Scopes: requirement.Scopes}, true
+			}
+		}
+	}
+	return MissingScopeError{}, false
+}
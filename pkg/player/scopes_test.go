@@ -0,0 +1,67 @@
+package player
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/zmb3/spotify"
+)
+
+func TestScopeTransportReportsMissingScopes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"error": {"status": 403, "message": "Insufficient client scope"}}`))
+	}))
+	defer server.Close()
+	transport := &ScopeTransport{}
+	client := NewClient(&http.Client{Transport: transport})
+	client.baseURL = server.URL + "/"
+
+	err := client.AddAlbumsToLibrary(context.Background(), "album")
+	expected := MissingScopeError{Feature: "Saving to the library", Scopes: []string{spotify.ScopeUserLibraryModify}}
+	if err == nil || err.Error() != expected.Error() {
+		t.Fatalf("Expected to fail with %v, got %v", expected, err)
+	}
+
+	var reported []MissingScopeError
+	transport.OnMissing(func(missing MissingScopeError) {
+		reported = append(reported, missing)
+	})
+	if !reflect.DeepEqual(reported, []MissingScopeError{expected}) {
+		t.Fatalf("Expected features missing before OnMissing was set to be reported, got %v", reported)
+	}
+
+	_, err = client.PlayerCurrentlyPlaying(context.Background())
+	if err == nil || err.Error() != "Insufficient client scope" {
+		t.Fatalf("Expected to fail with message from Spotify when scopes are not known, got %v", err)
+	}
+	if len(reported) != 1 {
+		t.Fatalf("Expected only features needing scopes to be reported, got %v", reported)
+	}
+}
+
+func TestScopeTransportPassesOtherForbiddenRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"error": {"status": 403, "message": "You cannot add tracks to a playlist you don't own."}}`))
+	}))
+	defer server.Close()
+	transport := &ScopeTransport{}
+	var reported []MissingScopeError
+	transport.OnMissing(func(missing MissingScopeError) {
+		reported = append(reported, missing)
+	})
+	client := NewClient(&http.Client{Transport: transport})
+	client.baseURL = server.URL + "/"
+
+	_, err := client.AddTracksToPlaylist(context.Background(), "playlist", "track")
+	if err == nil || err.Error() != "You cannot add tracks to a playlist you don't own." {
+		t.Fatalf("Expected to fail with message from Spotify, got %v", err)
+	}
+	if len(reported) != 0 {
+		t.Fatalf("Expected request forbidden for another reason than scopes not to be reported, got %v", reported)
+	}
+}
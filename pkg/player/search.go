@@ -1,7 +1,11 @@
 package player
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"strings"
+	"sync/atomic"
 
 	"github.com/marcusolsson/tui-go"
 	"github.com/zmb3/spotify"
@@ -13,114 +17,368 @@ type URIName struct {
 }
 
 type Search struct {
-	Focusables []tui.Widget
-	Box        *tui.Box
+	Focusables  []tui.Widget
+	Box         *tui.Box
+	songs       searchResultsInterface
+	albums      searchResultsInterface
+	library     AlbumLibrary
+	foundAlbums []spotify.SimpleAlbum
+	status      *tui.Label
+	// saving is set while marked items are being saved, so that they are not saved twice.
+	saving int32
 }
 
-func searchInputOnSubmit(client SpotifyClient, searchedSongs, searchedAlbums, searchedArtists searchResultsInterface) func(*tui.Entry) {
+// searchSelectedMark marks results which are saved with librarySaveSelectedKey.
+var searchSelectedMark = "x"
+
+type searchFilter struct {
+	name        string
+	description string
+}
+
+// searchFilters are field filters understood by Spotify search, they are
+// passed through as a part of the query, i.e. "artist:queen year:1975-1980".
+var searchFilters = []searchFilter{
+	{"album:", "albums with matching name"},
+	{"artist:", "items by matching artist"},
+	{"track:", "tracks with matching name"},
+	{"year:", "released in the year or range, i.e. year:1980-1990"},
+	{"genre:", "artists and tracks of the genre"},
+	{"isrc:", "track with the ISRC code"},
+	{"upc:", "album with the UPC code"},
+	{"tag:new", "albums released in the past two weeks"},
+	{"tag:hipster", "albums with the lowest 10% popularity"},
+}
+
+// searchFiltersHelpMinLength is how many letters of a filter have to be typed for it to be suggested.
+var searchFiltersHelpMinLength = 2
+
+func searchInputOnSubmit(ctx context.Context, client SpotifyClient, searchedSongs, searchedAlbums, searchedArtists searchResultsInterface, onAlbumsFound func([]spotify.SimpleAlbum)) func(*tui.Entry) {
 	return func(entry *tui.Entry) {
-		result, err := client.Search(
+		result, err := client.Search(ctx,
 			entry.Text(),
 			spotify.SearchTypeAlbum|spotify.SearchTypeTrack|spotify.SearchTypeArtist,
 		)
 		if err != nil {
-			log.Fatalf("could not search for %v, %s", entry, err)
+			log.Printf("could not search for %v, %s", entry.Text(), err)
+			return
 		}
 
+		// Filters like tag:new narrow results down to some of the types,
+		// pages of the other types are missing then.
 		searchedAlbums.resetSearchResults()
-		for _, i := range result.Albums.Albums {
-			searchedAlbums.appendSearchResult(URIName{Name: i.Name, URI: i.URI})
+		albums := []spotify.SimpleAlbum{}
+		if result.Albums != nil {
+			for _, i := range result.Albums.Albums {
+				searchedAlbums.appendSearchResult(URIName{Name: i.Name, URI: i.URI})
+			}
+			albums = result.Albums.Albums
 		}
+		onAlbumsFound(albums)
 
 		searchedSongs.resetSearchResults()
-		for _, i := range result.Tracks.Tracks {
-			searchedSongs.appendSearchResult(URIName{Name: i.Name, URI: i.URI})
+		if result.Tracks != nil {
+			for _, i := range result.Tracks.Tracks {
+				searchedSongs.appendSearchResult(URIName{Name: i.Name, URI: i.URI})
+			}
+			searchedSongs.markSavedTracks()
 		}
 
 		searchedArtists.resetSearchResults()
-		for _, i := range result.Artists.Artists {
-			searchedArtists.appendSearchResult(URIName{Name: i.Name, URI: i.URI})
+		if result.Artists != nil {
+			for _, i := range result.Artists.Artists {
+				searchedArtists.appendSearchResult(URIName{Name: i.Name, URI: i.URI})
+			}
+			searchedArtists.markSavedTracks()
 		}
 
 	}
 }
 
 // NewSearch creates data structure which represent search input
-// with search results.
-func NewSearch(client SpotifyClient) *Search {
-	searchedSongs := NewSearchResults(client, "Songs")
-	searchedAlbums := NewSearchResults(client, "Albums")
-	searchedArtists := NewSearchResults(client, "Artists")
+// with search results. Found albums can be saved to or removed from the library,
+// removal is confirmed with the given confirmation. Found albums and tracks can
+// be marked and saved together.
+func NewSearch(ctx context.Context, client SpotifyClient, library AlbumLibrary, confirmation *Confirmation) *Search {
+	searchedSongs := NewSearchResults(ctx, client, "Songs")
+	searchedAlbums := NewSearchResults(ctx, client, "Albums")
+	searchedArtists := NewSearchResults(ctx, client, "Artists")
+	status := tui.NewLabel("")
+	status.SetWordWrap(true)
+
+	search := &Search{
+		songs:   searchedSongs,
+		albums:  searchedAlbums,
+		library: library,
+		status:  status,
+	}
+	selectedAlbum := func() (spotify.SimpleAlbum, bool) {
+		selected := searchedAlbums.getTable().Selected()
+		if selected < 0 || selected >= len(search.foundAlbums) {
+			return spotify.SimpleAlbum{}, false
+		}
+		return search.foundAlbums[selected], true
+	}
+	albumKeys := searchedAlbums.getKeys()
+	albumKeys.onSave = func() {
+		if album, ok := selectedAlbum(); ok {
+			if err := library.SaveAlbum(album); err != nil {
+				notifyError(fmt.Errorf("could not save %s to the library: %v", album.Name, err))
+				return
+			}
+			notify("Saved %s to the library", album.Name)
+		}
+	}
+	albumKeys.onRemove = func() {
+		if album, ok := selectedAlbum(); ok {
+			confirmation.Ask(fmt.Sprintf("Remove %s from the library?", album.Name), func() {
+				if err := library.RemoveAlbum(album.ID); err != nil {
+					notifyError(fmt.Errorf("could not remove %s from the library: %v", album.Name, err))
+					return
+				}
+				notify("Removed %s from the library", album.Name)
+			})
+		}
+	}
+	for _, results := range []searchResultsInterface{searchedSongs, searchedAlbums} {
+		results := results
+		keys := results.getKeys()
+		keys.onSelect = func() { results.toggleSelected(results.getTable().Selected()) }
+		keys.onSaveSelected = search.saveSelectedInBackground
+	}
 
 	searchInput := tui.NewEntry()
 	searchInput.SetSizePolicy(tui.Preferred, tui.Minimum)
-	searchInput.OnSubmit(searchInputOnSubmit(client, searchedSongs, searchedAlbums, searchedArtists))
+	searchInput.OnSubmit(searchInputOnSubmit(ctx, client, searchedSongs, searchedAlbums, searchedArtists, func(albums []spotify.SimpleAlbum) {
+		search.foundAlbums = albums
+	}))
 
-	searchInputBox := tui.NewHBox(searchInput, tui.NewSpacer())
+	filtersHelp := tui.NewLabel("")
+	searchInputBox := tui.NewVBox(tui.NewHBox(clickable(searchInput), tui.NewSpacer()))
 	searchInputBox.SetTitle("Search")
 	searchInputBox.SetBorder(true)
+	searchInput.OnChanged(func(e *tui.Entry) {
+		help := searchFiltersHelp(e.Text())
+		filtersHelp.SetText(help)
+		switch {
+		case help != "" && searchInputBox.Length() == 1:
+			searchInputBox.Append(filtersHelp)
+		case help == "" && searchInputBox.Length() > 1:
+			searchInputBox.Remove(1)
+		}
+	})
 
-	searchResults := tui.NewVBox(searchedSongs.getBox(), searchedAlbums.getBox(), searchedArtists.getBox())
+	searchResults := tui.NewVBox(searchedSongs.getBox(), searchedAlbums.getBox(), searchedArtists.getBox(), status)
 	searchResults.SetTitle("Search Results")
 	searchResults.SetBorder(true)
 
-	return &Search{
-		Focusables: []tui.Widget{searchInput, searchedSongs.getTable(), searchedAlbums.getTable(), searchedArtists.getTable()},
-		Box:        tui.NewVBox(searchInputBox, searchResults),
+	search.Focusables = []tui.Widget{searchInput, searchedSongs.getTable(), searchedAlbums.getTable(), searchedArtists.getTable()}
+	search.Box = tui.NewVBox(searchInputBox, searchResults)
+	return search
+}
+
+// saveSelectedInBackground saves marked albums and tracks without holding the UI,
+// progress is shown in the status line, which is repainted periodically.
+func (search *Search) saveSelectedInBackground() {
+	if !atomic.CompareAndSwapInt32(&search.saving, 0, 1) {
+		return
 	}
+	go func() {
+		defer atomic.StoreInt32(&search.saving, 0)
+		search.status.SetText(search.saveSelected(search.status.SetText))
+	}()
+}
 
+// saveSelected saves marked albums and tracks to the library in batches, progress
+// is reported after each batch. Returned text describes the outcome.
+func (search *Search) saveSelected(progress func(string)) string {
+	albums := []spotify.SimpleAlbum{}
+	for _, row := range search.albums.selectedRows() {
+		if row < len(search.foundAlbums) {
+			albums = append(albums, search.foundAlbums[row])
+		}
+	}
+	tracks := search.songs.selectedRows()
+	total := len(albums) + len(tracks)
+	if total == 0 {
+		return fmt.Sprintf("There is nothing to save, mark albums or tracks with %c first", librarySelectKey)
+	}
+	report := func(saved int) {
+		progress(fmt.Sprintf("Saving to the library, %d of %d done", saved, total))
+	}
+	report(0)
+	for start := 0; start < len(albums); start += librarySaveAlbumsBatchSize {
+		end := start + librarySaveAlbumsBatchSize
+		if end > len(albums) {
+			end = len(albums)
+		}
+		if err := search.library.SaveAlbums(albums[start:end]...); err != nil {
+			return fmt.Sprintf("Saved %d of %d, but could not save albums: %v", start, total, err)
+		}
+		report(end)
+	}
+	search.albums.clearSelected()
+	err := search.songs.saveTracks(tracks, func(saved int) {
+		report(len(albums) + saved)
+	})
+	if err != nil {
+		return fmt.Sprintf("Saved %d albums, but could not save tracks: %v", len(albums), err)
+	}
+	search.songs.clearSelected()
+	return fmt.Sprintf("Saved %d albums and %d tracks to the library", len(albums), len(tracks))
+}
+
+// OnAddToPlaylist sets function called with found tracks which should be added to a playlist.
+func (search *Search) OnAddToPlaylist(fn func([]spotify.ID)) {
+	search.songs.onAddToPlaylist(fn)
+}
+
+// searchFiltersHelp lists filters starting with the word which is being typed,
+// it is empty when no filter is being typed.
+func searchFiltersHelp(query string) string {
+	words := strings.Fields(query)
+	if len(words) == 0 || strings.HasSuffix(query, " ") {
+		return ""
+	}
+	word := strings.ToLower(words[len(words)-1])
+	if len(word) < searchFiltersHelpMinLength {
+		return ""
+	}
+	lines := []string{}
+	for _, filter := range searchFilters {
+		if strings.HasPrefix(filter.name, word) {
+			lines = append(lines, filter.name+" "+filter.description)
+		}
+	}
+	return strings.Join(lines, "\n")
 }
 
 type searchResults struct {
-	table *tui.Table
+	table *countedTable
 	box   *tui.Box
+	keys  *libraryKeys
 	data  []spotify.URI
+	saved *savedTracks
+	// selected tells which of the results are marked, selectMarks show it.
+	selected    []bool
+	selectMarks []*tui.Label
 }
 
 type appendReseter interface {
 	appendSearchResult(URIName)
 	resetSearchResults()
+	// markSavedTracks marks which of the appended tracks are saved in Liked Songs,
+	// and which of the appended artists are followed.
+	markSavedTracks()
 }
 
 type searchResultsInterface interface {
 	appendReseter
 	getBox() *tui.Box
-	getTable() *tui.Table
+	getTable() *countedTable
+	getKeys() *libraryKeys
 	getData() []spotify.URI
-	onItemActivated(SpotifyClient) func(*tui.Table)
+	onItemActivated(context.Context, SpotifyClient) func(*tui.Table)
+	onAddToPlaylist(func([]spotify.ID))
+	// toggleSelected marks or unmarks result at the given row.
+	toggleSelected(int)
+	selectedRows() []int
+	clearSelected()
+	// saveTracks saves tracks at the given rows to Liked Songs, see savedTracks.saveAll.
+	saveTracks([]int, func(int)) error
 }
 
 func (sr *searchResults) appendSearchResult(uriName URIName) {
-	sr.table.AppendRow(tui.NewLabel(uriName.Name))
+	var mark *tui.Label
+	if id := artistID(uriName.URI); id != "" {
+		mark = sr.saved.addArtist(id)
+	} else {
+		mark = sr.saved.add(trackID(uriName.URI))
+	}
+	selectMark := tui.NewLabel("")
+	sr.table.AppendRow(selectMark, mark, tui.NewLabel(uriName.Name))
 	sr.data = append(sr.data, uriName.URI)
+	sr.selected = append(sr.selected, false)
+	sr.selectMarks = append(sr.selectMarks, selectMark)
 }
 
 func (sr *searchResults) resetSearchResults() {
 	sr.table.RemoveRows()
 	sr.data = sr.data[:0]
+	sr.selected = sr.selected[:0]
+	sr.selectMarks = sr.selectMarks[:0]
+	sr.saved.reset()
+}
+
+func (sr *searchResults) toggleSelected(row int) {
+	if row < 0 || row >= len(sr.selected) {
+		return
+	}
+	sr.selected[row] = !sr.selected[row]
+	if sr.selected[row] {
+		sr.selectMarks[row].SetText(searchSelectedMark)
+	} else {
+		sr.selectMarks[row].SetText("")
+	}
+}
+
+func (sr *searchResults) selectedRows() []int {
+	rows := []int{}
+	for row, selected := range sr.selected {
+		if selected {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+func (sr *searchResults) clearSelected() {
+	for row := range sr.selected {
+		sr.selected[row] = false
+		sr.selectMarks[row].SetText("")
+	}
+}
+
+func (sr *searchResults) saveTracks(rows []int, done func(int)) error {
+	return sr.saved.saveAll(rows, done)
+}
+
+func (sr *searchResults) markSavedTracks() {
+	err := sr.saved.fetch()
+	if err != nil {
+		log.Printf("Could not mark saved tracks with %s", err)
+	}
+}
+
+func (sr *searchResults) onAddToPlaylist(fn func([]spotify.ID)) {
+	sr.saved.addToPlaylist = fn
 }
 
 func (sr *searchResults) getBox() *tui.Box {
 	return sr.box
 }
 
-func (sr *searchResults) getTable() *tui.Table {
+func (sr *searchResults) getTable() *countedTable {
 	return sr.table
 }
 
+func (sr *searchResults) getKeys() *libraryKeys {
+	return sr.keys
+}
+
 func (sr *searchResults) getData() []spotify.URI {
 	return sr.data
 }
 
-func (sr *searchResults) onItemActivated(client SpotifyClient) func(*tui.Table) {
+func (sr *searchResults) onItemActivated(ctx context.Context, client SpotifyClient) func(*tui.Table) {
 	return func(t *tui.Table) {
 		selectedRow := t.Selected()
 		trackURI := &sr.data[selectedRow]
-		err := client.PlayOpt(&spotify.PlayOptions{URIs: []spotify.URI{*trackURI}})
+		err := client.PlayOpt(ctx, &spotify.PlayOptions{URIs: []spotify.URI{*trackURI}})
 		if err != nil {
-			err := client.PlayOpt(&spotify.PlayOptions{PlaybackContext: trackURI}) // Fallback to these if previous vall won't work parameters.
+			err := client.PlayOpt(ctx, &spotify.PlayOptions{PlaybackContext: trackURI}) // Fallback to these if previous vall won't work parameters.
 			if err != nil {
-				log.Printf("Could not play searched URI: %s\n", *trackURI)
+				notifyError(fmt.Errorf("could not play %s: %v", *trackURI, err))
 				return
 			}
 		}
@@ -129,10 +387,16 @@ func (sr *searchResults) onItemActivated(client SpotifyClient) func(*tui.Table)
 	}
 }
 
-func NewSearchResults(client SpotifyClient, name string) searchResultsInterface {
-	table := tui.NewTable(0, 0)
+func NewSearchResults(ctx context.Context, client SpotifyClient, name string) searchResultsInterface {
+	return newSearchResults(ctx, client, name)
+}
+
+func newSearchResults(ctx context.Context, client SpotifyClient, name string) *searchResults {
+	table := newCountedTable()
 	data := make([]spotify.URI, 0)
-	box := tui.NewVBox(table, tui.NewSpacer())
+	saved := &savedTracks{ctx: ctx, client: client}
+	keys := saved.keys(table, 0)
+	box := tui.NewVBox(keys, tui.NewSpacer())
 
 	box.SetTitle(name)
 	box.SetBorder(true)
@@ -140,8 +404,10 @@ func NewSearchResults(client SpotifyClient, name string) searchResultsInterface
 	results := &searchResults{
 		table: table,
 		box:   box,
+		keys:  keys,
 		data:  data,
+		saved: saved,
 	}
-	table.OnItemActivated(results.onItemActivated(client))
+	table.OnItemActivated(results.onItemActivated(ctx, client))
 	return results
 }
@@ -2,6 +2,7 @@ package player
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"log"
 	"strings"
@@ -13,7 +14,7 @@ import (
 
 func TestNewSearch(t *testing.T) {
 	client := &DebugClient{}
-	search := NewSearch(client)
+	search := NewSearch(context.Background(), client, newEmptyAlbumList(context.Background(), client), NewConfirmation())
 	if len(search.Focusables) != 4 {
 		t.Fatalf("Expected to have 4 focusables elements, got %d", len(search.Focusables))
 	}
@@ -24,7 +25,7 @@ func TestNewSearch(t *testing.T) {
 
 type FakeSearcher struct{}
 
-func (fs *FakeSearcher) Search(query string, t spotify.SearchType) (*spotify.SearchResult, error) {
+func (fs *FakeSearcher) Search(ctx context.Context, query string, t spotify.SearchType) (*spotify.SearchResult, error) {
 	return &spotify.SearchResult{
 		Albums: &spotify.SimpleAlbumPage{Albums: []spotify.SimpleAlbum{
 			{Name: "Album", URI: "album:uri"},
@@ -43,6 +44,7 @@ type FakeSearchResult struct {
 	searchResultsInterface // I do not actually implement it, but I guarantee that this struct implements these methods (but it does not, but I am not using them so it does not matter)
 	appendCalls            int
 	resetCalls             int
+	markSavedCalls         int
 }
 
 func (fsr *FakeSearchResult) appendSearchResult(uriName URIName) {
@@ -53,6 +55,10 @@ func (fsr *FakeSearchResult) resetSearchResults() {
 	fsr.resetCalls++
 }
 
+func (fsr *FakeSearchResult) markSavedTracks() {
+	fsr.markSavedCalls++
+}
+
 func TestSearchInputOnSubmit(t *testing.T) {
 	client := &DebugClient{}
 	client.Searcher = &FakeSearcher{}
@@ -62,8 +68,14 @@ func TestSearchInputOnSubmit(t *testing.T) {
 	searchedSongs := &FakeSearchResult{}
 	searchedAlbums := &FakeSearchResult{}
 	searchedArtists := &FakeSearchResult{}
-	callback := searchInputOnSubmit(client, searchedSongs, searchedAlbums, searchedArtists)
+	foundAlbums := []spotify.SimpleAlbum{}
+	callback := searchInputOnSubmit(context.Background(), client, searchedSongs, searchedAlbums, searchedArtists, func(albums []spotify.SimpleAlbum) {
+		foundAlbums = albums
+	})
 	callback(&testEntry)
+	if len(foundAlbums) != 2 {
+		t.Fatalf("Expected 2 found albums to be passed on, got %v", foundAlbums)
+	}
 	for _, s := range []*FakeSearchResult{searchedSongs, searchedAlbums, searchedArtists} {
 		if s.resetCalls != 1 {
 			t.Fatalf("Expected to reset old results once, got %d resets", s.resetCalls)
@@ -90,20 +102,20 @@ type FakePlayer struct {
 	playOptErrCallWithContext bool
 }
 
-func (fp *FakePlayer) PlayOpt(opt *spotify.PlayOptions) error {
+func (fp *FakePlayer) PlayOpt(ctx context.Context, opt *spotify.PlayOptions) error {
 	fp.playOptCalls++
 
 	if fp.playOptErrCallWithContext && opt.PlaybackContext != nil {
-		return fmt.Errorf("")
+		return fmt.Errorf("playback failed")
 	}
 	if fp.playOptErrCallWithURI && opt.URIs != nil {
-		return fmt.Errorf("")
+		return fmt.Errorf("playback failed")
 	}
 
 	return nil
 }
 
-func (fp *FakePlayer) Play() error {
+func (fp *FakePlayer) Play(ctx context.Context) error {
 	return nil
 }
 
@@ -118,7 +130,7 @@ func TestOnItemActivatedCallback(t *testing.T) {
 			errCallWithURI:          true,
 			errCallWithContext:      true,
 			expectedPlayOptNumCalls: 2,
-			expectedLogs:            "Could not play searched URI: some:spotify:uri\n",
+			expectedLogs:            "could not play some:spotify:uri: playback failed\n",
 		},
 		{
 			errCallWithURI:          false,
@@ -151,10 +163,10 @@ func TestOnItemActivatedCallback(t *testing.T) {
 		}
 		client.Player = fakePlayer
 
-		results := NewSearchResults(client, "Results")
+		results := NewSearchResults(context.Background(), client, "Results")
 		results.appendSearchResult(URIName{Name: "Name", URI: "some:spotify:uri"})
-		callback := results.onItemActivated(client)
-		callback(results.getTable())
+		callback := results.onItemActivated(context.Background(), client)
+		callback(results.getTable().Table)
 
 		if !strings.HasSuffix(str.String(), c.expectedLogs) {
 			t.Errorf("Expect log to have %s message, but log was %s", c.expectedLogs, str.String())
@@ -168,7 +180,7 @@ func TestOnItemActivatedCallback(t *testing.T) {
 
 func TestAppendRemoveSearchResults(t *testing.T) {
 	client := &DebugClient{}
-	results := NewSearchResults(client, "Results")
+	results := NewSearchResults(context.Background(), client, "Results")
 	testURIName := URIName{URI: "test:spotify:uri", Name: "Test Name"}
 
 	results.appendSearchResult(testURIName)
@@ -181,3 +193,127 @@ func TestAppendRemoveSearchResults(t *testing.T) {
 		t.Fatalf("Expect results to have 0 item, but results have %d items", resultsItemsCount)
 	}
 }
+
+func TestSearchFiltersHelp(t *testing.T) {
+	var tests = []struct {
+		query   string
+		filters []string
+	}{
+		{"", nil},
+		{"queen ar", []string{"artist:"}},
+		{"queen ta", []string{"tag:new", "tag:hipster"}},
+		{"queen tr", []string{"track:"}},
+		{"Tag:N", []string{"tag:new"}},
+		{"artist:", []string{"artist:"}},
+		{"artist:queen", nil},
+		{"artist ", nil},
+		{"a", nil},
+	}
+	for _, test := range tests {
+		help := searchFiltersHelp(test.query)
+		filters := []string{}
+		for _, line := range strings.Split(help, "\n") {
+			if line != "" {
+				filters = append(filters, strings.Fields(line)[0])
+			}
+		}
+		if len(filters) != len(test.filters) || (len(filters) > 0 && strings.Join(filters, ",") != strings.Join(test.filters, ",")) {
+			t.Errorf("For query %q got filters: %v, want: %v", test.query, filters, test.filters)
+		}
+	}
+}
+
+type FakeFilteredSearcher struct{}
+
+func (fs *FakeFilteredSearcher) Search(ctx context.Context, query string, t spotify.SearchType) (*spotify.SearchResult, error) {
+	return &spotify.SearchResult{
+		Albums: &spotify.SimpleAlbumPage{Albums: []spotify.SimpleAlbum{{Name: "New Album", URI: "album:uri"}}},
+	}, nil
+}
+
+func TestSearchInputOnSubmitWithTypeNarrowingFilter(t *testing.T) {
+	client := &DebugClient{}
+	client.Searcher = &FakeFilteredSearcher{}
+	testEntry := tui.Entry{}
+	testEntry.SetText("tag:new")
+
+	searchedSongs := &FakeSearchResult{}
+	searchedAlbums := &FakeSearchResult{}
+	searchedArtists := &FakeSearchResult{}
+	callback := searchInputOnSubmit(context.Background(), client, searchedSongs, searchedAlbums, searchedArtists, func([]spotify.SimpleAlbum) {})
+	callback(&testEntry)
+	if searchedAlbums.appendCalls != 1 || searchedSongs.appendCalls != 0 || searchedArtists.appendCalls != 0 {
+		t.Fatalf("Expected only albums to be found, got %d albums, %d songs and %d artists",
+			searchedAlbums.appendCalls, searchedSongs.appendCalls, searchedArtists.appendCalls)
+	}
+}
+
+type FakeManyResultsSearcher struct{}
+
+func (fs *FakeManyResultsSearcher) Search(ctx context.Context, query string, t spotify.SearchType) (*spotify.SearchResult, error) {
+	tracks := []spotify.FullTrack{}
+	for i := 0; i < 60; i++ {
+		id := spotify.ID(fmt.Sprintf("track%d", i))
+		tracks = append(tracks, spotify.FullTrack{SimpleTrack: spotify.SimpleTrack{ID: id, Name: string(id), URI: "spotify:track:" + spotify.URI(id)}})
+	}
+	return &spotify.SearchResult{
+		Albums: &spotify.SimpleAlbumPage{Albums: []spotify.SimpleAlbum{
+			{ID: "first", Name: "First", URI: "spotify:album:first"},
+			{ID: "second", Name: "Second", URI: "spotify:album:second"},
+		}},
+		Tracks: &spotify.FullTrackPage{Tracks: tracks},
+	}, nil
+}
+
+type fakeTracksBatchEditor struct {
+	fakeLibraryEditor
+	trackBatches []int
+}
+
+func (fake *fakeTracksBatchEditor) AddTracksToLibrary(ctx context.Context, trackIDs ...spotify.ID) error {
+	fake.trackBatches = append(fake.trackBatches, len(trackIDs))
+	return fake.fakeLibraryEditor.AddTracksToLibrary(context.Background(), trackIDs...)
+}
+
+func TestSearchSaveSelected(t *testing.T) {
+	editor := &fakeTracksBatchEditor{}
+	client := NewDebugClient().(DebugClient)
+	client.Searcher = &FakeManyResultsSearcher{}
+	client.LibraryEditor = editor
+	albumList := newEmptyAlbumList(context.Background(), client)
+	search := NewSearch(context.Background(), client, albumList, NewConfirmation())
+	entry := search.Focusables[0].(*tui.Entry)
+	entry.SetText("many")
+	entry.SetFocused(true)
+	entry.OnKeyEvent(tui.KeyEvent{Key: tui.KeyEnter})
+
+	if status := search.saveSelected(func(string) {}); !strings.Contains(status, "nothing to save") {
+		t.Fatalf("Expected nothing to be saved without marked results, got %q", status)
+	}
+	search.albums.toggleSelected(1)
+	for row := 0; row < 55; row++ {
+		search.songs.toggleSelected(row)
+	}
+	search.songs.toggleSelected(0)
+	progress := []string{}
+	status := search.saveSelected(func(text string) { progress = append(progress, text) })
+
+	if status != "Saved 1 albums and 54 tracks to the library" {
+		t.Fatalf("Unexpected status %q", status)
+	}
+	if len(editor.added) != 1 || editor.added[0] != "second" || len(albumList.albums()) != 1 {
+		t.Fatalf("Expected the second album to be saved and listed, saved %v", editor.added)
+	}
+	if len(editor.trackBatches) != 2 || editor.trackBatches[0] != 50 || editor.trackBatches[1] != 4 {
+		t.Fatalf("Expected tracks to be saved in batches of 50 and 4, got %v", editor.trackBatches)
+	}
+	if len(progress) != 4 || progress[3] != "Saving to the library, 55 of 55 done" {
+		t.Fatalf("Expected progress to be reported after each batch, got %v", progress)
+	}
+	if has, _ := client.UserHasTracks(context.Background(), "track0", "track1"); has[0] || !has[1] {
+		t.Fatalf("Expected only marked tracks to be saved, got %v", has)
+	}
+	if rows := search.songs.selectedRows(); len(rows) != 0 {
+		t.Fatalf("Expected marks to be cleared once saved, got %v", rows)
+	}
+}
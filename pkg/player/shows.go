@@ -0,0 +1,206 @@
+package player
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/marcusolsson/tui-go"
+	"github.com/zmb3/spotify"
+)
+
+// Shows represents view with podcasts saved by the user, activating
+// a show lists its episodes, which can be played.
+type Shows struct {
+	Focusables []tui.Widget
+	Box        *tui.Box
+}
+
+var (
+	showsPageSize    = 50
+	episodesPageSize = 20
+	// episodesDescriptionToggleKey collapses and expands description
+	// of the selected episode when episodes table is focused.
+	episodesDescriptionToggleKey = 'd'
+)
+
+type episodesList struct {
+	ctx         context.Context
+	client      SpotifyClient
+	table       *episodesTable
+	description *tui.Label
+	showID      spotify.ID
+	episodes    []spotify.EpisodePage
+	hasNext     bool
+}
+
+// episodesTable is a table of episodes which additionally
+// toggles episode description on key press.
+type episodesTable struct {
+	*countedTable
+	onToggle func()
+}
+
+// OnKeyEvent toggles description when table is focused,
+// other keys are handled by the table.
+func (t *episodesTable) OnKeyEvent(ev tui.KeyEvent) {
+	if t.IsFocused() && ev.Key == tui.KeyRune && ev.Rune == episodesDescriptionToggleKey && t.onToggle != nil {
+		t.onToggle()
+		return
+	}
+	t.Table.OnKeyEvent(ev)
+}
+
+// NewShows creates view with shows saved in the user's library.
+func NewShows(ctx context.Context, client SpotifyClient) (*Shows, error) {
+	page, err := client.CurrentUsersShowsOpt(ctx, &spotify.Options{Limit: &showsPageSize})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch saved shows: %v", err)
+	}
+	shows := page.Shows
+
+	showsTable := newCountedTable()
+	showsTable.AppendRow(
+		tui.NewLabel("Show"),
+		tui.NewLabel("Publisher"),
+	)
+	for _, show := range shows {
+		showsTable.AppendRow(
+			tui.NewLabel(trimWithCommasIfTooLong(show.Name, uiColumnWidth)),
+			tui.NewLabel(trimWithCommasIfTooLong(show.Publisher, uiColumnWidth)),
+		)
+	}
+	showsBox := tui.NewVBox(showsTable, tui.NewSpacer())
+	showsBox.SetTitle("Saved shows")
+	showsBox.SetBorder(true)
+
+	episodes := newEpisodesList(ctx, client)
+	episodes.table.OnSelectionChanged(episodes.onSelectionChanged())
+	episodes.table.OnItemActivated(episodes.onItemActivated())
+	showsTable.OnItemActivated(func(t *tui.Table) {
+		selectedRow := t.Selected()
+		if selectedRow == 0 {
+			return // Selecting table header
+		}
+		show := shows[selectedRow-1]
+		err := episodes.show(show.ID)
+		if err != nil {
+			log.Printf("Could not show episodes of %s with %s", show.Name, err)
+		}
+	})
+
+	episodesBox := tui.NewVBox(episodes.table, tui.NewSpacer())
+	episodesBox.SetTitle("Episodes (d - toggle description)")
+	episodesBox.SetBorder(true)
+	descriptionBox := tui.NewVBox(episodes.description)
+	descriptionBox.SetTitle("Description")
+	descriptionBox.SetBorder(true)
+
+	details := tui.NewVBox(episodesBox, descriptionBox)
+	details.SetSizePolicy(tui.Expanding, tui.Expanding)
+	episodes.table.onToggle = func() {
+		if details.Length() > 1 {
+			details.Remove(1)
+		} else {
+			details.Append(descriptionBox)
+		}
+	}
+
+	return &Shows{
+		Focusables: []tui.Widget{showsTable, episodes.table},
+		Box:        tui.NewHBox(showsBox, details),
+	}, nil
+}
+
+func newEpisodesList(ctx context.Context, client SpotifyClient) *episodesList {
+	description := tui.NewLabel("")
+	description.SetWordWrap(true)
+	return &episodesList{
+		ctx:         ctx,
+		client:      client,
+		table:       &episodesTable{countedTable: newCountedTable()},
+		description: description,
+	}
+}
+
+// show replaces listed episodes with the first page of episodes of the given show.
+func (list *episodesList) show(showID spotify.ID) error {
+	list.showID = showID
+	list.episodes = []spotify.EpisodePage{}
+	list.hasNext = true
+	list.description.SetText("")
+	list.table.RemoveRows()
+	list.table.AppendRow(
+		tui.NewLabel("Released"),
+		tui.NewLabel("Duration"),
+		tui.NewLabel("Episode"),
+	)
+	return list.fetchNextPage()
+}
+
+func (list *episodesList) fetchNextPage() error {
+	if !list.hasNext {
+		return nil
+	}
+	offset := len(list.episodes)
+	page, err := list.client.GetShowEpisodesOpt(list.ctx, &spotify.Options{Limit: &episodesPageSize, Offset: &offset}, string(list.showID))
+	if err != nil {
+		return fmt.Errorf("could not fetch episodes: %v", err)
+	}
+	for _, episode := range page.Episodes {
+		list.table.AppendRow(
+			tui.NewLabel(episode.ReleaseDate),
+			tui.NewLabel(formatEpisodeDuration(episode.Duration_ms)),
+			tui.NewLabel(trimWithCommasIfTooLong(episode.Name, uiColumnWidth)),
+		)
+	}
+	list.episodes = append(list.episodes, page.Episodes...)
+	list.hasNext = page.Next != "" && len(page.Episodes) > 0
+	return nil
+}
+
+func (list *episodesList) onSelectionChanged() func(*tui.Table) {
+	return func(t *tui.Table) {
+		selectedRow := t.Selected()
+		if selectedRow <= 0 || selectedRow > len(list.episodes) {
+			list.description.SetText("")
+			return
+		}
+		list.description.SetText(list.episodes[selectedRow-1].Description)
+		if selectedRow != len(list.episodes) {
+			return
+		}
+		err := list.fetchNextPage()
+		if err != nil {
+			log.Printf("Could not fetch next page of episodes with %s", err)
+		}
+	}
+}
+
+func (list *episodesList) onItemActivated() func(*tui.Table) {
+	return func(t *tui.Table) {
+		selectedRow := t.Selected()
+		if selectedRow <= 0 || selectedRow > len(list.episodes) {
+			return // Selecting table header
+		}
+		episode := list.episodes[selectedRow-1]
+		err := list.client.PlayOpt(list.ctx, &spotify.PlayOptions{URIs: []spotify.URI{episode.URI}})
+		if err != nil {
+			notifyError(fmt.Errorf("could not play episode %s: %v", episode.Name, err))
+		}
+	}
+}
+
+// formatEpisodeDuration formats duration given in milliseconds as h:mm:ss,
+// or as mm:ss for episodes shorter than an hour.
+func formatEpisodeDuration(ms int) string {
+	d := time.Duration(ms) * time.Millisecond
+	hours := int(d / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+	seconds := int(d % time.Minute / time.Second)
+	if hours > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", hours, minutes, seconds)
+	}
+	return fmt.Sprintf("%d:%02d", minutes, seconds)
+}
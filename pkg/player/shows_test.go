@@ -0,0 +1,83 @@
+package player
+
+import (
+	"context"
+	"testing"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+func TestNewShows(t *testing.T) {
+	shows, err := NewShows(context.Background(), NewDebugClient())
+	if err != nil {
+		t.Fatalf("Unexpected error occured: %s", err)
+	}
+	if len(shows.Focusables) != 2 {
+		t.Fatalf("Expected to have 2 focusables elements, got %d", len(shows.Focusables))
+	}
+}
+
+func TestEpisodesListFetchesPagesUsingOffset(t *testing.T) {
+	list := newEpisodesList(context.Background(), NewDebugClient())
+	err := list.show("show1")
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if len(list.episodes) != 20 {
+		t.Fatalf("Expected to fetch first page of 20 episodes, fetched %d", len(list.episodes))
+	}
+
+	callback := list.onSelectionChanged()
+	table := &tui.Table{}
+	table.SetSelected(20)
+	callback(table)
+	// DebugClient returns 25 episodes for each show
+	if len(list.episodes) != 25 {
+		t.Fatalf("Expected to fetch next page, but have %d episodes", len(list.episodes))
+	}
+	if list.hasNext {
+		t.Fatalf("Expected not to have next page after fetching all episodes")
+	}
+	if list.description.Text() != "Description of episode 20" {
+		t.Fatalf("Expected description of selected episode, got %q", list.description.Text())
+	}
+
+	err = list.show("show2")
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if len(list.episodes) != 20 || list.episodes[0].ID != "show2episode1" {
+		t.Fatalf("Expected episodes of previous show to be replaced, got %d episodes", len(list.episodes))
+	}
+}
+
+func TestEpisodesTableTogglesDescription(t *testing.T) {
+	toggles := 0
+	table := &episodesTable{countedTable: newCountedTable(), onToggle: func() { toggles++ }}
+	table.OnKeyEvent(tui.KeyEvent{Key: tui.KeyRune, Rune: 'd'})
+	if toggles != 0 {
+		t.Fatalf("Expected not to toggle description when table is not focused")
+	}
+	table.SetFocused(true)
+	table.OnKeyEvent(tui.KeyEvent{Key: tui.KeyRune, Rune: 'd'})
+	if toggles != 1 {
+		t.Fatalf("Expected to toggle description once, toggled %d times", toggles)
+	}
+}
+
+func TestFormatEpisodeDuration(t *testing.T) {
+	var tests = []struct {
+		ms       int
+		duration string
+	}{
+		{0, "0:00"},
+		{65 * 1000, "1:05"},
+		{(2*3600 + 3*60 + 4) * 1000, "2:03:04"},
+	}
+	for _, test := range tests {
+		got := formatEpisodeDuration(test.ms)
+		if got != test.duration {
+			t.Errorf("Got: %v, want: %v", got, test.duration)
+		}
+	}
+}
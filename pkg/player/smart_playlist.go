@@ -0,0 +1,246 @@
+package player
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/zmb3/spotify"
+)
+
+// SmartPlaylistQuery filters tracks by their audio features, release year and
+// popularity. All of its filters have to match for the track to be included.
+type SmartPlaylistQuery []trackFilter
+
+// trackFilter compares feature of a track with the value, or checks whether it
+// is between value and to, both included, when the operator is "..".
+type trackFilter struct {
+	feature  string
+	operator string
+	value    float64
+	to       float64
+}
+
+// SmartPlaylist is the outcome of creating a smart playlist.
+type SmartPlaylist struct {
+	Playlist *spotify.FullPlaylist
+	// Added is the number of tracks added to the playlist, out of Checked liked tracks.
+	Added   int
+	Checked int
+}
+
+// trackFeatures are values which tracks can be filtered by, features is nil
+// when Spotify has no audio features of the track.
+var trackFeatures = map[string]func(track spotify.FullTrack, features *spotify.AudioFeatures) (float64, bool){
+	"year": func(track spotify.FullTrack, features *spotify.AudioFeatures) (float64, bool) {
+		if len(track.Album.ReleaseDate) < len("2006") {
+			return 0, false
+		}
+		year, err := strconv.Atoi(track.Album.ReleaseDate[:len("2006")])
+		return float64(year), err == nil
+	},
+	"popularity": func(track spotify.FullTrack, features *spotify.AudioFeatures) (float64, bool) {
+		return float64(track.Popularity), true
+	},
+	"tempo":            audioFeature(func(f *spotify.AudioFeatures) float32 { return f.Tempo }),
+	"energy":           audioFeature(func(f *spotify.AudioFeatures) float32 { return f.Energy }),
+	"danceability":     audioFeature(func(f *spotify.AudioFeatures) float32 { return f.Danceability }),
+	"valence":          audioFeature(func(f *spotify.AudioFeatures) float32 { return f.Valence }),
+	"acousticness":     audioFeature(func(f *spotify.AudioFeatures) float32 { return f.Acousticness }),
+	"instrumentalness": audioFeature(func(f *spotify.AudioFeatures) float32 { return f.Instrumentalness }),
+	"liveness":         audioFeature(func(f *spotify.AudioFeatures) float32 { return f.Liveness }),
+	"speechiness":      audioFeature(func(f *spotify.AudioFeatures) float32 { return f.Speechiness }),
+	"loudness":         audioFeature(func(f *spotify.AudioFeatures) float32 { return f.Loudness }),
+}
+
+func audioFeature(value func(*spotify.AudioFeatures) float32) func(spotify.FullTrack, *spotify.AudioFeatures) (float64, bool) {
+	return func(track spotify.FullTrack, features *spotify.AudioFeatures) (float64, bool) {
+		if features == nil {
+			return 0, false
+		}
+		// formatted, so that i.e. 0.7 is not compared as 0.699999988
+		converted, err := strconv.ParseFloat(strconv.FormatFloat(float64(value(features)), 'f', -1, 32), 64)
+		return converted, err == nil
+	}
+}
+
+var (
+	// trackFeatureNames are names of trackFeatures in the order they are suggested.
+	trackFeatureNames = []string{"year", "popularity", "tempo", "energy", "danceability", "valence", "acousticness", "instrumentalness", "liveness", "speechiness", "loudness"}
+	// trackFilterPattern matches a single filter, i.e. "tempo > 120" or "year = 1990..1999".
+	trackFilterPattern = regexp.MustCompile(`^\s*([a-z]+)\s*(>=|<=|>|<|=)\s*(-?[0-9]*\.?[0-9]+)(?:\.\.(-?[0-9]*\.?[0-9]+))?`)
+	// audioFeaturesBatchSize is the limit of tracks whose audio features are fetched with one request.
+	audioFeaturesBatchSize = 100
+)
+
+// ParseSmartPlaylistQuery parses filters separated with spaces or commas, each of them
+// is a feature name, an operator (>, >=, <, <= or =) and a number, i.e. "tempo > 120
+// energy>0.7". Range of values is given with "=", i.e. "year = 1990..1999".
+func ParseSmartPlaylistQuery(query string) (SmartPlaylistQuery, error) {
+	filters := SmartPlaylistQuery{}
+	rest := strings.ToLower(query)
+	for {
+		rest = strings.TrimLeft(rest, " ,")
+		if rest == "" {
+			break
+		}
+		match := trackFilterPattern.FindStringSubmatch(rest)
+		if match == nil {
+			return nil, fmt.Errorf("could not parse filter %q, expected i.e. tempo > 120", rest)
+		}
+		rest = rest[len(match[0]):]
+		filter := trackFilter{feature: match[1], operator: match[2]}
+		if _, ok := trackFeatures[filter.feature]; !ok {
+			return nil, fmt.Errorf("unknown feature %s, expected one of %s", filter.feature, strings.Join(trackFeatureNames, ", "))
+		}
+		var err error
+		if filter.value, err = strconv.ParseFloat(match[3], 64); err != nil {
+			return nil, fmt.Errorf("could not parse value of %s: %v", filter.feature, err)
+		}
+		if match[4] != "" {
+			if filter.operator != "=" {
+				return nil, fmt.Errorf("range of %s has to be given with =, i.e. %s = 1..2", filter.feature, filter.feature)
+			}
+			filter.operator = ".."
+			if filter.to, err = strconv.ParseFloat(match[4], 64); err != nil {
+				return nil, fmt.Errorf("could not parse value of %s: %v", filter.feature, err)
+			}
+		}
+		filters = append(filters, filter)
+	}
+	if len(filters) == 0 {
+		return nil, fmt.Errorf("query has no filters, expected i.e. tempo > 120 energy > 0.7")
+	}
+	return filters, nil
+}
+
+// matches tells whether the feature of the track matches the filter,
+// tracks without the feature never match.
+func (filter trackFilter) matches(track spotify.FullTrack, features *spotify.AudioFeatures) bool {
+	value, ok := trackFeatures[filter.feature](track, features)
+	if !ok {
+		return false
+	}
+	switch filter.operator {
+	case ">":
+		return value > filter.value
+	case ">=":
+		return value >= filter.value
+	case "<":
+		return value < filter.value
+	case "<=":
+		return value <= filter.value
+	case "..":
+		return value >= filter.value && value <= filter.to
+	default:
+		return value == filter.value
+	}
+}
+
+// Matches tells whether the track matches all the filters.
+func (query SmartPlaylistQuery) Matches(track spotify.FullTrack, features *spotify.AudioFeatures) bool {
+	for _, filter := range query {
+		if !filter.matches(track, features) {
+			return false
+		}
+	}
+	return true
+}
+
+// needsAudioFeatures tells whether any filter compares audio features, which have to be fetched.
+func (query SmartPlaylistQuery) needsAudioFeatures() bool {
+	for _, filter := range query {
+		if filter.feature != "year" && filter.feature != "popularity" {
+			return true
+		}
+	}
+	return false
+}
+
+// String formats the query so that it can be parsed again.
+func (query SmartPlaylistQuery) String() string {
+	filters := []string{}
+	for _, filter := range query {
+		value := strconv.FormatFloat(filter.value, 'f', -1, 64)
+		if filter.operator == ".." {
+			filters = append(filters, fmt.Sprintf("%s = %s..%s", filter.feature, value, strconv.FormatFloat(filter.to, 'f', -1, 64)))
+			continue
+		}
+		filters = append(filters, fmt.Sprintf("%s %s %s", filter.feature, filter.operator, value))
+	}
+	return strings.Join(filters, ", ")
+}
+
+// CreateSmartPlaylist creates private playlist of liked tracks matching the query, in the
+// order they were liked. Audio features are fetched in batches, and only when the query
+// needs them. The playlist is not created when none of the tracks matches.
+func CreateSmartPlaylist(ctx context.Context, client SpotifyClient, library *LibraryCache, name string, query SmartPlaylistQuery) (*SmartPlaylist, error) {
+	tracks, err := library.SavedTracks(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	features := map[spotify.ID]*spotify.AudioFeatures{}
+	if query.needsAudioFeatures() {
+		features, err = fetchAudioFeatures(ctx, client, tracks)
+		if err != nil {
+			return nil, err
+		}
+	}
+	trackIDs := []spotify.ID{}
+	for _, track := range tracks {
+		if query.Matches(track.FullTrack, features[track.ID]) {
+			trackIDs = append(trackIDs, track.ID)
+		}
+	}
+	if len(trackIDs) == 0 {
+		return nil, fmt.Errorf("none of %d liked songs matches %s", len(tracks), query)
+	}
+
+	user, err := client.CurrentUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch current user: %v", err)
+	}
+	if name == "" {
+		name = query.String()
+	}
+	playlist, err := client.CreatePlaylistForUser(ctx, user.ID, name, "Liked songs with "+query.String()+", created with spotify-cli", false)
+	if err != nil {
+		return nil, fmt.Errorf("could not create playlist %s: %v", name, err)
+	}
+	if _, err := addTracksToPlaylist(ctx, client, playlist.ID, trackIDs); err != nil {
+		return nil, err
+	}
+	return &SmartPlaylist{Playlist: playlist, Added: len(trackIDs), Checked: len(tracks)}, nil
+}
+
+// fetchAudioFeatures returns audio features of the tracks by their IDs, tracks without them are left out.
+func fetchAudioFeatures(ctx context.Context, client SpotifyClient, tracks []spotify.SavedTrack) (map[spotify.ID]*spotify.AudioFeatures, error) {
+	features := map[spotify.ID]*spotify.AudioFeatures{}
+	for start := 0; start < len(tracks); start += audioFeaturesBatchSize {
+		end := start + audioFeaturesBatchSize
+		if end > len(tracks) {
+			end = len(tracks)
+		}
+		ids := []spotify.ID{}
+		for _, track := range tracks[start:end] {
+			ids = append(ids, track.ID)
+		}
+		batch, err := client.GetAudioFeatures(ctx, ids...)
+		if err != nil {
+			return nil, fmt.Errorf("could not fetch audio features: %v", err)
+		}
+		for _, f := range batch {
+			if f != nil {
+				features[f.ID] = f
+			}
+		}
+	}
+	return features, nil
+}
+
+// Summary describes the outcome of creating the smart playlist.
+func (smart *SmartPlaylist) Summary() string {
+	return fmt.Sprintf("Added %d of %d liked songs to %s", smart.Added, smart.Checked, smart.Playlist.Name)
+}
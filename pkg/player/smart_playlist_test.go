@@ -0,0 +1,100 @@
+package player
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/zmb3/spotify"
+)
+
+func TestParseSmartPlaylistQuery(t *testing.T) {
+	cases := []struct {
+		query    string
+		expected string
+	}{
+		{"tempo > 120 energy>0.7", "tempo > 120, energy > 0.7"},
+		{"Year = 1990..1999, popularity<=50", "year = 1990..1999, popularity <= 50"},
+		{"loudness >= -8", "loudness >= -8"},
+	}
+	for _, c := range cases {
+		query, err := ParseSmartPlaylistQuery(c.query)
+		if err != nil {
+			t.Fatalf("Did not expect to fail, but it did with %v", err)
+		}
+		if query.String() != c.expected {
+			t.Fatalf("Expected %q to be parsed as %q, got %q", c.query, c.expected, query.String())
+		}
+	}
+	for _, invalid := range []string{"", "tempo", "tempo > fast", "mood > 0.5", "year > 1990..1999"} {
+		if _, err := ParseSmartPlaylistQuery(invalid); err == nil {
+			t.Fatalf("Expected to fail parsing %q", invalid)
+		}
+	}
+}
+
+type fakeAudioFeatureFetcher struct {
+	requests int
+}
+
+// GetAudioFeatures returns features with tempo equal to the number in the track ID,
+// there are no features of the track with number 0.
+func (fake *fakeAudioFeatureFetcher) GetAudioFeatures(ctx context.Context, ids ...spotify.ID) ([]*spotify.AudioFeatures, error) {
+	fake.requests++
+	features := []*spotify.AudioFeatures{}
+	for _, id := range ids {
+		var number int
+		fmt.Sscanf(string(id), "track%d", &number)
+		if number == 0 {
+			features = append(features, nil)
+			continue
+		}
+		features = append(features, &spotify.AudioFeatures{ID: id, Tempo: float32(number), Energy: float32(number%10) / 10})
+	}
+	return features, nil
+}
+
+func TestCreateSmartPlaylist(t *testing.T) {
+	fetcher := &fakeAudioFeatureFetcher{}
+	client := NewDebugClient().(DebugClient)
+	client.AudioFeatureFetcher = fetcher
+	ids := []spotify.ID{}
+	for i := 0; i < 150; i++ {
+		ids = append(ids, spotify.ID(fmt.Sprintf("track%03d", i)))
+	}
+	if err := client.AddTracksToLibrary(context.Background(), ids...); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	query, err := ParseSmartPlaylistQuery("tempo >= 140 energy = 0.7..1")
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+
+	smart, err := CreateSmartPlaylist(context.Background(), client, nil, "", query)
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if fetcher.requests != 2 {
+		t.Fatalf("Expected audio features to be fetched in 2 batches, got %d requests", fetcher.requests)
+	}
+	if smart.Playlist.Name != "tempo >= 140, energy = 0.7..1" || smart.Summary() != "Added 3 of 150 liked songs to tempo >= 140, energy = 0.7..1" {
+		t.Fatalf("Unexpected smart playlist: %s", smart.Summary())
+	}
+	tracks, err := fetchPlaylistTracks(context.Background(), client, smart.Playlist.ID)
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if ids := trackIDs(tracks); !reflect.DeepEqual(ids, []spotify.ID{"track147", "track148", "track149"}) {
+		t.Fatalf("Expected matching tracks in the playlist, got %v", ids)
+	}
+
+	// features are not needed to filter by popularity
+	query, _ = ParseSmartPlaylistQuery("popularity > 50")
+	if _, err := CreateSmartPlaylist(context.Background(), client, nil, "Popular", query); err == nil {
+		t.Fatalf("Expected to fail when none of the tracks matches")
+	}
+	if fetcher.requests != 2 {
+		t.Fatalf("Expected audio features not to be fetched, got %d requests", fetcher.requests)
+	}
+}
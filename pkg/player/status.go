@@ -0,0 +1,141 @@
+package player
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+var (
+	// statusAfter is replaced in tests, so that they do not wait for messages to fade out.
+	statusAfter = time.AfterFunc
+	// statusTimeout is how long a message is shown before it fades out.
+	statusTimeout = 4 * time.Second
+	// statusFadeTimeout is how long a faded message is shown before it is removed.
+	statusFadeTimeout = time.Second
+)
+
+// Style names of messages in the status bar, they are plain once faded out.
+var (
+	statusMessageStyle = "status"
+	statusErrorStyle   = "status-error"
+	statusFadedStyle   = "status-faded"
+)
+
+// StatusBar is a line at the bottom of the window telling what the application
+// is waiting for, i.e. for the rate limit of Spotify to pass, and what has just
+// happened, i.e. that playback was transferred or could not be.
+type StatusBar struct {
+	Box     *tui.Box
+	message *tui.Label
+	style   string
+	// lasting is shown until it is cleared, messages which fade out are shown over it
+	lasting string
+	// shown counts messages, so that a message replaced by a newer one does not fade it out
+	shown  int
+	update func(func())
+}
+
+// NewStatusBar creates status bar with no message.
+func NewStatusBar() *StatusBar {
+	message := tui.NewLabel("")
+	message.SetSizePolicy(tui.Expanding, tui.Minimum)
+	return &StatusBar{
+		Box:     tui.NewHBox(message),
+		message: message,
+		update:  func(f func()) { f() },
+	}
+}
+
+// OnUpdate sets function messages are faded out with, once they are shown for a while,
+// i.e. ui.Update, as it happens outside of the UI goroutine.
+func (s *StatusBar) OnUpdate(update func(func())) {
+	s.update = update
+}
+
+// Show shows the message until it is cleared, replacing the previous one.
+func (s *StatusBar) Show(message string) {
+	s.shown++
+	s.lasting = message
+	s.set(message, statusMessageStyle)
+}
+
+// Clear removes the message.
+func (s *StatusBar) Clear() {
+	s.Show("")
+}
+
+// Message shows the message for a while, then it fades out and the message shown before
+// with Show is back.
+func (s *StatusBar) Message(format string, args ...interface{}) {
+	s.showFading(fmt.Sprintf(format, args...), statusMessageStyle)
+}
+
+// Error shows the error the same way as Message, but in red, and logs it.
+func (s *StatusBar) Error(err error) {
+	log.Print(err)
+	s.showFading(err.Error(), statusErrorStyle)
+}
+
+func (s *StatusBar) set(message, style string) {
+	s.style = style
+	s.message.SetStyleName(style)
+	s.message.SetText(message)
+}
+
+func (s *StatusBar) showFading(message, style string) {
+	s.shown++
+	shown := s.shown
+	s.set(message, style)
+	statusAfter(statusTimeout, func() {
+		s.update(func() {
+			if s.shown != shown {
+				return
+			}
+			s.set(message, statusFadedStyle)
+			statusAfter(statusFadeTimeout, func() {
+				s.update(func() {
+					if s.shown == shown {
+						s.Show(s.lasting)
+					}
+				})
+			})
+		})
+	})
+}
+
+// statusBar tells what views have just done, or could not do, they are only logged until
+// UseStatusBar is called.
+var statusBar *StatusBar
+
+// UseStatusBar makes views tell in the status bar what they have just done, or could not do.
+func UseStatusBar(status *StatusBar) {
+	statusBar = status
+}
+
+// notify tells what has just been done in the status bar.
+func notify(format string, args ...interface{}) {
+	if statusBar != nil {
+		statusBar.Message(format, args...)
+	}
+}
+
+// notifyError tells what could not be done in the status bar, and logs it.
+func notifyError(err error) {
+	if statusBar == nil {
+		log.Print(err)
+		return
+	}
+	statusBar.Error(err)
+}
+
+// RateLimited shows for how long requests are held back, the message is removed once it is 0.
+func (s *StatusBar) RateLimited(retryIn time.Duration) {
+	if retryIn == 0 {
+		s.Clear()
+		return
+	}
+	s.Show(fmt.Sprintf("Rate limited, retrying in %ds", int(retryIn.Round(time.Second)/time.Second)))
+}
@@ -0,0 +1,54 @@
+package player
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStatusBarRateLimited(t *testing.T) {
+	status := NewStatusBar()
+	status.RateLimited(3 * time.Second)
+	if text := status.message.Text(); text != "Rate limited, retrying in 3s" {
+		t.Fatalf("Expected rate limit to be shown, got %q", text)
+	}
+	status.RateLimited(0)
+	if text := status.message.Text(); text != "" {
+		t.Fatalf("Expected message to be removed once requests go through, got %q", text)
+	}
+}
+
+func TestStatusBarMessageFadesOut(t *testing.T) {
+	timers := []func(){}
+	defer func(previous func(time.Duration, func()) *time.Timer) { statusAfter = previous }(statusAfter)
+	statusAfter = func(d time.Duration, f func()) *time.Timer {
+		timers = append(timers, f)
+		return nil
+	}
+	status := NewStatusBar()
+	status.Show("Offline")
+	status.Error(errors.New("could not play next track"))
+	if text := status.message.Text(); text != "could not play next track" || status.style != statusErrorStyle {
+		t.Fatalf("Expected error to be shown, got %q in %s", text, status.style)
+	}
+	timers[0]()
+	if text := status.message.Text(); text != "could not play next track" || status.style != statusFadedStyle {
+		t.Fatalf("Expected error to fade out, got %q in %s", text, status.style)
+	}
+	timers[1]()
+	if text := status.message.Text(); text != "Offline" || status.style != statusMessageStyle {
+		t.Fatalf("Expected lasting message to be back, got %q in %s", text, status.style)
+	}
+
+	status.Message("Playback transferred to %s", "Kitchen")
+	status.Message("Playback transferred to %s", "Laptop")
+	timers[2]()
+	if status.style != statusMessageStyle {
+		t.Fatalf("Expected newer message not to fade out with the one it replaced, got %s", status.style)
+	}
+	timers[3]()
+	timers[4]()
+	if text := status.message.Text(); text != "Offline" {
+		t.Fatalf("Expected newer message to fade out, got %q", text)
+	}
+}
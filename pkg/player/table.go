@@ -0,0 +1,66 @@
+package player
+
+import (
+	"github.com/jedruniu/spotify-cli/pkg/ui"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+// rowsTable is a widget of a table which knows how many rows it has, tui-go keeps the number
+// to itself. Vim keys and the mouse move the selection in such widgets.
+type rowsTable interface {
+	tui.Widget
+	table() *tui.Table
+	rows() int
+}
+
+// countedTable is a table counting its rows as they are appended and removed, tables of the
+// player are created as such, so that their wrappers are rowsTable as well.
+type countedTable struct {
+	*tui.Table
+	count int
+}
+
+var _ rowsTable = &countedTable{}
+
+func newCountedTable() *countedTable {
+	return &countedTable{Table: tui.NewTable(0, 0)}
+}
+
+// backend creates widgets of views built on ui.Backend, its lists are counted tables.
+var backend ui.Backend = ui.TuiGo{NewTable: func() ui.TuiGoTable { return newCountedTable() }}
+
+// AppendRow adds a new row at the end.
+func (t *countedTable) AppendRow(row ...tui.Widget) {
+	t.Table.AppendRow(row...)
+	t.count++
+}
+
+// RemoveRow removes the row at the index.
+func (t *countedTable) RemoveRow(index int) {
+	t.Table.RemoveRow(index)
+	// the same way the grid of the table does
+	if index < t.count {
+		t.count--
+	}
+}
+
+// RemoveRows removes all the rows.
+func (t *countedTable) RemoveRows() {
+	t.Table.RemoveRows()
+	t.count = 0
+}
+
+// Draw draws the table, recording where it is drawn for the mouse.
+func (t *countedTable) Draw(p *tui.Painter) {
+	markOrigin(p, t.Table)
+	t.Table.Draw(p)
+}
+
+func (t *countedTable) table() *tui.Table {
+	return t.Table
+}
+
+func (t *countedTable) rows() int {
+	return t.count
+}
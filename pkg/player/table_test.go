@@ -0,0 +1,29 @@
+package player
+
+import (
+	"testing"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+func TestCountedTableCountsRows(t *testing.T) {
+	table := newCountedTable()
+	for i := 0; i < 3; i++ {
+		table.AppendRow(tui.NewLabel("row"))
+	}
+	table.RemoveRow(1)
+	// there is no such row
+	table.RemoveRow(5)
+	if table.rows() != 2 {
+		t.Fatalf("Expected 2 rows, got %d", table.rows())
+	}
+	table.RemoveRows()
+	if table.rows() != 0 {
+		t.Fatalf("Expected no rows, got %d", table.rows())
+	}
+
+	var wrapped tui.Widget = newTypeAheadTable(table, func(string) bool { return false }, func(string) {})
+	if _, ok := wrapped.(rowsTable); !ok {
+		t.Fatalf("Expected wrapped table to count its rows")
+	}
+}
@@ -0,0 +1,108 @@
+package player
+
+import (
+	"fmt"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+// tabStyle and tabSelectedStyle are style names of titles in the tab bar, the selected
+// one is the tab of the view shown in the main area.
+var (
+	tabStyle         = "tab"
+	tabSelectedStyle = "tab-selected"
+)
+
+// Tab groups views of the main area under a title listed in the tab bar.
+type Tab struct {
+	Title string
+	// Views are names of views of the tab, the first one is shown when the tab is
+	// switched to for the first time.
+	Views []string
+	// Refresh is called, when given, each time the tab is switched to, before its view is shown.
+	Refresh func() error
+}
+
+// Tabs is the tab bar above the main area, switching to a tab shows the view of the tab
+// shown the last time.
+type Tabs struct {
+	Box      *tui.Box
+	mainArea *MainArea
+	tabs     []Tab
+	titles   []*tui.Label
+	// shown is the view of each tab shown the last time.
+	shown   []string
+	current int
+}
+
+// NewTabs creates tab bar of the main area with the given tabs, numbered from 1. Views
+// which were not added to the main area are left out.
+func NewTabs(mainArea *MainArea, tabs ...Tab) *Tabs {
+	bar := &Tabs{Box: tui.NewHBox(), mainArea: mainArea, current: -1}
+	for i, tab := range tabs {
+		views := []string{}
+		for _, name := range tab.Views {
+			if _, ok := mainArea.views[name]; ok {
+				views = append(views, name)
+			}
+		}
+		tab.Views = views
+		title := tui.NewLabel(fmt.Sprintf(" %d %s ", i+1, tab.Title))
+		bar.tabs = append(bar.tabs, tab)
+		bar.titles = append(bar.titles, title)
+		bar.shown = append(bar.shown, "")
+		bar.Box.Append(title)
+	}
+	bar.Box.Append(tui.NewSpacer())
+	bar.Update()
+	return bar
+}
+
+// Switch shows the view of the tab with the given number, counted from 1. When the tab is
+// already selected, its first view is shown.
+func (tabs *Tabs) Switch(number int) error {
+	if number < 1 || number > len(tabs.tabs) {
+		return fmt.Errorf("there is no tab %d, there are %d", number, len(tabs.tabs))
+	}
+	index := number - 1
+	tab := tabs.tabs[index]
+	if len(tab.Views) == 0 {
+		return fmt.Errorf("there are no views in tab %s", tab.Title)
+	}
+	name := tabs.shown[index]
+	if name == "" || index == tabs.current {
+		name = tab.Views[0]
+	}
+	if tab.Refresh != nil {
+		if err := tab.Refresh(); err != nil {
+			return err
+		}
+	}
+	return tabs.mainArea.Show(name)
+}
+
+// Update selects the tab of the view shown in the main area, none when the view is in no tab.
+// It has to be called each time a view is shown.
+func (tabs *Tabs) Update() {
+	tabs.current = -1
+	for i, tab := range tabs.tabs {
+		for _, name := range tab.Views {
+			if name == tabs.mainArea.current {
+				tabs.current = i
+				tabs.shown[i] = name
+			}
+		}
+	}
+	for i, title := range tabs.titles {
+		if i == tabs.current {
+			title.SetStyleName(tabSelectedStyle)
+		} else {
+			title.SetStyleName(tabStyle)
+		}
+	}
+}
+
+// Current returns number of the selected tab counted from 1, 0 when the shown view is in no tab.
+func (tabs *Tabs) Current() int {
+	return tabs.current + 1
+}
@@ -0,0 +1,58 @@
+package player
+
+import (
+	"testing"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+func TestTabsSwitchToViewShownLastTime(t *testing.T) {
+	mainArea := NewMainArea()
+	home, top, liked := tui.NewLabel("home"), tui.NewLabel("top"), tui.NewLabel("liked")
+	mainArea.Add("home", View{Widget: home})
+	mainArea.Add("top", View{Widget: top})
+	mainArea.Add("liked", View{Widget: liked})
+	mainArea.Add("keys", View{Widget: tui.NewLabel("keys")})
+	refreshed := 0
+	tabs := NewTabs(mainArea,
+		Tab{Title: "Library", Views: []string{"liked"}, Refresh: func() error { refreshed++; return nil }},
+		Tab{Title: "Browse", Views: []string{"home", "top", "unknown"}},
+	)
+	mainArea.OnShow(func(View) { tabs.Update() })
+	if tabs.Current() != 2 {
+		t.Fatalf("Expected tab of the first view to be selected, got %d", tabs.Current())
+	}
+
+	if err := mainArea.Show("top"); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if err := tabs.Switch(1); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if mainArea.Current().Widget != liked || tabs.Current() != 1 || refreshed != 1 {
+		t.Fatalf("Expected refreshed liked view to be shown in tab 1, got tab %d refreshed %d times", tabs.Current(), refreshed)
+	}
+	if err := tabs.Switch(2); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if mainArea.Current().Widget != top {
+		t.Fatalf("Expected view shown last time in the tab to be shown")
+	}
+	// switching to the selected tab goes back to its first view
+	if err := tabs.Switch(2); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if mainArea.Current().Widget != home {
+		t.Fatalf("Expected first view of the tab to be shown")
+	}
+
+	if err := mainArea.Show("keys"); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if tabs.Current() != 0 {
+		t.Fatalf("Expected no tab to be selected for view in no tab, got %d", tabs.Current())
+	}
+	if err := tabs.Switch(3); err == nil {
+		t.Fatalf("Expected to fail for unknown tab, but it didn't")
+	}
+}
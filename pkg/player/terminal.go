@@ -0,0 +1,310 @@
+package player
+
+import (
+	"image"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell"
+	"github.com/marcusolsson/tui-go"
+)
+
+// doubleClickTimeout is how soon the second click at the same position has to follow
+// the first one, to make a double click.
+var doubleClickTimeout = 500 * time.Millisecond
+
+// MouseButton is what was done with the mouse.
+type MouseButton int
+
+const (
+	// MouseClick is a press of the left button.
+	MouseClick MouseButton = iota
+	// MouseWheelUp is a scroll of the wheel away from the user.
+	MouseWheelUp
+	// MouseWheelDown is a scroll of the wheel towards the user.
+	MouseWheelDown
+)
+
+// MouseEvent is a click or a scroll of the wheel at the position of the terminal.
+type MouseEvent struct {
+	Pos    image.Point
+	Button MouseButton
+	// DoubleClick tells whether the click quickly follows the previous one at the same position.
+	DoubleClick bool
+}
+
+// Terminal runs the ui on a tcell screen like tui.New does, it reports mouse events as well,
+// which tui-go leaves out.
+type Terminal struct {
+	screen      tcell.Screen
+	surface     *terminalSurface
+	painter     *tui.Painter
+	root        tui.Widget
+	keybindings []terminalKeybinding
+	chain       tui.FocusChain
+	focused     tui.Widget
+	onMouse     func(MouseEvent)
+	events      chan func()
+	quit        chan struct{}
+
+	// pressed are buttons pressed at the last mouse event, lastClick tells when and where
+	// the last click was.
+	pressed     tcell.ButtonMask
+	lastClick   time.Time
+	lastClickAt image.Point
+	now         func() time.Time
+}
+
+type terminalKeybinding struct {
+	key     string
+	handler func()
+}
+
+var _ tui.UI = &Terminal{}
+
+// NewTerminal creates the ui with the root widget, styled with tui.DefaultTheme.
+func NewTerminal(root tui.Widget) (*Terminal, error) {
+	screen, err := tcell.NewScreen()
+	if err != nil {
+		return nil, err
+	}
+	surface := &terminalSurface{screen: screen}
+	return &Terminal{
+		screen:  screen,
+		surface: surface,
+		painter: tui.NewPainter(markingSurface{surface}, tui.DefaultTheme),
+		root:    root,
+		chain:   &tui.SimpleFocusChain{},
+		events:  make(chan func()),
+		quit:    make(chan struct{}, 1),
+		now:     time.Now,
+	}, nil
+}
+
+// Size returns size of the terminal.
+func (t *Terminal) Size() image.Point {
+	return t.surface.Size()
+}
+
+// OnMouse sets function called with each click and scroll of the wheel, in the ui goroutine.
+// The mouse is left to the terminal, which selects text with it, unless it is set before Run.
+func (t *Terminal) OnMouse(fn func(MouseEvent)) {
+	t.onMouse = fn
+}
+
+// SetWidget sets the root widget of the ui.
+func (t *Terminal) SetWidget(w tui.Widget) {
+	t.root = w
+}
+
+// SetTheme sets styles of the ui.
+func (t *Terminal) SetTheme(theme *tui.Theme) {
+	t.painter = tui.NewPainter(markingSurface{t.surface}, theme)
+}
+
+// SetKeybinding calls fn each time the key, named like tui.KeyEvent.Name does, is pressed.
+func (t *Terminal) SetKeybinding(key string, fn func()) {
+	t.keybindings = append(t.keybindings, terminalKeybinding{key: key, handler: fn})
+}
+
+// ClearKeybindings removes all keybindings.
+func (t *Terminal) ClearKeybindings() {
+	t.keybindings = nil
+}
+
+// SetFocusChain sets widgets focused with Tab and Backtab, focusing the default one.
+func (t *Terminal) SetFocusChain(chain tui.FocusChain) {
+	if t.focused != nil {
+		t.focused.SetFocused(false)
+	}
+	t.chain = chain
+	t.focused = chain.FocusDefault()
+	if t.focused != nil {
+		t.focused.SetFocused(true)
+	}
+}
+
+// Run shows the ui until Quit is called.
+func (t *Terminal) Run() error {
+	if err := t.screen.Init(); err != nil {
+		return err
+	}
+	if t.onMouse != nil {
+		t.screen.EnableMouse()
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			t.screen.Fini()
+			log.Printf("Terminal interface failed unexpectedly: %v", r)
+		}
+	}()
+
+	if w := t.chain.FocusDefault(); w != nil {
+		w.SetFocused(true)
+		t.focused = w
+	}
+	t.screen.SetStyle(tcell.StyleDefault)
+	t.screen.Clear()
+
+	go func() {
+		for {
+			switch ev := t.screen.PollEvent().(type) {
+			case *tcell.EventKey:
+				key := tui.KeyEvent{Key: tui.Key(ev.Key()), Rune: ev.Rune(), Modifiers: tui.ModMask(ev.Modifiers())}
+				t.events <- func() { t.handleKey(key) }
+			case *tcell.EventMouse:
+				t.events <- func() { t.handleMouse(ev) }
+			case *tcell.EventResize:
+				t.events <- func() {}
+			case nil:
+				// the screen is finished once the ui quits
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-t.quit:
+			return nil
+		case fn := <-t.events:
+			fn()
+			t.painter.Repaint(t.root)
+		}
+	}
+}
+
+// Update runs fn in the ui goroutine and waits for it, it must not be called from there.
+func (t *Terminal) Update(fn func()) {
+	done := make(chan struct{})
+	t.events <- func() {
+		fn()
+		close(done)
+	}
+	<-done
+}
+
+// Quit stops showing the ui.
+func (t *Terminal) Quit() {
+	t.screen.Fini()
+	t.quit <- struct{}{}
+}
+
+func (t *Terminal) handleKey(ev tui.KeyEvent) {
+	for _, binding := range t.keybindings {
+		if strings.EqualFold(binding.key, ev.Name()) {
+			binding.handler()
+		}
+	}
+	if t.focused != nil {
+		switch ev.Key {
+		case tui.KeyTab:
+			t.move(t.chain.FocusNext(t.focused))
+		case tui.KeyBacktab:
+			t.move(t.chain.FocusPrev(t.focused))
+		}
+	}
+	t.root.OnKeyEvent(ev)
+}
+
+// move moves focus to the widget, focus stays when there is none.
+func (t *Terminal) move(w tui.Widget) {
+	if w == nil {
+		return
+	}
+	t.focused.SetFocused(false)
+	t.focused = w
+	w.SetFocused(true)
+}
+
+func (t *Terminal) handleMouse(ev *tcell.EventMouse) {
+	x, y := ev.Position()
+	pos := image.Point{x, y}
+	buttons := ev.Buttons()
+	pressed := t.pressed
+	t.pressed = buttons
+	if t.onMouse == nil {
+		return
+	}
+	switch {
+	case buttons&tcell.WheelUp != 0:
+		t.onMouse(MouseEvent{Pos: pos, Button: MouseWheelUp})
+	case buttons&tcell.WheelDown != 0:
+		t.onMouse(MouseEvent{Pos: pos, Button: MouseWheelDown})
+	case buttons&tcell.Button1 != 0 && pressed&tcell.Button1 == 0:
+		now := t.now()
+		double := pos == t.lastClickAt && now.Sub(t.lastClick) <= doubleClickTimeout
+		t.lastClick, t.lastClickAt = now, pos
+		if double {
+			// the third click starts another double click
+			t.lastClick = time.Time{}
+		}
+		t.onMouse(MouseEvent{Pos: pos, Button: MouseClick, DoubleClick: double})
+	}
+}
+
+// terminalSurface paints on the tcell screen.
+type terminalSurface struct {
+	screen tcell.Screen
+}
+
+func (s *terminalSurface) SetCell(x, y int, ch rune, style tui.Style) {
+	st := tcell.StyleDefault.Normal().
+		Foreground(terminalColor(style.Fg)).
+		Background(terminalColor(style.Bg)).
+		Reverse(style.Reverse == tui.DecorationOn).
+		Bold(style.Bold == tui.DecorationOn).
+		Underline(style.Underline == tui.DecorationOn)
+	s.screen.SetContent(x, y, ch, nil, st)
+}
+
+func (s *terminalSurface) SetCursor(x, y int) {
+	s.screen.ShowCursor(x, y)
+}
+
+func (s *terminalSurface) HideCursor() {
+	s.screen.HideCursor()
+}
+
+func (s *terminalSurface) Begin() {
+	s.screen.Clear()
+}
+
+func (s *terminalSurface) End() {
+	s.screen.Show()
+}
+
+func (s *terminalSurface) Size() image.Point {
+	w, h := s.screen.Size()
+	return image.Point{w, h}
+}
+
+// terminalColor converts the color the same way tui-go does.
+func terminalColor(color tui.Color) tcell.Color {
+	switch color {
+	case tui.ColorDefault:
+		return tcell.ColorDefault
+	case tui.ColorBlack:
+		return tcell.ColorBlack
+	case tui.ColorWhite:
+		return tcell.ColorWhite
+	case tui.ColorRed:
+		return tcell.ColorRed
+	case tui.ColorGreen:
+		return tcell.ColorGreen
+	case tui.ColorBlue:
+		return tcell.ColorBlue
+	case tui.ColorCyan:
+		return tcell.ColorDarkCyan
+	case tui.ColorMagenta:
+		return tcell.ColorDarkMagenta
+	case tui.ColorYellow:
+		return tcell.ColorYellow
+	default:
+		if color > 0 {
+			return tcell.Color(color)
+		}
+		return tcell.ColorDefault
+	}
+}
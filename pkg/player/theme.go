@@ -0,0 +1,51 @@
+package player
+
+import (
+	"github.com/jedruniu/spotify-cli/pkg/config"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+// themeColors maps names of colors which can be used in the theme to terminal colors.
+var themeColors = map[string]tui.Color{
+	"default": tui.ColorDefault,
+	"black":   tui.ColorBlack,
+	"red":     tui.ColorRed,
+	"green":   tui.ColorGreen,
+	"yellow":  tui.ColorYellow,
+	"blue":    tui.ColorBlue,
+	"magenta": tui.ColorMagenta,
+	"cyan":    tui.ColorCyan,
+	"white":   tui.ColorWhite,
+}
+
+// nowPlayingStyle is the style name of the description of currently played item.
+var nowPlayingStyle = "now-playing"
+
+// ApplyTheme styles widgets with colors of the theme, it has to be resolved already.
+func ApplyTheme(theme config.Theme) {
+	applyTheme(tui.DefaultTheme, theme)
+}
+
+func applyTheme(t *tui.Theme, theme config.Theme) {
+	t.SetStyle("box.border", tui.Style{Fg: themeColors[theme.Border]})
+
+	focused := tui.Style{Fg: themeColors[theme.Focused], Bg: tui.ColorDefault}
+	t.SetStyle("box.focused.border", focused)
+	t.SetStyle("table.focused.border", focused)
+	t.SetStyle("button.focused", tui.Style{Fg: tui.ColorBlack, Bg: themeColors[theme.Focused]})
+
+	selected := tui.Style{Reverse: tui.DecorationOn}
+	if theme.Selected != config.SelectedReverse {
+		selected = tui.Style{Fg: tui.ColorBlack, Bg: themeColors[theme.Selected]}
+	}
+	t.SetStyle("table.cell.selected", selected)
+	t.SetStyle("list.item.selected", selected)
+	t.SetStyle("label."+tabSelectedStyle, selected)
+	t.SetStyle("label."+tabStyle, tui.Style{})
+
+	t.SetStyle("label."+nowPlayingStyle, tui.Style{Fg: themeColors[theme.NowPlaying], Bold: tui.DecorationOn})
+	t.SetStyle("label."+statusMessageStyle, tui.Style{Bold: tui.DecorationOn})
+	t.SetStyle("label."+statusErrorStyle, tui.Style{Fg: tui.ColorRed, Bold: tui.DecorationOn})
+	t.SetStyle("label."+statusFadedStyle, tui.Style{})
+}
@@ -0,0 +1,28 @@
+package player
+
+import (
+	"testing"
+
+	"github.com/jedruniu/spotify-cli/pkg/config"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+func TestApplyTheme(t *testing.T) {
+	theme := tui.NewTheme()
+	applyTheme(theme, config.Presets[config.DefaultPreset])
+	if style := theme.Style("box.focused.border"); style.Fg != tui.ColorYellow {
+		t.Fatalf("Expected focused border to be yellow, got %#v", style)
+	}
+	if style := theme.Style("table.cell.selected"); style.Reverse != tui.DecorationOn {
+		t.Fatalf("Expected selected rows to be reversed, got %#v", style)
+	}
+
+	applyTheme(theme, config.Presets["ocean"])
+	if style := theme.Style("table.cell.selected"); style.Bg != tui.ColorBlue || style.Reverse != tui.DecorationInherit {
+		t.Fatalf("Expected selected rows to have blue background, got %#v", style)
+	}
+	if style := theme.Style("label." + nowPlayingStyle); style.Fg != tui.ColorCyan {
+		t.Fatalf("Expected now playing to be cyan, got %#v", style)
+	}
+}
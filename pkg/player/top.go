@@ -0,0 +1,99 @@
+package player
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/marcusolsson/tui-go"
+	"github.com/zmb3/spotify"
+)
+
+// Top represents "Your Top" view with the user's most listened tracks
+// and artists within the chosen time range.
+type Top struct {
+	Focusables []tui.Widget
+	Box        *tui.Box
+	tracks     searchResultsInterface
+}
+
+type topTimeRange struct {
+	name string
+	// value is passed to Spotify Web API as spotify.Options.Timerange
+	value string
+}
+
+var (
+	topPageSize   = 50
+	topTimeRanges = []topTimeRange{
+		{name: "Last 4 weeks", value: "short"},
+		{name: "Last 6 months", value: "medium"},
+		{name: "All time", value: "long"},
+	}
+)
+
+// NewTop creates "Your Top" view, initially showing the last 6 months.
+func NewTop(ctx context.Context, client SpotifyClient) (*Top, error) {
+	topTracks := NewSearchResults(ctx, client, "Top tracks")
+	topArtists := NewSearchResults(ctx, client, "Top artists")
+
+	defaultRange := 1
+	err := showTop(ctx, client, topTimeRanges[defaultRange].value, topTracks, topArtists)
+	if err != nil {
+		return nil, err
+	}
+
+	rangesTable := newCountedTable()
+	for _, timeRange := range topTimeRanges {
+		rangesTable.AppendRow(tui.NewLabel(timeRange.name))
+	}
+	rangesTable.SetSelected(defaultRange)
+	rangesTable.OnItemActivated(func(t *tui.Table) {
+		timeRange := topTimeRanges[t.Selected()]
+		err := showTop(ctx, client, timeRange.value, topTracks, topArtists)
+		if err != nil {
+			log.Printf("Could not show top of %s with %s", timeRange.name, err)
+		}
+	})
+	rangesBox := tui.NewVBox(rangesTable, tui.NewSpacer())
+	rangesBox.SetTitle("Your Top")
+	rangesBox.SetBorder(true)
+
+	details := tui.NewHBox(topTracks.getBox(), topArtists.getBox())
+	details.SetSizePolicy(tui.Expanding, tui.Expanding)
+
+	return &Top{
+		Focusables: []tui.Widget{rangesTable, topTracks.getTable(), topArtists.getTable()},
+		Box:        tui.NewHBox(rangesBox, details),
+		tracks:     topTracks,
+	}, nil
+}
+
+// OnAddToPlaylist sets function called with top tracks which should be added to a playlist.
+func (top *Top) OnAddToPlaylist(fn func([]spotify.ID)) {
+	top.tracks.onAddToPlaylist(fn)
+}
+
+func showTop(ctx context.Context, client SpotifyClient, timeRange string, tracks, artists appendReseter) error {
+	opt := &spotify.Options{Limit: &topPageSize, Timerange: &timeRange}
+	tracksPage, err := client.CurrentUsersTopTracksOpt(ctx, opt)
+	if err != nil {
+		return fmt.Errorf("could not fetch top tracks: %v", err)
+	}
+	artistsPage, err := client.CurrentUsersTopArtistsOpt(ctx, opt)
+	if err != nil {
+		return fmt.Errorf("could not fetch top artists: %v", err)
+	}
+
+	tracks.resetSearchResults()
+	for _, track := range tracksPage.Tracks {
+		tracks.appendSearchResult(URIName{Name: track.Name, URI: track.URI})
+	}
+	tracks.markSavedTracks()
+	artists.resetSearchResults()
+	for _, artist := range artistsPage.Artists {
+		artists.appendSearchResult(URIName{Name: artist.Name, URI: artist.URI})
+	}
+	artists.markSavedTracks()
+	return nil
+}
@@ -0,0 +1,32 @@
+package player
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNewTop(t *testing.T) {
+	top, err := NewTop(context.Background(), NewDebugClient())
+	if err != nil {
+		t.Fatalf("Unexpected error occured: %s", err)
+	}
+	if len(top.Focusables) != 3 {
+		t.Fatalf("Expected to have 3 focusables elements, got %d", len(top.Focusables))
+	}
+}
+
+func TestShowTop(t *testing.T) {
+	tracks := &FakeSearchResult{}
+	artists := &FakeSearchResult{}
+	err := showTop(context.Background(), NewDebugClient(), "short", tracks, artists)
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if tracks.resetCalls != 1 || artists.resetCalls != 1 {
+		t.Fatalf("Expected to reset old results once, got %d and %d resets", tracks.resetCalls, artists.resetCalls)
+	}
+	// DebugClient returns 10 top tracks and 5 top artists
+	if tracks.appendCalls != 10 || artists.appendCalls != 5 {
+		t.Fatalf("Expected to append 10 tracks and 5 artists, got %d and %d", tracks.appendCalls, artists.appendCalls)
+	}
+}
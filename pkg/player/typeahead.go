@@ -0,0 +1,132 @@
+package player
+
+import (
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/marcusolsson/tui-go"
+	"github.com/zmb3/spotify"
+)
+
+// typeAheadTimeout is how long typed prefix is kept after the last key press.
+var typeAheadTimeout = time.Second
+
+// typeAheadTable is a table in which typing letters jumps to the first row starting
+// with the typed prefix. As long as no prefix is being typed, j and k move the
+// selection like in the plain table.
+type typeAheadTable struct {
+	*countedTable
+	// jump selects row starting with the prefix, it tells whether such row was found.
+	jump func(prefix string) bool
+	// onPrefix shows prefix to the user, it is called with empty prefix once it expires.
+	onPrefix func(prefix string)
+	now      func() time.Time
+
+	mu       sync.Mutex
+	prefix   string
+	lastType time.Time
+}
+
+func newTypeAheadTable(table *countedTable, jump func(string) bool, onPrefix func(string)) *typeAheadTable {
+	return &typeAheadTable{
+		countedTable: table,
+		jump:         jump,
+		onPrefix:     onPrefix,
+		now:          time.Now,
+	}
+}
+
+// OnKeyEvent extends prefix with typed letters, other keys are handled by the table.
+func (t *typeAheadTable) OnKeyEvent(ev tui.KeyEvent) {
+	if !t.IsFocused() || ev.Key != tui.KeyRune || !isTypeAheadRune(ev.Rune) {
+		t.resetPrefix()
+		t.Table.OnKeyEvent(ev)
+		return
+	}
+
+	t.mu.Lock()
+	now := t.now()
+	if now.Sub(t.lastType) > typeAheadTimeout {
+		t.prefix = ""
+	}
+	if t.prefix == "" && (ev.Rune == 'j' || ev.Rune == 'k' || ev.Rune == ' ') {
+		t.mu.Unlock()
+		t.Table.OnKeyEvent(ev)
+		return
+	}
+	t.prefix += string(unicode.ToLower(ev.Rune))
+	t.lastType = now
+	prefix := t.prefix
+	t.mu.Unlock()
+
+	t.jump(prefix)
+	t.onPrefix(prefix)
+	time.AfterFunc(typeAheadTimeout, t.expirePrefix)
+}
+
+// typing tells whether a prefix is being typed, so that keys continue it.
+func (t *typeAheadTable) typing() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.prefix != "" && t.now().Sub(t.lastType) <= typeAheadTimeout
+}
+
+func (t *typeAheadTable) expirePrefix() {
+	t.mu.Lock()
+	expired := t.prefix != "" && t.now().Sub(t.lastType) >= typeAheadTimeout
+	if expired {
+		t.prefix = ""
+	}
+	t.mu.Unlock()
+	if expired {
+		t.onPrefix("")
+	}
+}
+
+func (t *typeAheadTable) resetPrefix() {
+	t.mu.Lock()
+	hadPrefix := t.prefix != ""
+	t.prefix = ""
+	t.mu.Unlock()
+	if hadPrefix {
+		t.onPrefix("")
+	}
+}
+
+func isTypeAheadRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == ' '
+}
+
+// hasTypeAheadPrefix tells whether any of the texts starts with the prefix, ignoring case.
+func hasTypeAheadPrefix(prefix string, texts ...string) bool {
+	for _, text := range texts {
+		if strings.HasPrefix(strings.ToLower(text), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// jumpToPlaylist returns jump function selecting the first playlist, in the table
+// listing playlists row by row, which name or owner name starts with the prefix.
+func jumpToPlaylist(table *countedTable, playlists []spotify.SimplePlaylist) func(string) bool {
+	return func(prefix string) bool {
+		for i, playlist := range playlists {
+			if hasTypeAheadPrefix(prefix, playlist.Name, playlist.Owner.DisplayName) {
+				table.SetSelected(i)
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// typeAheadTitle returns box title with the typed prefix appended.
+func typeAheadTitle(title, prefix string) string {
+	if prefix == "" {
+		return title
+	}
+	return title + " [" + prefix + "]"
+}
@@ -0,0 +1,104 @@
+package player
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/marcusolsson/tui-go"
+	"github.com/zmb3/spotify"
+)
+
+func newTestTypeAheadTable(jumps *[]string, prefixes *[]string) (*typeAheadTable, *time.Time) {
+	table := newCountedTable()
+	for i := 0; i < 3; i++ {
+		table.AppendRow(tui.NewLabel("row"))
+	}
+	table.SetFocused(true)
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	typeAhead := newTypeAheadTable(table, func(prefix string) bool {
+		*jumps = append(*jumps, prefix)
+		return true
+	}, func(prefix string) {
+		*prefixes = append(*prefixes, prefix)
+	})
+	typeAhead.now = func() time.Time { return now }
+	return typeAhead, &now
+}
+
+func TestTypeAheadAccumulatesPrefix(t *testing.T) {
+	jumps, prefixes := []string{}, []string{}
+	typeAhead, _ := newTestTypeAheadTable(&jumps, &prefixes)
+
+	for _, r := range "Que k" {
+		typeAhead.OnKeyEvent(tui.KeyEvent{Key: tui.KeyRune, Rune: r})
+	}
+
+	expected := []string{"q", "qu", "que", "que ", "que k"}
+	if !reflect.DeepEqual(jumps, expected) {
+		t.Fatalf("Expected jumps to %v, got %v", expected, jumps)
+	}
+	if !reflect.DeepEqual(prefixes, expected) {
+		t.Fatalf("Expected indicated prefixes %v, got %v", expected, prefixes)
+	}
+}
+
+func TestTypeAheadLeavesNavigationKeysToTable(t *testing.T) {
+	jumps, prefixes := []string{}, []string{}
+	typeAhead, _ := newTestTypeAheadTable(&jumps, &prefixes)
+
+	selected := typeAhead.Selected()
+	typeAhead.OnKeyEvent(tui.KeyEvent{Key: tui.KeyRune, Rune: 'j'})
+	if len(jumps) != 0 {
+		t.Fatalf("Expected j not to start the prefix, got jumps %v", jumps)
+	}
+	if typeAhead.Selected() != selected+1 {
+		t.Fatalf("Expected j to move selection to row %d, but %d is selected", selected+1, typeAhead.Selected())
+	}
+
+	typeAhead.OnKeyEvent(tui.KeyEvent{Key: tui.KeyRune, Rune: 'a'})
+	typeAhead.OnKeyEvent(tui.KeyEvent{Key: tui.KeyEnter})
+	if !reflect.DeepEqual(prefixes, []string{"a", ""}) {
+		t.Fatalf("Expected other keys to clear the prefix, got indicated prefixes %v", prefixes)
+	}
+}
+
+func TestTypeAheadPrefixExpires(t *testing.T) {
+	jumps, prefixes := []string{}, []string{}
+	typeAhead, now := newTestTypeAheadTable(&jumps, &prefixes)
+
+	typeAhead.OnKeyEvent(tui.KeyEvent{Key: tui.KeyRune, Rune: 'a'})
+	*now = now.Add(typeAheadTimeout + time.Millisecond)
+	typeAhead.OnKeyEvent(tui.KeyEvent{Key: tui.KeyRune, Rune: 'b'})
+
+	expected := []string{"a", "b"}
+	if !reflect.DeepEqual(jumps, expected) {
+		t.Fatalf("Expected jumps to %v, got %v", expected, jumps)
+	}
+
+	*now = now.Add(typeAheadTimeout)
+	typeAhead.expirePrefix()
+	if prefixes[len(prefixes)-1] != "" {
+		t.Fatalf("Expected expired prefix to be cleared, got indicated prefixes %v", prefixes)
+	}
+}
+
+func TestJumpToPlaylist(t *testing.T) {
+	table := newCountedTable()
+	playlists := []spotify.SimplePlaylist{{Name: "Chill"}, {Name: "Rock Classics"}}
+	playlists[1].Owner.DisplayName = "Spotify"
+	for _, playlist := range playlists {
+		table.AppendRow(tui.NewLabel(playlist.Name))
+	}
+	jump := jumpToPlaylist(table, playlists)
+
+	if !jump("spo") || table.Selected() != 1 {
+		t.Fatalf("Expected playlist owned by Spotify to be selected, but %d is selected", table.Selected())
+	}
+	if !jump("ch") || table.Selected() != 0 {
+		t.Fatalf("Expected Chill to be selected, but %d is selected", table.Selected())
+	}
+	if jump("jazz") {
+		t.Fatalf("Expected no playlist to start with jazz")
+	}
+}
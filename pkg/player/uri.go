@@ -0,0 +1,101 @@
+package player
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/zmb3/spotify"
+)
+
+// uriTypes are types of items which can be given as URIs or links.
+var uriTypes = map[string]bool{
+	"track":    true,
+	"album":    true,
+	"artist":   true,
+	"playlist": true,
+	"show":     true,
+	"episode":  true,
+}
+
+var (
+	spotifyURI = regexp.MustCompile(`^spotify:([a-z]+):([A-Za-z0-9]+)$`)
+	// links shared from Spotify apps, i.e. https://open.spotify.com/intl-pl/track/<id>?si=...
+	spotifyLink = regexp.MustCompile(`^/(?:intl-[a-zA-Z-]+/)?([a-z]+)/([A-Za-z0-9]+)/?$`)
+)
+
+// shortLinkHosts are hosts of shortened links, which redirect to links of open.spotify.com.
+var shortLinkHosts = map[string]bool{
+	"spotify.link":     true,
+	"spotify.app.link": true,
+}
+
+// shortLinkClient follows redirects of shortened links, it is replaced in tests.
+var shortLinkClient = &http.Client{
+	Timeout: 10 * time.Second,
+	// the page the link leads to is not needed, only its address
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		if req.URL.Host == "open.spotify.com" || len(via) >= 10 {
+			return http.ErrUseLastResponse
+		}
+		return nil
+	},
+}
+
+// ParseURI converts item given as Spotify URI, link shared from Spotify apps, or its shortened
+// spotify.link version, into its URI. Shortened links are followed to the links they lead to.
+func ParseURI(ctx context.Context, value string) (spotify.URI, error) {
+	value = strings.TrimSpace(value)
+	if match := spotifyURI.FindStringSubmatch(value); match != nil && uriTypes[match[1]] {
+		return spotify.URI(value), nil
+	}
+	link, err := url.Parse(value)
+	if err != nil || link.Scheme != "https" && link.Scheme != "http" {
+		return "", usageErrorf("%s is neither Spotify URI nor link", value)
+	}
+	if shortLinkHosts[link.Host] {
+		if link, err = followShortLink(ctx, link); err != nil {
+			return "", err
+		}
+	}
+	if link.Host == "open.spotify.com" {
+		if match := spotifyLink.FindStringSubmatch(link.Path); match != nil && uriTypes[match[1]] {
+			return spotify.URI("spotify:" + match[1] + ":" + match[2]), nil
+		}
+	}
+	return "", usageErrorf("%s is not a link to a track, album, artist, playlist, show or episode", value)
+}
+
+// followShortLink returns the link the shortened one redirects to.
+func followShortLink(ctx context.Context, link *url.URL) (*url.URL, error) {
+	req, err := http.NewRequest(http.MethodGet, link.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := shortLinkClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("could not follow link %s: %v", link, err)
+	}
+	defer resp.Body.Close()
+	location, err := resp.Location()
+	if err != nil {
+		return nil, usageErrorf("link %s does not lead to Spotify", link)
+	}
+	return location, nil
+}
+
+// playURI plays the item, tracks and episodes on their own, the others from their start.
+func playURI(ctx context.Context, client SpotifyClient, uri spotify.URI) error {
+	opt := &spotify.PlayOptions{PlaybackContext: &uri}
+	if trackID(uri) != "" || uriID(uri, "episode") != "" {
+		opt = &spotify.PlayOptions{URIs: []spotify.URI{uri}}
+	}
+	if err := client.PlayOpt(ctx, opt); err != nil {
+		return commandError(err, "could not play %s", uri)
+	}
+	return nil
+}
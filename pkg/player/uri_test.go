@@ -0,0 +1,55 @@
+package player
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/zmb3/spotify"
+)
+
+// shortLinks redirects shortened links like spotify.link does, through spotify.app.link.
+type shortLinks map[string]string
+
+func (links shortLinks) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp := &http.Response{StatusCode: http.StatusNotFound, Header: http.Header{}, Body: ioutil.NopCloser(strings.NewReader("")), Request: req}
+	if location, ok := links[req.URL.String()]; ok {
+		resp.StatusCode = http.StatusTemporaryRedirect
+		resp.Header.Set("Location", location)
+	}
+	return resp, nil
+}
+
+func TestParseURI(t *testing.T) {
+	defer func(previous *http.Client) { shortLinkClient = previous }(shortLinkClient)
+	client := *shortLinkClient
+	client.Transport = shortLinks{
+		"https://spotify.link/kidA":           "https://spotify.app.link/kidA?_p=c1",
+		"https://spotify.app.link/kidA?_p=c1": "https://open.spotify.com/album/6GjwtEZcfenmOf6l18N7T7?si=shared",
+		"https://spotify.link/elsewhere":      "https://example.com/",
+	}
+	shortLinkClient = &client
+
+	for _, test := range []struct {
+		value string
+		uri   spotify.URI
+	}{
+		{"spotify:track:2CVV8PtUYYsux8XOzWkCP0", "spotify:track:2CVV8PtUYYsux8XOzWkCP0"},
+		{"https://open.spotify.com/track/2CVV8PtUYYsux8XOzWkCP0?si=1234", "spotify:track:2CVV8PtUYYsux8XOzWkCP0"},
+		{"https://open.spotify.com/intl-pl/playlist/37i9dQZF1DXcBWIGoYBM5M", "spotify:playlist:37i9dQZF1DXcBWIGoYBM5M"},
+		{" https://open.spotify.com/episode/512ojhOuo1ktJprKbVcKyQ/ ", "spotify:episode:512ojhOuo1ktJprKbVcKyQ"},
+		{"https://spotify.link/kidA", "spotify:album:6GjwtEZcfenmOf6l18N7T7"},
+		{"spotify:user:someone", ""},
+		{"https://open.spotify.com/user/someone", ""},
+		{"https://spotify.link/elsewhere", ""},
+		{"https://spotify.link/missing", ""},
+		{"Radiohead - Idioteque", ""},
+	} {
+		uri, err := ParseURI(context.Background(), test.value)
+		if uri != test.uri || (err != nil) != (test.uri == "") {
+			t.Fatalf("Expected %q to be parsed into %q, got %q, %v", test.value, test.uri, uri, err)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package player
+
+import (
+	"fmt"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+// View is a widget which can be displayed in the main area of the
+// application, along with widgets which should be focusable when
+// view is displayed.
+type View struct {
+	Widget     tui.Widget
+	Focusables []tui.Widget
+}
+
+// MainArea represents part of the application in which one
+// of registered views is displayed at a time.
+type MainArea struct {
+	Box     *tui.Box
+	views   map[string]View
+	current string
+	onShow  func(View)
+}
+
+// NewMainArea creates empty main area, views are added with Add.
+func NewMainArea() *MainArea {
+	box := tui.NewVBox()
+	box.SetSizePolicy(tui.Expanding, tui.Expanding)
+	return &MainArea{
+		Box:   box,
+		views: map[string]View{},
+	}
+}
+
+// Add registers view under the given name. The very first
+// added view is displayed right away.
+func (mainArea *MainArea) Add(name string, view View) {
+	mainArea.views[name] = view
+	if mainArea.current == "" {
+		mainArea.current = name
+		mainArea.Box.Append(view.Widget)
+	}
+}
+
+// Show replaces currently displayed view with the one registered under given name.
+func (mainArea *MainArea) Show(name string) error {
+	view, ok := mainArea.views[name]
+	if !ok {
+		return fmt.Errorf("there is no view named %q", name)
+	}
+	if mainArea.Box.Length() > 0 {
+		mainArea.Box.Remove(0)
+	}
+	mainArea.Box.Append(view.Widget)
+	mainArea.current = name
+	if mainArea.onShow != nil {
+		mainArea.onShow(view)
+	}
+	return nil
+}
+
+// Current returns view which is currently displayed.
+func (mainArea *MainArea) Current() View {
+	return mainArea.views[mainArea.current]
+}
+
+// OnShow sets function called each time view is changed.
+func (mainArea *MainArea) OnShow(fn func(View)) {
+	mainArea.onShow = fn
+}
@@ -0,0 +1,44 @@
+package player
+
+import (
+	"testing"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+func TestMainAreaShowsFirstAddedView(t *testing.T) {
+	mainArea := NewMainArea()
+	first := tui.NewLabel("first")
+	mainArea.Add("first", View{Widget: first})
+	mainArea.Add("second", View{Widget: tui.NewLabel("second")})
+
+	if mainArea.Box.Length() != 1 {
+		t.Fatalf("Expected main area to display 1 view, displays %d", mainArea.Box.Length())
+	}
+	if mainArea.Current().Widget != first {
+		t.Fatalf("Expected first added view to be displayed")
+	}
+}
+
+func TestMainAreaShow(t *testing.T) {
+	mainArea := NewMainArea()
+	second := tui.NewLabel("second")
+	mainArea.Add("first", View{Widget: tui.NewLabel("first")})
+	mainArea.Add("second", View{Widget: second})
+
+	var shown View
+	mainArea.OnShow(func(v View) { shown = v })
+
+	if err := mainArea.Show("second"); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if mainArea.Box.Length() != 1 {
+		t.Fatalf("Expected main area to display 1 view, displays %d", mainArea.Box.Length())
+	}
+	if mainArea.Current().Widget != second || shown.Widget != second {
+		t.Fatalf("Expected second view to be displayed")
+	}
+	if err := mainArea.Show("unknown"); err == nil {
+		t.Fatalf("Expected to fail for unknown view, but it didn't")
+	}
+}
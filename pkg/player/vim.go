@@ -0,0 +1,95 @@
+package player
+
+import (
+	"github.com/marcusolsson/tui-go"
+)
+
+// VimKeys moves the selection in the focused table with j, k, g, G, Ctrl+D and Ctrl+U,
+// and focus between widgets with h and l, unless text is typed into an entry or a table.
+type VimKeys struct {
+	focused func() tui.Widget
+	move    func(back bool)
+}
+
+// NewVimKeys creates vim keys moving in the widget returned by focused, move moves focus
+// to the next widget, or the previous one when back is true.
+func NewVimKeys(focused func() tui.Widget, move func(back bool)) *VimKeys {
+	return &VimKeys{focused: focused, move: move}
+}
+
+// Wrap wraps the root widget of the ui, so that it gets vim keys before the focused widget.
+func (vim *VimKeys) Wrap(root tui.Widget) tui.Widget {
+	return &vimKeysWidget{Widget: root, vim: vim}
+}
+
+// vimKeysWidget handles vim keys, other keys are passed to the wrapped widget.
+type vimKeysWidget struct {
+	tui.Widget
+	vim *VimKeys
+}
+
+// OnKeyEvent moves selection or focus, j and k are moved by tables themselves.
+func (w *vimKeysWidget) OnKeyEvent(ev tui.KeyEvent) {
+	if !w.vim.handle(ev) {
+		w.Widget.OnKeyEvent(ev)
+	}
+}
+
+// handle tells whether the key was a vim key, which is not passed to the focused widget then.
+func (vim *VimKeys) handle(ev tui.KeyEvent) bool {
+	focused := vim.focused()
+	if focused == nil {
+		return false
+	}
+	if _, typing := focused.(*tui.Entry); typing {
+		return false
+	}
+	if t, ok := focused.(interface{ typing() bool }); ok && t.typing() {
+		return false
+	}
+	switch {
+	case ev.Key == tui.KeyRune && ev.Rune == 'h':
+		vim.move(true)
+		return true
+	case ev.Key == tui.KeyRune && ev.Rune == 'l':
+		vim.move(false)
+		return true
+	}
+	focusedTable, ok := focused.(rowsTable)
+	if !ok {
+		return false
+	}
+	table, rows := focusedTable.table(), focusedTable.rows()
+	// a half of the visible rows, each row is a line
+	half := table.Size().Y / 2
+	if half < 1 {
+		half = 1
+	}
+	switch {
+	case ev.Key == tui.KeyRune && ev.Rune == 'g':
+		selectRow(table, 0, rows)
+	case ev.Key == tui.KeyRune && ev.Rune == 'G':
+		selectRow(table, rows-1, rows)
+	case ev.Key == tui.KeyCtrlD:
+		selectRow(table, table.Selected()+half, rows)
+	case ev.Key == tui.KeyCtrlU:
+		selectRow(table, table.Selected()-half, rows)
+	default:
+		return false
+	}
+	return true
+}
+
+// selectRow selects the row, or the closest one of the table with the given number of rows.
+func selectRow(table *tui.Table, row, rows int) {
+	if row >= rows {
+		row = rows - 1
+	}
+	if row < 0 {
+		row = 0
+	}
+	if rows == 0 || row == table.Selected() {
+		return
+	}
+	table.Select(row)
+}
@@ -0,0 +1,56 @@
+package player
+
+import (
+	"image"
+	"testing"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+func TestVimKeysMoveInTables(t *testing.T) {
+	table := newCountedTable()
+	for i := 0; i < 20; i++ {
+		table.AppendRow(tui.NewLabel("row"))
+	}
+	table.Resize(image.Point{10, 10})
+	table.SetFocused(true)
+	wrapped := newTypeAheadTable(table, func(string) bool { return false }, func(string) {})
+	var focused tui.Widget = wrapped
+	moves := []bool{}
+	root := &keyCountingWidget{}
+	w := NewVimKeys(func() tui.Widget { return focused }, func(back bool) { moves = append(moves, back) }).Wrap(root)
+
+	keys := []struct {
+		ev       tui.KeyEvent
+		selected int
+	}{
+		{tui.KeyEvent{Key: tui.KeyRune, Rune: 'G'}, 19},
+		{tui.KeyEvent{Key: tui.KeyCtrlU}, 14},
+		{tui.KeyEvent{Key: tui.KeyRune, Rune: 'g'}, 0},
+		{tui.KeyEvent{Key: tui.KeyCtrlD}, 5},
+		{tui.KeyEvent{Key: tui.KeyCtrlU}, 0},
+	}
+	for _, key := range keys {
+		w.OnKeyEvent(key.ev)
+		if table.Selected() != key.selected {
+			t.Fatalf("Expected row %d to be selected after %s, got %d", key.selected, key.ev.Name(), table.Selected())
+		}
+	}
+	w.OnKeyEvent(tui.KeyEvent{Key: tui.KeyRune, Rune: 'h'})
+	w.OnKeyEvent(tui.KeyEvent{Key: tui.KeyRune, Rune: 'l'})
+	if len(moves) != 2 || !moves[0] || moves[1] || root.keys != 0 {
+		t.Fatalf("Expected focus to move back and forth without passing keys on, got %v", moves)
+	}
+
+	// keys continue the prefix typed into the table
+	wrapped.OnKeyEvent(tui.KeyEvent{Key: tui.KeyRune, Rune: 'a'})
+	w.OnKeyEvent(tui.KeyEvent{Key: tui.KeyRune, Rune: 'l'})
+	if len(moves) != 2 || root.keys != 1 {
+		t.Fatalf("Expected l to be typed into the table, got moves %v", moves)
+	}
+	focused = tui.NewEntry()
+	w.OnKeyEvent(tui.KeyEvent{Key: tui.KeyRune, Rune: 'G'})
+	if root.keys != 2 {
+		t.Fatalf("Expected G to be typed into the entry")
+	}
+}
@@ -0,0 +1,182 @@
+package scrobble
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/jedruniu/spotify-cli/pkg/cache"
+)
+
+// DefaultListenBrainzURL is the URL of ListenBrainz API used when no other is configured.
+var DefaultListenBrainzURL = "https://api.listenbrainz.org"
+
+var (
+	listenBrainzQueueEntry = "listenbrainz_queue"
+	// listenBrainzMaxBatch is the maximal number of listens in a single
+	// submission allowed by ListenBrainz.
+	listenBrainzMaxBatch = 100
+)
+
+// ListenBrainz submits listens to ListenBrainz. Listens which could not be
+// submitted because ListenBrainz was unreachable are queued in the store
+// and submitted along with the next listen.
+type ListenBrainz struct {
+	token string
+	url   string
+	http  *http.Client
+	store *cache.Store
+	mu    sync.Mutex
+}
+
+type listenBrainzPayload struct {
+	ListenType string               `json:"listen_type"`
+	Payload    []listenBrainzListen `json:"payload"`
+}
+
+type listenBrainzListen struct {
+	ListenedAt    int64                     `json:"listened_at,omitempty"`
+	TrackMetadata listenBrainzTrackMetadata `json:"track_metadata"`
+}
+
+type listenBrainzTrackMetadata struct {
+	ArtistName  string `json:"artist_name"`
+	TrackName   string `json:"track_name"`
+	ReleaseName string `json:"release_name,omitempty"`
+}
+
+// unreachableError means that submission may succeed when retried later.
+type unreachableError struct {
+	err error
+}
+
+func (e unreachableError) Error() string {
+	return fmt.Sprintf("listenbrainz is unreachable: %v", e.err)
+}
+
+// NewListenBrainz creates ListenBrainz client authorized with the user token,
+// offline queue is kept in the given store.
+func NewListenBrainz(url, token string, store *cache.Store) *ListenBrainz {
+	if url == "" {
+		url = DefaultListenBrainzURL
+	}
+	return &ListenBrainz{
+		token: token,
+		url:   url,
+		http:  http.DefaultClient,
+		store: store,
+	}
+}
+
+// SetProxy sends requests to ListenBrainz through the proxy, instead of the one given
+// by HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables.
+func (lb *ListenBrainz) SetProxy(proxy *url.URL) {
+	lb.http = &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxy)}}
+}
+
+// SubmitListen submits listen along with the queued ones. When ListenBrainz is
+// unreachable listen is queued and no error is returned.
+func (lb *ListenBrainz) SubmitListen(listen Listen) error {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
+	queued := []Listen{}
+	err := lb.store.Load(listenBrainzQueueEntry, &queued)
+	if err != nil {
+		log.Printf("Could not load queued listens: %s", err)
+	}
+	listens := append(queued, listen)
+
+	submitted := 0
+	for submitted < len(listens) {
+		end := submitted + listenBrainzMaxBatch
+		if end > len(listens) {
+			end = len(listens)
+		}
+		err = lb.submit(listens[submitted:end])
+		if err != nil {
+			break
+		}
+		submitted = end
+	}
+
+	if _, unreachable := err.(unreachableError); unreachable {
+		log.Printf("Queueing %d listens, %s", len(listens)-submitted, err)
+		if saveErr := lb.store.Save(listenBrainzQueueEntry, listens[submitted:]); saveErr != nil {
+			return fmt.Errorf("could not queue listens: %v", saveErr)
+		}
+		return nil
+	}
+	if saveErr := lb.store.Save(listenBrainzQueueEntry, []Listen{}); saveErr != nil {
+		log.Printf("Could not clear queued listens: %s", saveErr)
+	}
+	return err
+}
+
+// NowPlaying tells ListenBrainz what the user is listening to, it is not queued.
+func (lb *ListenBrainz) NowPlaying(listen Listen) error {
+	listen.ListenedAt = 0
+	return lb.post(listenBrainzPayload{
+		ListenType: "playing_now",
+		Payload:    []listenBrainzListen{toListenBrainzListen(listen)},
+	})
+}
+
+func (lb *ListenBrainz) submit(listens []Listen) error {
+	listenType := "single"
+	if len(listens) > 1 {
+		listenType = "import"
+	}
+	payload := listenBrainzPayload{ListenType: listenType}
+	for _, listen := range listens {
+		payload.Payload = append(payload.Payload, toListenBrainzListen(listen))
+	}
+	return lb.post(payload)
+}
+
+func (lb *ListenBrainz) post(payload listenBrainzPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("could not encode listens: %v", err)
+	}
+	req, err := http.NewRequest(http.MethodPost, lb.url+"/1/submit-listens", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("could not create submission request: %v", err)
+	}
+	req.Header.Set("Authorization", "Token "+lb.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := lb.http.Do(req)
+	if err != nil {
+		return unreachableError{err}
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError:
+		return unreachableError{fmt.Errorf("HTTP %d", resp.StatusCode)}
+	default:
+		var e struct {
+			Error string `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&e)
+		return fmt.Errorf("listenbrainz rejected listens: HTTP %d: %s", resp.StatusCode, e.Error)
+	}
+}
+
+func toListenBrainzListen(listen Listen) listenBrainzListen {
+	return listenBrainzListen{
+		ListenedAt: listen.ListenedAt,
+		TrackMetadata: listenBrainzTrackMetadata{
+			ArtistName:  listen.ArtistName,
+			TrackName:   listen.TrackName,
+			ReleaseName: listen.ReleaseName,
+		},
+	}
+}
@@ -0,0 +1,103 @@
+package scrobble
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/jedruniu/spotify-cli/pkg/cache"
+)
+
+func newTestStore(t *testing.T) (*cache.Store, func()) {
+	dir, err := ioutil.TempDir("", "spotify-cli-scrobble")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+	return cache.NewStore(dir), func() { os.RemoveAll(dir) }
+}
+
+func TestListenBrainzSubmitsListen(t *testing.T) {
+	var submitted listenBrainzPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Token secret" {
+			t.Errorf("Expected to be authorized with token, got %s", r.Header.Get("Authorization"))
+		}
+		json.NewDecoder(r.Body).Decode(&submitted)
+	}))
+	defer server.Close()
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	lb := NewListenBrainz(server.URL, "secret", store)
+	err := lb.SubmitListen(Listen{ArtistName: "Artist", TrackName: "Track", ListenedAt: 1})
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if submitted.ListenType != "single" || len(submitted.Payload) != 1 || submitted.Payload[0].TrackMetadata.TrackName != "Track" {
+		t.Fatalf("Expected single listen to be submitted, got %#v", submitted)
+	}
+}
+
+func TestListenBrainzQueuesListensWhenUnreachable(t *testing.T) {
+	available := false
+	var submitted listenBrainzPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !available {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&submitted)
+	}))
+	defer server.Close()
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	lb := NewListenBrainz(server.URL, "secret", store)
+	for _, track := range []string{"First", "Second"} {
+		err := lb.SubmitListen(Listen{ArtistName: "Artist", TrackName: track})
+		if err != nil {
+			t.Fatalf("Did not expect to fail when ListenBrainz is unreachable, but it did with %v", err)
+		}
+	}
+	queued := []Listen{}
+	store.Load(listenBrainzQueueEntry, &queued)
+	if len(queued) != 2 {
+		t.Fatalf("Expected 2 listens to be queued, got %d", len(queued))
+	}
+
+	available = true
+	err := lb.SubmitListen(Listen{ArtistName: "Artist", TrackName: "Third"})
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if submitted.ListenType != "import" || len(submitted.Payload) != 3 {
+		t.Fatalf("Expected queued listens to be imported with the new one, got %#v", submitted)
+	}
+	store.Load(listenBrainzQueueEntry, &queued)
+	if len(queued) != 0 {
+		t.Fatalf("Expected queue to be empty after submission, got %d listens", len(queued))
+	}
+}
+
+func TestListenBrainzDoesNotQueueRejectedListens(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"code": 401, "error": "Invalid authorization token."}`))
+	}))
+	defer server.Close()
+	store, cleanup := newTestStore(t)
+	defer cleanup()
+
+	lb := NewListenBrainz(server.URL, "wrong", store)
+	if err := lb.SubmitListen(Listen{ArtistName: "Artist", TrackName: "Track"}); err == nil {
+		t.Fatalf("Expected to fail with invalid token, but it didn't")
+	}
+	queued := []Listen{}
+	store.Load(listenBrainzQueueEntry, &queued)
+	if len(queued) != 0 {
+		t.Fatalf("Expected rejected listens not to be queued, got %d listens", len(queued))
+	}
+}
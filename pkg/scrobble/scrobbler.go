@@ -0,0 +1,64 @@
+package scrobble
+
+import (
+	"log"
+	"time"
+)
+
+// Listen describes a track listened by the user.
+type Listen struct {
+	ArtistName  string `json:"artist_name"`
+	TrackName   string `json:"track_name"`
+	ReleaseName string `json:"release_name"`
+	// ListenedAt is a Unix time at which listening started.
+	ListenedAt int64 `json:"listened_at"`
+}
+
+// Submitter submits listens to a listening history service.
+type Submitter interface {
+	SubmitListen(Listen) error
+	NowPlaying(Listen) error
+}
+
+// MinListenDuration is how long track has to be played in order to be
+// submitted. Played track duration is not known, thus only this part of
+// the ListenBrainz and Last.fm rules is applied.
+var MinListenDuration = 30 * time.Second
+
+// Scrobbler turns track changes into submitted listens.
+type Scrobbler struct {
+	submitter Submitter
+	current   *Listen
+	started   time.Time
+}
+
+// NewScrobbler creates scrobbler submitting listens with the given submitter.
+func NewScrobbler(submitter Submitter) *Scrobbler {
+	return &Scrobbler{submitter: submitter}
+}
+
+// Update is called with each known state of the player, when played track
+// changes the previous one is submitted if it was played long enough.
+func (s *Scrobbler) Update(listen Listen, now time.Time) {
+	if s.current != nil && s.current.ArtistName == listen.ArtistName && s.current.TrackName == listen.TrackName {
+		return
+	}
+	if s.current != nil && now.Sub(s.started) >= MinListenDuration {
+		err := s.submitter.SubmitListen(*s.current)
+		if err != nil {
+			log.Printf("Could not submit listen of %s: %s", s.current.TrackName, err)
+		}
+	}
+
+	if listen.TrackName == "" {
+		s.current = nil
+		return
+	}
+	listen.ListenedAt = now.Unix()
+	s.current = &listen
+	s.started = now
+	err := s.submitter.NowPlaying(listen)
+	if err != nil {
+		log.Printf("Could not submit now playing %s: %s", listen.TrackName, err)
+	}
+}
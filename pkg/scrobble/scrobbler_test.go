@@ -0,0 +1,42 @@
+package scrobble
+
+import (
+	"testing"
+	"time"
+)
+
+type FakeSubmitter struct {
+	submitted  []Listen
+	nowPlaying []Listen
+}
+
+func (s *FakeSubmitter) SubmitListen(listen Listen) error {
+	s.submitted = append(s.submitted, listen)
+	return nil
+}
+
+func (s *FakeSubmitter) NowPlaying(listen Listen) error {
+	s.nowPlaying = append(s.nowPlaying, listen)
+	return nil
+}
+
+func TestScrobblerSubmitsTracksPlayedLongEnough(t *testing.T) {
+	submitter := &FakeSubmitter{}
+	scrobbler := NewScrobbler(submitter)
+	start := time.Unix(1000, 0)
+
+	scrobbler.Update(Listen{ArtistName: "Artist", TrackName: "First"}, start)
+	scrobbler.Update(Listen{ArtistName: "Artist", TrackName: "First"}, start.Add(time.Minute))
+	scrobbler.Update(Listen{ArtistName: "Artist", TrackName: "Second"}, start.Add(2*time.Minute))
+	scrobbler.Update(Listen{ArtistName: "Artist", TrackName: "Third"}, start.Add(2*time.Minute+time.Second))
+
+	if len(submitter.nowPlaying) != 3 {
+		t.Fatalf("Expected each of 3 tracks to be submitted as now playing, got %d", len(submitter.nowPlaying))
+	}
+	if len(submitter.submitted) != 1 {
+		t.Fatalf("Expected only first track to be submitted, got %d listens", len(submitter.submitted))
+	}
+	if submitter.submitted[0].TrackName != "First" || submitter.submitted[0].ListenedAt != 1000 {
+		t.Fatalf("Expected first track listened at start, got %#v", submitter.submitted[0])
+	}
+}
@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"github.com/marcusolsson/tui-go"
+)
+
+// TuiGo is a Backend creating tui-go widgets, Native of its widgets returns tui.Widget.
+type TuiGo struct {
+	// NewTable creates tables lists are built of, tui.NewTable is used when it is nil.
+	NewTable func() TuiGoTable
+}
+
+// TuiGoTable is a tui-go table, or a widget wrapping one, i.e. to keep track of its rows.
+type TuiGoTable interface {
+	tui.Widget
+	AppendRow(row ...tui.Widget)
+	RemoveRows()
+	Selected() int
+	Select(row int)
+	SetSelected(row int)
+	OnSelectionChanged(fn func(*tui.Table))
+	OnItemActivated(fn func(*tui.Table))
+}
+
+type tuiGoLabel struct {
+	*tui.Label
+}
+
+type tuiGoButton struct {
+	*tui.Button
+}
+
+type tuiGoList struct {
+	TuiGoTable
+}
+
+// NewLabel creates tui-go label.
+func (TuiGo) NewLabel(text string) Label {
+	return &tuiGoLabel{tui.NewLabel(text)}
+}
+
+// NewButton creates tui-go button.
+func (TuiGo) NewButton(text string) Button {
+	return &tuiGoButton{tui.NewButton(text)}
+}
+
+// NewList creates tui-go table.
+func (backend TuiGo) NewList() List {
+	if backend.NewTable == nil {
+		return &tuiGoList{tui.NewTable(0, 0)}
+	}
+	return &tuiGoList{backend.NewTable()}
+}
+
+func (l *tuiGoLabel) Native() interface{} {
+	return l.Label
+}
+
+func (b *tuiGoButton) Native() interface{} {
+	return b.Button
+}
+
+func (b *tuiGoButton) OnActivated(fn func()) {
+	b.Button.OnActivated(func(*tui.Button) { fn() })
+}
+
+func (l *tuiGoList) Native() interface{} {
+	return l.TuiGoTable
+}
+
+func (l *tuiGoList) AppendRow(cells ...string) {
+	labels := make([]tui.Widget, 0, len(cells))
+	for _, cell := range cells {
+		labels = append(labels, tui.NewLabel(cell))
+	}
+	l.TuiGoTable.AppendRow(labels...)
+}
+
+func (l *tuiGoList) OnSelectionChanged(fn func(row int)) {
+	l.TuiGoTable.OnSelectionChanged(func(t *tui.Table) { fn(t.Selected()) })
+}
+
+func (l *tuiGoList) OnItemActivated(fn func(row int)) {
+	l.TuiGoTable.OnItemActivated(func(t *tui.Table) { fn(t.Selected()) })
+}
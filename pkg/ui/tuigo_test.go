@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/marcusolsson/tui-go"
+)
+
+func TestTuiGoImplementsBackend(t *testing.T) {
+	var _ Backend = TuiGo{}
+}
+
+func TestTuiGoList(t *testing.T) {
+	list := TuiGo{}.NewList()
+	list.AppendRow("first", "artist")
+	list.AppendRow("second", "artist")
+
+	changed := []int{}
+	list.OnSelectionChanged(func(row int) { changed = append(changed, row) })
+	list.SetSelected(0)
+	list.Select(1)
+	if len(changed) != 1 || changed[0] != 1 {
+		t.Fatalf("Expected only Select to report selection of row 1, got %v", changed)
+	}
+
+	activated := -1
+	list.OnItemActivated(func(row int) { activated = row })
+	list.(*tuiGoList).SetFocused(true)
+	list.(*tuiGoList).OnKeyEvent(tui.KeyEvent{Key: tui.KeyEnter})
+	if activated != 1 {
+		t.Fatalf("Expected row 1 to be activated, got %d", activated)
+	}
+
+	list.RemoveRows()
+	if _, ok := list.Native().(*tui.Table); !ok {
+		t.Fatalf("Expected native widget to be tui-go table, got %T", list.Native())
+	}
+}
+
+// rowsTable is a table counting its rows, tui-go keeps the number to itself.
+type rowsTable struct {
+	*tui.Table
+	rows int
+}
+
+func (t *rowsTable) AppendRow(row ...tui.Widget) {
+	t.Table.AppendRow(row...)
+	t.rows++
+}
+
+func TestTuiGoListOfTable(t *testing.T) {
+	table := &rowsTable{Table: tui.NewTable(0, 0)}
+	list := TuiGo{NewTable: func() TuiGoTable { return table }}.NewList()
+	list.AppendRow("first", "artist")
+	list.AppendRow("second", "artist")
+	if table.rows != 2 {
+		t.Fatalf("Expected rows to be appended to the given table, got %d", table.rows)
+	}
+	if list.Native() != table {
+		t.Fatalf("Expected native widget to be the given table, got %T", list.Native())
+	}
+}
+
+func TestTuiGoLabelAndButton(t *testing.T) {
+	label := TuiGo{}.NewLabel("None")
+	label.SetText("Playing")
+	if label.Text() != "Playing" {
+		t.Fatalf("Expected label text to be changed, got %q", label.Text())
+	}
+
+	button := TuiGo{}.NewButton("Play")
+	pressed := false
+	button.OnActivated(func() { pressed = true })
+	native := button.Native().(*tui.Button)
+	native.SetFocused(true)
+	native.OnKeyEvent(tui.KeyEvent{Key: tui.KeyEnter})
+	if !pressed {
+		t.Fatalf("Expected button to be activated")
+	}
+}
@@ -0,0 +1,47 @@
+// Package ui abstracts widgets spotify-cli is built of, so that views do
+// not depend on a particular terminal UI library. tui-go, which is used
+// today, is no longer maintained; another backend (i.e. tview or bubbletea)
+// can be added by implementing Backend, primitive by primitive.
+package ui
+
+// Widget is implemented by every widget, it gives access to the
+// widget of the backend, which is needed to lay widgets out until
+// layouts are abstracted as well.
+type Widget interface {
+	Native() interface{}
+}
+
+// Label displays text.
+type Label interface {
+	Widget
+	Text() string
+	SetText(text string)
+}
+
+// Button calls the function set with OnActivated when pressed.
+type Button interface {
+	Widget
+	OnActivated(fn func())
+}
+
+// List displays rows of text cells, one of which can be selected.
+// Rows are numbered from 0.
+type List interface {
+	Widget
+	AppendRow(cells ...string)
+	RemoveRows()
+	Selected() int
+	// Select selects the row and calls function set with OnSelectionChanged.
+	Select(row int)
+	// SetSelected selects the row without calling any function.
+	SetSelected(row int)
+	OnSelectionChanged(fn func(row int))
+	OnItemActivated(fn func(row int))
+}
+
+// Backend creates widgets of a terminal UI library.
+type Backend interface {
+	NewLabel(text string) Label
+	NewButton(text string) Button
+	NewList() List
+}
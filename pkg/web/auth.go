@@ -2,7 +2,6 @@ package web
 
 import (
 	"fmt"
-	"github.com/zmb3/spotify"
 	"golang.org/x/oauth2"
 	"log"
 	"net/http"
@@ -10,9 +9,9 @@ import (
 
 type AuthHandler struct {
 	// Client is created as the result of Spotify backend calling auth callback,
-	// as soon as spotify Client is created, the rest of the application can use
+	// as soon as HTTP Client is created, the rest of the application can use
 	// is to call any spotify apis.
-	Client chan *spotify.Client
+	Client chan *http.Client
 
 	// State is random string used in order to authenticate Client. It is used to
 	// generate spotify backend URL to which user will be redirected. After user
@@ -23,12 +22,18 @@ type AuthHandler struct {
 	// used by auth callback to verify message from spotify backend
 	// and to create a spotify Client.
 	Authenticator SpotifyAuthenticatorInterface
+
+	// Tokens keeps the token, so that user does not have to log in again
+	// next time. Token is only kept in memory when it is nil.
+	Tokens TokenStore
 }
 
 type SpotifyAuthenticatorInterface interface {
 	AuthURL(string) string
 	Token(string, *http.Request) (*oauth2.Token, error)
-	NewClient(*oauth2.Token) spotify.Client
+	// TokenSource returns source of the token, which refreshes it once it expires.
+	TokenSource(*oauth2.Token) oauth2.TokenSource
+	NewClient(oauth2.TokenSource) *http.Client
 }
 
 
@@ -44,9 +49,8 @@ func (s *AuthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	client := s.Authenticator.NewClient(token)
-	s.Client <- &client
+	s.Client <- s.Authenticator.NewClient(newStoredTokenSource(s.Authenticator, token, s.Tokens))
 
-	// TODO parametrize port and host
-	http.Redirect(w, r, fmt.Sprintf("http://localhost:8888/player?token=%s", token.AccessToken), 301)
+	// player is served along with the callback, under the host user was redirected to
+	http.Redirect(w, r, fmt.Sprintf("http://%s/player?token=%s", r.Host, token.AccessToken), 301)
 }
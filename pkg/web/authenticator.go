@@ -0,0 +1,132 @@
+package web
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/zmb3/spotify"
+	"golang.org/x/oauth2"
+)
+
+// Authenticator implements OAuth2 authorization code flow with Spotify
+// Accounts Service. HTTP clients it creates refresh access tokens on their own.
+type Authenticator struct {
+	config    *oauth2.Config
+	context   context.Context
+	transport *http.Transport
+	// verifier is the PKCE code verifier, it is empty unless the flow is used with PKCE.
+	verifier string
+}
+
+// pkceVerifierLength is the number of random bytes of the code verifier,
+// 32 bytes encode to 43 characters, the minimum length allowed.
+var pkceVerifierLength = 32
+
+// NewAuthenticator creates authenticator for Spotify Application with given credentials.
+func NewAuthenticator(redirectURL, clientID, secretKey string, scopes ...string) *Authenticator {
+	return newAuthenticator(redirectURL, clientID, secretKey, scopes)
+}
+
+// NewPKCEAuthenticator creates authenticator which uses authorization code flow with PKCE,
+// so that only ID of the Spotify Application is needed, without the client secret.
+func NewPKCEAuthenticator(redirectURL, clientID string, scopes ...string) (*Authenticator, error) {
+	random := make([]byte, pkceVerifierLength)
+	if _, err := rand.Read(random); err != nil {
+		return nil, fmt.Errorf("could not generate code verifier: %v", err)
+	}
+	a := newAuthenticator(redirectURL, clientID, "", scopes)
+	// client ID is sent in the body, as there is no secret to authorize with
+	a.config.Endpoint.AuthStyle = oauth2.AuthStyleInParams
+	a.verifier = base64.RawURLEncoding.EncodeToString(random)
+	return a, nil
+}
+
+// pkceChallenge returns S256 code challenge of the verifier.
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func newAuthenticator(redirectURL, clientID, secretKey string, scopes []string) *Authenticator {
+	config := &oauth2.Config{
+		ClientID:     clientID,
+		ClientSecret: secretKey,
+		RedirectURL:  redirectURL,
+		Scopes:       scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  spotify.AuthURL,
+			TokenURL: spotify.TokenURL,
+		},
+	}
+	// HTTP/2 is disabled, see: https://github.com/zmb3/spotify/issues/20
+	transport := &http.Transport{
+		Proxy:        http.ProxyFromEnvironment,
+		TLSNextProto: map[string]func(authority string, c *tls.Conn) http.RoundTripper{},
+	}
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{Transport: transport})
+	return &Authenticator{config: config, context: ctx, transport: transport}
+}
+
+// SetProxy sends requests of the authenticator and clients it creates through the proxy,
+// instead of the one given by HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables.
+func (a *Authenticator) SetProxy(proxy *url.URL) {
+	a.transport.Proxy = http.ProxyURL(proxy)
+}
+
+// AuthURL returns URL of Spotify Accounts Service to which user should be
+// redirected in order to log in. State is verified later on by Token.
+func (a *Authenticator) AuthURL(state string) string {
+	if a.verifier != "" {
+		return a.config.AuthCodeURL(state,
+			oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+			oauth2.SetAuthURLParam("code_challenge", pkceChallenge(a.verifier)),
+		)
+	}
+	return a.config.AuthCodeURL(state)
+}
+
+// Token exchanges authorization code, which Spotify Accounts Service passed in the
+// request made to the redirect URL, for an access token.
+func (a *Authenticator) Token(state string, r *http.Request) (*oauth2.Token, error) {
+	values := r.URL.Query()
+	if e := values.Get("error"); e != "" {
+		return nil, errors.New("spotify: auth failed - " + e)
+	}
+	code := values.Get("code")
+	if code == "" {
+		return nil, errors.New("spotify: didn't get access code")
+	}
+	if values.Get("state") != state {
+		return nil, errors.New("spotify: redirect state parameter doesn't match")
+	}
+	if a.verifier != "" {
+		return a.config.Exchange(a.context, code, oauth2.SetAuthURLParam("code_verifier", a.verifier))
+	}
+	return a.config.Exchange(a.context, code)
+}
+
+// TokenSource returns source of the token, which refreshes it with
+// Spotify Accounts Service once it expires.
+func (a *Authenticator) TokenSource(token *oauth2.Token) oauth2.TokenSource {
+	return a.config.TokenSource(a.context, token)
+}
+
+// NewClient creates HTTP client which authorizes requests with tokens from the source.
+func (a *Authenticator) NewClient(source oauth2.TokenSource) *http.Client {
+	return newClient(a.context, source)
+}
+
+// newClient creates client authorizing requests with tokens from the source, unlike oauth2.NewClient
+// it does not keep the token until it expires, so that the source can refresh it earlier.
+func newClient(ctx context.Context, source oauth2.TokenSource) *http.Client {
+	// without the source, client of the context is returned
+	base := oauth2.NewClient(ctx, nil)
+	return &http.Client{Transport: &oauth2.Transport{Base: base.Transport, Source: source}}
+}
@@ -0,0 +1,89 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+func TestPKCEChallenge(t *testing.T) {
+	// example from RFC 7636, appendix B
+	challenge := pkceChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk")
+	if challenge != "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM" {
+		t.Fatalf("Unexpected code challenge %s", challenge)
+	}
+}
+
+func TestPKCEAuthenticator(t *testing.T) {
+	exchanged := url.Values{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		exchanged = r.PostForm
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token":"access","refresh_token":"refresh","token_type":"Bearer","expires_in":3600}`)
+	}))
+	defer server.Close()
+	a, err := NewPKCEAuthenticator("http://localhost:8888/spotify-cli", "client")
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	a.config.Endpoint.TokenURL = server.URL
+	if len(a.verifier) != 43 {
+		t.Fatalf("Expected code verifier of 43 characters, got %q", a.verifier)
+	}
+
+	authURL, err := url.Parse(a.AuthURL("state"))
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	query := authURL.Query()
+	if query.Get("code_challenge") != pkceChallenge(a.verifier) || query.Get("code_challenge_method") != "S256" {
+		t.Fatalf("Expected auth URL with the code challenge, got %s", authURL)
+	}
+
+	token, err := a.Token("state", httptest.NewRequest("GET", "/spotify-cli?code=code&state=state", nil))
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if token.AccessToken != "access" {
+		t.Fatalf("Expected access token to be given, got %v", token)
+	}
+	if exchanged.Get("code_verifier") != a.verifier || exchanged.Get("client_id") != "client" || exchanged.Get("client_secret") != "" {
+		t.Fatalf("Expected code to be exchanged with the verifier and client ID only, got %v", exchanged)
+	}
+}
+
+func TestAuthenticatorSendsRequestsThroughProxy(t *testing.T) {
+	proxied := make(chan string, 1)
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case proxied <- fmt.Sprintf("%s %s", r.Method, r.Host):
+		default:
+		}
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer proxy.Close()
+	proxyURL, err := url.Parse(proxy.URL)
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+
+	a := NewAuthenticator("http://localhost:8888/spotify-cli", "id", "secret")
+	a.SetProxy(proxyURL)
+	client := a.NewClient(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "access"}))
+	if _, err := client.Get("https://api.spotify.com/v1/me"); err == nil {
+		t.Fatalf("Expected to fail once proxy refused to connect, but it didn't")
+	}
+	select {
+	case request := <-proxied:
+		if request != "CONNECT api.spotify.com:443" {
+			t.Fatalf("Expected proxy to be asked to connect to Spotify API, got %s", request)
+		}
+	default:
+		t.Fatalf("Expected request to be sent through the proxy")
+	}
+}
@@ -0,0 +1,29 @@
+package web
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+	"runtime"
+)
+
+// OpenBrowser opens the URL in the user's browser, it is BrowserOpener used outside of debug mode.
+func OpenBrowser(url string) error {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("open", "-a", "/Applications/Google Chrome.app", url).Start()
+	case "linux":
+		return exec.Command("xdg-open", url).Start()
+	default:
+		return fmt.Errorf("OS: %v is not supported", runtime.GOOS)
+	}
+}
+
+// PrintURL returns BrowserOpener which prints the URL instead of opening it, so
+// that user can open it on another device, i.e. when running over SSH.
+func PrintURL(w io.Writer) BrowserOpener {
+	return func(url string) error {
+		_, err := fmt.Fprintf(w, "Open the following URL in a browser on any device and log in:\n\n%s\n\n", url)
+		return err
+	}
+}
@@ -0,0 +1,226 @@
+package web
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/oauth2"
+)
+
+// CallbackServer serves the handler receiving auth callback from Spotify backend.
+type CallbackServer interface {
+	// Serve starts serving in the background, error is returned
+	// when serving could not be started.
+	Serve(handler http.Handler) error
+}
+
+// BrowserOpener opens the URL in the user's browser.
+type BrowserOpener func(url string) error
+
+// Flow is OAuth2 authorization code flow, in which user logs in at the
+// Spotify site opened in the browser and Spotify backend calls back the
+// application. Authenticator, browser and server are injected, so that
+// the flow can be run without real credentials.
+type Flow struct {
+	Authenticator SpotifyAuthenticatorInterface
+	OpenBrowser   BrowserOpener
+	Server        CallbackServer
+	// CallbackPath is the path of redirect URL, i.e. "/spotify-cli".
+	CallbackPath string
+	// State is random string verifying that callback comes from this flow.
+	State string
+	// Tokens keeps the token between runs, user logs in each time when it is nil.
+	Tokens TokenStore
+	// Pasted is read for the URL user was redirected to after logging in, or the code
+	// from it, when the callback cannot reach this machine, i.e. over SSH. It is not
+	// read when nil. Output is where user is asked to paste it.
+	Pasted io.Reader
+	Output io.Writer
+}
+
+// Authenticate registers auth callback in the mux, serves it and waits until user logs in,
+// HTTP client which authorizes requests on behalf of the user is returned. User does not
+// log in when there is a stored token which can still be refreshed.
+func (flow *Flow) Authenticate(mux *http.ServeMux) (*http.Client, error) {
+	// buffered, so that the pasted URL is not waited for once callback is received
+	clients := make(chan *http.Client, 1)
+	mux.Handle(flow.CallbackPath, &AuthHandler{
+		Client:        clients,
+		State:         flow.State,
+		Authenticator: flow.Authenticator,
+		Tokens:        flow.Tokens,
+	})
+	err := flow.Server.Serve(mux)
+	if err != nil {
+		return nil, fmt.Errorf("could not serve auth callback: %v", err)
+	}
+	if source := flow.storedTokenSource(); source != nil {
+		return flow.Authenticator.NewClient(source), nil
+	}
+	authURL := flow.Authenticator.AuthURL(flow.State)
+	err = flow.OpenBrowser(authURL)
+	if err != nil {
+		return nil, fmt.Errorf("could not open browser with url: %s, err: %v", authURL, err)
+	}
+	if flow.Pasted != nil {
+		go flow.readPasted(clients)
+	}
+	return <-clients, nil
+}
+
+// readPasted reads lines pasted by the user until the redirect URL, or the code from it,
+// is exchanged for the token. Callback which reaches the server first is not waited for.
+func (flow *Flow) readPasted(clients chan<- *http.Client) {
+	output := flow.Output
+	if output == nil {
+		output = ioutil.Discard
+	}
+	fmt.Fprintln(output, "Once logged in, paste the URL you were redirected to, even if the page did not load:")
+	scanner := bufio.NewScanner(flow.Pasted)
+	for scanner.Scan() {
+		pasted := strings.TrimSpace(scanner.Text())
+		if pasted == "" {
+			continue
+		}
+		client, err := flow.exchangePasted(pasted)
+		if err != nil {
+			fmt.Fprintf(output, "Could not log in: %v, paste the URL again:\n", err)
+			continue
+		}
+		select {
+		case clients <- client:
+		default:
+		}
+		return
+	}
+}
+
+// exchangePasted exchanges the code from the pasted redirect URL for the token, URL without
+// a query is treated as the code itself, i.e. when only the code was copied.
+func (flow *Flow) exchangePasted(pasted string) (*http.Client, error) {
+	query := url.Values{"code": {pasted}, "state": {flow.State}}
+	if strings.Contains(pasted, "?") {
+		redirected, err := url.Parse(pasted)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse URL: %v", err)
+		}
+		query = redirected.Query()
+	}
+	r := &http.Request{Method: "GET", URL: &url.URL{Path: flow.CallbackPath, RawQuery: query.Encode()}}
+	token, err := flow.Authenticator.Token(flow.State, r)
+	if err != nil {
+		return nil, err
+	}
+	return flow.Authenticator.NewClient(newStoredTokenSource(flow.Authenticator, token, flow.Tokens)), nil
+}
+
+// storedTokenSource returns source of the stored token, or nil when there is no token
+// with which requests can be authorized, i.e. because it could not be refreshed.
+func (flow *Flow) storedTokenSource() oauth2.TokenSource {
+	if flow.Tokens == nil {
+		return nil
+	}
+	token, err := flow.Tokens.Load()
+	if err != nil {
+		log.Printf("Could not load stored token with %s", err)
+		return nil
+	}
+	if token == nil || token.RefreshToken == "" {
+		return nil
+	}
+	source := newStoredTokenSource(flow.Authenticator, token, flow.Tokens)
+	// expired token is refreshed right away, so that user logs in again when it cannot be
+	if _, err := source.Token(); err != nil {
+		log.Printf("Could not refresh stored token with %s", err)
+		return nil
+	}
+	return source
+}
+
+// HTTPServer is a CallbackServer listening on the TCP address, i.e. ":8888". When it cannot
+// listen on the address, i.e. because the port is used by another server, Fallbacks are tried in order.
+type HTTPServer struct {
+	Addr      string
+	Fallbacks []string
+	listener  net.Listener
+}
+
+// Listen listens on the first address which can be used, and returns it. It is called by
+// Serve when it was not called before, i.e. when redirect URL does not depend on the address.
+func (server *HTTPServer) Listen() (string, error) {
+	var err error
+	for _, addr := range append([]string{server.Addr}, server.Fallbacks...) {
+		server.listener, err = net.Listen("tcp", addr)
+		if err == nil {
+			return server.listener.Addr().String(), nil
+		}
+		log.Printf("Could not listen on %s with %s", addr, err)
+	}
+	return "", err
+}
+
+// Serve serves the handler in the background.
+func (server *HTTPServer) Serve(handler http.Handler) error {
+	if server.listener == nil {
+		if _, err := server.Listen(); err != nil {
+			return err
+		}
+	}
+	go http.Serve(server.listener, handler)
+	return nil
+}
+
+// DebugAuthenticator authenticates anyone without talking to Spotify Accounts Service,
+// its auth URL points straight at the auth callback under RedirectURL.
+type DebugAuthenticator struct {
+	RedirectURL string
+}
+
+var debugAuthCode = "debug"
+
+// AuthURL returns redirect URL with the code which would be given by Spotify.
+func (a DebugAuthenticator) AuthURL(state string) string {
+	return a.RedirectURL + "?" + url.Values{"code": {debugAuthCode}, "state": {state}}.Encode()
+}
+
+// Token verifies the state and gives fake token.
+func (a DebugAuthenticator) Token(state string, r *http.Request) (*oauth2.Token, error) {
+	values := r.URL.Query()
+	if values.Get("code") != debugAuthCode {
+		return nil, errors.New("debug: didn't get access code")
+	}
+	if values.Get("state") != state {
+		return nil, errors.New("debug: redirect state parameter doesn't match")
+	}
+	return &oauth2.Token{AccessToken: "debug", TokenType: "Bearer"}, nil
+}
+
+// TokenSource returns source of the fake token, which never expires.
+func (a DebugAuthenticator) TokenSource(token *oauth2.Token) oauth2.TokenSource {
+	return oauth2.StaticTokenSource(token)
+}
+
+// NewClient creates HTTP client which sends the fake token along with requests.
+func (a DebugAuthenticator) NewClient(source oauth2.TokenSource) *http.Client {
+	return newClient(context.Background(), source)
+}
+
+// DebugBrowser visits the URL without opening the browser, like the user would after logging in.
+func DebugBrowser(url string) error {
+	go func() {
+		resp, err := http.Get(url)
+		if err == nil {
+			resp.Body.Close()
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,176 @@
+package web
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type fakeCallbackServer struct {
+	handler http.Handler
+	err     error
+}
+
+func (fake *fakeCallbackServer) Serve(handler http.Handler) error {
+	fake.handler = handler
+	return fake.err
+}
+
+// browser returns BrowserOpener which follows the URL straight to the server,
+// as Spotify would after user logs in.
+func (fake *fakeCallbackServer) browser(opened *[]string) BrowserOpener {
+	return func(url string) error {
+		*opened = append(*opened, url)
+		go fake.handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", url, nil))
+		return nil
+	}
+}
+
+func TestFlowAuthenticate(t *testing.T) {
+	server := &fakeCallbackServer{}
+	opened := []string{}
+	flow := &Flow{
+		Authenticator: DebugAuthenticator{RedirectURL: "http://localhost:8888/spotify-cli"},
+		OpenBrowser:   server.browser(&opened),
+		Server:        server,
+		CallbackPath:  "/spotify-cli",
+		State:         "state",
+	}
+
+	client, err := flow.Authenticate(http.NewServeMux())
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if client == nil {
+		t.Fatalf("Expected HTTP client to be created")
+	}
+	expectedURL := "http://localhost:8888/spotify-cli?code=debug&state=state"
+	if len(opened) != 1 || opened[0] != expectedURL {
+		t.Fatalf("Expected browser to be opened with %s, got %v", expectedURL, opened)
+	}
+}
+
+func TestFlowAuthenticateFails(t *testing.T) {
+	server := &fakeCallbackServer{err: errors.New("address already in use")}
+	opened := []string{}
+	flow := &Flow{
+		Authenticator: DebugAuthenticator{},
+		OpenBrowser:   server.browser(&opened),
+		Server:        server,
+		CallbackPath:  "/spotify-cli",
+	}
+	if _, err := flow.Authenticate(http.NewServeMux()); err == nil {
+		t.Fatalf("Expected to fail when callback could not be served, but it didn't")
+	}
+	if len(opened) != 0 {
+		t.Fatalf("Expected browser not to be opened, but it was with %v", opened)
+	}
+
+	server.err = nil
+	flow.OpenBrowser = func(string) error { return errors.New("OS not supported") }
+	if _, err := flow.Authenticate(http.NewServeMux()); err == nil {
+		t.Fatalf("Expected to fail when browser could not be opened, but it didn't")
+	}
+}
+
+func TestAuthHandlerRejectsWrongState(t *testing.T) {
+	handler := &AuthHandler{
+		Client:        make(chan *http.Client, 1),
+		State:         "state",
+		Authenticator: DebugAuthenticator{},
+	}
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/spotify-cli?code=debug&state=other", nil))
+	if recorder.Code != http.StatusNotFound {
+		t.Fatalf("Expected callback with wrong state to be rejected, got status %d", recorder.Code)
+	}
+	if len(handler.Client) != 0 {
+		t.Fatalf("Expected no client to be created")
+	}
+}
+
+func TestFlowAuthenticatesWithPastedURL(t *testing.T) {
+	server := &fakeCallbackServer{}
+	output := &bytes.Buffer{}
+	tokens := &memoryTokenStore{}
+	flow := &Flow{
+		Authenticator: DebugAuthenticator{RedirectURL: "http://localhost:8888/spotify-cli"},
+		OpenBrowser:   PrintURL(output),
+		Server:        server,
+		CallbackPath:  "/spotify-cli",
+		State:         "state",
+		Tokens:        tokens,
+		Pasted: strings.NewReader(strings.Join([]string{
+			"",
+			"http://localhost:8888/spotify-cli?code=debug&state=other",
+			"http://localhost:8888/spotify-cli?code=debug&state=state",
+		}, "\n")),
+		Output: output,
+	}
+
+	client, err := flow.Authenticate(http.NewServeMux())
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if client == nil || tokens.saves != 1 {
+		t.Fatalf("Expected client to be created and token to be saved, got %d saves", tokens.saves)
+	}
+	printed := output.String()
+	if !strings.Contains(printed, "http://localhost:8888/spotify-cli?code=debug&state=state\n") {
+		t.Fatalf("Expected auth URL to be printed, got %q", printed)
+	}
+	if strings.Count(printed, "Could not log in") != 1 {
+		t.Fatalf("Expected URL with wrong state to be rejected once, got %q", printed)
+	}
+}
+
+func TestFlowAuthenticatesWithPastedCode(t *testing.T) {
+	server := &fakeCallbackServer{}
+	flow := &Flow{
+		Authenticator: DebugAuthenticator{RedirectURL: "http://localhost:8888/spotify-cli"},
+		OpenBrowser:   PrintURL(ioutil.Discard),
+		Server:        server,
+		CallbackPath:  "/spotify-cli",
+		State:         "state",
+		Pasted:        strings.NewReader(" debug \n"),
+	}
+	client, err := flow.Authenticate(http.NewServeMux())
+	if err != nil || client == nil {
+		t.Fatalf("Expected client to be created with the pasted code, got %v", err)
+	}
+}
+
+func TestHTTPServerFallsBackWhenPortIsInUse(t *testing.T) {
+	used, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Could not listen: %v", err)
+	}
+	defer used.Close()
+	server := &HTTPServer{Addr: used.Addr().String(), Fallbacks: []string{"127.0.0.1:0"}}
+
+	addr, err := server.Listen()
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if addr == used.Addr().String() {
+		t.Fatalf("Expected fallback address to be used, got %s", addr)
+	}
+	if err := server.Serve(http.NotFoundHandler()); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	resp, err := http.Get("http://" + addr + "/spotify-cli")
+	if err != nil {
+		t.Fatalf("Expected server to serve on fallback address, but it failed with %v", err)
+	}
+	resp.Body.Close()
+
+	server = &HTTPServer{Addr: used.Addr().String()}
+	if _, err := server.Listen(); err == nil {
+		t.Fatalf("Expected to fail when there are no fallbacks, but it didn't")
+	}
+}
@@ -0,0 +1,52 @@
+package web
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// echo turns echoing of the terminal on or off with stty, so that the passphrase is not
+// shown while it is typed. It is replaced in tests.
+var echo = func(terminal *os.File, on bool) error {
+	mode := "-echo"
+	if on {
+		mode = "echo"
+	}
+	cmd := exec.Command("stty", mode)
+	cmd.Stdin = terminal
+	return cmd.Run()
+}
+
+// AskPassphrase returns function asking for passphrase of the token file in the terminal, for
+// EncryptedFileTokenStore. It is asked only the first time, i.e. when the token is loaded on
+// start, and given again afterwards, when the refreshed token is saved.
+func AskPassphrase(terminal *os.File, output io.Writer) func() (string, error) {
+	var once sync.Once
+	var passphrase string
+	var err error
+	return func() (string, error) {
+		once.Do(func() {
+			fmt.Fprint(output, "Passphrase of the token: ")
+			// without stty, i.e. on Windows, the passphrase is shown
+			if echo(terminal, false) == nil {
+				defer echo(terminal, true)
+			}
+			passphrase, err = bufio.NewReader(terminal).ReadString('\n')
+			fmt.Fprintln(output)
+			if err != nil && !(err == io.EOF && passphrase != "") {
+				err = fmt.Errorf("could not read passphrase: %v", err)
+				return
+			}
+			passphrase, err = strings.TrimRight(passphrase, "\r\n"), nil
+			if passphrase == "" {
+				err = fmt.Errorf("passphrase cannot be empty")
+			}
+		})
+		return passphrase, err
+	}
+}
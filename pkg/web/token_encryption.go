@@ -0,0 +1,145 @@
+package web
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jedruniu/spotify-cli/pkg/atomicfile"
+
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/oauth2"
+)
+
+// EncryptedFileTokenStore keeps token in the file under Path encrypted with AES-GCM,
+// with the key derived from Passphrase with PBKDF2. Without the passphrase, it is asked
+// for with AskPassphrase when the file is used. Without either of them, the key is derived
+// from identity of the machine and the user instead, which keeps the token from being
+// stored in plain text or used on another machine, but not from other programs of the user.
+type EncryptedFileTokenStore struct {
+	Path          string
+	Passphrase    string
+	AskPassphrase func() (string, error)
+}
+
+// encryptedToken is the content of the encrypted token file.
+type encryptedToken struct {
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Token      []byte `json:"token"`
+}
+
+var (
+	tokenKDF = "pbkdf2-sha256"
+	// tokenKDFIterations is the number of PBKDF2 iterations of newly encrypted tokens.
+	tokenKDFIterations = 100000
+	tokenSaltLength    = 16
+	tokenKeyLength     = 32
+)
+
+// Load decrypts token from the file, missing file means there is no token.
+func (store EncryptedFileTokenStore) Load() (*oauth2.Token, error) {
+	data, err := ioutil.ReadFile(store.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read token: %v", err)
+	}
+	encrypted := encryptedToken{}
+	if err := json.Unmarshal(data, &encrypted); err != nil {
+		return nil, fmt.Errorf("could not decode token: %v", err)
+	}
+	if encrypted.KDF != tokenKDF {
+		return nil, fmt.Errorf("could not decrypt token derived with unknown KDF %s", encrypted.KDF)
+	}
+	aead, err := store.cipher(encrypted.Salt, encrypted.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := aead.Open(nil, encrypted.Nonce, encrypted.Token, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not decrypt token, passphrase is wrong or token was encrypted on another machine")
+	}
+	token := &oauth2.Token{}
+	if err := json.Unmarshal(plain, token); err != nil {
+		return nil, fmt.Errorf("could not decode token: %v", err)
+	}
+	return token, nil
+}
+
+// Save encrypts token with a new salt and nonce, and writes it to the file.
+func (store EncryptedFileTokenStore) Save(token *oauth2.Token) error {
+	plain, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("could not encode token: %v", err)
+	}
+	encrypted := encryptedToken{KDF: tokenKDF, Iterations: tokenKDFIterations, Salt: make([]byte, tokenSaltLength)}
+	if _, err := rand.Read(encrypted.Salt); err != nil {
+		return fmt.Errorf("could not generate salt: %v", err)
+	}
+	aead, err := store.cipher(encrypted.Salt, encrypted.Iterations)
+	if err != nil {
+		return err
+	}
+	encrypted.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(encrypted.Nonce); err != nil {
+		return fmt.Errorf("could not generate nonce: %v", err)
+	}
+	encrypted.Token = aead.Seal(nil, encrypted.Nonce, plain, nil)
+	data, err := json.Marshal(encrypted)
+	if err != nil {
+		return fmt.Errorf("could not encode token: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(store.Path), 0700); err != nil {
+		return fmt.Errorf("could not create token directory: %v", err)
+	}
+	return atomicfile.WriteFile(store.Path, data, 0600)
+}
+
+// Delete removes the file.
+func (store EncryptedFileTokenStore) Delete() error {
+	return removeToken(store.Path)
+}
+
+// cipher returns AES-GCM with the key derived from the passphrase and the salt.
+func (store EncryptedFileTokenStore) cipher(salt []byte, iterations int) (cipher.AEAD, error) {
+	passphrase := store.Passphrase
+	if passphrase == "" && store.AskPassphrase != nil {
+		asked, err := store.AskPassphrase()
+		if err != nil {
+			return nil, err
+		}
+		passphrase = asked
+	}
+	if passphrase == "" {
+		passphrase = machinePassphrase()
+	}
+	block, err := aes.NewCipher(pbkdf2.Key([]byte(passphrase), salt, iterations, tokenKeyLength, sha256.New))
+	if err != nil {
+		return nil, fmt.Errorf("could not create cipher: %v", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("could not create cipher: %v", err)
+	}
+	return aead, nil
+}
+
+// machinePassphrase identifies the machine and the user, i.e. when no passphrase is given.
+func machinePassphrase() string {
+	// machine ID is only available on Linux, host name and home directory are used everywhere
+	id, _ := ioutil.ReadFile("/etc/machine-id")
+	host, _ := os.Hostname()
+	home, _ := os.UserHomeDir()
+	return strings.Join([]string{strings.TrimSpace(string(id)), host, home}, "\n")
+}
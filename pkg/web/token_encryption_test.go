@@ -0,0 +1,94 @@
+package web
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+func TestEncryptedFileTokenStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spotify-cli-tokens")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	defer func(previous int) { tokenKDFIterations = previous }(tokenKDFIterations)
+	tokenKDFIterations = 10
+	store := EncryptedFileTokenStore{Path: filepath.Join(dir, "token.enc"), Passphrase: "secret"}
+
+	token, err := store.Load()
+	if err != nil || token != nil {
+		t.Fatalf("Expected no token before it is saved, got %v, %v", token, err)
+	}
+	saved := &oauth2.Token{AccessToken: "access", RefreshToken: "refresh", TokenType: "Bearer"}
+	if err := store.Save(saved); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	data, err := ioutil.ReadFile(store.Path)
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if strings.Contains(string(data), "refresh") {
+		t.Fatalf("Expected token not to be stored in plain text, got %s", data)
+	}
+	token, err = store.Load()
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if !reflect.DeepEqual(token, saved) {
+		t.Fatalf("Expected token %v to be loaded, got %v", saved, token)
+	}
+
+	store.Passphrase = "wrong"
+	if _, err := store.Load(); err == nil {
+		t.Fatalf("Expected to fail with wrong passphrase, but it did not")
+	}
+}
+
+func TestEncryptedFileTokenStoreAsksForPassphrase(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spotify-cli-tokens")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	defer func(previous int) { tokenKDFIterations = previous }(tokenKDFIterations)
+	tokenKDFIterations = 10
+	defer func(previous func(*os.File, bool) error) { echo = previous }(echo)
+	echoed := []bool{}
+	echo = func(terminal *os.File, on bool) error {
+		echoed = append(echoed, on)
+		return nil
+	}
+	terminal, typed, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	defer terminal.Close()
+	typed.Write([]byte("secret\n"))
+	typed.Close()
+	prompt := &strings.Builder{}
+	store := EncryptedFileTokenStore{Path: filepath.Join(dir, "token.enc"), AskPassphrase: AskPassphrase(terminal, prompt)}
+
+	saved := &oauth2.Token{AccessToken: "access", RefreshToken: "refresh", TokenType: "Bearer"}
+	if err := store.Save(saved); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if err := store.Save(saved); err != nil {
+		t.Fatalf("Expected passphrase to be asked only once, but it failed with %v", err)
+	}
+	if !strings.Contains(prompt.String(), "Passphrase") || !reflect.DeepEqual(echoed, []bool{false, true}) {
+		t.Fatalf("Expected passphrase to be asked once without echo, got prompt %q and echo %v", prompt.String(), echoed)
+	}
+	token, err := EncryptedFileTokenStore{Path: store.Path, Passphrase: "secret"}.Load()
+	if err != nil || !reflect.DeepEqual(token, saved) {
+		t.Fatalf("Expected token to be encrypted with the passphrase, got %v, %v", token, err)
+	}
+	if _, err := (EncryptedFileTokenStore{Path: store.Path}).Load(); err == nil {
+		t.Fatalf("Expected token not to be decrypted without the passphrase")
+	}
+}
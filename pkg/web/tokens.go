@@ -0,0 +1,283 @@
+package web
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/jedruniu/spotify-cli/pkg/atomicfile"
+	"github.com/jedruniu/spotify-cli/pkg/keyring"
+
+	"golang.org/x/oauth2"
+)
+
+// TokenStore keeps OAuth2 token between runs, so that user does not have to
+// log in each time the application starts.
+type TokenStore interface {
+	// Load returns the stored token, or nil when there is none.
+	Load() (*oauth2.Token, error)
+	Save(token *oauth2.Token) error
+	// Delete removes the stored token, it is not an error when there is none.
+	Delete() error
+}
+
+// FileTokenStore keeps token in JSON file under Path, readable only by the user.
+type FileTokenStore struct {
+	Path string
+}
+
+// Load reads token from the file, missing file means there is no token.
+func (store FileTokenStore) Load() (*oauth2.Token, error) {
+	data, err := ioutil.ReadFile(store.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read token: %v", err)
+	}
+	token := &oauth2.Token{}
+	if err := json.Unmarshal(data, token); err != nil {
+		return nil, fmt.Errorf("could not decode token: %v", err)
+	}
+	return token, nil
+}
+
+// Save writes token to the file, creating its directory when needed.
+func (store FileTokenStore) Save(token *oauth2.Token) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("could not encode token: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(store.Path), 0700); err != nil {
+		return fmt.Errorf("could not create token directory: %v", err)
+	}
+	return atomicfile.WriteFile(store.Path, data, 0600)
+}
+
+// keyringGet, keyringSet and keyringDelete are replaced in tests, so that they do not use the keyring of the user.
+var (
+	keyringGet    = keyring.Get
+	keyringSet    = keyring.Set
+	keyringDelete = keyring.Delete
+)
+
+// Delete removes the file.
+func (store FileTokenStore) Delete() error {
+	return removeToken(store.Path)
+}
+
+// removeToken removes the token file, it is not an error when there is none.
+func removeToken(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("could not remove token: %v", err)
+	}
+	return nil
+}
+
+// KeyringTokenStore keeps token of the account in the keyring of the operating system
+// under the service name. When the keyring cannot be used, i.e. there is no Secret
+// Service running, Fallback is used instead. Token kept in plain text under Legacy
+// path, as it was before tokens were kept in the keyring, is moved when loaded.
+type KeyringTokenStore struct {
+	Service  string
+	Account  string
+	Fallback TokenStore
+	Legacy   string
+}
+
+// Load returns token from the keyring, or from the fallback store when keyring cannot be used.
+func (store KeyringTokenStore) Load() (*oauth2.Token, error) {
+	token, err := store.load()
+	if err != nil || token != nil || store.Legacy == "" {
+		return token, err
+	}
+	legacy := FileTokenStore{Path: store.Legacy}
+	token, err = legacy.Load()
+	if err != nil || token == nil {
+		return nil, err
+	}
+	if err := store.Save(token); err != nil {
+		return nil, err
+	}
+	if err := os.Remove(store.Legacy); err != nil {
+		log.Printf("Could not remove plain text token with %s", err)
+	}
+	return token, nil
+}
+
+func (store KeyringTokenStore) load() (*oauth2.Token, error) {
+	secret, err := keyringGet(store.Service, store.Account)
+	if err == keyring.ErrNotFound {
+		// token might have been saved in the fallback store while keyring was unavailable
+		return store.Fallback.Load()
+	}
+	if err != nil {
+		log.Printf("Could not use keyring, falling back to file with %s", err)
+		return store.Fallback.Load()
+	}
+	data, err := base64.StdEncoding.DecodeString(secret)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode token from keyring: %v", err)
+	}
+	token := &oauth2.Token{}
+	if err := json.Unmarshal(data, token); err != nil {
+		return nil, fmt.Errorf("could not decode token from keyring: %v", err)
+	}
+	return token, nil
+}
+
+// Save keeps token in the keyring, or in the fallback store when keyring cannot be used.
+func (store KeyringTokenStore) Save(token *oauth2.Token) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("could not encode token: %v", err)
+	}
+	// encoded, so that the secret is safe to pass to keyring commands
+	err = keyringSet(store.Service, store.Account, base64.StdEncoding.EncodeToString(data))
+	if err != nil {
+		log.Printf("Could not use keyring, falling back to file with %s", err)
+		return store.Fallback.Save(token)
+	}
+	return nil
+}
+
+// Delete removes token from the keyring, the fallback store and the plain text file,
+// wherever it was kept.
+func (store KeyringTokenStore) Delete() error {
+	if err := keyringDelete(store.Service, store.Account); err != nil {
+		log.Printf("Could not use keyring, removing token only from file with %s", err)
+	}
+	if err := store.Fallback.Delete(); err != nil {
+		return err
+	}
+	if store.Legacy == "" {
+		return nil
+	}
+	return removeToken(store.Legacy)
+}
+
+// refreshingTokenSource gives token of the authenticator, which is refreshed once it expires,
+// or on demand, i.e. when Spotify rejected it before it expired.
+type refreshingTokenSource struct {
+	authenticator SpotifyAuthenticatorInterface
+	mu            sync.Mutex
+	source        oauth2.TokenSource
+	// token is the one given most recently.
+	token *oauth2.Token
+}
+
+func newRefreshingTokenSource(authenticator SpotifyAuthenticatorInterface, token *oauth2.Token) *refreshingTokenSource {
+	return &refreshingTokenSource{authenticator: authenticator, source: authenticator.TokenSource(token), token: token}
+}
+
+// Token returns the token, refreshing it once it expires.
+func (refreshing *refreshingTokenSource) Token() (*oauth2.Token, error) {
+	refreshing.mu.Lock()
+	defer refreshing.mu.Unlock()
+	token, err := refreshing.source.Token()
+	if err != nil {
+		return nil, err
+	}
+	refreshing.token = token
+	return token, nil
+}
+
+// Refresh refreshes the token even though it has not expired yet.
+func (refreshing *refreshingTokenSource) Refresh() error {
+	refreshing.mu.Lock()
+	defer refreshing.mu.Unlock()
+	if refreshing.token.RefreshToken == "" {
+		return fmt.Errorf("could not refresh token, there is no refresh token")
+	}
+	// token which expired is refreshed by the source right away
+	expired := *refreshing.token
+	expired.Expiry = time.Now().Add(-time.Minute)
+	source := refreshing.authenticator.TokenSource(&expired)
+	token, err := source.Token()
+	if err != nil {
+		return fmt.Errorf("could not refresh token: %v", err)
+	}
+	refreshing.source, refreshing.token = source, token
+	return nil
+}
+
+// refresher is a token source which can refresh the token on demand.
+type refresher interface {
+	oauth2.TokenSource
+	Refresh() error
+}
+
+// TokenRefresh returns function refreshing the token which authorizes requests of the client
+// returned by Flow.Authenticate, i.e. once Spotify rejected it before it expired.
+func TokenRefresh(client *http.Client) func() error {
+	transport, ok := client.Transport.(*oauth2.Transport)
+	if !ok {
+		return func() error { return fmt.Errorf("could not refresh token of client which is not authorized") }
+	}
+	source, ok := transport.Source.(refresher)
+	if !ok {
+		return func() error { return fmt.Errorf("could not refresh token which cannot be refreshed") }
+	}
+	return source.Refresh
+}
+
+// storedTokenSource saves each new token given by the source, i.e. once it is refreshed.
+type storedTokenSource struct {
+	source refresher
+	store  TokenStore
+	mu     sync.Mutex
+	// saved is the access token which was saved most recently.
+	saved string
+}
+
+// newStoredTokenSource creates source refreshing the token with the authenticator, the token is saved
+// right away. Without the store, tokens are only kept in memory.
+func newStoredTokenSource(authenticator SpotifyAuthenticatorInterface, token *oauth2.Token, store TokenStore) refresher {
+	source := newRefreshingTokenSource(authenticator, token)
+	if store == nil {
+		return source
+	}
+	stored := &storedTokenSource{source: source, store: store}
+	stored.save(token)
+	return stored
+}
+
+// Token returns token of the underlying source, which is saved when it changed.
+func (stored *storedTokenSource) Token() (*oauth2.Token, error) {
+	token, err := stored.source.Token()
+	if err != nil {
+		return nil, err
+	}
+	stored.save(token)
+	return token, nil
+}
+
+// Refresh refreshes the token of the underlying source and saves it.
+func (stored *storedTokenSource) Refresh() error {
+	if err := stored.source.Refresh(); err != nil {
+		return err
+	}
+	_, err := stored.Token()
+	return err
+}
+
+// save saves the token unless it was saved already, failing to do so only means logging in again next time.
+func (stored *storedTokenSource) save(token *oauth2.Token) {
+	stored.mu.Lock()
+	defer stored.mu.Unlock()
+	if token.AccessToken == stored.saved {
+		return
+	}
+	if err := stored.store.Save(token); err != nil {
+		log.Printf("Could not save token with %s", err)
+		return
+	}
+	stored.saved = token.AccessToken
+}
@@ -0,0 +1,303 @@
+package web
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jedruniu/spotify-cli/pkg/keyring"
+
+	"golang.org/x/oauth2"
+)
+
+type memoryTokenStore struct {
+	token *oauth2.Token
+	saves int
+}
+
+func (store *memoryTokenStore) Load() (*oauth2.Token, error) {
+	return store.token, nil
+}
+
+func (store *memoryTokenStore) Save(token *oauth2.Token) error {
+	store.token = token
+	store.saves++
+	return nil
+}
+
+func (store *memoryTokenStore) Delete() error {
+	store.token = nil
+	return nil
+}
+
+func TestFileTokenStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spotify-cli-tokens")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	store := FileTokenStore{Path: filepath.Join(dir, "spotify-cli", "token.json")}
+
+	token, err := store.Load()
+	if err != nil || token != nil {
+		t.Fatalf("Expected no token before it is saved, got %v, %v", token, err)
+	}
+	saved := &oauth2.Token{AccessToken: "access", RefreshToken: "refresh", TokenType: "Bearer", Expiry: time.Date(2020, 3, 1, 12, 0, 0, 0, time.UTC)}
+	if err := store.Save(saved); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	token, err = store.Load()
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if !reflect.DeepEqual(token, saved) {
+		t.Fatalf("Expected token %v to be loaded, got %v", saved, token)
+	}
+	info, err := os.Stat(store.Path)
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Fatalf("Expected token to be readable only by the user, got %v", info.Mode().Perm())
+	}
+	for i := 0; i < 2; i++ {
+		if err := store.Delete(); err != nil {
+			t.Fatalf("Did not expect to fail, but it did with %v", err)
+		}
+	}
+	if token, err := store.Load(); err != nil || token != nil {
+		t.Fatalf("Expected no token once it is deleted, got %v, %v", token, err)
+	}
+}
+
+// refreshingAuthenticator gives source which refreshes any token with the refreshed one.
+type refreshingAuthenticator struct {
+	DebugAuthenticator
+	refreshed *oauth2.Token
+}
+
+func (a *refreshingAuthenticator) TokenSource(token *oauth2.Token) oauth2.TokenSource {
+	return oauth2.StaticTokenSource(a.refreshed)
+}
+
+func TestFlowAuthenticatesWithStoredToken(t *testing.T) {
+	server := &fakeCallbackServer{}
+	opened := []string{}
+	stored := &oauth2.Token{AccessToken: "expired", RefreshToken: "refresh"}
+	refreshed := &oauth2.Token{AccessToken: "refreshed", RefreshToken: "refresh"}
+	tokens := &memoryTokenStore{token: stored}
+	flow := &Flow{
+		Authenticator: &refreshingAuthenticator{refreshed: refreshed},
+		OpenBrowser:   server.browser(&opened),
+		Server:        server,
+		CallbackPath:  "/spotify-cli",
+		State:         "state",
+		Tokens:        tokens,
+	}
+
+	client, err := flow.Authenticate(http.NewServeMux())
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if client == nil || len(opened) != 0 {
+		t.Fatalf("Expected client to be created without logging in, browser was opened with %v", opened)
+	}
+	if tokens.token != refreshed || tokens.saves != 2 {
+		t.Fatalf("Expected stored and refreshed tokens to be saved, got %v saved %d times", tokens.token, tokens.saves)
+	}
+}
+
+// expiringAuthenticator gives source which refreshes only expired tokens with the refreshed one.
+type expiringAuthenticator struct {
+	DebugAuthenticator
+	refreshed *oauth2.Token
+}
+
+func (a *expiringAuthenticator) TokenSource(token *oauth2.Token) oauth2.TokenSource {
+	if token.Valid() {
+		return oauth2.StaticTokenSource(token)
+	}
+	return oauth2.StaticTokenSource(a.refreshed)
+}
+
+func TestTokenRefreshRefreshesTokenBeforeItExpires(t *testing.T) {
+	authorizations := []string{}
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+	}))
+	defer api.Close()
+	rejected := &oauth2.Token{AccessToken: "rejected", RefreshToken: "refresh", Expiry: time.Now().Add(time.Hour)}
+	refreshed := &oauth2.Token{AccessToken: "refreshed", RefreshToken: "refresh", Expiry: time.Now().Add(time.Hour)}
+	tokens := &memoryTokenStore{token: rejected}
+	flow := &Flow{
+		Authenticator: &expiringAuthenticator{refreshed: refreshed},
+		Server:        &fakeCallbackServer{},
+		CallbackPath:  "/spotify-cli",
+		State:         "state",
+		Tokens:        tokens,
+	}
+	client, err := flow.Authenticate(http.NewServeMux())
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(api.URL)
+		if err != nil {
+			t.Fatalf("Did not expect to fail, but it did with %v", err)
+		}
+		resp.Body.Close()
+		if i == 0 {
+			if err := TokenRefresh(client)(); err != nil {
+				t.Fatalf("Did not expect to fail, but it did with %v", err)
+			}
+		}
+	}
+	if expected := []string{"Bearer rejected", "Bearer refreshed"}; !reflect.DeepEqual(authorizations, expected) {
+		t.Fatalf("Expected requests to be authorized with %v, got %v", expected, authorizations)
+	}
+	if tokens.token != refreshed {
+		t.Fatalf("Expected refreshed token to be saved, got %v", tokens.token)
+	}
+}
+
+func TestFlowSavesTokenOnceUserLogsIn(t *testing.T) {
+	server := &fakeCallbackServer{}
+	opened := []string{}
+	// token without refresh token cannot be used
+	tokens := &memoryTokenStore{token: &oauth2.Token{AccessToken: "access"}}
+	flow := &Flow{
+		Authenticator: DebugAuthenticator{RedirectURL: "http://localhost:8888/spotify-cli"},
+		OpenBrowser:   server.browser(&opened),
+		Server:        server,
+		CallbackPath:  "/spotify-cli",
+		State:         "state",
+		Tokens:        tokens,
+	}
+
+	if _, err := flow.Authenticate(http.NewServeMux()); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if len(opened) != 1 {
+		t.Fatalf("Expected user to log in, browser was opened with %v", opened)
+	}
+	if tokens.token.AccessToken != "debug" || tokens.saves != 1 {
+		t.Fatalf("Expected token of the user who logged in to be saved, got %v saved %d times", tokens.token, tokens.saves)
+	}
+}
+
+// fakeKeyring replaces keyring of the user, when unavailable it fails like a keyring without Secret Service.
+func fakeKeyring(available bool) (map[string]string, func()) {
+	previousGet, previousSet, previousDelete := keyringGet, keyringSet, keyringDelete
+	secrets := map[string]string{}
+	keyringGet = func(service, user string) (string, error) {
+		if !available {
+			return "", keyring.ErrUnsupported
+		}
+		secret, ok := secrets[service+"/"+user]
+		if !ok {
+			return "", keyring.ErrNotFound
+		}
+		return secret, nil
+	}
+	keyringSet = func(service, user, secret string) error {
+		if !available {
+			return keyring.ErrUnsupported
+		}
+		secrets[service+"/"+user] = secret
+		return nil
+	}
+	keyringDelete = func(service, user string) error {
+		if !available {
+			return keyring.ErrUnsupported
+		}
+		delete(secrets, service+"/"+user)
+		return nil
+	}
+	return secrets, func() { keyringGet, keyringSet, keyringDelete = previousGet, previousSet, previousDelete }
+}
+
+func TestKeyringTokenStore(t *testing.T) {
+	secrets, restore := fakeKeyring(true)
+	defer restore()
+	fallback := &memoryTokenStore{}
+	store := KeyringTokenStore{Service: "spotify-cli", Account: "work", Fallback: fallback}
+
+	token, err := store.Load()
+	if err != nil || token != nil {
+		t.Fatalf("Expected no token before it is saved, got %v, %v", token, err)
+	}
+	saved := &oauth2.Token{AccessToken: "access", RefreshToken: "refresh", TokenType: "Bearer"}
+	if err := store.Save(saved); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if len(secrets) != 1 || fallback.saves != 0 {
+		t.Fatalf("Expected token to be kept only in the keyring, got %v and %d saves to fallback", secrets, fallback.saves)
+	}
+	token, err = store.Load()
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if !reflect.DeepEqual(token, saved) {
+		t.Fatalf("Expected token %v to be loaded, got %v", saved, token)
+	}
+
+	fallback.token = saved
+	if err := store.Delete(); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if token, err := store.Load(); err != nil || token != nil || len(secrets) != 0 {
+		t.Fatalf("Expected token to be deleted everywhere, got %v, %v and %v", token, err, secrets)
+	}
+}
+
+func TestKeyringTokenStoreFallsBack(t *testing.T) {
+	_, restore := fakeKeyring(false)
+	defer restore()
+	fallback := &memoryTokenStore{}
+	store := KeyringTokenStore{Service: "spotify-cli", Account: "work", Fallback: fallback}
+
+	saved := &oauth2.Token{AccessToken: "access", RefreshToken: "refresh"}
+	if err := store.Save(saved); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	token, err := store.Load()
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if token != saved || fallback.saves != 1 {
+		t.Fatalf("Expected token to be kept in the fallback store, got %v saved %d times", token, fallback.saves)
+	}
+}
+
+func TestKeyringTokenStoreMovesPlainTextToken(t *testing.T) {
+	secrets, restore := fakeKeyring(true)
+	defer restore()
+	dir, err := ioutil.TempDir("", "spotify-cli-tokens")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	legacy := FileTokenStore{Path: filepath.Join(dir, "token.json")}
+	saved := &oauth2.Token{AccessToken: "access", RefreshToken: "refresh"}
+	if err := legacy.Save(saved); err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	store := KeyringTokenStore{Service: "spotify-cli", Account: "default", Fallback: &memoryTokenStore{}, Legacy: legacy.Path}
+
+	token, err := store.Load()
+	if err != nil {
+		t.Fatalf("Did not expect to fail, but it did with %v", err)
+	}
+	if token.RefreshToken != saved.RefreshToken || len(secrets) != 1 {
+		t.Fatalf("Expected plain text token to be moved to the keyring, got %v and %v", token, secrets)
+	}
+	if _, err := os.Stat(legacy.Path); !os.IsNotExist(err) {
+		t.Fatalf("Expected plain text token to be removed, got %v", err)
+	}
+}